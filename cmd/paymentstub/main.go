@@ -0,0 +1,26 @@
+// Command paymentstub serves a local stand-in for the Stripe API, to run
+// the reservation service with the stripe payment gateway without a Stripe
+// account:
+//
+//	paymentstub -addr :12111
+//	reservation -payment.gateway stripe -payment.stripe-url http://localhost:12111 -payment.stripe-key sk_test
+package main
+
+import (
+	"flag"
+	"github.com/go-kit/kit/log"
+	"net/http"
+	"os"
+	"reservations/pkg/payment"
+)
+
+func main() {
+	addr := flag.String("addr", ":12111", "HTTP listen address")
+	flag.Parse()
+
+	logger := log.NewLogfmtLogger(os.Stdout)
+	logger = log.With(logger, "ts", log.DefaultTimestampUTC)
+
+	logger.Log("transport", "HTTP", "addr", *addr)
+	logger.Log("exit", http.ListenAndServe(*addr, payment.NewStripeStub()))
+}
@@ -1,19 +1,44 @@
 package main
 
 import (
-	"flag"
+	"context"
 	"fmt"
+	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/gorilla/mux"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/swaggo/http-swagger"
+	"google.golang.org/grpc"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	_ "reservations/docs"
+	"reservations/pkg/audit"
+	"reservations/pkg/auth"
+	"reservations/pkg/authz"
+	"reservations/pkg/config"
 	"reservations/pkg/customer"
+	"reservations/pkg/event"
+	"reservations/pkg/fee"
+	"reservations/pkg/hoststand"
+	"reservations/pkg/idempotency"
+	"reservations/pkg/outbox"
+	"reservations/pkg/payment"
+	"reservations/pkg/pb"
 	"reservations/pkg/reservation"
+	"reservations/pkg/seating"
+	"reservations/pkg/series"
 	"reservations/pkg/storage"
+	"reservations/pkg/stream"
+	"reservations/pkg/tenant"
+	"reservations/pkg/tracing"
+	"reservations/pkg/webhook"
 	"syscall"
+	"time"
 )
 
 // @title Reservation System API
@@ -31,54 +56,308 @@ import (
 // @BasePath /
 func main() {
 
-	var (
-		httpAddr = flag.String("http.addr", ":8080", "HTTP listen address")
-	)
-	flag.Parse()
+	cfg, err := config.Load(os.Args[0], os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
-	db, err := storage.NewDB("reservations")
+	db, err := storage.NewDB(cfg.DB.Dialect, cfg.DB.DSN)
 	if err != nil {
 		panic(err)
 	}
+	db.DefaultLimit = cfg.DB.DefaultLimit
+	db.MaxLimit = cfg.DB.MaxLimit
+	db.BookingDuration = cfg.Seating.Duration
 
 	logger := log.NewLogfmtLogger(os.Stdout)
 	logger = log.With(logger, "ts", log.DefaultTimestampUTC)
 	logger = log.With(logger, "caller", log.DefaultCaller)
 
+	if cfg.DB.Migrate {
+		applied, err := db.Migrate()
+		if err != nil {
+			panic(err)
+		}
+		for _, m := range applied {
+			logger.Log("migration", fmt.Sprintf("%04d_%s", m.Version, m.Name), "msg", "applied")
+		}
+	}
+
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.Endpoint,
+		cfg.Tracing.ServiceName, cfg.Tracing.SampleRatio)
+	if err != nil {
+		panic(err)
+	}
+	defer shutdownTracing(context.Background())
+
+	db.Instrument(
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "reservations",
+			Subsystem: "db",
+			Name:      "transactions_total",
+			Help:      "Number of database transactions by outcome.",
+		}, []string{"outcome"}),
+		kitprometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: "reservations",
+			Subsystem: "db",
+			Name:      "transaction_duration_seconds",
+			Help:      "Duration of database transactions in seconds.",
+		}, []string{"outcome"}),
+	)
+
 	r := mux.NewRouter()
 
+	r.Handle("/metrics", promhttp.Handler())
+
 	r.PathPrefix("/swagger/").Handler(httpSwagger.Handler(
-		httpSwagger.URL("http://localhost:8080/swagger/doc.json"), // The url pointing to API definition"
+		httpSwagger.URL(cfg.HTTP.SwaggerURL), // The url pointing to API definition"
 	))
 
-	r = initCustomerHandler(r, db, logger)
-	r = initReservationHandler(r, db, logger)
+	authenticator, err := initAuthenticator(cfg.Auth, db)
+	if err != nil {
+		panic(err)
+	}
+	authorizer := initAuthorizer(cfg.Auth, db)
+
+	// Idempotency keys are scoped to the authenticated caller, so requests
+	// are authenticated before the key is looked up.
+	var authMiddleware []endpoint.Middleware
+	idempotencyClient := idempotency.DefaultClientFunc
+	if authenticator != nil {
+		r.Use(auth.HTTPMiddleware(authenticator))
+		authMiddleware = []endpoint.Middleware{auth.Middleware(authenticator)}
+		idempotencyClient = idempotency.PrincipalClientFunc
+	}
+	r.Use(idempotency.Middleware(idempotency.NewIdempotencyRepository(*db), cfg.Idempotency.TTL, idempotencyClient, logger))
+
+	// The venue is resolved after authentication, as a token may be bound
+	// to one.
+	venues := tenant.NewVenueRepository(*db)
+	mw := append(authMiddleware, tenant.Middleware(venues, cfg.Tenant.Required))
+
+	auditor := initAuditService(db, logger)
+
+	customerService := initCustomerService(db, logger)
+	hub := hoststand.NewHub(cfg.HostStand.LockTTL, logger)
+	paymentRepo := payment.NewPaymentRepository(*db)
+	paymentService := initPaymentService(paymentRepo, cfg.Payment, logger)
+	terms := reservation.Terms{Policy: cfg.Policy, Payments: paymentService, DepositTTL: cfg.Payment.DepositTTL}
+	reservationRepo := reservation.NewReservationRepository(*db)
+	reservationService := initReservationService(reservationRepo, cfg.Seating.Duration, terms, hub, logger)
+
+	r = customer.MakeHTTPHandler(r, customerService, authorizer, logger, mw...)
+	r = reservation.MakeHTTPHandler(r, reservationService, authorizer, logger, mw...)
+	r = audit.MakeHTTPHandler(r, auditor, authorizer, logger, mw...)
+	r = authz.MakeHTTPHandler(r, initRoleService(db, logger), authorizer, logger, mw...)
+	seatingRepo := seating.NewSeatingRepository(*db)
+	r = seating.MakeHTTPHandler(r, initSeatingService(seatingRepo, cfg.Seating, cfg.Holds, logger), authorizer, logger, mw...)
+
+	seriesRepo := series.NewSeriesRepository(*db, terms)
+	r = series.MakeHTTPHandler(r, initSeriesService(seriesRepo, cfg.Seating.Duration, cfg.Series, terms, logger), authorizer, logger, mw...)
+	r = fee.MakeHTTPHandler(r, initFeeService(db, paymentService, logger), authorizer, logger, mw...)
+	r = payment.MakeHTTPHandler(r, paymentService, authorizer, logger, mw...)
+
+	webhookRepo := webhook.NewWebhookRepository(*db)
+	r = initWebhookHandler(r, webhookRepo, authorizer, logger, mw...)
+
+	broker := stream.NewBroker()
+	r = stream.MakeHTTPHandler(r, initStreamService(db, broker, logger), authorizer, logger, mw...)
+	r = hoststand.MakeHTTPHandler(r, hub, authorizer, logger, mw...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	relay := outbox.NewRelay(outbox.NewOutboxRepository(*db), cfg.Outbox.Interval, logger, map[string]event.Publisher{
+		"log":     outbox.LogPublisher(logger),
+		"webhook": webhook.Publisher(webhookRepo),
+		"stream":  broker,
+	})
+	go relay.Run(ctx)
+	go hub.Run(ctx)
+
+	dispatcher := webhook.NewDispatcher(webhookRepo, cfg.Webhook.Interval, logger,
+		webhook.MaxAttempts(cfg.Webhook.MaxAttempts),
+		webhook.Backoff(cfg.Webhook.Backoff, cfg.Webhook.MaxBackoff),
+		webhook.HTTPClient(&http.Client{Timeout: cfg.Webhook.Timeout}),
+	)
+	go dispatcher.Run(ctx)
+
+	go seating.NewHoldSweeper(seatingRepo, cfg.Holds.SweepInterval, logger).Run(ctx)
+	go series.NewMaterializer(seriesRepo, cfg.Series.Horizon, cfg.Series.Interval, logger).Run(ctx)
+	go reservation.NewDepositExpirer(paymentRepo, reservationRepo, cfg.Payment.ExpiryInterval, logger).Run(ctx)
+	go payment.NewSettler(paymentRepo, paymentService, cfg.Payment.SettleInterval, logger).Run(ctx)
+
+	go db.ReportPoolStats(ctx, cfg.Metrics.Interval,
+		kitprometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: "reservations",
+			Subsystem: "db",
+			Name:      "connections",
+			Help:      "Database connections by state.",
+		}, []string{"state"}),
+		kitprometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: "reservations",
+			Subsystem: "db",
+			Name:      "connection_waits",
+			Help:      "Total number of times a database connection had to be waited for.",
+		}, []string{}),
+	)
+
+	go reservation.ReportStats(ctx, reservationRepo, venues, cfg.Metrics.Interval, logger,
+		kitprometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: "reservations",
+			Name:      "bookings_today",
+			Help:      "Reservations booked since midnight UTC.",
+		}, []string{"venue"}),
+		kitprometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: "reservations",
+			Name:      "booked_seats_today",
+			Help:      "Seats held by reservations booked since midnight UTC.",
+		}, []string{"venue"}),
+	)
 
 	errs := make(chan error)
 	go func() {
-		c := make(chan os.Signal)
+		c := make(chan os.Signal, 1)
 		signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
 		errs <- fmt.Errorf("%s", <-c)
 	}()
 
 	go func() {
-		logger.Log("transport", "HTTP", "addr", *httpAddr)
-		errs <- http.ListenAndServe(*httpAddr, r)
+		logger.Log("transport", "HTTP", "addr", cfg.HTTP.Addr)
+		errs <- http.ListenAndServe(cfg.HTTP.Addr, tenant.StripPathPrefix(r))
+	}()
+
+	go func() {
+		logger.Log("transport", "gRPC", "addr", cfg.GRPC.Addr)
+		ln, err := net.Listen("tcp", cfg.GRPC.Addr)
+		if err != nil {
+			errs <- err
+			return
+		}
+		srv := grpc.NewServer()
+		pb.RegisterCustomersServer(srv, customer.MakeGRPCServer(customerService, authorizer, logger, mw...))
+		pb.RegisterReservationsServer(srv, reservation.MakeGRPCServer(reservationService, authorizer, logger, mw...))
+		errs <- srv.Serve(ln)
 	}()
 
 	logger.Log("exit", <-errs)
 }
 
-func initCustomerHandler(router *mux.Router, db *storage.Persistence, logger log.Logger) *mux.Router {
+// initAuthenticator returns the authenticator of callers, or nil when
+// authentication is disabled.
+func initAuthenticator(cfg config.Auth, db *storage.Persistence) (*auth.Authenticator, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	var validator *auth.JWTValidator
+	if cfg.JWKSFile != "" {
+		keys, err := auth.LoadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		validator = auth.NewJWTValidator(keys, cfg.Issuer, cfg.Audience)
+	}
+
+	return auth.NewAuthenticator(validator, auth.NewAPIKeyRepository(*db)), nil
+}
+
+// initAuthorizer returns the authorizer checking callers against the role
+// policy, or one allowing everything when authentication is disabled.
+func initAuthorizer(cfg config.Auth, db *storage.Persistence) authz.Authorizer {
+	if !cfg.Enabled {
+		return authz.AllowAll()
+	}
+	return authz.NewAuthorizer(authz.NewRoleRepository(*db))
+}
+
+func initRoleService(db *storage.Persistence, logger log.Logger) authz.Service {
+	r := authz.NewRoleRepository(*db)
+	s := authz.NewRoleService(r)
+	return authz.LoggingMiddleware(logger)(s)
+}
+
+func initAuditService(db *storage.Persistence, logger log.Logger) audit.Service {
+	r := audit.NewAuditRepository(*db)
+	s := audit.NewAuditService(r)
+	return audit.LoggingMiddleware(logger)(s)
+}
+
+func initCustomerService(db *storage.Persistence, logger log.Logger) customer.Service {
 	r := customer.NewCustomerRepository(*db)
 	s := customer.NewCustomerService(r)
-	s = customer.LoggingMiddleware(logger)(s)
-	return customer.MakeHTTPHandler(router, s, logger)
+	s = customer.InstrumentingMiddleware(serviceMetrics("customer_service"))(s)
+	s = customer.TracingMiddleware()(s)
+	return customer.LoggingMiddleware(logger)(s)
+}
+
+func initReservationService(r reservation.Repository, duration time.Duration, terms reservation.Terms, notifier reservation.Notifier, logger log.Logger) reservation.Service {
+	s := reservation.NewReservationService(r, duration, terms)
+	s = reservation.NotifyingMiddleware(notifier)(s)
+	s = reservation.InstrumentingMiddleware(serviceMetrics("reservation_service"))(s)
+	s = reservation.TracingMiddleware()(s)
+	return reservation.LoggingMiddleware(logger)(s)
+}
+
+func initStreamService(db *storage.Persistence, broker *stream.Broker, logger log.Logger) stream.Service {
+	r := stream.NewStreamRepository(*db)
+	s := stream.NewStreamService(r, broker)
+	return stream.LoggingMiddleware(logger)(s)
+}
+
+func initSeatingService(r seating.Repository, cfg config.Seating, holds config.Holds, logger log.Logger) seating.Service {
+	s := seating.NewSeatingService(r, cfg.Duration, cfg.Soon, holds.TTL, holds.GuestLimit)
+	s = seating.TracingMiddleware()(s)
+	return seating.LoggingMiddleware(logger)(s)
+}
+
+func initFeeService(db *storage.Persistence, payments payment.Service, logger log.Logger) fee.Service {
+	r := fee.NewFeeRepository(*db)
+	s := fee.NewFeeService(r, payments)
+	s = fee.TracingMiddleware()(s)
+	return fee.LoggingMiddleware(logger)(s)
+}
+
+func initPaymentService(r payment.Repository, cfg config.Payment, logger log.Logger) payment.Service {
+	s := payment.NewPaymentService(r, payment.NewGateway(cfg.Gateway, cfg.StripeURL, cfg.StripeKey))
+	s = payment.TracingMiddleware()(s)
+	return payment.LoggingMiddleware(logger)(s)
+}
+
+func initSeriesService(r series.Repository, duration time.Duration, cfg config.Series, terms reservation.Terms, logger log.Logger) series.Service {
+	s := series.NewSeriesService(r, duration, cfg.Horizon, terms)
+	s = series.TracingMiddleware()(s)
+	return series.LoggingMiddleware(logger)(s)
+}
+
+func initWebhookHandler(router *mux.Router, r webhook.Repository, az authz.Authorizer, logger log.Logger, mw ...endpoint.Middleware) *mux.Router {
+	s := webhook.NewWebhookService(r)
+	s = webhook.LoggingMiddleware(logger)(s)
+	return webhook.MakeHTTPHandler(router, s, az, logger, mw...)
 }
 
-func initReservationHandler(router *mux.Router, db *storage.Persistence, logger log.Logger) *mux.Router {
-	r := reservation.NewReservationRepository(*db)
-	s := reservation.NewReservationService(r)
-	s = reservation.LoggingMiddleware(logger)(s)
-	return reservation.MakeHTTPHandler(router, s, logger)
+// serviceMetrics returns the request count, request latency and error count
+// metrics for the service exposed under subsystem.
+func serviceMetrics(subsystem string) (metrics.Counter, metrics.Histogram, metrics.Counter) {
+	fieldKeys := []string{"method", "error"}
+	requestCount := kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: "reservations",
+		Subsystem: subsystem,
+		Name:      "request_count",
+		Help:      "Number of requests received.",
+	}, fieldKeys)
+	requestLatency := kitprometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+		Namespace: "reservations",
+		Subsystem: subsystem,
+		Name:      "request_latency_seconds",
+		Help:      "Total duration of requests in seconds.",
+	}, fieldKeys)
+	errorCount := kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: "reservations",
+		Subsystem: subsystem,
+		Name:      "error_count",
+		Help:      "Number of failed requests by error type.",
+	}, []string{"method", "type"})
+	return requestCount, requestLatency, errorCount
 }
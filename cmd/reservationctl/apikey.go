@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"reservations/pkg/auth"
+	"reservations/pkg/storage"
+	"time"
+)
+
+var apiKeyHeader = []string{"ID", "NAME", "SUBJECT", "CREATED", "REVOKED"}
+
+func apiKeyRow(k auth.APIKey) []interface{} {
+	revoked := ""
+	if k.RevokedAt != 0 {
+		revoked = time.Unix(k.RevokedAt, 0).UTC().Format(time.RFC3339)
+	}
+	return []interface{}{k.KeyID, k.Name, k.Subject, time.Unix(k.Created, 0).UTC().Format(time.RFC3339), revoked}
+}
+
+func runAPIKey(ctx context.Context, svc *services, out *printer, command string, args []string) error {
+	if svc.db == nil {
+		return fmt.Errorf("API keys can only be managed against a database, not with -addr")
+	}
+	repo := auth.NewAPIKeyRepository(*svc.db)
+
+	fs := flag.NewFlagSet("apikey "+command, flag.ExitOnError)
+
+	switch command {
+	case "list":
+		limit := fs.Uint("limit", 0, "API key count limit")
+		offset := fs.Uint("offset", 0, "API key count offset")
+		fs.Parse(args)
+
+		kk, err := repo.FindAllAPIKeys(ctx, &storage.QueryOptions{Limit: *limit, Offset: *offset})
+		if err != nil {
+			return err
+		}
+		rows := make([][]interface{}, len(kk))
+		for i, k := range kk {
+			rows[i] = apiKeyRow(k)
+		}
+		return out.print(kk, apiKeyHeader, rows)
+
+	case "create":
+		subject := fs.String("subject", "", "Principal the key authenticates as")
+		name := fs.String("name", "", "Description of the key")
+		fs.Parse(args)
+
+		key, k, err := auth.CreateAPIKey(ctx, repo, *name, *subject)
+		if err != nil {
+			return err
+		}
+		if err := out.print(k, apiKeyHeader, [][]interface{}{apiKeyRow(*k)}); err != nil {
+			return err
+		}
+		fmt.Printf("\nAPI key (shown only once): %s\n", key)
+		return nil
+
+	case "revoke":
+		id, err := idArg(fs, args, "API key")
+		if err != nil {
+			return err
+		}
+		return repo.RevokeAPIKey(ctx, id)
+
+	default:
+		return fmt.Errorf("unknown apikey command %q", command)
+	}
+}
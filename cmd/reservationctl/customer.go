@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"reservations/pkg/customer"
+	"reservations/pkg/storage"
+	"strconv"
+)
+
+var customerHeader = []string{"ID", "FIRST NAME", "LAST NAME", "EMAIL", "PHONE"}
+
+func customerRow(c customer.Customer) []interface{} {
+	return []interface{}{c.CustomerID, c.FirstName, c.LastName, c.Email, c.Phone}
+}
+
+func runCustomer(ctx context.Context, svc *services, out *printer, command string, args []string) error {
+	fs := flag.NewFlagSet("customer "+command, flag.ExitOnError)
+
+	switch command {
+	case "list":
+		limit := fs.Uint("limit", 0, "Customer count limit")
+		offset := fs.Uint("offset", 0, "Customer count offset")
+		fs.Parse(args)
+
+		cc, err := svc.customers.GetAllCustomers(ctx, &storage.QueryOptions{Limit: *limit, Offset: *offset})
+		if err != nil {
+			return err
+		}
+		rows := make([][]interface{}, len(cc))
+		for i, c := range cc {
+			rows[i] = customerRow(c)
+		}
+		return out.print(cc, customerHeader, rows)
+
+	case "get":
+		id, err := idArg(fs, args, "customer")
+		if err != nil {
+			return err
+		}
+		c, err := svc.customers.GetCustomerByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return out.print(c, customerHeader, [][]interface{}{customerRow(c)})
+
+	case "create":
+		var c customer.Customer
+		fs.StringVar(&c.FirstName, "first", "", "First name")
+		fs.StringVar(&c.LastName, "last", "", "Last name")
+		fs.StringVar(&c.Email, "email", "", "Email address")
+		fs.StringVar(&c.Phone, "phone", "", "Phone number")
+		fs.Parse(args)
+
+		created, err := svc.customers.RegisterCustomer(ctx, &c)
+		if err != nil {
+			return err
+		}
+		return out.print(created, customerHeader, [][]interface{}{customerRow(*created)})
+
+	case "delete":
+		id, err := idArg(fs, args, "customer")
+		if err != nil {
+			return err
+		}
+		return svc.customers.UnregisterCustomer(ctx, id)
+
+	default:
+		return fmt.Errorf("unknown customer command %q", command)
+	}
+}
+
+// idArg parses the single positional ID argument of a command.
+func idArg(fs *flag.FlagSet, args []string, name string) (int, error) {
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return 0, fmt.Errorf("expected a single %s ID", name)
+	}
+	id, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s ID %q", name, fs.Arg(0))
+	}
+	return id, nil
+}
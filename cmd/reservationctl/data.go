@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/doug-martin/goqu/v7"
+	"io"
+	"os"
+	"reservations/pkg/customer"
+	"reservations/pkg/reservation"
+	"reservations/pkg/seating"
+	"reservations/pkg/storage"
+	"reservations/pkg/tenant"
+	"time"
+)
+
+const pageSize uint = 100
+
+// dump is the document written by export and read by import.
+type dump struct {
+	Customers    []customer.Customer       `json:"customers"`
+	Reservations []reservation.Reservation `json:"reservations"`
+}
+
+func runData(ctx context.Context, svc *services, command string, args []string) error {
+	fs := flag.NewFlagSet("data "+command, flag.ExitOnError)
+	file := fs.String("file", "-", "File to read from or write to, - for stdin/stdout")
+	fs.Parse(args)
+
+	switch command {
+	case "export":
+		w := io.Writer(os.Stdout)
+		if *file != "-" {
+			f, err := os.Create(*file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		return export(ctx, svc, w)
+
+	case "import":
+		r := io.Reader(os.Stdin)
+		if *file != "-" {
+			f, err := os.Open(*file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		return importDump(ctx, svc, r)
+
+	default:
+		return fmt.Errorf("unknown data command %q", command)
+	}
+}
+
+func export(ctx context.Context, svc *services, w io.Writer) error {
+	var d dump
+
+	// Pages end at the first empty one, as the server may return fewer
+	// records than asked for.
+	for offset := uint(0); ; {
+		cc, err := svc.customers.GetAllCustomers(ctx, &storage.QueryOptions{Limit: pageSize, Offset: offset})
+		if err != nil {
+			return err
+		}
+		if len(cc) == 0 {
+			break
+		}
+		d.Customers = append(d.Customers, cc...)
+		offset += uint(len(cc))
+	}
+
+	for _, c := range d.Customers {
+		for offset := uint(0); ; {
+			rr, err := svc.reservations.GetReservationHistoryPerCustomer(ctx, c.CustomerID, &storage.QueryOptions{Limit: pageSize, Offset: offset})
+			if err != nil {
+				return err
+			}
+			if len(rr) == 0 {
+				break
+			}
+			d.Reservations = append(d.Reservations, rr...)
+			offset += uint(len(rr))
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// importDump registers every customer and reservation of a dump. Records get
+// new IDs, and reservations are attached to the newly created customers.
+// Against the database, the dump is imported in a single transaction, and
+// reservations are seated without the booking policy or deposits, which
+// applied when they were first booked. Through the API, every record is
+// booked like a new one, and those imported before a failure remain.
+func importDump(ctx context.Context, svc *services, r io.Reader) error {
+	var d dump
+	if err := json.NewDecoder(r).Decode(&d); err != nil {
+		return fmt.Errorf("error reading dump: %v", err)
+	}
+
+	var err error
+	if svc.db != nil {
+		err = svc.db.WithTx(func(tx *goqu.TxDatabase) error {
+			return importRecords(d, svc.insertCustomer(ctx, tx), svc.insertReservation(ctx, tx))
+		})
+	} else {
+		err = importRecords(d, svc.registerCustomer(ctx), svc.bookReservation(ctx))
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "imported %d customers and %d reservations\n", len(d.Customers), len(d.Reservations))
+	return nil
+}
+
+func importRecords(d dump, addCustomer func(*customer.Customer) error, addReservation func(int, *reservation.Reservation) error) error {
+	ids := make(map[int]int, len(d.Customers))
+	for _, c := range d.Customers {
+		oldID := c.CustomerID
+		if err := addCustomer(&c); err != nil {
+			return fmt.Errorf("error importing customer %d: %v", oldID, err)
+		}
+		ids[oldID] = c.CustomerID
+	}
+
+	for _, res := range d.Reservations {
+		cID, ok := ids[res.CustomerID]
+		if !ok {
+			return fmt.Errorf("reservation %d refers to customer %d which is not part of the dump", res.ReservationID, res.CustomerID)
+		}
+		oldID := res.ReservationID
+		if err := addReservation(cID, &res); err != nil {
+			return fmt.Errorf("error importing reservation %d: %v", oldID, err)
+		}
+	}
+	return nil
+}
+
+func (svc *services) registerCustomer(ctx context.Context) func(*customer.Customer) error {
+	return func(c *customer.Customer) error {
+		created, err := svc.customers.RegisterCustomer(ctx, c)
+		if err == nil {
+			*c = *created
+		}
+		return err
+	}
+}
+
+func (svc *services) bookReservation(ctx context.Context) func(int, *reservation.Reservation) error {
+	return func(cID int, res *reservation.Reservation) error {
+		_, err := svc.reservations.BookReservation(ctx, cID, res)
+		return err
+	}
+}
+
+func (svc *services) insertCustomer(ctx context.Context, tx *goqu.TxDatabase) func(*customer.Customer) error {
+	return func(c *customer.Customer) error {
+		return customer.Insert(ctx, tx, c, time.Now().Unix())
+	}
+}
+
+func (svc *services) insertReservation(ctx context.Context, tx *goqu.TxDatabase) func(int, *reservation.Reservation) error {
+	return func(cID int, res *reservation.Reservation) error {
+		startsAt, err := seating.ParseStartTime(res.StartTime)
+		if err != nil {
+			return err
+		}
+		res.ReservationID = 0
+		res.StartsAt = startsAt
+		res.EndsAt = startsAt + int64(svc.duration/time.Second)
+		res.SeriesID = 0
+		res.Occurrence = ""
+		res.Deposit = 0
+		res.PayBy = 0
+		return reservation.Insert(ctx, tx, tenant.VenueFromContext(ctx), cID, res, time.Now().Unix())
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"reservations/pkg/auth"
+	"reservations/pkg/config"
+	"reservations/pkg/customer"
+	customerclient "reservations/pkg/customer/client"
+	"reservations/pkg/payment"
+	"reservations/pkg/request"
+	"reservations/pkg/reservation"
+	reservationclient "reservations/pkg/reservation/client"
+	"reservations/pkg/storage"
+	"reservations/pkg/tenant"
+	"time"
+)
+
+const usage = `reservationctl manages customers and reservations.
+
+Usage:
+  reservationctl [flags] <resource> <command> [arguments]
+
+Resources and commands:
+  customer list [-limit n] [-offset n]
+  customer get <id>
+  customer create -first <name> -last <name> -email <email> [-phone <phone>]
+  customer delete <id>
+
+  reservation list -customer <id> [-limit n] [-offset n]
+  reservation get <id>
+  reservation create -customer <id> -start <time> [-seats n] [-area <id>] [-name <name>] [-phone <phone>] [-comments <text>]
+  reservation edit <id> [-start <time>] [-seats n] [-area <id>] [-name <name>] [-phone <phone>] [-comments <text>]
+  reservation cancel <id>
+
+  data export [-file <path>]
+  data import [-file <path>]
+
+  migrate up
+  migrate down [-steps n]
+  migrate status
+
+  apikey list [-limit n] [-offset n]
+  apikey create -subject <subject> [-name <name>]
+  apikey revoke <id>
+
+  role list [-limit n] [-offset n]
+  role assign -role <guest|staff|manager> [-customer <id>] <subject>
+  role unassign <subject>
+
+  venue list [-limit n] [-offset n]
+  venue create -slug <slug> -name <name>
+
+  area list
+  area create -name <name>
+  area close <id> [-reason <text>]
+  area open <id>
+  area delete <id>
+
+  table list [-area <id>]
+  table create -area <id> -name <name> -capacity n [-x n] [-y n] [-width n] [-height n] [-shape square|round|rectangle] [-rotation deg]
+  table edit <id> [-area <id>] [-name <name>] [-capacity n] [-x n] [-y n] [-width n] [-height n] [-shape <shape>] [-rotation deg]
+  table delete <id>
+  table floor [-area <id>]
+  table state <id> seated|needs_cleaning|free [-reservation <id>]
+
+  combination list
+  combination create -name <name> -tables <id,id,...> [-capacity n]
+  combination delete <id>
+
+Flags:
+`
+
+// services are the backends the commands operate on, either a remote API or
+// the local database. db and duration, how long bookings hold their table,
+// are only set for the latter.
+type services struct {
+	customers    customer.Service
+	reservations reservation.Service
+	db           *storage.Persistence
+	duration     time.Duration
+}
+
+func main() {
+	fs := flag.NewFlagSet("reservationctl", flag.ExitOnError)
+	var (
+		addr      = fs.String("addr", "", "Address of the reservation API, e.g. http://localhost:8080")
+		cfgFile   = fs.String("config", os.Getenv(config.EnvPrefix+"CONFIG"), "Configuration file of the server, whose database, policy and payment settings are used when -addr is not set")
+		dbDialect = fs.String("db.dialect", storage.SQLite, "Database dialect used directly when -addr is not set: sqlite3 or postgres, overriding the configuration")
+		dbDSN     = fs.String("db.dsn", "reservations.db", "Database data source name used directly when -addr is not set, overriding the configuration")
+		output    = fs.String("o", "table", "Output format: table or json")
+		actor     = fs.String("actor", currentUser(), "Actor recorded in the audit log when using the database directly; the API records the authenticated principal")
+		token     = fs.String("token", os.Getenv("RESERVATIONS_TOKEN"), "Bearer token sent to the API")
+		apiKey    = fs.String("api-key", os.Getenv("RESERVATIONS_API_KEY"), "API key sent to the API")
+		venue     = fs.String("venue", os.Getenv("RESERVATIONS_VENUE"), "Slug of the venue to operate on, the default venue if empty")
+	)
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		fs.PrintDefaults()
+	}
+	fs.Parse(os.Args[1:])
+
+	if fs.NArg() < 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	out, err := newPrinter(*output, os.Stdout)
+	if err != nil {
+		fatal(err)
+	}
+
+	resource, command, args := fs.Arg(0), fs.Arg(1), fs.Args()[2:]
+
+	// The database flags given override the configuration like they do for
+	// the server.
+	cfgArgs := []string{"-config", *cfgFile}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "db.dialect":
+			cfgArgs = append(cfgArgs, "-db.dialect", *dbDialect)
+		case "db.dsn":
+			cfgArgs = append(cfgArgs, "-db.dsn", *dbDSN)
+		}
+	})
+
+	svc, err := connect(*addr, cfgArgs, resource != "migrate")
+	if err != nil {
+		fatal(err)
+	}
+
+	ctx := request.WithActor(context.Background(), *actor)
+	ctx = request.WithID(ctx, request.NewID())
+	if *token != "" {
+		ctx = auth.WithToken(ctx, *token)
+	}
+	if *apiKey != "" {
+		ctx = auth.WithAPIKey(ctx, *apiKey)
+	}
+	if *venue != "" && resource != "migrate" {
+		if ctx, err = selectVenue(ctx, svc, *venue); err != nil {
+			fatal(err)
+		}
+	}
+
+	switch resource {
+	case "customer", "customers":
+		err = runCustomer(ctx, svc, out, command, args)
+	case "reservation", "reservations":
+		err = runReservation(ctx, svc, out, command, args)
+	case "data":
+		err = runData(ctx, svc, command, args)
+	case "migrate":
+		err = runMigrate(svc, out, command, args)
+	case "apikey", "apikeys":
+		err = runAPIKey(ctx, svc, out, command, args)
+	case "role", "roles":
+		err = runRole(ctx, svc, out, command, args)
+	case "venue", "venues":
+		err = runVenue(ctx, svc, out, command, args)
+	case "area", "areas":
+		err = runArea(ctx, svc, out, command, args)
+	case "table", "tables":
+		err = runTable(ctx, svc, out, command, args)
+	case "combination", "combinations":
+		err = runCombination(ctx, svc, out, command, args)
+	default:
+		err = fmt.Errorf("unknown resource %q", resource)
+	}
+
+	if err != nil {
+		fatal(err)
+	}
+}
+
+// connect returns the API backed services when addr is set and database
+// backed ones otherwise, configured like the server by the configuration
+// loaded from cfgArgs. Pending migrations are applied first if migrate is
+// set.
+func connect(addr string, cfgArgs []string, migrate bool) (*services, error) {
+	if addr != "" {
+		cs, err := customerclient.New(addr)
+		if err != nil {
+			return nil, err
+		}
+		rs, err := reservationclient.New(addr)
+		if err != nil {
+			return nil, err
+		}
+		return &services{customers: cs, reservations: rs}, nil
+	}
+
+	cfg, err := config.Load("reservationctl", cfgArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := storage.NewDB(cfg.DB.Dialect, cfg.DB.DSN)
+	if err != nil {
+		return nil, err
+	}
+	db.DefaultLimit = cfg.DB.DefaultLimit
+	db.MaxLimit = cfg.DB.MaxLimit
+	db.BookingDuration = cfg.Seating.Duration
+	if migrate {
+		if _, err := db.Migrate(); err != nil {
+			return nil, err
+		}
+	}
+
+	cs := customer.NewCustomerService(customer.NewCustomerRepository(*db))
+
+	payments := payment.NewPaymentService(payment.NewPaymentRepository(*db), payment.NewGateway(cfg.Payment.Gateway, cfg.Payment.StripeURL, cfg.Payment.StripeKey))
+	terms := reservation.Terms{Policy: cfg.Policy, Payments: payments, DepositTTL: cfg.Payment.DepositTTL}
+	rs := reservation.NewReservationService(reservation.NewReservationRepository(*db), cfg.Seating.Duration, terms)
+
+	return &services{customers: cs, reservations: rs, db: db, duration: cfg.Seating.Duration}, nil
+}
+
+// selectVenue confines ctx to the venue named by slug, which the API
+// resolves itself.
+func selectVenue(ctx context.Context, svc *services, slug string) (context.Context, error) {
+	ctx = tenant.WithVenueSlug(ctx, slug)
+	if svc.db == nil {
+		return ctx, nil
+	}
+
+	v, err := tenant.NewVenueRepository(*svc.db).FindVenueBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return tenant.WithVenue(ctx, v.VenueID), nil
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return request.AnonymousActor
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "error:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"reservations/pkg/storage"
+	"time"
+)
+
+var migrationHeader = []string{"VERSION", "NAME", "APPLIED"}
+
+func runMigrate(svc *services, out *printer, command string, args []string) error {
+	if svc.db == nil {
+		return fmt.Errorf("migrations can only be run against a database, not with -addr")
+	}
+
+	fs := flag.NewFlagSet("migrate "+command, flag.ExitOnError)
+
+	switch command {
+	case "up":
+		fs.Parse(args)
+		mm, err := svc.db.Migrate()
+		printMigrations("applied", mm)
+		return err
+
+	case "down":
+		steps := fs.Int("steps", 1, "Number of migrations to roll back")
+		fs.Parse(args)
+		mm, err := svc.db.Rollback(*steps)
+		printMigrations("rolled back", mm)
+		return err
+
+	case "status":
+		fs.Parse(args)
+		ss, err := svc.db.MigrationStatus()
+		if err != nil {
+			return err
+		}
+		rows := make([][]interface{}, len(ss))
+		for i, s := range ss {
+			applied := "pending"
+			if s.AppliedAt != 0 {
+				applied = time.Unix(s.AppliedAt, 0).UTC().Format(time.RFC3339)
+			}
+			rows[i] = []interface{}{s.Version, s.Name, applied}
+		}
+		return out.print(ss, migrationHeader, rows)
+
+	default:
+		return fmt.Errorf("unknown migrate command %q", command)
+	}
+}
+
+func printMigrations(verb string, mm []storage.Migration) {
+	if len(mm) == 0 {
+		fmt.Println("nothing to do")
+	}
+	for _, m := range mm {
+		fmt.Printf("%s %04d_%s\n", verb, m.Version, m.Name)
+	}
+}
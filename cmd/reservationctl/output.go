@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// printer writes command results either as an aligned table or as JSON.
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+func newPrinter(format string, w io.Writer) (*printer, error) {
+	switch format {
+	case "table":
+		return &printer{w: w}, nil
+	case "json":
+		return &printer{w: w, json: true}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// print writes v as JSON, or the given header and rows as a table.
+func (p *printer) print(v interface{}, header []string, rows [][]interface{}) error {
+	if p.json {
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			cells[i] = fmt.Sprint(c)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
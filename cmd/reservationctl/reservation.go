@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"reservations/pkg/reservation"
+	"reservations/pkg/storage"
+)
+
+var reservationHeader = []string{"ID", "CUSTOMER", "START", "SEATS", "STATUS", "AREA", "TABLE", "NAME", "PHONE", "COMMENTS"}
+
+func reservationRow(r reservation.Reservation) []interface{} {
+	table := fmt.Sprint(r.TableID)
+	if r.CombinationID != 0 {
+		table = fmt.Sprintf("combination %d", r.CombinationID)
+	}
+	if r.NeedsReassignment {
+		table += " (reassign)"
+	}
+	return []interface{}{r.ReservationID, r.CustomerID, r.StartTime, r.SeatCount, r.Status, r.AreaID, table, r.ReservationName, r.Phone, r.Comments}
+}
+
+func reservationFlags(fs *flag.FlagSet, r *reservation.Reservation) {
+	fs.StringVar(&r.StartTime, "start", r.StartTime, "Start time in RFC 3339 format, e.g. 2006-01-02T19:30:00+01:00")
+	fs.IntVar(&r.SeatCount, "seats", r.SeatCount, "Seat count")
+	fs.IntVar(&r.AreaID, "area", r.AreaID, "Seating area ID, any area if 0")
+	fs.StringVar(&r.ReservationName, "name", r.ReservationName, "Name the reservation is made under")
+	fs.StringVar(&r.Phone, "phone", r.Phone, "Phone number")
+	fs.StringVar(&r.Comments, "comments", r.Comments, "Comments")
+	fs.StringVar(&r.HoldToken, "hold", r.HoldToken, "Token of a hold to redeem")
+}
+
+func runReservation(ctx context.Context, svc *services, out *printer, command string, args []string) error {
+	fs := flag.NewFlagSet("reservation "+command, flag.ExitOnError)
+
+	switch command {
+	case "list":
+		cID := fs.Int("customer", 0, "Customer ID")
+		limit := fs.Uint("limit", 0, "Reservation count limit")
+		offset := fs.Uint("offset", 0, "Reservation count offset")
+		fs.Parse(args)
+		if *cID == 0 {
+			return fmt.Errorf("-customer is required")
+		}
+
+		rr, err := svc.reservations.GetReservationHistoryPerCustomer(ctx, *cID, &storage.QueryOptions{Limit: *limit, Offset: *offset})
+		if err != nil {
+			return err
+		}
+		rows := make([][]interface{}, len(rr))
+		for i, r := range rr {
+			rows[i] = reservationRow(r)
+		}
+		return out.print(rr, reservationHeader, rows)
+
+	case "get":
+		id, err := idArg(fs, args, "reservation")
+		if err != nil {
+			return err
+		}
+		r, err := svc.reservations.GetReservationByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return out.print(r, reservationHeader, [][]interface{}{reservationRow(r)})
+
+	case "create":
+		r := reservation.Reservation{SeatCount: 1}
+		cID := fs.Int("customer", 0, "Customer ID")
+		reservationFlags(fs, &r)
+		fs.Parse(args)
+		if *cID == 0 {
+			return fmt.Errorf("-customer is required")
+		}
+
+		created, err := svc.reservations.BookReservation(ctx, *cID, &r)
+		if err != nil {
+			return err
+		}
+		return out.print(created, reservationHeader, [][]interface{}{reservationRow(*created)})
+
+	case "edit":
+		if len(args) == 0 {
+			return fmt.Errorf("expected a reservation ID")
+		}
+		id, err := idArg(flag.NewFlagSet("", flag.ContinueOnError), args[:1], "reservation")
+		if err != nil {
+			return err
+		}
+		r, err := svc.reservations.GetReservationByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		reservationFlags(fs, &r)
+		fs.Parse(args[1:])
+
+		edited, err := svc.reservations.EditReservation(ctx, id, &r)
+		if err != nil {
+			return err
+		}
+		return out.print(edited, reservationHeader, [][]interface{}{reservationRow(edited)})
+
+	case "cancel":
+		id, err := idArg(fs, args, "reservation")
+		if err != nil {
+			return err
+		}
+		return svc.reservations.DiscardReservation(ctx, id)
+
+	default:
+		return fmt.Errorf("unknown reservation command %q", command)
+	}
+}
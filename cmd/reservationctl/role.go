@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"reservations/pkg/authz"
+	"reservations/pkg/storage"
+	"time"
+)
+
+var roleHeader = []string{"SUBJECT", "ROLE", "CUSTOMER", "UPDATED"}
+
+func roleRow(a authz.Assignment) []interface{} {
+	customer := ""
+	if a.CustomerID != 0 {
+		customer = fmt.Sprint(a.CustomerID)
+	}
+	return []interface{}{a.Subject, a.Role, customer, time.Unix(a.LastUpdated, 0).UTC().Format(time.RFC3339)}
+}
+
+func runRole(ctx context.Context, svc *services, out *printer, command string, args []string) error {
+	if svc.db == nil {
+		return fmt.Errorf("roles can only be managed against a database, not with -addr")
+	}
+	s := authz.NewRoleService(authz.NewRoleRepository(*svc.db))
+
+	fs := flag.NewFlagSet("role "+command, flag.ExitOnError)
+
+	switch command {
+	case "list":
+		limit := fs.Uint("limit", 0, "Assignment count limit")
+		offset := fs.Uint("offset", 0, "Assignment count offset")
+		fs.Parse(args)
+
+		aa, err := s.GetAllAssignments(ctx, &storage.QueryOptions{Limit: *limit, Offset: *offset})
+		if err != nil {
+			return err
+		}
+		rows := make([][]interface{}, len(aa))
+		for i, a := range aa {
+			rows[i] = roleRow(a)
+		}
+		return out.print(aa, roleHeader, rows)
+
+	case "assign":
+		role := fs.String("role", "", "Role to assign: guest, staff or manager")
+		customerID := fs.Int("customer", 0, "Customer record of a guest")
+		subject, err := subjectArg(fs, args)
+		if err != nil {
+			return err
+		}
+
+		a, err := s.AssignRole(ctx, &authz.Assignment{
+			Subject:    subject,
+			Role:       authz.Role(*role),
+			CustomerID: *customerID,
+		})
+		if err != nil {
+			return err
+		}
+		return out.print(a, roleHeader, [][]interface{}{roleRow(*a)})
+
+	case "unassign":
+		subject, err := subjectArg(fs, args)
+		if err != nil {
+			return err
+		}
+		return s.UnassignRole(ctx, subject)
+
+	default:
+		return fmt.Errorf("unknown role command %q", command)
+	}
+}
+
+func subjectArg(fs *flag.FlagSet, args []string) (string, error) {
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return "", fmt.Errorf("expected a single subject")
+	}
+	return fs.Arg(0), nil
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"reservations/pkg/config"
+	"reservations/pkg/seating"
+	"strconv"
+	"strings"
+)
+
+var areaHeader = []string{"ID", "NAME", "CLOSED", "REASON"}
+
+func areaRow(a seating.Area) []interface{} {
+	return []interface{}{a.AreaID, a.Name, a.Closed, a.ClosedReason}
+}
+
+var tableHeader = []string{"ID", "AREA", "NAME", "CAPACITY", "SHAPE", "X", "Y", "WIDTH", "HEIGHT", "ROTATION"}
+
+func tableRow(t seating.Table) []interface{} {
+	return []interface{}{t.TableID, t.AreaID, t.Name, t.Capacity, t.Shape, t.X, t.Y, t.Width, t.Height, t.Rotation}
+}
+
+var floorHeader = []string{"ID", "AREA", "NAME", "CAPACITY", "STATE", "RESERVATION"}
+
+func floorRow(t seating.FloorTable) []interface{} {
+	return []interface{}{t.TableID, t.AreaID, t.Name, t.Capacity, t.State, t.ReservationID}
+}
+
+func tableFlags(fs *flag.FlagSet, t *seating.Table) {
+	fs.IntVar(&t.AreaID, "area", t.AreaID, "Area ID")
+	fs.StringVar(&t.Name, "name", t.Name, "Table name")
+	fs.IntVar(&t.Capacity, "capacity", t.Capacity, "Seat count")
+	fs.Float64Var(&t.X, "x", t.X, "Horizontal position of the center on the floor plan")
+	fs.Float64Var(&t.Y, "y", t.Y, "Vertical position of the center on the floor plan")
+	fs.Float64Var(&t.Width, "width", t.Width, "Width on the floor plan")
+	fs.Float64Var(&t.Height, "height", t.Height, "Height on the floor plan")
+	shape := (*string)(&t.Shape)
+	fs.StringVar(shape, "shape", *shape, "Shape: square, round or rectangle")
+	fs.Float64Var(&t.Rotation, "rotation", t.Rotation, "Clockwise rotation in degrees")
+}
+
+var combinationHeader = []string{"ID", "NAME", "CAPACITY", "TABLES"}
+
+func combinationRow(c seating.Combination) []interface{} {
+	return []interface{}{c.CombinationID, c.Name, c.Capacity, c.TableIDs}
+}
+
+func seatingService(svc *services, resource string) (seating.Service, error) {
+	if svc.db == nil {
+		return nil, fmt.Errorf("%ss can only be managed against a database, not with -addr", resource)
+	}
+	cfg := config.Default()
+	return seating.NewSeatingService(seating.NewSeatingRepository(*svc.db), cfg.Seating.Duration, cfg.Seating.Soon, cfg.Holds.TTL, cfg.Holds.GuestLimit), nil
+}
+
+func runArea(ctx context.Context, svc *services, out *printer, command string, args []string) error {
+	s, err := seatingService(svc, "area")
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("area "+command, flag.ExitOnError)
+
+	switch command {
+	case "list":
+		fs.Parse(args)
+		aa, err := s.GetAllAreas(ctx)
+		if err != nil {
+			return err
+		}
+		rows := make([][]interface{}, len(aa))
+		for i, a := range aa {
+			rows[i] = areaRow(a)
+		}
+		return out.print(aa, areaHeader, rows)
+
+	case "create":
+		name := fs.String("name", "", "Area name")
+		fs.Parse(args)
+
+		a, err := s.CreateArea(ctx, &seating.Area{Name: *name})
+		if err != nil {
+			return err
+		}
+		return out.print(a, areaHeader, [][]interface{}{areaRow(*a)})
+
+	case "close":
+		reason := fs.String("reason", "", "Reason for closing")
+		if len(args) == 0 {
+			return fmt.Errorf("expected an area ID")
+		}
+		id, err := idArg(flag.NewFlagSet("", flag.ContinueOnError), args[:1], "area")
+		if err != nil {
+			return err
+		}
+		fs.Parse(args[1:])
+
+		c, err := s.CloseArea(ctx, id, *reason)
+		if err != nil {
+			return err
+		}
+		return out.print(c, []string{"ID", "NAME", "FLAGGED"}, [][]interface{}{{c.Area.AreaID, c.Area.Name, c.Flagged}})
+
+	case "open":
+		id, err := idArg(fs, args, "area")
+		if err != nil {
+			return err
+		}
+		a, err := s.OpenArea(ctx, id)
+		if err != nil {
+			return err
+		}
+		return out.print(a, areaHeader, [][]interface{}{areaRow(a)})
+
+	case "delete":
+		id, err := idArg(fs, args, "area")
+		if err != nil {
+			return err
+		}
+		return s.RemoveArea(ctx, id)
+
+	default:
+		return fmt.Errorf("unknown area command %q", command)
+	}
+}
+
+func runTable(ctx context.Context, svc *services, out *printer, command string, args []string) error {
+	s, err := seatingService(svc, "table")
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("table "+command, flag.ExitOnError)
+
+	switch command {
+	case "list":
+		aID := fs.Int("area", 0, "Area ID, all areas if 0")
+		fs.Parse(args)
+
+		tt, err := s.GetTables(ctx, *aID)
+		if err != nil {
+			return err
+		}
+		rows := make([][]interface{}, len(tt))
+		for i, t := range tt {
+			rows[i] = tableRow(t)
+		}
+		return out.print(tt, tableHeader, rows)
+
+	case "create":
+		var t seating.Table
+		tableFlags(fs, &t)
+		fs.Parse(args)
+
+		created, err := s.CreateTable(ctx, &t)
+		if err != nil {
+			return err
+		}
+		return out.print(created, tableHeader, [][]interface{}{tableRow(*created)})
+
+	case "edit":
+		if len(args) == 0 {
+			return fmt.Errorf("expected a table ID")
+		}
+		id, err := idArg(flag.NewFlagSet("", flag.ContinueOnError), args[:1], "table")
+		if err != nil {
+			return err
+		}
+		tt, err := s.GetTables(ctx, 0)
+		if err != nil {
+			return err
+		}
+		var t *seating.Table
+		for i := range tt {
+			if tt[i].TableID == id {
+				t = &tt[i]
+			}
+		}
+		if t == nil {
+			return fmt.Errorf("table with ID %d not found", id)
+		}
+		tableFlags(fs, t)
+		fs.Parse(args[1:])
+
+		updated, err := s.UpdateTable(ctx, id, t)
+		if err != nil {
+			return err
+		}
+		return out.print(updated, tableHeader, [][]interface{}{tableRow(updated)})
+
+	case "floor":
+		aID := fs.Int("area", 0, "Area ID, all areas if 0")
+		fs.Parse(args)
+
+		f, err := s.GetFloor(ctx, *aID)
+		if err != nil {
+			return err
+		}
+		rows := make([][]interface{}, len(f.Tables))
+		for i, t := range f.Tables {
+			rows[i] = floorRow(t)
+		}
+		return out.print(f, floorHeader, rows)
+
+	case "state":
+		rID := fs.Int("reservation", 0, "ID of the reservation seated, for state seated")
+		if len(args) < 2 {
+			return fmt.Errorf("expected a table ID and a state")
+		}
+		id, err := idArg(flag.NewFlagSet("", flag.ContinueOnError), args[:1], "table")
+		if err != nil {
+			return err
+		}
+		fs.Parse(args[2:])
+
+		t, err := s.SetTableState(ctx, id, seating.TableState(args[1]), *rID)
+		if err != nil {
+			return err
+		}
+		return out.print(t, floorHeader, [][]interface{}{floorRow(t)})
+
+	case "delete":
+		id, err := idArg(fs, args, "table")
+		if err != nil {
+			return err
+		}
+		return s.RemoveTable(ctx, id)
+
+	default:
+		return fmt.Errorf("unknown table command %q", command)
+	}
+}
+
+func runCombination(ctx context.Context, svc *services, out *printer, command string, args []string) error {
+	s, err := seatingService(svc, "combination")
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("combination "+command, flag.ExitOnError)
+
+	switch command {
+	case "list":
+		fs.Parse(args)
+		cc, err := s.GetAllCombinations(ctx)
+		if err != nil {
+			return err
+		}
+		rows := make([][]interface{}, len(cc))
+		for i, c := range cc {
+			rows[i] = combinationRow(c)
+		}
+		return out.print(cc, combinationHeader, rows)
+
+	case "create":
+		var c seating.Combination
+		fs.StringVar(&c.Name, "name", "", "Combination name")
+		fs.IntVar(&c.Capacity, "capacity", 0, "Seat count of the combined tables, their summed capacities if 0")
+		tables := fs.String("tables", "", "Comma separated IDs of the tables combined")
+		fs.Parse(args)
+
+		for _, f := range strings.Split(*tables, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(f))
+			if err != nil {
+				return fmt.Errorf("invalid table ID %q", f)
+			}
+			c.TableIDs = append(c.TableIDs, id)
+		}
+
+		created, err := s.CreateCombination(ctx, &c)
+		if err != nil {
+			return err
+		}
+		return out.print(created, combinationHeader, [][]interface{}{combinationRow(*created)})
+
+	case "delete":
+		id, err := idArg(fs, args, "combination")
+		if err != nil {
+			return err
+		}
+		return s.RemoveCombination(ctx, id)
+
+	default:
+		return fmt.Errorf("unknown combination command %q", command)
+	}
+}
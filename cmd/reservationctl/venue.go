@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"reservations/pkg/storage"
+	"reservations/pkg/tenant"
+	"time"
+)
+
+var venueHeader = []string{"ID", "SLUG", "NAME", "CREATED"}
+
+func venueRow(v tenant.Venue) []interface{} {
+	return []interface{}{v.VenueID, v.Slug, v.Name, time.Unix(v.Created, 0).UTC().Format(time.RFC3339)}
+}
+
+func runVenue(ctx context.Context, svc *services, out *printer, command string, args []string) error {
+	if svc.db == nil {
+		return fmt.Errorf("venues can only be managed against a database, not with -addr")
+	}
+	repo := tenant.NewVenueRepository(*svc.db)
+
+	fs := flag.NewFlagSet("venue "+command, flag.ExitOnError)
+
+	switch command {
+	case "list":
+		limit := fs.Uint("limit", 0, "Venue count limit")
+		offset := fs.Uint("offset", 0, "Venue count offset")
+		fs.Parse(args)
+
+		vv, err := repo.FindAllVenues(ctx, &storage.QueryOptions{Limit: *limit, Offset: *offset})
+		if err != nil {
+			return err
+		}
+		rows := make([][]interface{}, len(vv))
+		for i, v := range vv {
+			rows[i] = venueRow(v)
+		}
+		return out.print(vv, venueHeader, rows)
+
+	case "create":
+		slug := fs.String("slug", "", "Short name identifying the venue in requests")
+		name := fs.String("name", "", "Name of the venue")
+		fs.Parse(args)
+
+		v, err := tenant.CreateVenue(ctx, repo, *slug, *name)
+		if err != nil {
+			return err
+		}
+		return out.print(v, venueHeader, [][]interface{}{venueRow(*v)})
+
+	default:
+		return fmt.Errorf("unknown venue command %q", command)
+	}
+}
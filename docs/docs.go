@@ -1,6 +1,6 @@
 // GENERATED BY THE COMMAND ABOVE; DO NOT EDIT
 // This file was generated by swaggo/swag at
-// 2019-08-14 18:05:36.613005 +0300 EEST m=+0.065788785
+// 2026-10-19 12:35:20.928814556 +0000 UTC m=+0.079531709
 
 package docs
 
@@ -32,9 +32,9 @@ var doc = `{
     "host": "localhost:8080",
     "basePath": "/",
     "paths": {
-        "/customer": {
+        "/area": {
             "post": {
-                "description": "Register a new Customer",
+                "description": "Create a seating area such as a terrace, to which tables are then added",
                 "consumes": [
                     "application/json"
                 ],
@@ -42,18 +42,18 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "customer"
+                    "seating"
                 ],
-                "summary": "Register a new Customer",
+                "summary": "Create a seating area",
                 "parameters": [
                     {
-                        "description": "New Customer",
-                        "name": "customer",
+                        "description": "New Area",
+                        "name": "area",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "type": "object",
-                            "$ref": "#/definitions/customer.Customer"
+                            "$ref": "#/definitions/seating.Area"
                         }
                     }
                 ],
@@ -62,15 +62,15 @@ var doc = `{
                         "description": "OK",
                         "schema": {
                             "type": "object",
-                            "$ref": "#/definitions/customer.Customer"
+                            "$ref": "#/definitions/seating.Area"
                         }
                     }
                 }
             }
         },
-        "/customer/{id}": {
-            "get": {
-                "description": "Get an existing customer",
+        "/area/{id}": {
+            "delete": {
+                "description": "Remove a seating area, which must have no tables left",
                 "consumes": [
                     "application/json"
                 ],
@@ -78,21 +78,23 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "customer"
+                    "seating"
                 ],
-                "summary": "Get an existing customer",
+                "summary": "Remove a seating area",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Customer ID",
+                        "description": "Area ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ]
-            },
-            "delete": {
-                "description": "Unregister an existing customer",
+            }
+        },
+        "/area/{id}/close": {
+            "post": {
+                "description": "Close a seating area, e.g. the terrace in rain. No tables are allocated in it until it is opened again and upcoming reservations seated in it are flagged for reassignment.",
                 "consumes": [
                     "application/json"
                 ],
@@ -100,23 +102,71 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "customer"
+                    "seating"
                 ],
-                "summary": "Unregister an existing customer",
+                "summary": "Close a seating area",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Customer ID",
+                        "description": "Area ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason for closing",
+                        "name": "reason",
+                        "in": "query"
                     }
-                ]
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/seating.Closure"
+                        }
+                    }
+                }
             }
         },
-        "/customer/{id}/reservations": {
+        "/area/{id}/open": {
+            "post": {
+                "description": "Open a closed seating area again, clearing the reassignment flag of the reservations seated in it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "Open a closed seating area",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Area ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/seating.Area"
+                        }
+                    }
+                }
+            }
+        },
+        "/areas": {
             "get": {
-                "description": "List existing reservations per customer ordered by newest.",
+                "description": "List the seating areas of the venue",
                 "consumes": [
                     "application/json"
                 ],
@@ -124,30 +174,91 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reservation"
+                    "seating"
                 ],
-                "summary": "List existing reservations per customer ordered by newest.",
+                "summary": "List seating areas",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/seating.Area"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/audit": {
+            "get": {
+                "description": "List changes made to customers and reservations ordered by newest",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "List audit log entries ordered by newest",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Actor who made the change",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action (create, update, delete)",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entity type (customer, reservation)",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Entity ID",
+                        "name": "entityId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request ID",
+                        "name": "requestId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Earliest change as Unix timestamp",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Latest change as Unix timestamp",
+                        "name": "to",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "default": 100,
-                        "description": "Reservation count limit",
+                        "description": "Entry count limit",
                         "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "integer",
                         "default": 0,
-                        "description": "Reservation count offset",
+                        "description": "Entry count offset",
                         "name": "offset",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Customer ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -156,16 +267,16 @@ var doc = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/reservation.Reservation"
+                                "$ref": "#/definitions/audit.Entry"
                             }
                         }
                     }
                 }
             }
         },
-        "/customers": {
+        "/availability": {
             "get": {
-                "description": "List existing customers",
+                "description": "List the tables free for a party at a given time, smallest first, optionally in one area, along with the free table combinations seating the party",
                 "consumes": [
                     "application/json"
                 ],
@@ -173,41 +284,131 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "customer"
+                    "seating"
                 ],
-                "summary": "List existing customers",
+                "summary": "Search free tables",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start time (RFC 3339)",
+                        "name": "start",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "integer",
-                        "default": 100,
-                        "description": "Customer count limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Party size",
+                        "name": "seats",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "integer",
-                        "default": 0,
-                        "description": "Customer count offset",
-                        "name": "offset",
+                        "description": "Area ID",
+                        "name": "area",
                         "in": "query"
                     }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/seating.Availability"
+                        }
+                    }
+                }
+            }
+        },
+        "/combination": {
+            "post": {
+                "description": "Define tables which may be pushed together for large parties. The capacity defaults to the sum of the tables' capacities.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "Define a table combination",
+                "parameters": [
+                    {
+                        "description": "New Combination",
+                        "name": "combination",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/seating.Combination"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/seating.Combination"
+                        }
+                    }
+                }
+            }
+        },
+        "/combination/{id}": {
+            "delete": {
+                "description": "Remove a table combination, flagging the reservations holding it for reassignment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "Remove a table combination",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Combination ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ]
+            }
+        },
+        "/combinations": {
+            "get": {
+                "description": "List the table combinations of the venue",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "List table combinations",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/customer.Customer"
+                                "$ref": "#/definitions/seating.Combination"
                             }
                         }
                     }
                 }
             }
         },
-        "/reservation": {
+        "/customer": {
             "post": {
-                "description": "Book a new Reservation",
+                "description": "Register a new Customer",
                 "consumes": [
                     "application/json"
                 ],
@@ -215,18 +416,18 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reservation"
+                    "customer"
                 ],
-                "summary": "Book a new Reservation",
+                "summary": "Register a new Customer",
                 "parameters": [
                     {
-                        "description": "New Reservation",
-                        "name": "reservation",
+                        "description": "New Customer",
+                        "name": "customer",
                         "in": "body",
                         "required": true,
                         "schema": {
                             "type": "object",
-                            "$ref": "#/definitions/reservation.Reservation"
+                            "$ref": "#/definitions/customer.Customer"
                         }
                     }
                 ],
@@ -235,15 +436,15 @@ var doc = `{
                         "description": "OK",
                         "schema": {
                             "type": "object",
-                            "$ref": "#/definitions/reservation.Reservation"
+                            "$ref": "#/definitions/customer.Customer"
                         }
                     }
                 }
             }
         },
-        "/reservation/{id}": {
-            "put": {
-                "description": "Edit an existing reservation",
+        "/customer/{id}": {
+            "get": {
+                "description": "Get an existing customer",
                 "consumes": [
                     "application/json"
                 ],
@@ -251,13 +452,13 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reservation"
+                    "customer"
                 ],
-                "summary": "Edit an existing reservation",
+                "summary": "Get an existing customer",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Reservation ID",
+                        "description": "Customer ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -265,7 +466,7 @@ var doc = `{
                 ]
             },
             "delete": {
-                "description": "Discard an existing reservation",
+                "description": "Unregister an existing customer. Customers with reservations, series, fees or deposits cannot be unregistered.",
                 "consumes": [
                     "application/json"
                 ],
@@ -273,77 +474,2190 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reservation"
+                    "customer"
                 ],
-                "summary": "Discard an existing reservation",
+                "summary": "Unregister an existing customer",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Reservation ID",
+                        "description": "Customer ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ]
             }
-        }
-    },
-    "definitions": {
-        "customer.Customer": {
-            "type": "object",
-            "properties": {
-                "created": {
-                    "type": "integer"
-                },
-                "customerId": {
-                    "type": "integer"
-                },
+        },
+        "/customer/{id}/fees": {
+            "get": {
+                "description": "List the fees charged to a customer for cancelling or modifying reservations late, waived ones included, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fee"
+                ],
+                "summary": "List the fees charged to a customer, newest first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Fee count limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Fee count offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/fee.Fee"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/customer/{id}/reservations": {
+            "get": {
+                "description": "List existing reservations per customer ordered by newest.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservation"
+                ],
+                "summary": "List existing reservations per customer ordered by newest.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Reservation count limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Reservation count offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/reservation.Reservation"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/customer/{id}/series": {
+            "post": {
+                "description": "Book a reservation recurring daily, weekly or monthly, until a date, for a number of occurrences or until cancelled. The occurrences are booked as individual reservations some time ahead; those no table is free for are flagged for reassignment. The series is checked against the booking policy like its first occurrence; later occurrences are not held to the lead time and advance window, and those breaking another rule are cancelled, listed among the exceptions. Every occurrence requires its own deposit where the deposit terms apply.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Book a recurring reservation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New Series",
+                        "name": "series",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/series.Series"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/series.Series"
+                        }
+                    }
+                }
+            }
+        },
+        "/customers": {
+            "get": {
+                "description": "List existing customers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customer"
+                ],
+                "summary": "List existing customers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Customer count limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Customer count offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/customer.Customer"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/events/stream": {
+            "get": {
+                "description": "Push reservation and table state events as Server-Sent Events while they are committed. Each event carries its sequence number, in the order the events were committed, as its id, which a client reconnecting sends back in the Last-Event-ID header to receive the events it missed.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "stream"
+                ],
+                "summary": "Stream reservation and table changes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sequence number of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sequence number of the last event received, for clients unable to set headers",
+                        "name": "lastEventId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/event.Event"
+                        }
+                    }
+                }
+            }
+        },
+        "/fee/{id}": {
+            "get": {
+                "description": "Get a fee charged for cancelling or modifying a reservation late",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fee"
+                ],
+                "summary": "Get a fee",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Fee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/fee.Fee"
+                        }
+                    }
+                }
+            }
+        },
+        "/fee/{id}/waive": {
+            "post": {
+                "description": "Let the customer off a fee, recording who waived it and why in the fee and the audit log. What a deposit kept in payment of the fee is refunded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fee"
+                ],
+                "summary": "Waive a fee",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Fee ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the waiver",
+                        "name": "waiver",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/fee.waiveFeeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/fee.Fee"
+                        }
+                    }
+                }
+            }
+        },
+        "/floor": {
+            "get": {
+                "description": "Show the areas and tables of the venue, or of one of its areas, with the live state of each table: free, reserved for a booking starting soon, seated or needing cleaning",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "Show the floor plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Area ID",
+                        "name": "area",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/seating.Floor"
+                        }
+                    }
+                }
+            }
+        },
+        "/holds": {
+            "post": {
+                "description": "Keep a table or combination for a party at a given time while the guest completes the booking. The hold expires after a while unless redeemed by booking with its token. Guests may only keep a few holds at once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "Hold a table during checkout",
+                "parameters": [
+                    {
+                        "description": "Party and start time to hold",
+                        "name": "hold",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/seating.Hold"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/seating.Hold"
+                        }
+                    }
+                }
+            }
+        },
+        "/holds/{token}": {
+            "delete": {
+                "description": "Release a hold before it expires, for instance when the guest abandons the checkout",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "Release a hold",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hold token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ]
+            }
+        },
+        "/hoststand": {
+            "get": {
+                "description": "Open a WebSocket relaying reservation changes, the hosts present and their edit locks to every host of the venue. Clients send {\"type\":\"lock\",\"reservationId\":n} to take or refresh a lock, which expires unless refreshed, and {\"type\":\"unlock\",\"reservationId\":n} to release it. Changes to a reservation release its lock.",
+                "tags": [
+                    "hoststand"
+                ],
+                "summary": "Join the host stand channel",
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/hoststand.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservation": {
+            "post": {
+                "description": "Book a new Reservation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservation"
+                ],
+                "summary": "Book a new Reservation",
+                "parameters": [
+                    {
+                        "description": "New Reservation",
+                        "name": "reservation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/reservation.Reservation"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/reservation.Reservation"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservation/{id}": {
+            "get": {
+                "description": "Get an existing reservation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservation"
+                ],
+                "summary": "Get an existing reservation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/reservation.Reservation"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Edit an existing reservation. An edit requiring a deposit, or a larger one, raises the deposit while unpaid, and is refused once a smaller one is paid.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservation"
+                ],
+                "summary": "Edit an existing reservation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Discard an existing reservation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservation"
+                ],
+                "summary": "Discard an existing reservation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ]
+            }
+        },
+        "/reservation/{id}/deposit": {
+            "get": {
+                "description": "Get the deposit required to hold a reservation, with how much of it was paid, captured and refunded",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Get the deposit of a reservation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/payment.Deposit"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Authorize the deposit of a reservation on a payment method before it is due, after which the reservation is cancelled",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Pay the deposit of a reservation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment method",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/payment.payDepositRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/payment.Deposit"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservation/{id}/deposit/settle": {
+            "post": {
+                "description": "Capture part of the deposit of a reservation, such as for a no-show, releasing or refunding the rest. A deposit captured already is only refunded further. The settlement is stored before the payment gateway is called; should the gateway fail, the deposit stays settling and the settlement is retried.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Settle the deposit of a reservation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Amount to keep",
+                        "name": "settlement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/payment.settleDepositRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/payment.Deposit"
+                        }
+                    }
+                }
+            }
+        },
+        "/role/{subject}": {
+            "get": {
+                "description": "Get the role assignment of a subject",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "Get the role assignment of a subject",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subject",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/authz.Assignment"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Assign a role to a subject, replacing the one it had",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "Assign a role to a subject",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subject",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role Assignment",
+                        "name": "assignment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/authz.Assignment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/authz.Assignment"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove the role assignment of a subject, who falls back to the role claim of their token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "Remove the role assignment of a subject",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subject",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    }
+                ]
+            }
+        },
+        "/roles": {
+            "get": {
+                "description": "List role assignments ordered by subject",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "List role assignments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Assignment count limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Assignment count offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/authz.Assignment"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}": {
+            "get": {
+                "description": "Get a recurring reservation with the occurrences booked so far",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Get a recurring reservation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/series.Series"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/occurrence/{date}": {
+            "put": {
+                "description": "Edit the occurrence on a date only, that one and the following ones, which are split off into a new series, or the whole series. Moving the start time of the occurrence moves the others alike. Occurrences moved or reduced late are charged like reservations edited on their own. The series edited is returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Edit occurrences of a recurring reservation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of the occurrence, e.g. 2006-01-02",
+                        "name": "date",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "this",
+                            "following",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "this",
+                        "description": "Occurrences to edit",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Changes",
+                        "name": "reservation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/reservation.Reservation"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/series.Series"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Cancel the occurrence on a date only, that one and the following ones, or the whole series. Occurrences already started are kept. Each occurrence cancelled is charged the cancellation fee, its deposit being settled keeping the fee.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "series"
+                ],
+                "summary": "Cancel occurrences of a recurring reservation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of the occurrence, e.g. 2006-01-02",
+                        "name": "date",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "this",
+                            "following",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "this",
+                        "description": "Occurrences to cancel",
+                        "name": "scope",
+                        "in": "query"
+                    }
+                ]
+            }
+        },
+        "/table": {
+            "post": {
+                "description": "Add a table to a seating area",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "Add a table to a seating area",
+                "parameters": [
+                    {
+                        "description": "New Table",
+                        "name": "table",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/seating.Table"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/seating.Table"
+                        }
+                    }
+                }
+            }
+        },
+        "/table/{id}": {
+            "put": {
+                "description": "Update a table, including its place on the floor plan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "Update a table",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Table ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Table",
+                        "name": "table",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/seating.Table"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/seating.Table"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a table, flagging the reservations seated at it for reassignment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "Remove a table",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Table ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ]
+            }
+        },
+        "/table/{id}/state": {
+            "put": {
+                "description": "Seat a reservation at a table, moving it there if booked elsewhere, or clear a table once its party leaves, marking it as needing cleaning or free",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "Update the state of a table",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Table ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New state, and the reservation seated for state seated",
+                        "name": "state",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/seating.setTableStateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/seating.FloorTable"
+                        }
+                    }
+                }
+            }
+        },
+        "/tables": {
+            "get": {
+                "description": "List the tables of the venue or of one of its areas",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "seating"
+                ],
+                "summary": "List tables",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Area ID",
+                        "name": "area",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/seating.Table"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook": {
+            "post": {
+                "description": "Subscribe a URL to webhook events. The returned secret is used to sign deliveries and is shown only once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Subscribe a URL to webhook events",
+                "parameters": [
+                    {
+                        "description": "New Subscription",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/webhook.Subscription"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/webhook.Subscription"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook/delivery/{id}/retry": {
+            "post": {
+                "description": "Move a dead webhook delivery back to the pending state",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Retry a dead webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/webhook.Delivery"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhook/{id}": {
+            "get": {
+                "description": "Get a webhook subscription",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Get a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "$ref": "#/definitions/webhook.Subscription"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a webhook subscription together with its delivery log",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Remove a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ]
+            }
+        },
+        "/webhook/{id}/deliveries": {
+            "get": {
+                "description": "List deliveries of a webhook subscription ordered by newest",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "List deliveries of a webhook subscription ordered by newest",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery status (pending, retrying, delivered, dead)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Delivery count limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Delivery count offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/webhook.Delivery"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "List webhook subscriptions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "List webhook subscriptions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Subscription count limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Subscription count offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/webhook.Subscription"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "audit.Entry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object",
+                    "$ref": "#/definitions/storage.JSON"
+                },
+                "auditId": {
+                    "type": "integer"
+                },
+                "before": {
+                    "type": "object",
+                    "$ref": "#/definitions/storage.JSON"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "diff": {
+                    "type": "object",
+                    "$ref": "#/definitions/storage.JSON"
+                },
+                "entityId": {
+                    "type": "integer"
+                },
+                "entityType": {
+                    "type": "string"
+                },
+                "requestId": {
+                    "type": "string"
+                },
+                "venueId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "authz.Assignment": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "customerId": {
+                    "description": "CustomerID links a guest to their customer record.",
+                    "type": "integer"
+                },
+                "lastUpdated": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "guest",
+                        "staff",
+                        "manager"
+                    ]
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "venueId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "customer.Customer": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "customerId": {
+                    "type": "integer"
+                },
                 "email": {
                     "type": "string"
                 },
-                "firstName": {
+                "firstName": {
+                    "type": "string"
+                },
+                "lastName": {
+                    "type": "string"
+                },
+                "lastUpdated": {
+                    "type": "integer"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "venueId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "event.Event": {
+            "type": "object",
+            "properties": {
+                "aggregateId": {
+                    "type": "integer"
+                },
+                "aggregateType": {
+                    "type": "string"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "eventId": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "type": "object",
+                    "$ref": "#/definitions/storage.JSON"
+                },
+                "sequence": {
+                    "description": "Sequence orders the events as they were committed, which event IDs\ndo not as they are taken before. It is assigned by the outbox relay\nwhen it first sees the event, and is 0 until then.",
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "venueId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fee.Fee": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount is in the minor unit of the currency, such as cents.",
+                    "type": "integer"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "customerId": {
+                    "type": "integer"
+                },
+                "feeId": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "cancellation",
+                        "modification"
+                    ]
+                },
+                "lastUpdated": {
+                    "type": "integer"
+                },
+                "policy": {
+                    "description": "Policy names the cancellation terms the fee is charged under.",
+                    "type": "string"
+                },
+                "reservationId": {
+                    "description": "ReservationID is the reservation charged for, which no longer exists\nonce cancelled.",
+                    "type": "integer"
+                },
+                "venueId": {
+                    "type": "integer"
+                },
+                "waived": {
+                    "type": "boolean"
+                },
+                "waivedBy": {
+                    "type": "string"
+                },
+                "waiverReason": {
+                    "type": "string"
+                }
+            }
+        },
+        "fee.waiveFeeRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "hoststand.Host": {
+            "type": "object",
+            "properties": {
+                "clientId": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "since": {
+                    "type": "integer"
+                }
+            }
+        },
+        "hoststand.Lock": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "integer"
+                },
+                "host": {
+                    "type": "object",
+                    "$ref": "#/definitions/hoststand.Host"
+                },
+                "reservationId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "hoststand.Message": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "error"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "hosts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/hoststand.Host"
+                    }
+                },
+                "lock": {
+                    "type": "object",
+                    "$ref": "#/definitions/hoststand.Lock"
+                },
+                "locks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/hoststand.Lock"
+                    }
+                },
+                "reservation": {
+                    "type": "object",
+                    "$ref": "#/definitions/reservation.Reservation"
+                },
+                "reservationId": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "you": {
+                    "description": "You is the host a welcome message is sent to.",
+                    "type": "object",
+                    "$ref": "#/definitions/hoststand.Host"
+                }
+            }
+        },
+        "payment.Deposit": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount, Captured and Refunded are in the minor unit of Currency, such\nas cents.",
+                    "type": "integer"
+                },
+                "captured": {
+                    "type": "integer"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "customerId": {
+                    "type": "integer"
+                },
+                "depositId": {
+                    "type": "integer"
+                },
+                "feeId": {
+                    "type": "integer"
+                },
+                "keep": {
+                    "description": "Keep is what the latest settlement keeps of the deposit, in payment\nof the fee FeeID unless 0, which is refunded when waived.",
+                    "type": "integer"
+                },
+                "lastUpdated": {
+                    "type": "integer"
+                },
+                "payBy": {
+                    "description": "PayBy is the time the deposit is due by.",
+                    "type": "integer"
+                },
+                "paymentId": {
+                    "description": "PaymentID is the payment of the gateway authorized for the deposit.",
+                    "type": "string"
+                },
+                "policy": {
+                    "description": "Policy names the deposit terms requiring the deposit.",
+                    "type": "string"
+                },
+                "refunded": {
+                    "type": "integer"
+                },
+                "reservationId": {
+                    "description": "ReservationID is the reservation held by the deposit, which no longer\nexists once cancelled.",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "authorized",
+                        "settling",
+                        "captured",
+                        "refunded",
+                        "voided",
+                        "expired"
+                    ]
+                },
+                "venueId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "payment.payDepositRequest": {
+            "type": "object",
+            "properties": {
+                "paymentMethod": {
+                    "description": "PaymentMethod is the token the payment provider gave the client for\nthe card of the guest.",
+                    "type": "string"
+                }
+            }
+        },
+        "payment.settleDepositRequest": {
+            "type": "object",
+            "properties": {
+                "keep": {
+                    "description": "Keep is the amount of the deposit to capture, e.g. for a no-show,\nthe rest being released or refunded.",
+                    "type": "integer"
+                }
+            }
+        },
+        "reservation.Reservation": {
+            "type": "object",
+            "properties": {
+                "areaId": {
+                    "description": "AreaID is the seating area asked for, or the one of the table\nallocated when any area would do.",
+                    "type": "integer"
+                },
+                "combinationId": {
+                    "description": "CombinationID is set instead of TableID for parties seated at tables\npushed together.",
+                    "type": "integer"
+                },
+                "comments": {
+                    "type": "string"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "customerId": {
+                    "type": "integer"
+                },
+                "deposit": {
+                    "description": "Deposit is required to hold the reservation by PayBy, after which it\nis cancelled unless paid, in the minor unit of the currency.",
+                    "type": "integer"
+                },
+                "endsAt": {
+                    "type": "integer"
+                },
+                "fee": {
+                    "description": "Fee totals the unwaived fees charged for modifying the reservation\nlate, in the minor unit of the currency.",
+                    "type": "integer"
+                },
+                "holdToken": {
+                    "description": "HoldToken redeems a hold taken during checkout: the booking gets the\ntable kept by the hold, which is released.",
+                    "type": "string"
+                },
+                "lastUpdated": {
+                    "type": "integer"
+                },
+                "needsReassignment": {
+                    "description": "NeedsReassignment is set when the table allocated is no longer\navailable, e.g. because its area was closed.",
+                    "type": "boolean"
+                },
+                "occurrence": {
+                    "type": "string"
+                },
+                "payBy": {
+                    "type": "integer"
+                },
+                "phone": {
                     "type": "string"
                 },
-                "lastName": {
+                "reservationId": {
+                    "type": "integer"
+                },
+                "reservationName": {
+                    "type": "string"
+                },
+                "seatCount": {
+                    "type": "integer"
+                },
+                "seriesId": {
+                    "description": "SeriesID is the recurring series the reservation is an occurrence of,\nscheduled on the date Occurrence, or 0 for one-off bookings.",
+                    "type": "integer"
+                },
+                "startTime": {
+                    "description": "StartTime is when the party arrives, as an RFC 3339 timestamp or, as\nolder clients send it, in Unix seconds.",
+                    "type": "string"
+                },
+                "startsAt": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Status is booked until the host stand seats the party, and completed\nonce it leaves.",
+                    "type": "string",
+                    "enum": [
+                        "booked",
+                        "seated",
+                        "completed"
+                    ]
+                },
+                "tableId": {
+                    "type": "integer"
+                },
+                "venueId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "seating.Area": {
+            "type": "object",
+            "properties": {
+                "areaId": {
+                    "type": "integer"
+                },
+                "closed": {
+                    "type": "boolean"
+                },
+                "closedReason": {
                     "type": "string"
                 },
+                "created": {
+                    "type": "integer"
+                },
                 "lastUpdated": {
                     "type": "integer"
                 },
-                "phone": {
+                "name": {
                     "type": "string"
+                },
+                "venueId": {
+                    "type": "integer"
                 }
             }
         },
-        "reservation.Reservation": {
+        "seating.Availability": {
             "type": "object",
             "properties": {
-                "comments": {
+                "combinations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/seating.Combination"
+                    }
+                },
+                "tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/seating.Table"
+                    }
+                }
+            }
+        },
+        "seating.Closure": {
+            "type": "object",
+            "properties": {
+                "area": {
+                    "type": "object",
+                    "$ref": "#/definitions/seating.Area"
+                },
+                "flagged": {
+                    "description": "Flagged lists the upcoming reservations seated in the area, which\nare flagged for reassignment.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "seating.Combination": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "description": "Capacity is the seat count of the combined tables, which defaults to\nthe sum of their capacities.",
+                    "type": "integer"
+                },
+                "combinationId": {
+                    "type": "integer"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "lastUpdated": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
                 },
+                "tableIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "venueId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "seating.Floor": {
+            "type": "object",
+            "properties": {
+                "areas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/seating.Area"
+                    }
+                },
+                "tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/seating.FloorTable"
+                    }
+                }
+            }
+        },
+        "seating.FloorTable": {
+            "type": "object",
+            "properties": {
+                "areaId": {
+                    "type": "integer"
+                },
+                "capacity": {
+                    "type": "integer"
+                },
                 "created": {
                     "type": "integer"
                 },
-                "customerId": {
+                "height": {
+                    "type": "number"
+                },
+                "lastUpdated": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "needsCleaning": {
+                    "description": "NeedsCleaning is set when a party leaves until the host stand marks\nthe table free again.",
+                    "type": "boolean"
+                },
+                "reservationId": {
+                    "description": "ReservationID is the reservation seated at the table or, for reserved\ntables, the one expected next.",
+                    "type": "integer"
+                },
+                "rotation": {
+                    "type": "number"
+                },
+                "shape": {
+                    "type": "string",
+                    "enum": [
+                        "square",
+                        "round",
+                        "rectangle"
+                    ]
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "free",
+                        "reserved",
+                        "seated",
+                        "needs_cleaning"
+                    ]
+                },
+                "tableId": {
+                    "type": "integer"
+                },
+                "venueId": {
+                    "type": "integer"
+                },
+                "width": {
+                    "type": "number"
+                },
+                "x": {
+                    "description": "X and Y place the center of the table on the floor plan of its area,\nwhich it covers Width by Height before being rotated clockwise by\nRotation degrees.",
+                    "type": "number"
+                },
+                "y": {
+                    "type": "number"
+                }
+            }
+        },
+        "seating.Hold": {
+            "type": "object",
+            "properties": {
+                "areaId": {
+                    "type": "integer"
+                },
+                "combinationId": {
+                    "type": "integer"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "endsAt": {
+                    "type": "integer"
+                },
+                "expiresAt": {
+                    "type": "integer"
+                },
+                "seats": {
+                    "type": "integer"
+                },
+                "startTime": {
+                    "type": "string"
+                },
+                "startsAt": {
+                    "type": "integer"
+                },
+                "tableId": {
+                    "type": "integer"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "venueId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "seating.Table": {
+            "type": "object",
+            "properties": {
+                "areaId": {
+                    "type": "integer"
+                },
+                "capacity": {
                     "type": "integer"
                 },
+                "created": {
+                    "type": "integer"
+                },
+                "height": {
+                    "type": "number"
+                },
                 "lastUpdated": {
                     "type": "integer"
                 },
-                "phone": {
+                "name": {
                     "type": "string"
                 },
+                "needsCleaning": {
+                    "description": "NeedsCleaning is set when a party leaves until the host stand marks\nthe table free again.",
+                    "type": "boolean"
+                },
+                "rotation": {
+                    "type": "number"
+                },
+                "shape": {
+                    "type": "string",
+                    "enum": [
+                        "square",
+                        "round",
+                        "rectangle"
+                    ]
+                },
+                "tableId": {
+                    "type": "integer"
+                },
+                "venueId": {
+                    "type": "integer"
+                },
+                "width": {
+                    "type": "number"
+                },
+                "x": {
+                    "description": "X and Y place the center of the table on the floor plan of its area,\nwhich it covers Width by Height before being rotated clockwise by\nRotation degrees.",
+                    "type": "number"
+                },
+                "y": {
+                    "type": "number"
+                }
+            }
+        },
+        "seating.setTableStateRequest": {
+            "type": "object",
+            "properties": {
                 "reservationId": {
                     "type": "integer"
                 },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "series.Series": {
+            "type": "object",
+            "properties": {
+                "areaId": {
+                    "type": "integer"
+                },
+                "comments": {
+                    "type": "string"
+                },
+                "count": {
+                    "description": "Count is the number of occurrences, including the cancelled ones.",
+                    "type": "integer"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "customerId": {
+                    "type": "integer"
+                },
+                "exceptions": {
+                    "description": "Exceptions lists the dates of the cancelled occurrences.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly"
+                    ]
+                },
+                "interval": {
+                    "description": "Interval is the number of days, weeks or months between occurrences,\n1 unless given.",
+                    "type": "integer"
+                },
+                "lastUpdated": {
+                    "type": "integer"
+                },
+                "materializedUntil": {
+                    "description": "MaterializedUntil is the Unix time up to which the occurrences are\nbooked.",
+                    "type": "integer"
+                },
+                "occurrences": {
+                    "description": "Occurrences are the reservations booked for the series, oldest first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/reservation.Reservation"
+                    }
+                },
+                "phone": {
+                    "type": "string"
+                },
                 "reservationName": {
                     "type": "string"
                 },
                 "seatCount": {
                     "type": "integer"
                 },
+                "seriesId": {
+                    "type": "integer"
+                },
                 "startTime": {
                     "type": "string"
+                },
+                "until": {
+                    "description": "Until is the date of the last possible occurrence.",
+                    "type": "string"
+                },
+                "venueId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "storage.JSON": {
+            "type": "array",
+            "items": {}
+        },
+        "webhook.Delivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "deliveryId": {
+                    "type": "integer"
+                },
+                "eventId": {
+                    "type": "integer"
+                },
+                "eventType": {
+                    "type": "string"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "lastStatusCode": {
+                    "type": "integer"
+                },
+                "lastUpdated": {
+                    "type": "integer"
+                },
+                "nextAttemptAt": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "type": "object",
+                    "$ref": "#/definitions/storage.JSON"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscriptionId": {
+                    "type": "integer"
+                },
+                "venueId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "webhook.Subscription": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "eventTypes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "lastUpdated": {
+                    "type": "integer"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "subscriptionId": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "venueId": {
+                    "type": "integer"
                 }
             }
         }
@@ -0,0 +1,56 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	errors "reservations/pkg/error"
+	"reservations/pkg/storage"
+)
+
+// Change holds the old and new value of a single changed field.
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Diff compares two JSON objects field by field and returns a JSON object
+// mapping every changed field to its before and after value. A missing side
+// (creation or deletion) is treated as an empty object.
+func Diff(before storage.JSON, after storage.JSON) (storage.JSON, error) {
+	b, err := decodeObject(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := decodeObject(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]Change)
+	for k, bv := range b {
+		if av, ok := a[k]; !ok || !reflect.DeepEqual(av, bv) {
+			changes[k] = Change{Before: bv, After: a[k]}
+		}
+	}
+	for k, av := range a {
+		if _, ok := b[k]; !ok {
+			changes[k] = Change{After: av}
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(changes)
+}
+
+func decodeObject(raw storage.JSON) (map[string]interface{}, error) {
+	obj := make(map[string]interface{})
+	if len(raw) == 0 {
+		return obj, nil
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, errors.Wrap(err, "error decoding audited state")
+	}
+	return obj, nil
+}
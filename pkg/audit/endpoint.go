@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"reservations/pkg/authz"
+	"reservations/pkg/storage"
+)
+
+type Endpoints struct {
+	GetAuditLogEndpoint endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns the service endpoints, each checked against
+// the policy by az and wrapped in the given middlewares, the first one
+// outermost.
+func MakeServerEndpoints(s Service, az authz.Authorizer, mw ...endpoint.Middleware) Endpoints {
+	e := Endpoints{
+		GetAuditLogEndpoint: az.Require(authz.PermAuditRead, nil)(MakeGetAuditLogEndpoint(s)),
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		m := mw[i]
+		e.GetAuditLogEndpoint = m(e.GetAuditLogEndpoint)
+	}
+	return e
+}
+
+type getAuditLogRequest struct {
+	Filter Filter
+	Limit  uint
+	Offset uint
+}
+
+type getAuditLogResponse struct {
+	Entries []Entry `json:"entries,omitempty"`
+	Err     error   `json:"err,omitempty"`
+}
+
+func (r getAuditLogResponse) HTTPError() error { return r.Err }
+
+// GetAuditLog godoc
+// @Summary List audit log entries ordered by newest
+// @Description List changes made to customers and reservations ordered by newest
+// @Tags audit
+// @Param actor query string false "Actor who made the change"
+// @Param action query string false "Action (create, update, delete)"
+// @Param entity query string false "Entity type (customer, reservation)"
+// @Param entityId query int false "Entity ID"
+// @Param requestId query string false "Request ID"
+// @Param from query int false "Earliest change as Unix timestamp"
+// @Param to query int false "Latest change as Unix timestamp"
+// @Param limit query int false "Entry count limit" default(100)
+// @Param offset query int false "Entry count offset" default(0)
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} audit.Entry
+// @Router /audit [get]
+func MakeGetAuditLogEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getAuditLogRequest)
+		ee, e := s.GetAuditLog(ctx, &req.Filter, &storage.QueryOptions{
+			Limit:  req.Limit,
+			Offset: req.Offset,
+		})
+		return getAuditLogResponse{
+			Entries: ee,
+			Err:     e,
+		}, nil
+	}
+}
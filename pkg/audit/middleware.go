@@ -0,0 +1,32 @@
+package audit
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"reservations/pkg/storage"
+	"time"
+)
+
+// Middleware describes a service (as opposed to endpoint) middleware.
+type Middleware func(Service) Service
+
+func LoggingMiddleware(logger log.Logger) Middleware {
+	return func(next Service) Service {
+		return &loggingMiddleware{
+			next:   next,
+			logger: logger,
+		}
+	}
+}
+
+type loggingMiddleware struct {
+	next   Service
+	logger log.Logger
+}
+
+func (mw loggingMiddleware) GetAuditLog(ctx context.Context, filter *Filter, opts *storage.QueryOptions) (result []Entry, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "GetAuditLog", "limit", opts.Limit, "offset", opts.Offset, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetAuditLog(ctx, filter, opts)
+}
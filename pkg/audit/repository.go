@@ -0,0 +1,104 @@
+package audit
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v7"
+	errors "reservations/pkg/error"
+	"reservations/pkg/request"
+	"reservations/pkg/storage"
+	"reservations/pkg/tenant"
+	"reservations/pkg/tracing"
+	"time"
+)
+
+// Append records a change to an entity as part of the transaction making
+// it, in the venue of ctx and on behalf of its actor, so that no change
+// goes unaudited. before is nil for creations and after for deletions.
+func Append(ctx context.Context, tx *goqu.TxDatabase, action string, entityType string, entityID int, before interface{}, after interface{}) error {
+	b, err := marshalState(before)
+	if err != nil {
+		return err
+	}
+	a, err := marshalState(after)
+	if err != nil {
+		return err
+	}
+	d, err := Diff(b, a)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.From("audit_log").Prepared(true).Insert(&Entry{
+		Actor:      request.ActorFromContext(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     b,
+		After:      a,
+		Diff:       d,
+		RequestID:  request.IDFromContext(ctx),
+		VenueID:    tenant.VenueFromContext(ctx),
+		Created:    time.Now().Unix(),
+	}).Exec()
+
+	if err != nil {
+		return errors.DBError.Wrapf(err, "error adding audit entry for %s %d", entityType, entityID)
+	}
+	return nil
+}
+
+type Repository interface {
+	FindEntries(ctx context.Context, vID int, filter *Filter, opts *storage.QueryOptions) ([]Entry, error)
+}
+
+type auditRepository struct {
+	db storage.Persistence
+}
+
+func NewAuditRepository(db storage.Persistence) Repository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) FindEntries(ctx context.Context, vID int, filter *Filter, opts *storage.QueryOptions) (ee []Entry, err error) {
+	_, span := tracing.StartSpan(ctx, "auditRepository.FindEntries", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	opts.Limit = r.db.PageSize(opts.Limit)
+
+	ds := r.db.DB.From("audit_log").Prepared(true)
+
+	where := goqu.Ex{"venue_id": vID}
+	if filter.Actor != "" {
+		where["actor"] = filter.Actor
+	}
+	if filter.Action != "" {
+		where["action"] = filter.Action
+	}
+	if filter.EntityType != "" {
+		where["entity_type"] = filter.EntityType
+	}
+	if filter.EntityID != 0 {
+		where["entity_id"] = filter.EntityID
+	}
+	if filter.RequestID != "" {
+		where["request_id"] = filter.RequestID
+	}
+	ds = ds.Where(where)
+	if filter.From != 0 {
+		ds = ds.Where(goqu.C("created").Gte(filter.From))
+	}
+	if filter.To != 0 {
+		ds = ds.Where(goqu.C("created").Lte(filter.To))
+	}
+
+	err = ds.
+		Order(goqu.C("aid").Desc()).
+		Limit(opts.Limit).
+		Offset(opts.Offset).
+		ScanStructs(&ee)
+
+	if err != nil {
+		return nil, errors.DBError.Wrapf(err, "error getting audit log")
+	}
+	return ee, nil
+}
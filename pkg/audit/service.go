@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	errors "reservations/pkg/error"
+	"reservations/pkg/storage"
+	"reservations/pkg/tenant"
+)
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+type Service interface {
+	GetAuditLog(ctx context.Context, filter *Filter, opts *storage.QueryOptions) ([]Entry, error)
+}
+
+// Entry records a single change made to an entity.
+type Entry struct {
+	AuditID    int          `json:"auditId" db:"aid" goqu:"skipinsert"`
+	Actor      string       `json:"actor"`
+	Action     string       `json:"action"`
+	EntityType string       `json:"entityType" db:"entity_type"`
+	EntityID   int          `json:"entityId" db:"entity_id"`
+	Before     storage.JSON `json:"before,omitempty"`
+	After      storage.JSON `json:"after,omitempty"`
+	Diff       storage.JSON `json:"diff,omitempty"`
+	RequestID  string       `json:"requestId" db:"request_id"`
+	VenueID    int          `json:"venueId" db:"venue_id"`
+	Created    int64        `json:"created"`
+}
+
+// Filter narrows down the audit log. Zero values are ignored.
+type Filter struct {
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   int
+	RequestID  string
+	From       int64
+	To         int64
+}
+
+type auditService struct {
+	auditRepo Repository
+}
+
+func NewAuditService(repo Repository) Service {
+	return &auditService{
+		auditRepo: repo,
+	}
+}
+
+func (s *auditService) GetAuditLog(ctx context.Context, filter *Filter, opts *storage.QueryOptions) ([]Entry, error) {
+	return s.auditRepo.FindEntries(ctx, tenant.VenueFromContext(ctx), filter, opts)
+}
+
+func marshalState(v interface{}) (storage.JSON, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "error encoding audited state")
+	}
+	return b, nil
+}
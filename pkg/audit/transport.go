@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"net/http"
+	"reservations/pkg/authz"
+	"reservations/pkg/transport"
+)
+
+func MakeHTTPHandler(r *mux.Router, s Service, az authz.Authorizer, logger log.Logger, mw ...endpoint.Middleware) *mux.Router {
+	e := MakeServerEndpoints(s, az, mw...)
+
+	options := httpjson.DefaultServerOptions(logger)
+
+	r.Methods("GET").Path("/audit").
+		Handler(httptransport.NewServer(
+			e.GetAuditLogEndpoint,
+			decodeGetAuditLogRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	return r
+}
+
+func decodeGetAuditLogRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	q := r.URL.Query()
+	return getAuditLogRequest{
+		Filter: Filter{
+			Actor:      q.Get("actor"),
+			Action:     q.Get("action"),
+			EntityType: q.Get("entity"),
+			EntityID:   int(httpjson.ParseUintQueryParam(r, "entityId")),
+			RequestID:  q.Get("requestId"),
+			From:       int64(httpjson.ParseUintQueryParam(r, "from")),
+			To:         int64(httpjson.ParseUintQueryParam(r, "to")),
+		},
+		Limit:  httpjson.ParseUintQueryParam(r, "limit"),
+		Offset: httpjson.ParseUintQueryParam(r, "offset"),
+	}, nil
+}
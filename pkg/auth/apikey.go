@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/doug-martin/goqu/v7"
+	"github.com/doug-martin/goqu/v7/exec"
+	errors "reservations/pkg/error"
+	"reservations/pkg/storage"
+	"reservations/pkg/tracing"
+	"time"
+)
+
+const (
+	apiKeyPrefix = "rk_"
+	apiKeyLength = 32
+)
+
+// APIKey grants its holder the identity of Subject. Only a hash of the key
+// is stored, the key itself is shown once when it is created.
+type APIKey struct {
+	KeyID     int    `json:"keyId" db:"kid" goqu:"skipinsert"`
+	Name      string `json:"name"`
+	Subject   string `json:"subject"`
+	Hash      string `json:"-" db:"key_hash"`
+	Created   int64  `json:"created"`
+	RevokedAt int64  `json:"revokedAt" db:"revoked_at"`
+}
+
+type Repository interface {
+	AddAPIKey(ctx context.Context, k *APIKey) (*APIKey, error)
+	RevokeAPIKey(ctx context.Context, kID int) error
+	FindAllAPIKeys(ctx context.Context, opts *storage.QueryOptions) ([]APIKey, error)
+	FindActiveAPIKeyByHash(ctx context.Context, hash string) (APIKey, error)
+}
+
+type apiKeyRepository struct {
+	db storage.Persistence
+}
+
+func NewAPIKeyRepository(db storage.Persistence) Repository {
+	return &apiKeyRepository{db: db}
+}
+
+// CreateAPIKey generates a new API key for subject and returns it together
+// with its stored record.
+func CreateAPIKey(ctx context.Context, repo Repository, name string, subject string) (string, *APIKey, error) {
+	if subject == "" {
+		return "", nil, errors.ValidationError.New("no subject given").
+			AddContext("subject", "an API key needs a subject")
+	}
+
+	b := make([]byte, apiKeyLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, errors.Wrap(err, "error generating API key")
+	}
+	key := apiKeyPrefix + hex.EncodeToString(b)
+
+	k, err := repo.AddAPIKey(ctx, &APIKey{
+		Name:    name,
+		Subject: subject,
+		Hash:    HashAPIKey(key),
+	})
+	return key, k, err
+}
+
+// HashAPIKey returns the hash an API key is stored and looked up by.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (r *apiKeyRepository) AddAPIKey(ctx context.Context, k *APIKey) (_ *APIKey, err error) {
+	_, span := tracing.StartSpan(ctx, "apiKeyRepository.AddAPIKey", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	k.Created = time.Now().Unix()
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		kID, err := storage.InsertReturningID(tx, "api_key", "kid", k)
+		k.KeyID = kID
+		return err
+	})
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error adding new API key")
+	}
+	return k, nil
+}
+
+func (r *apiKeyRepository) RevokeAPIKey(ctx context.Context, kID int) (err error) {
+	_, span := tracing.StartSpan(ctx, "apiKeyRepository.RevokeAPIKey", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	_, err = r.db.Tx(func(tx *goqu.TxDatabase) exec.QueryExecutor {
+		return tx.From("api_key").Where(goqu.Ex{"kid": kID}).Update(goqu.Record{
+			"revoked_at": time.Now().Unix(),
+		})
+	})
+
+	if err != nil {
+		return errors.DBError.Wrapf(err, "error revoking API key with ID %d", kID)
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) FindAllAPIKeys(ctx context.Context, opts *storage.QueryOptions) (kk []APIKey, err error) {
+	_, span := tracing.StartSpan(ctx, "apiKeyRepository.FindAllAPIKeys", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	opts.Limit = r.db.PageSize(opts.Limit)
+
+	err = r.db.DB.From("api_key").
+		Order(goqu.C("kid").Asc()).
+		Limit(opts.Limit).
+		Offset(opts.Offset).
+		ScanStructs(&kk)
+
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error getting all API keys")
+	}
+	return kk, nil
+}
+
+func (r *apiKeyRepository) FindActiveAPIKeyByHash(ctx context.Context, hash string) (k APIKey, err error) {
+	_, span := tracing.StartSpan(ctx, "apiKeyRepository.FindActiveAPIKeyByHash", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	found, err := r.db.DB.From("api_key").Prepared(true).Where(goqu.Ex{
+		"key_hash":   hash,
+		"revoked_at": 0,
+	}).ScanStruct(&k)
+
+	if err != nil {
+		return k, errors.DBError.Wrap(err, "error getting API key")
+	}
+
+	if !found {
+		return k, errors.NotFound.New("API key not found")
+	}
+
+	return k, nil
+}
@@ -0,0 +1,111 @@
+// Package auth authenticates callers by signed JWT bearer tokens or API keys
+// and makes the authenticated principal available in the request context.
+package auth
+
+import (
+	"context"
+	kitjwt "github.com/go-kit/kit/auth/jwt"
+	grpctransport "github.com/go-kit/kit/transport/grpc"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"google.golang.org/grpc/metadata"
+	"net/http"
+)
+
+const (
+	// HeaderAPIKey carries an API key as an alternative to a bearer token.
+	HeaderAPIKey = "X-API-Key"
+	// MetadataAPIKey is the gRPC counterpart of HeaderAPIKey.
+	MetadataAPIKey = "x-api-key"
+)
+
+// Authentication methods.
+const (
+	MethodJWT    = "jwt"
+	MethodAPIKey = "apikey"
+)
+
+// Principal is an authenticated caller.
+type Principal struct {
+	Subject string `json:"subject"`
+	Method  string `json:"method"`
+	// Claims holds the claims of a JWT and is empty for API keys.
+	Claims map[string]interface{} `json:"claims,omitempty"`
+}
+
+type contextKey int
+
+const (
+	principalKey contextKey = iota
+	apiKeyKey
+)
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey, p)
+}
+
+// PrincipalFromContext returns the authenticated caller, if any.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey).(Principal)
+	return p, ok
+}
+
+// WithAPIKey returns a copy of ctx carrying the raw API key sent by a caller
+// or to be sent by a client.
+func WithAPIKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, apiKeyKey, key)
+}
+
+// WithToken returns a copy of ctx carrying the raw JWT sent by a caller or
+// to be sent by a client.
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, kitjwt.JWTTokenContextKey, token)
+}
+
+func apiKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(apiKeyKey).(string)
+	return key
+}
+
+func tokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(kitjwt.JWTTokenContextKey).(string)
+	return token
+}
+
+// HTTPToContext moves the bearer token and API key of a request into the
+// context, where Middleware picks them up.
+func HTTPToContext() httptransport.RequestFunc {
+	bearer := kitjwt.HTTPToContext()
+	return func(ctx context.Context, r *http.Request) context.Context {
+		ctx = bearer(ctx, r)
+		if key := r.Header.Get(HeaderAPIKey); key != "" {
+			ctx = WithAPIKey(ctx, key)
+		}
+		return ctx
+	}
+}
+
+// GRPCToContext is the gRPC counterpart of HTTPToContext.
+func GRPCToContext() grpctransport.ServerRequestFunc {
+	bearer := kitjwt.GRPCToContext()
+	return func(ctx context.Context, md metadata.MD) context.Context {
+		ctx = bearer(ctx, md)
+		if vv := md.Get(MetadataAPIKey); len(vv) > 0 {
+			ctx = WithAPIKey(ctx, vv[0])
+		}
+		return ctx
+	}
+}
+
+// ContextToHTTP sets the bearer token or API key stored in the context on an
+// outgoing request.
+func ContextToHTTP() httptransport.RequestFunc {
+	bearer := kitjwt.ContextToHTTP()
+	return func(ctx context.Context, r *http.Request) context.Context {
+		ctx = bearer(ctx, r)
+		if key := apiKeyFromContext(ctx); key != "" {
+			r.Header.Set(HeaderAPIKey, key)
+		}
+		return ctx
+	}
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/dgrijalva/jwt-go"
+	"io/ioutil"
+	"math/big"
+	errors "reservations/pkg/error"
+)
+
+// KeySet holds the keys JWTs may be signed with, by key ID.
+type KeySet struct {
+	keys map[string]interface{}
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// LoadJWKS reads a JSON Web Key Set file. RSA and EC public keys as well as
+// symmetric (oct) keys for HMAC signatures are supported.
+func LoadJWKS(path string) (*KeySet, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading JWKS file %s", path)
+	}
+	return ParseJWKS(b)
+}
+
+// ParseJWKS parses a JSON Web Key Set.
+func ParseJWKS(b []byte) (*KeySet, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, errors.ValidationError.Wrap(err, "invalid JWKS")
+	}
+
+	ks := &KeySet{keys: make(map[string]interface{}, len(set.Keys))}
+	for _, k := range set.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, errors.ValidationError.Wrapf(err, "invalid JWK %q", k.Kid)
+		}
+		ks.keys[k.Kid] = key
+	}
+	return ks, nil
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Newf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(k.K)
+
+	default:
+		return nil, errors.Newf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// keyFunc picks the key named by the kid header, or the only key when the
+// token names none, and makes sure it fits the signing method.
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok && kid == "" && len(ks.keys) == 1 {
+		for _, k := range ks.keys {
+			key, ok = k, true
+		}
+	}
+	if !ok {
+		return nil, errors.Newf("unknown signing key %q", kid)
+	}
+
+	var fits bool
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		_, fits = key.(*rsa.PublicKey)
+	case *jwt.SigningMethodECDSA:
+		_, fits = key.(*ecdsa.PublicKey)
+	case *jwt.SigningMethodHMAC:
+		_, fits = key.([]byte)
+	}
+	if !fits {
+		return nil, errors.Newf("signing method %s does not match key %q", token.Method.Alg(), kid)
+	}
+	return key, nil
+}
+
+// JWTValidator checks the signature and standard claims of bearer tokens.
+type JWTValidator struct {
+	keys     *KeySet
+	issuer   string
+	audience string
+}
+
+// NewJWTValidator returns a validator accepting tokens signed by one of keys.
+// The iss and aud claims are only checked when issuer and audience are set.
+func NewJWTValidator(keys *KeySet, issuer string, audience string) *JWTValidator {
+	return &JWTValidator{keys: keys, issuer: issuer, audience: audience}
+}
+
+// Validate returns the principal named by the sub claim of a valid token.
+func (v *JWTValidator) Validate(token string) (Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, v.keys.keyFunc); err != nil {
+		return Principal{}, errors.Unauthorized.Wrap(err, "invalid bearer token")
+	}
+
+	if v.issuer != "" && !claims.VerifyIssuer(v.issuer, true) {
+		return Principal{}, errors.Unauthorized.New("invalid bearer token: unexpected issuer")
+	}
+	if v.audience != "" && !hasAudience(claims, v.audience) {
+		return Principal{}, errors.Unauthorized.New("invalid bearer token: unexpected audience")
+	}
+
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return Principal{}, errors.Unauthorized.New("invalid bearer token: missing subject")
+	}
+
+	return Principal{Subject: sub, Method: MethodJWT, Claims: claims}, nil
+}
+
+func hasAudience(claims jwt.MapClaims, audience string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"net/http"
+	errors "reservations/pkg/error"
+	"reservations/pkg/request"
+)
+
+// Authenticator resolves the credentials a caller sent into a Principal.
+type Authenticator struct {
+	jwt  *JWTValidator
+	keys Repository
+}
+
+// NewAuthenticator returns an Authenticator accepting bearer tokens checked
+// by jwt and API keys found in keys. Either may be nil to disable that
+// method.
+func NewAuthenticator(jwt *JWTValidator, keys Repository) *Authenticator {
+	return &Authenticator{jwt: jwt, keys: keys}
+}
+
+// Authenticate returns the principal for the credentials in ctx.
+func (a *Authenticator) Authenticate(ctx context.Context) (Principal, error) {
+	if token := tokenFromContext(ctx); token != "" {
+		if a.jwt == nil {
+			return Principal{}, errors.Unauthorized.New("bearer tokens are not accepted")
+		}
+		return a.jwt.Validate(token)
+	}
+
+	if key := apiKeyFromContext(ctx); key != "" {
+		if a.keys == nil {
+			return Principal{}, errors.Unauthorized.New("API keys are not accepted")
+		}
+		k, err := a.keys.FindActiveAPIKeyByHash(ctx, HashAPIKey(key))
+		if errors.GetType(err) == errors.NotFound {
+			return Principal{}, errors.Unauthorized.New("invalid API key")
+		}
+		if err != nil {
+			return Principal{}, err
+		}
+		return Principal{Subject: k.Subject, Method: MethodAPIKey}, nil
+	}
+
+	return Principal{}, errors.Unauthorized.New("missing credentials").
+		AddContext("Authorization", "a bearer token or an "+HeaderAPIKey+" header is required")
+}
+
+// Middleware rejects calls without valid credentials and stores the
+// principal in the context, also recording it as the actor of the request.
+// Calls already authenticated by HTTPMiddleware are not checked again.
+func Middleware(a *Authenticator) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			p, ok := PrincipalFromContext(ctx)
+			if !ok {
+				var err error
+				if p, err = a.Authenticate(ctx); err != nil {
+					return nil, err
+				}
+			}
+			ctx = WithPrincipal(ctx, p)
+			ctx = request.WithActor(ctx, p.Subject)
+			return next(ctx, req)
+		}
+	}
+}
+
+// HTTPMiddleware authenticates requests before they are routed, storing the
+// principal in the request context for the HTTP middlewares running ahead of
+// the endpoints, such as the idempotency one. Requests without valid
+// credentials are passed on as they are, for Middleware to reject.
+func HTTPMiddleware(a *Authenticator) func(http.Handler) http.Handler {
+	toContext := HTTPToContext()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if p, err := a.Authenticate(toContext(r.Context(), r)); err == nil {
+				r = r.WithContext(WithPrincipal(r.Context(), p))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// Package authz decides what an authenticated caller may do. Every caller
+// has a role, which grants a set of permissions either on all resources or
+// only on the ones belonging to the caller's own customer record.
+package authz
+
+import (
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"reservations/pkg/auth"
+	errors "reservations/pkg/error"
+	"reservations/pkg/tenant"
+)
+
+// Role names a set of permissions.
+type Role string
+
+const (
+	// RoleGuest is a customer acting on their own reservations.
+	RoleGuest Role = "guest"
+	// RoleStaff runs the floor and may see and change all reservations.
+	RoleStaff Role = "staff"
+	// RoleManager may additionally purge data and administer the system.
+	RoleManager Role = "manager"
+)
+
+// Permission names an action on a kind of resource.
+type Permission string
+
+const (
+	PermCustomerRead     Permission = "customer:read"
+	PermCustomerWrite    Permission = "customer:write"
+	PermCustomerPurge    Permission = "customer:purge"
+	PermReservationRead  Permission = "reservation:read"
+	PermReservationWrite Permission = "reservation:write"
+	PermAuditRead        Permission = "audit:read"
+	PermWebhookManage    Permission = "webhook:manage"
+	PermRoleManage       Permission = "role:manage"
+	PermFloorRead        Permission = "floor:read"
+	PermFloorManage      Permission = "floor:manage"
+	PermAreaClose        Permission = "area:close"
+	PermFloorHost        Permission = "floor:host"
+	PermHoldWrite        Permission = "hold:write"
+	PermFeeWaive         Permission = "fee:waive"
+	PermDepositSettle    Permission = "deposit:settle"
+)
+
+// Scope tells which resources a permission applies to.
+type Scope int
+
+const (
+	ScopeNone Scope = iota
+	// ScopeOwn limits a permission to the caller's own customer record and
+	// its reservations.
+	ScopeOwn
+	ScopeAll
+)
+
+var policy = map[Role]map[Permission]Scope{
+	RoleGuest: {
+		PermCustomerRead:     ScopeOwn,
+		PermReservationRead:  ScopeOwn,
+		PermReservationWrite: ScopeOwn,
+		PermFloorRead:        ScopeAll,
+		PermHoldWrite:        ScopeAll,
+	},
+	RoleStaff: {
+		PermCustomerRead:     ScopeAll,
+		PermCustomerWrite:    ScopeAll,
+		PermReservationRead:  ScopeAll,
+		PermReservationWrite: ScopeAll,
+		PermFloorRead:        ScopeAll,
+		PermAreaClose:        ScopeAll,
+		PermFloorHost:        ScopeAll,
+		PermHoldWrite:        ScopeAll,
+		PermFeeWaive:         ScopeAll,
+		PermDepositSettle:    ScopeAll,
+	},
+	RoleManager: {
+		PermCustomerRead:     ScopeAll,
+		PermCustomerWrite:    ScopeAll,
+		PermCustomerPurge:    ScopeAll,
+		PermReservationRead:  ScopeAll,
+		PermReservationWrite: ScopeAll,
+		PermAuditRead:        ScopeAll,
+		PermWebhookManage:    ScopeAll,
+		PermRoleManage:       ScopeAll,
+		PermFloorRead:        ScopeAll,
+		PermFloorManage:      ScopeAll,
+		PermAreaClose:        ScopeAll,
+		PermFloorHost:        ScopeAll,
+		PermHoldWrite:        ScopeAll,
+		PermFeeWaive:         ScopeAll,
+		PermDepositSettle:    ScopeAll,
+	},
+}
+
+// Known reports whether r is one of the defined roles.
+func (r Role) Known() bool {
+	_, ok := policy[r]
+	return ok
+}
+
+// Scope returns the resources on which r grants p.
+func (r Role) Scope(p Permission) Scope {
+	return policy[r][p]
+}
+
+// Claims of a JWT read when a subject has no role assignment.
+const (
+	ClaimRole       = "role"
+	ClaimCustomerID = "customer_id"
+)
+
+// Grant is the role a caller acts in.
+type Grant struct {
+	Subject string `json:"subject"`
+	Role    Role   `json:"role"`
+	// CustomerID links a guest to their customer record, 0 if none.
+	CustomerID int `json:"customerId"`
+}
+
+type contextKey int
+
+const grantKey contextKey = iota
+
+// WithGrant returns a copy of ctx carrying g.
+func WithGrant(ctx context.Context, g Grant) context.Context {
+	return context.WithValue(ctx, grantKey, g)
+}
+
+// GrantFromContext returns the role the caller was authorized in, if any.
+// There is none when authorization is disabled.
+func GrantFromContext(ctx context.Context) (Grant, bool) {
+	g, ok := ctx.Value(grantKey).(Grant)
+	return g, ok
+}
+
+// OwnerFunc returns the ID of the customer owning the resource a request
+// addresses.
+type OwnerFunc func(ctx context.Context, request interface{}) (int, error)
+
+// Authorizer checks callers against the policy.
+type Authorizer interface {
+	// Require returns an endpoint middleware rejecting callers whose role
+	// does not grant p. owner resolves the customer owning the addressed
+	// resource for roles granting p on their own resources only; nil means
+	// the request addresses no single customer.
+	Require(p Permission, owner OwnerFunc) endpoint.Middleware
+}
+
+type authorizer struct {
+	repo Repository
+}
+
+// NewAuthorizer returns an Authorizer taking roles from the assignments in
+// repo for the venue of the request and, for subjects without one, from the
+// role and customer_id claims of their token, which only apply in the venue
+// the token is bound to. Callers with neither are guests.
+func NewAuthorizer(repo Repository) Authorizer {
+	return &authorizer{repo: repo}
+}
+
+func (a *authorizer) Require(p Permission, owner OwnerFunc) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			g, err := a.grant(ctx)
+			if err != nil {
+				return nil, err
+			}
+			ctx = WithGrant(ctx, g)
+
+			switch g.Role.Scope(p) {
+			case ScopeAll:
+				return next(ctx, request)
+			case ScopeOwn:
+				if owner != nil && g.CustomerID != 0 {
+					cID, err := owner(ctx, request)
+					if err != nil {
+						return nil, err
+					}
+					if cID == g.CustomerID {
+						return next(ctx, request)
+					}
+				}
+				return nil, errors.Forbidden.Newf("role %s grants %s on its own customer record only", g.Role, p).
+					AddContext("permission", string(p))
+			}
+
+			return nil, errors.Forbidden.Newf("role %s does not grant %s", g.Role, p).
+				AddContext("permission", string(p))
+		}
+	}
+}
+
+func (a *authorizer) grant(ctx context.Context) (Grant, error) {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return Grant{}, errors.Unauthorized.New("caller is not authenticated")
+	}
+
+	vID := tenant.VenueFromContext(ctx)
+	as, err := a.repo.FindAssignmentBySubject(ctx, vID, p.Subject)
+	if err == nil {
+		return Grant{Subject: p.Subject, Role: as.Role, CustomerID: as.CustomerID}, nil
+	}
+	if errors.GetType(err) != errors.NotFound {
+		return Grant{}, err
+	}
+
+	g := Grant{Subject: p.Subject, Role: RoleGuest}
+	if !claimsVenue(ctx, p, vID) {
+		return g, nil
+	}
+	if role, ok := p.Claims[ClaimRole].(string); ok && role != "" {
+		g.Role = Role(role)
+	}
+	// JSON numbers decode as float64.
+	if cID, ok := p.Claims[ClaimCustomerID].(float64); ok {
+		g.CustomerID = int(cID)
+	}
+	return g, nil
+}
+
+// claimsVenue reports whether the role and customer claimed by the token of
+// p apply in the venue vID of ctx: the venue the token is bound to by its
+// venue claim, as resolved by tenant.Middleware, or the default venue for
+// tokens bound to none. Customer IDs are only unique within a venue.
+func claimsVenue(ctx context.Context, p auth.Principal, vID int) bool {
+	venue, _ := p.Claims[tenant.ClaimVenue].(string)
+	if venue == "" {
+		return vID == tenant.DefaultVenueID
+	}
+	return venue == tenant.VenueSlugFromContext(ctx)
+}
+
+// AllowAll returns an Authorizer letting every call through, used when
+// authentication is disabled and there is no caller to check.
+func AllowAll() Authorizer {
+	return allowAll{}
+}
+
+type allowAll struct{}
+
+func (allowAll) Require(Permission, OwnerFunc) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint { return next }
+}
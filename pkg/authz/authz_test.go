@@ -0,0 +1,69 @@
+package authz
+
+import (
+	"context"
+	"reservations/pkg/auth"
+	errors "reservations/pkg/error"
+	"reservations/pkg/tenant"
+	"testing"
+)
+
+// assignments keeps role assignments by venue and subject, for the
+// authorizer only.
+type assignments struct {
+	Repository
+	roles map[int]map[string]Assignment
+}
+
+func (r assignments) FindAssignmentBySubject(_ context.Context, vID int, subject string) (Assignment, error) {
+	if a, ok := r.roles[vID][subject]; ok {
+		return a, nil
+	}
+	return Assignment{}, errors.NotFound.Newf("no role assigned to %s", subject)
+}
+
+// requestFor returns the context of a request by a caller with the given
+// token claims, as confined by tenant.Middleware to the venue vID, known
+// by slug.
+func requestFor(claims map[string]interface{}, vID int, slug string) context.Context {
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "alice", Method: auth.MethodJWT, Claims: claims})
+	if slug != "" {
+		ctx = tenant.WithVenueSlug(ctx, slug)
+	}
+	return tenant.WithVenue(ctx, vID)
+}
+
+func TestGrantScopesClaimsToTheTokenVenue(t *testing.T) {
+	a := &authorizer{repo: assignments{roles: map[int]map[string]Assignment{
+		3: {"alice": {VenueID: 3, Subject: "alice", Role: RoleStaff}},
+	}}}
+	manager := func(venue string) map[string]interface{} {
+		claims := map[string]interface{}{ClaimRole: "manager", ClaimCustomerID: float64(7)}
+		if venue != "" {
+			claims[tenant.ClaimVenue] = venue
+		}
+		return claims
+	}
+
+	for _, tc := range []struct {
+		name string
+		ctx  context.Context
+		want Grant
+	}{
+		{"bound token in its venue", requestFor(manager("north"), 2, "north"), Grant{Subject: "alice", Role: RoleManager, CustomerID: 7}},
+		{"bound token elsewhere", requestFor(manager("north"), 4, "south"), Grant{Subject: "alice", Role: RoleGuest}},
+		{"bound token in the default venue", requestFor(manager("north"), tenant.DefaultVenueID, ""), Grant{Subject: "alice", Role: RoleGuest}},
+		{"unbound token in the default venue", requestFor(manager(""), tenant.DefaultVenueID, ""), Grant{Subject: "alice", Role: RoleManager, CustomerID: 7}},
+		{"unbound token elsewhere", requestFor(manager(""), 2, "north"), Grant{Subject: "alice", Role: RoleGuest}},
+		{"assignment", requestFor(manager(""), 3, "east"), Grant{Subject: "alice", Role: RoleStaff}},
+	} {
+		g, err := a.grant(tc.ctx)
+		if err != nil || g != tc.want {
+			t.Errorf("%s: grant = %+v, %v, want %+v", tc.name, g, err, tc.want)
+		}
+	}
+
+	if _, err := a.grant(context.Background()); errors.GetType(err) != errors.Unauthorized {
+		t.Errorf("grant without a principal = %v, want an Unauthorized", err)
+	}
+}
@@ -0,0 +1,161 @@
+package authz
+
+import (
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"reservations/pkg/storage"
+)
+
+type Endpoints struct {
+	AssignRoleEndpoint        endpoint.Endpoint
+	UnassignRoleEndpoint      endpoint.Endpoint
+	GetAllAssignmentsEndpoint endpoint.Endpoint
+	GetAssignmentEndpoint     endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns the service endpoints, each checked against
+// the policy by az and wrapped in the given middlewares, the first one
+// outermost.
+func MakeServerEndpoints(s Service, az Authorizer, mw ...endpoint.Middleware) Endpoints {
+	e := Endpoints{
+		AssignRoleEndpoint:        az.Require(PermRoleManage, nil)(MakeAssignRoleEndpoint(s)),
+		UnassignRoleEndpoint:      az.Require(PermRoleManage, nil)(MakeUnassignRoleEndpoint(s)),
+		GetAllAssignmentsEndpoint: az.Require(PermRoleManage, nil)(MakeGetAllAssignmentsEndpoint(s)),
+		GetAssignmentEndpoint:     az.Require(PermRoleManage, nil)(MakeGetAssignmentEndpoint(s)),
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		m := mw[i]
+		e.AssignRoleEndpoint = m(e.AssignRoleEndpoint)
+		e.UnassignRoleEndpoint = m(e.UnassignRoleEndpoint)
+		e.GetAllAssignmentsEndpoint = m(e.GetAllAssignmentsEndpoint)
+		e.GetAssignmentEndpoint = m(e.GetAssignmentEndpoint)
+	}
+	return e
+}
+
+type assignRoleRequest struct {
+	Assignment *Assignment
+}
+
+type assignRoleResponse struct {
+	Assignment *Assignment `json:"assignment,omitempty"`
+	Err        error       `json:"err,omitempty"`
+}
+
+func (r assignRoleResponse) HTTPError() error { return r.Err }
+
+// AssignRole godoc
+// @Summary Assign a role to a subject
+// @Description Assign a role to a subject, replacing the one it had
+// @Tags role
+// @Param subject path string true "Subject"
+// @Param assignment body authz.Assignment true "Role Assignment"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} authz.Assignment
+// @Router /role/{subject} [put]
+func MakeAssignRoleEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(assignRoleRequest)
+		a, e := s.AssignRole(ctx, req.Assignment)
+		return assignRoleResponse{
+			Assignment: a,
+			Err:        e,
+		}, nil
+	}
+}
+
+type unassignRoleRequest struct {
+	Subject string
+}
+
+type unassignRoleResponse struct {
+	Err error `json:"err,omitempty"`
+}
+
+func (r unassignRoleResponse) HTTPError() error { return r.Err }
+
+// UnassignRole godoc
+// @Summary Remove the role assignment of a subject
+// @Description Remove the role assignment of a subject, who falls back to the role claim of their token
+// @Tags role
+// @Param subject path string true "Subject"
+// @Accept  json
+// @Produce  json
+// @Router /role/{subject} [delete]
+func MakeUnassignRoleEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(unassignRoleRequest)
+		e := s.UnassignRole(ctx, req.Subject)
+		return unassignRoleResponse{
+			Err: e,
+		}, nil
+	}
+}
+
+type getAllAssignmentsRequest struct {
+	Limit  uint
+	Offset uint
+}
+
+type getAllAssignmentsResponse struct {
+	Assignments []Assignment `json:"assignments,omitempty"`
+	Err         error        `json:"err,omitempty"`
+}
+
+func (r getAllAssignmentsResponse) HTTPError() error { return r.Err }
+
+// GetAllAssignments godoc
+// @Summary List role assignments
+// @Description List role assignments ordered by subject
+// @Tags role
+// @Param limit query int false "Assignment count limit" default(100)
+// @Param offset query int false "Assignment count offset" default(0)
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} authz.Assignment
+// @Router /roles [get]
+func MakeGetAllAssignmentsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getAllAssignmentsRequest)
+		aa, e := s.GetAllAssignments(ctx, &storage.QueryOptions{
+			Limit:  req.Limit,
+			Offset: req.Offset,
+		})
+		return getAllAssignmentsResponse{
+			Assignments: aa,
+			Err:         e,
+		}, nil
+	}
+}
+
+type getAssignmentRequest struct {
+	Subject string
+}
+
+type getAssignmentResponse struct {
+	Assignment Assignment `json:"assignment"`
+	Err        error      `json:"err,omitempty"`
+}
+
+func (r getAssignmentResponse) HTTPError() error { return r.Err }
+
+// GetAssignment godoc
+// @Summary Get the role assignment of a subject
+// @Description Get the role assignment of a subject
+// @Tags role
+// @Param subject path string true "Subject"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} authz.Assignment
+// @Router /role/{subject} [get]
+func MakeGetAssignmentEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getAssignmentRequest)
+		a, e := s.GetAssignment(ctx, req.Subject)
+		return getAssignmentResponse{
+			Assignment: a,
+			Err:        e,
+		}, nil
+	}
+}
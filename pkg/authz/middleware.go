@@ -0,0 +1,53 @@
+package authz
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"reservations/pkg/storage"
+	"time"
+)
+
+// Middleware describes a service (as opposed to endpoint) middleware.
+type Middleware func(Service) Service
+
+func LoggingMiddleware(logger log.Logger) Middleware {
+	return func(next Service) Service {
+		return &loggingMiddleware{
+			next:   next,
+			logger: logger,
+		}
+	}
+}
+
+type loggingMiddleware struct {
+	next   Service
+	logger log.Logger
+}
+
+func (mw loggingMiddleware) AssignRole(ctx context.Context, a *Assignment) (result *Assignment, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "AssignRole", "subject", a.Subject, "role", a.Role, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.AssignRole(ctx, a)
+}
+
+func (mw loggingMiddleware) UnassignRole(ctx context.Context, subject string) (err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "UnassignRole", "subject", subject, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.UnassignRole(ctx, subject)
+}
+
+func (mw loggingMiddleware) GetAllAssignments(ctx context.Context, opts *storage.QueryOptions) (result []Assignment, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "GetAllAssignments", "limit", opts.Limit, "offset", opts.Offset, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetAllAssignments(ctx, opts)
+}
+
+func (mw loggingMiddleware) GetAssignment(ctx context.Context, subject string) (result Assignment, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "GetAssignment", "subject", subject, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetAssignment(ctx, subject)
+}
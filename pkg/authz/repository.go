@@ -0,0 +1,115 @@
+package authz
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v7"
+	"github.com/doug-martin/goqu/v7/exec"
+	errors "reservations/pkg/error"
+	"reservations/pkg/storage"
+	"reservations/pkg/tracing"
+	"time"
+)
+
+type Repository interface {
+	SetAssignment(ctx context.Context, a *Assignment) (*Assignment, error)
+	RemoveAssignment(ctx context.Context, vID int, subject string) error
+	FindAllAssignments(ctx context.Context, vID int, opts *storage.QueryOptions) ([]Assignment, error)
+	FindAssignmentBySubject(ctx context.Context, vID int, subject string) (Assignment, error)
+}
+
+type roleRepository struct {
+	db storage.Persistence
+}
+
+func NewRoleRepository(db storage.Persistence) Repository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) SetAssignment(ctx context.Context, a *Assignment) (_ *Assignment, err error) {
+	_, span := tracing.StartSpan(ctx, "roleRepository.SetAssignment", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	now := time.Now().Unix()
+	where := goqu.Ex{"venue_id": a.VenueID, "subject": a.Subject}
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		var created int64
+		found, err := tx.From("role_assignment").Select("created").
+			Where(where).ScanVal(&created)
+		if err != nil {
+			return err
+		}
+
+		a.LastUpdated = now
+		if found {
+			a.Created = created
+			_, err = tx.From("role_assignment").Where(where).Update(goqu.Record{
+				"role":         a.Role,
+				"customer_id":  a.CustomerID,
+				"last_updated": a.LastUpdated,
+			}).Exec()
+			return err
+		}
+
+		a.Created = now
+		_, err = tx.From("role_assignment").Insert(a).Exec()
+		return err
+	})
+	if err != nil {
+		return nil, errors.DBError.Wrapf(err, "error assigning role to %s", a.Subject)
+	}
+
+	return a, nil
+}
+
+func (r *roleRepository) RemoveAssignment(ctx context.Context, vID int, subject string) (err error) {
+	_, span := tracing.StartSpan(ctx, "roleRepository.RemoveAssignment", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	_, err = r.db.Tx(func(tx *goqu.TxDatabase) exec.QueryExecutor {
+		return tx.From("role_assignment").Where(goqu.Ex{"venue_id": vID, "subject": subject}).Delete()
+	})
+
+	if err != nil {
+		return errors.DBError.Wrapf(err, "error removing role assignment of %s", subject)
+	}
+	return nil
+}
+
+func (r *roleRepository) FindAllAssignments(ctx context.Context, vID int, opts *storage.QueryOptions) (aa []Assignment, err error) {
+	_, span := tracing.StartSpan(ctx, "roleRepository.FindAllAssignments", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	opts.Limit = r.db.PageSize(opts.Limit)
+
+	err = r.db.DB.From("role_assignment").
+		Where(goqu.Ex{"venue_id": vID}).
+		Order(goqu.C("subject").Asc()).
+		Limit(opts.Limit).
+		Offset(opts.Offset).
+		ScanStructs(&aa)
+
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error getting all role assignments")
+	}
+	return aa, nil
+}
+
+func (r *roleRepository) FindAssignmentBySubject(ctx context.Context, vID int, subject string) (a Assignment, err error) {
+	_, span := tracing.StartSpan(ctx, "roleRepository.FindAssignmentBySubject", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	found, err := r.db.DB.From("role_assignment").Prepared(true).
+		Where(goqu.Ex{"venue_id": vID, "subject": subject}).
+		ScanStruct(&a)
+
+	if err != nil {
+		return a, errors.DBError.Wrapf(err, "error getting role assignment of %s", subject)
+	}
+
+	if !found {
+		return a, errors.NotFound.Newf("no role assigned to %s", subject)
+	}
+
+	return a, nil
+}
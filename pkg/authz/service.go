@@ -0,0 +1,72 @@
+package authz
+
+import (
+	"context"
+	errors "reservations/pkg/error"
+	"reservations/pkg/storage"
+	"reservations/pkg/tenant"
+)
+
+// Service manages the roles assigned to subjects.
+type Service interface {
+	AssignRole(ctx context.Context, a *Assignment) (*Assignment, error)
+	UnassignRole(ctx context.Context, subject string) error
+	GetAllAssignments(ctx context.Context, opts *storage.QueryOptions) ([]Assignment, error)
+	GetAssignment(ctx context.Context, subject string) (Assignment, error)
+}
+
+// Assignment gives a subject, as authenticated by a token or API key, a
+// role in a venue. It takes precedence over the role claim of a token.
+type Assignment struct {
+	VenueID int    `json:"venueId" db:"venue_id"`
+	Subject string `json:"subject"`
+	Role    Role   `json:"role" swaggertype:"string" enums:"guest,staff,manager"`
+	// CustomerID links a guest to their customer record.
+	CustomerID  int   `json:"customerId" db:"customer_id"`
+	Created     int64 `json:"created"`
+	LastUpdated int64 `json:"lastUpdated" db:"last_updated"`
+}
+
+type roleService struct {
+	roleRepo Repository
+}
+
+func NewRoleService(repo Repository) Service {
+	return &roleService{
+		roleRepo: repo,
+	}
+}
+
+func (s *roleService) AssignRole(ctx context.Context, a *Assignment) (*Assignment, error) {
+	if a.Subject == "" {
+		return nil, errors.ValidationError.New("no subject given").
+			AddContext("subject", "a role must be assigned to a subject")
+	}
+	if !a.Role.Known() {
+		return nil, errors.ValidationError.Newf("unknown role %q", a.Role).
+			AddContext("role", "must be one of guest, staff or manager")
+	}
+	if a.CustomerID < 0 {
+		return nil, errors.ValidationError.Newf("invalid customer ID %d", a.CustomerID).
+			AddContext("customerId", "must not be negative")
+	}
+
+	a.VenueID = tenant.VenueFromContext(ctx)
+	return s.roleRepo.SetAssignment(ctx, a)
+}
+
+func (s *roleService) UnassignRole(ctx context.Context, subject string) error {
+	vID := tenant.VenueFromContext(ctx)
+	if _, err := s.roleRepo.FindAssignmentBySubject(ctx, vID, subject); err != nil {
+		return err
+	}
+	return s.roleRepo.RemoveAssignment(ctx, vID, subject)
+}
+
+func (s *roleService) GetAllAssignments(ctx context.Context, opts *storage.QueryOptions) ([]Assignment, error) {
+	return s.roleRepo.FindAllAssignments(ctx, tenant.VenueFromContext(ctx), opts)
+}
+
+func (s *roleService) GetAssignment(ctx context.Context, subject string) (Assignment, error) {
+	return s.roleRepo.FindAssignmentBySubject(ctx, tenant.VenueFromContext(ctx), subject)
+}
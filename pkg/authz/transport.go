@@ -0,0 +1,79 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"net/http"
+	"reservations/pkg/transport"
+)
+
+func MakeHTTPHandler(r *mux.Router, s Service, az Authorizer, logger log.Logger, mw ...endpoint.Middleware) *mux.Router {
+	e := MakeServerEndpoints(s, az, mw...)
+
+	options := httpjson.DefaultServerOptions(logger)
+
+	r.Methods("PUT").Path("/role/{subject}").
+		Handler(httptransport.NewServer(
+			e.AssignRoleEndpoint,
+			decodeAssignRoleRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("DELETE").Path("/role/{subject}").
+		Handler(httptransport.NewServer(
+			e.UnassignRoleEndpoint,
+			decodeUnassignRoleRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("GET").Path("/role/{subject}").
+		Handler(httptransport.NewServer(
+			e.GetAssignmentEndpoint,
+			decodeGetAssignmentRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("GET").Path("/roles").
+		Handler(httptransport.NewServer(
+			e.GetAllAssignmentsEndpoint,
+			decodeGetAllAssignmentsRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	return r
+}
+
+func decodeAssignRoleRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req assignRoleRequest
+	if e := json.NewDecoder(r.Body).Decode(&req.Assignment); e != nil {
+		return nil, e
+	}
+	if req.Assignment == nil {
+		req.Assignment = &Assignment{}
+	}
+	req.Assignment.Subject = mux.Vars(r)["subject"]
+	return req, nil
+}
+
+func decodeUnassignRoleRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	return unassignRoleRequest{Subject: mux.Vars(r)["subject"]}, nil
+}
+
+func decodeGetAssignmentRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	return getAssignmentRequest{Subject: mux.Vars(r)["subject"]}, nil
+}
+
+func decodeGetAllAssignmentsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	return getAllAssignmentsRequest{
+		Limit:  httpjson.ParseUintQueryParam(r, "limit"),
+		Offset: httpjson.ParseUintQueryParam(r, "offset"),
+	}, nil
+}
@@ -0,0 +1,407 @@
+// Package config loads the service configuration from defaults, an optional
+// YAML or JSON file, environment variables and command line flags, each
+// source overriding the previous one.
+package config
+
+import (
+	"flag"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"net/url"
+	"os"
+	errors "reservations/pkg/error"
+	"reservations/pkg/payment"
+	"reservations/pkg/policy"
+	"reservations/pkg/storage"
+	"reservations/pkg/tracing"
+	"strings"
+	"time"
+)
+
+// EnvPrefix is prepended to the upper cased flag name, with dots and dashes
+// replaced by underscores, to form the environment variable of a setting,
+// e.g. RESERVATIONS_HTTP_ADDR for -http.addr.
+const EnvPrefix = "RESERVATIONS_"
+
+// Config holds every tunable of the reservation service.
+type Config struct {
+	HTTP        HTTP        `yaml:"http"`
+	GRPC        GRPC        `yaml:"grpc"`
+	DB          DB          `yaml:"db"`
+	Idempotency Idempotency `yaml:"idempotency"`
+	Outbox      Outbox      `yaml:"outbox"`
+	Webhook     Webhook     `yaml:"webhook"`
+	Metrics     Metrics     `yaml:"metrics"`
+	Tracing     Tracing     `yaml:"tracing"`
+	Auth        Auth        `yaml:"auth"`
+	Tenant      Tenant      `yaml:"tenant"`
+	Seating     Seating     `yaml:"seating"`
+	HostStand   HostStand   `yaml:"hoststand"`
+	Holds       Holds       `yaml:"holds"`
+	Series      Series      `yaml:"series"`
+	Payment     Payment     `yaml:"payment"`
+	// Policy restricts bookings by channel and shift. Having lists, it is
+	// only set in the configuration file.
+	Policy policy.Policy `yaml:"policy"`
+}
+
+type HTTP struct {
+	Addr       string `yaml:"addr"`
+	SwaggerURL string `yaml:"swaggerURL"`
+}
+
+type GRPC struct {
+	Addr string `yaml:"addr"`
+}
+
+type DB struct {
+	Dialect      string `yaml:"dialect"`
+	DSN          string `yaml:"dsn"`
+	Migrate      bool   `yaml:"migrate"`
+	DefaultLimit uint   `yaml:"defaultLimit"`
+	MaxLimit     uint   `yaml:"maxLimit"`
+}
+
+type Idempotency struct {
+	TTL time.Duration `yaml:"ttl"`
+}
+
+type Outbox struct {
+	Interval time.Duration `yaml:"interval"`
+}
+
+type Webhook struct {
+	Interval    time.Duration `yaml:"interval"`
+	MaxAttempts int           `yaml:"maxAttempts"`
+	Backoff     time.Duration `yaml:"backoff"`
+	MaxBackoff  time.Duration `yaml:"maxBackoff"`
+	Timeout     time.Duration `yaml:"timeout"`
+}
+
+type Metrics struct {
+	Interval time.Duration `yaml:"interval"`
+}
+
+type Tracing struct {
+	Exporter    string  `yaml:"exporter"`
+	Endpoint    string  `yaml:"endpoint"`
+	ServiceName string  `yaml:"serviceName"`
+	SampleRatio float64 `yaml:"sampleRatio"`
+}
+
+type Auth struct {
+	Enabled  bool   `yaml:"enabled"`
+	JWKSFile string `yaml:"jwksFile"`
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+}
+
+type Tenant struct {
+	Required bool `yaml:"required"`
+}
+
+type Seating struct {
+	Duration time.Duration `yaml:"duration"`
+	// Soon is how long before a booking starts its table shows as reserved
+	// on the floor plan.
+	Soon time.Duration `yaml:"soon"`
+}
+
+type Holds struct {
+	// TTL is how long a hold keeps its table before it has to be redeemed.
+	TTL time.Duration `yaml:"ttl"`
+	// SweepInterval is how often expired holds are deleted.
+	SweepInterval time.Duration `yaml:"sweepInterval"`
+	// GuestLimit is how many unexpired holds a guest may have at once, any
+	// number if 0.
+	GuestLimit int `yaml:"guestLimit"`
+}
+
+type Series struct {
+	// Horizon is how far ahead the occurrences of recurring reservations
+	// are booked.
+	Horizon time.Duration `yaml:"horizon"`
+	// Interval is how often further occurrences are booked as time goes by.
+	Interval time.Duration `yaml:"interval"`
+}
+
+type Payment struct {
+	// Gateway takes the deposits: fake, accepting every payment method
+	// but pm_card_declined, or stripe.
+	Gateway   string `yaml:"gateway"`
+	StripeURL string `yaml:"stripeURL"`
+	StripeKey string `yaml:"stripeKey"`
+	// DepositTTL is how long after booking a deposit is due, before the
+	// reservation is cancelled.
+	DepositTTL time.Duration `yaml:"depositTTL"`
+	// ExpiryInterval is how often reservations with overdue deposits are
+	// cancelled.
+	ExpiryInterval time.Duration `yaml:"expiryInterval"`
+	// SettleInterval is how often the settlements of deposits the gateway
+	// failed are retried.
+	SettleInterval time.Duration `yaml:"settleInterval"`
+}
+
+type HostStand struct {
+	// LockTTL is how long a host keeps the lock on a reservation being
+	// edited without refreshing it.
+	LockTTL time.Duration `yaml:"lockTTL"`
+}
+
+// Default returns the configuration used when nothing is overridden.
+func Default() Config {
+	return Config{
+		HTTP: HTTP{
+			Addr:       ":8080",
+			SwaggerURL: "http://localhost:8080/swagger/doc.json",
+		},
+		GRPC: GRPC{
+			Addr: ":8081",
+		},
+		DB: DB{
+			Dialect:      storage.SQLite,
+			DSN:          "reservations.db",
+			Migrate:      true,
+			DefaultLimit: storage.DefaultLimit,
+			MaxLimit:     storage.MaxLimit,
+		},
+		Idempotency: Idempotency{
+			TTL: 24 * time.Hour,
+		},
+		Outbox: Outbox{
+			Interval: time.Second,
+		},
+		Webhook: Webhook{
+			Interval:    time.Second,
+			MaxAttempts: 8,
+			Backoff:     30 * time.Second,
+			MaxBackoff:  time.Hour,
+			Timeout:     10 * time.Second,
+		},
+		Metrics: Metrics{
+			Interval: 15 * time.Second,
+		},
+		Tracing: Tracing{
+			Exporter:    tracing.ExporterNone,
+			Endpoint:    "localhost:4318",
+			ServiceName: "reservations",
+			SampleRatio: 1,
+		},
+		Auth: Auth{
+			Enabled: true,
+		},
+		Seating: Seating{
+			Duration: 2 * time.Hour,
+			Soon:     30 * time.Minute,
+		},
+		HostStand: HostStand{
+			LockTTL: time.Minute,
+		},
+		Holds: Holds{
+			TTL:           10 * time.Minute,
+			SweepInterval: time.Minute,
+			GuestLimit:    2,
+		},
+		Series: Series{
+			Horizon:  60 * 24 * time.Hour,
+			Interval: time.Hour,
+		},
+		Payment: Payment{
+			Gateway:        payment.GatewayFake,
+			StripeURL:      payment.DefaultStripeURL,
+			DepositTTL:     30 * time.Minute,
+			ExpiryInterval: time.Minute,
+			SettleInterval: time.Minute,
+		},
+	}
+}
+
+// register binds a flag to every setting of c.
+func (c *Config) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.HTTP.Addr, "http.addr", c.HTTP.Addr, "HTTP listen address")
+	fs.StringVar(&c.HTTP.SwaggerURL, "http.swagger-url", c.HTTP.SwaggerURL, "URL of the API definition used by the Swagger UI")
+	fs.StringVar(&c.GRPC.Addr, "grpc.addr", c.GRPC.Addr, "gRPC listen address")
+	fs.StringVar(&c.DB.Dialect, "db.dialect", c.DB.Dialect, "Database dialect: sqlite3 or postgres")
+	fs.StringVar(&c.DB.DSN, "db.dsn", c.DB.DSN, "Database data source name, e.g. a file for sqlite3 or a postgres:// URL")
+	fs.BoolVar(&c.DB.Migrate, "db.migrate", c.DB.Migrate, "Apply pending schema migrations on startup")
+	fs.UintVar(&c.DB.DefaultLimit, "db.default-limit", c.DB.DefaultLimit, "Page size used when a listing request sets no limit")
+	fs.UintVar(&c.DB.MaxLimit, "db.max-limit", c.DB.MaxLimit, "Largest page size returned by a listing request, whatever limit it sets")
+	fs.DurationVar(&c.Idempotency.TTL, "idempotency.ttl", c.Idempotency.TTL, "How long responses to requests with an Idempotency-Key are kept for replay")
+	fs.DurationVar(&c.Outbox.Interval, "outbox.interval", c.Outbox.Interval, "How often the outbox is polled for events to publish")
+	fs.DurationVar(&c.Webhook.Interval, "webhook.interval", c.Webhook.Interval, "How often due webhook deliveries are sent")
+	fs.IntVar(&c.Webhook.MaxAttempts, "webhook.max-attempts", c.Webhook.MaxAttempts, "Delivery attempts before a webhook delivery is dead-lettered")
+	fs.DurationVar(&c.Webhook.Backoff, "webhook.backoff", c.Webhook.Backoff, "Delay before the first webhook retry, doubled on every further attempt")
+	fs.DurationVar(&c.Webhook.MaxBackoff, "webhook.max-backoff", c.Webhook.MaxBackoff, "Upper bound of the delay between webhook retries")
+	fs.DurationVar(&c.Webhook.Timeout, "webhook.timeout", c.Webhook.Timeout, "Timeout of a single webhook call")
+	fs.DurationVar(&c.Metrics.Interval, "metrics.interval", c.Metrics.Interval, "How often database pool and booking gauges are refreshed")
+	fs.StringVar(&c.Tracing.Exporter, "tracing.exporter", c.Tracing.Exporter, "Trace exporter: none, stdout or otlp")
+	fs.StringVar(&c.Tracing.Endpoint, "tracing.endpoint", c.Tracing.Endpoint, "host:port of the OTLP HTTP collector used by the otlp exporter")
+	fs.StringVar(&c.Tracing.ServiceName, "tracing.service-name", c.Tracing.ServiceName, "Service name reported with every span")
+	fs.Float64Var(&c.Tracing.SampleRatio, "tracing.sample-ratio", c.Tracing.SampleRatio, "Fraction of new traces that are sampled, between 0 and 1")
+	fs.BoolVar(&c.Auth.Enabled, "auth.enabled", c.Auth.Enabled, "Require a bearer token or API key on every API call")
+	fs.StringVar(&c.Auth.JWKSFile, "auth.jwks-file", c.Auth.JWKSFile, "JSON Web Key Set used to verify bearer tokens, which are rejected when unset")
+	fs.StringVar(&c.Auth.Issuer, "auth.issuer", c.Auth.Issuer, "Required iss claim of bearer tokens")
+	fs.StringVar(&c.Auth.Audience, "auth.audience", c.Auth.Audience, "Required aud claim of bearer tokens")
+	fs.BoolVar(&c.Tenant.Required, "tenant.required", c.Tenant.Required, "Reject API calls naming no venue instead of serving them from the default venue")
+	fs.DurationVar(&c.Seating.Duration, "seating.duration", c.Seating.Duration, "How long the table allocated to a booking is held")
+	fs.DurationVar(&c.Seating.Soon, "seating.soon", c.Seating.Soon, "How long before a booking starts its table shows as reserved on the floor plan")
+	fs.DurationVar(&c.HostStand.LockTTL, "hoststand.lock-ttl", c.HostStand.LockTTL, "How long a host keeps the edit lock on a reservation without refreshing it")
+	fs.DurationVar(&c.Holds.TTL, "holds.ttl", c.Holds.TTL, "How long a hold keeps its table before it has to be redeemed")
+	fs.DurationVar(&c.Holds.SweepInterval, "holds.sweep-interval", c.Holds.SweepInterval, "How often expired holds are deleted")
+	fs.IntVar(&c.Holds.GuestLimit, "holds.guest-limit", c.Holds.GuestLimit, "How many unexpired holds a guest may have at once, 0 for any number")
+	fs.DurationVar(&c.Series.Horizon, "series.horizon", c.Series.Horizon, "How far ahead the occurrences of recurring reservations are booked")
+	fs.DurationVar(&c.Series.Interval, "series.interval", c.Series.Interval, "How often further occurrences of recurring reservations are booked")
+	fs.StringVar(&c.Payment.Gateway, "payment.gateway", c.Payment.Gateway, "Payment gateway taking deposits: fake or stripe")
+	fs.StringVar(&c.Payment.StripeURL, "payment.stripe-url", c.Payment.StripeURL, "Base URL of the Stripe API")
+	fs.StringVar(&c.Payment.StripeKey, "payment.stripe-key", c.Payment.StripeKey, "Secret key of the Stripe API")
+	fs.DurationVar(&c.Payment.DepositTTL, "payment.deposit-ttl", c.Payment.DepositTTL, "How long after booking a deposit is due")
+	fs.DurationVar(&c.Payment.ExpiryInterval, "payment.expiry-interval", c.Payment.ExpiryInterval, "How often reservations with overdue deposits are cancelled")
+	fs.DurationVar(&c.Payment.SettleInterval, "payment.settle-interval", c.Payment.SettleInterval, "How often failed settlements of deposits are retried")
+}
+
+// Load builds the configuration from args, which are parsed as flags. The
+// -config flag, or the RESERVATIONS_CONFIG environment variable, names a
+// YAML or JSON file read before the environment and the remaining flags
+// are applied.
+func Load(name string, args []string) (Config, error) {
+	flagCfg := Default()
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	file := fs.String("config", os.Getenv(EnvPrefix+"CONFIG"), "Path to a YAML or JSON configuration file")
+	flagCfg.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return flagCfg, err
+	}
+
+	cfg := Default()
+	if *file != "" {
+		if err := cfg.loadFile(*file); err != nil {
+			return cfg, err
+		}
+	}
+
+	sources := flag.NewFlagSet(name, flag.ContinueOnError)
+	cfg.register(sources)
+
+	var err error
+	sources.VisitAll(func(f *flag.Flag) {
+		if v, ok := os.LookupEnv(EnvName(f.Name)); ok && err == nil {
+			if e := sources.Set(f.Name, v); e != nil {
+				err = errors.ValidationError.Wrapf(e, "invalid value %q for %s", v, EnvName(f.Name)).
+					AddContext(EnvName(f.Name), e.Error())
+			}
+		}
+	})
+	if err != nil {
+		return cfg, err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name != "config" {
+			sources.Set(f.Name, f.Value.String())
+		}
+	})
+
+	return cfg, cfg.Validate()
+}
+
+// EnvName returns the environment variable overriding the flag with name.
+func EnvName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(flagName))
+}
+
+func (c *Config) loadFile(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "error reading configuration file %s", path)
+	}
+	// JSON is a subset of YAML, so both are handled by the YAML decoder.
+	if err := yaml.UnmarshalStrict(b, c); err != nil {
+		return errors.ValidationError.Wrapf(err, "invalid configuration file %s", path).
+			AddContext("config", err.Error())
+	}
+	return nil
+}
+
+// Validate reports the first invalid setting.
+func (c Config) Validate() error {
+	invalid := func(field, msg string) error {
+		return errors.ValidationError.Newf("invalid configuration: %s %s", field, msg).
+			AddContext(field, msg)
+	}
+
+	switch {
+	case c.HTTP.Addr == "":
+		return invalid("http.addr", "must not be empty")
+	case c.GRPC.Addr == "":
+		return invalid("grpc.addr", "must not be empty")
+	case c.DB.Dialect != storage.SQLite && c.DB.Dialect != storage.Postgres:
+		return invalid("db.dialect", "must be sqlite3 or postgres")
+	case c.DB.DSN == "":
+		return invalid("db.dsn", "must not be empty")
+	case c.DB.DefaultLimit == 0:
+		return invalid("db.default-limit", "must be positive")
+	case c.DB.MaxLimit < c.DB.DefaultLimit:
+		return invalid("db.max-limit", "must not be less than db.default-limit")
+	case c.Idempotency.TTL <= 0:
+		return invalid("idempotency.ttl", "must be positive")
+	case c.Outbox.Interval <= 0:
+		return invalid("outbox.interval", "must be positive")
+	case c.Webhook.Interval <= 0:
+		return invalid("webhook.interval", "must be positive")
+	case c.Webhook.MaxAttempts < 1:
+		return invalid("webhook.max-attempts", "must be at least 1")
+	case c.Webhook.Backoff <= 0:
+		return invalid("webhook.backoff", "must be positive")
+	case c.Webhook.MaxBackoff < c.Webhook.Backoff:
+		return invalid("webhook.max-backoff", "must not be less than webhook.backoff")
+	case c.Webhook.Timeout <= 0:
+		return invalid("webhook.timeout", "must be positive")
+	case c.Metrics.Interval <= 0:
+		return invalid("metrics.interval", "must be positive")
+	case c.Tracing.Exporter != tracing.ExporterNone && c.Tracing.Exporter != tracing.ExporterStdout && c.Tracing.Exporter != tracing.ExporterOTLP:
+		return invalid("tracing.exporter", "must be none, stdout or otlp")
+	case c.Tracing.Exporter == tracing.ExporterOTLP && c.Tracing.Endpoint == "":
+		return invalid("tracing.endpoint", "must not be empty with the otlp exporter")
+	case c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1:
+		return invalid("tracing.sample-ratio", "must be between 0 and 1")
+	case c.Seating.Duration < time.Minute:
+		return invalid("seating.duration", "must be at least 1m")
+	case c.Seating.Soon < 0:
+		return invalid("seating.soon", "must not be negative")
+	case c.HostStand.LockTTL <= 0:
+		return invalid("hoststand.lock-ttl", "must be positive")
+	case c.Holds.TTL < time.Minute:
+		return invalid("holds.ttl", "must be at least 1m")
+	case c.Holds.SweepInterval <= 0:
+		return invalid("holds.sweep-interval", "must be positive")
+	case c.Holds.GuestLimit < 0:
+		return invalid("holds.guest-limit", "must not be negative")
+	case c.Series.Horizon < 24*time.Hour:
+		return invalid("series.horizon", "must be at least 24h")
+	case c.Series.Interval <= 0:
+		return invalid("series.interval", "must be positive")
+	case c.Payment.Gateway != payment.GatewayFake && c.Payment.Gateway != payment.GatewayStripe:
+		return invalid("payment.gateway", "must be fake or stripe")
+	case c.Payment.Gateway == payment.GatewayStripe && c.Payment.StripeKey == "":
+		return invalid("payment.stripe-key", "must not be empty with the stripe gateway")
+	case c.Payment.DepositTTL < time.Minute:
+		return invalid("payment.deposit-ttl", "must be at least 1m")
+	case c.Payment.ExpiryInterval <= 0:
+		return invalid("payment.expiry-interval", "must be positive")
+	case c.Payment.SettleInterval <= 0:
+		return invalid("payment.settle-interval", "must be positive")
+	}
+
+	if err := c.Policy.Validate(); err != nil {
+		return err
+	}
+
+	if u, err := url.Parse(c.HTTP.SwaggerURL); err != nil || !u.IsAbs() {
+		return invalid("http.swagger-url", "must be an absolute URL")
+	}
+	if u, err := url.Parse(c.Payment.StripeURL); c.Payment.Gateway == payment.GatewayStripe && (err != nil || !u.IsAbs()) {
+		return invalid("payment.stripe-url", "must be an absolute URL")
+	}
+
+	return nil
+}
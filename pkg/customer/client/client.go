@@ -0,0 +1,144 @@
+// Package client provides a customer.Service implementation that calls a
+// remote customer service over HTTP.
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"net/http"
+	"net/url"
+	"reservations/pkg/customer"
+	"reservations/pkg/storage"
+	"reservations/pkg/transport"
+	"strings"
+)
+
+type client struct {
+	registerCustomer   endpoint.Endpoint
+	unregisterCustomer endpoint.Endpoint
+	getAllCustomers    endpoint.Endpoint
+	getCustomerByID    endpoint.Endpoint
+}
+
+// New returns a customer.Service backed by the HTTP API at instance, e.g.
+// "http://localhost:8080". Errors returned by the server are decoded into
+// errors.AppError values carrying the original error type.
+func New(instance string, options ...httptransport.ClientOption) (customer.Service, error) {
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	u, err := url.Parse(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	options = append(httpjson.DefaultClientOptions(), options...)
+
+	return &client{
+		registerCustomer: httptransport.NewClient(
+			"POST", u,
+			encodeRegisterCustomerRequest,
+			decodeRegisterCustomerResponse,
+			options...,
+		).Endpoint(),
+		unregisterCustomer: httptransport.NewClient(
+			"DELETE", u,
+			encodeUnregisterCustomerRequest,
+			decodeUnregisterCustomerResponse,
+			options...,
+		).Endpoint(),
+		getAllCustomers: httptransport.NewClient(
+			"GET", u,
+			encodeGetAllCustomersRequest,
+			decodeGetAllCustomersResponse,
+			options...,
+		).Endpoint(),
+		getCustomerByID: httptransport.NewClient(
+			"GET", u,
+			encodeGetCustomerByIDRequest,
+			decodeGetCustomerByIDResponse,
+			options...,
+		).Endpoint(),
+	}, nil
+}
+
+func (c *client) RegisterCustomer(ctx context.Context, cust *customer.Customer) (*customer.Customer, error) {
+	resp, err := c.registerCustomer(ctx, cust)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*customer.Customer), nil
+}
+
+func (c *client) UnregisterCustomer(ctx context.Context, cID int) error {
+	_, err := c.unregisterCustomer(ctx, cID)
+	return err
+}
+
+func (c *client) GetAllCustomers(ctx context.Context, opts *storage.QueryOptions) ([]customer.Customer, error) {
+	resp, err := c.getAllCustomers(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return resp.([]customer.Customer), nil
+}
+
+func (c *client) GetCustomerByID(ctx context.Context, cID int) (customer.Customer, error) {
+	resp, err := c.getCustomerByID(ctx, cID)
+	if err != nil {
+		return customer.Customer{}, err
+	}
+	return resp.(customer.Customer), nil
+}
+
+func encodeRegisterCustomerRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	r.URL.Path = "/customer"
+	return httptransport.EncodeJSONRequest(ctx, r, request)
+}
+
+func decodeRegisterCustomerResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var resp struct {
+		Customer *customer.Customer `json:"customer"`
+	}
+	err := httpjson.DecodeResponse(r, &resp)
+	return resp.Customer, err
+}
+
+func encodeUnregisterCustomerRequest(_ context.Context, r *http.Request, request interface{}) error {
+	r.URL.Path = fmt.Sprintf("/customer/%d", request.(int))
+	return nil
+}
+
+func decodeUnregisterCustomerResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	return nil, httpjson.DecodeResponse(r, nil)
+}
+
+func encodeGetAllCustomersRequest(_ context.Context, r *http.Request, request interface{}) error {
+	opts := request.(*storage.QueryOptions)
+	r.URL.Path = "/customers"
+	r.URL.RawQuery = httpjson.EncodeQueryOptions(opts).Encode()
+	return nil
+}
+
+func decodeGetAllCustomersResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var resp struct {
+		Customers []customer.Customer `json:"customers"`
+	}
+	err := httpjson.DecodeResponse(r, &resp)
+	return resp.Customers, err
+}
+
+func encodeGetCustomerByIDRequest(_ context.Context, r *http.Request, request interface{}) error {
+	r.URL.Path = fmt.Sprintf("/customer/%d", request.(int))
+	return nil
+}
+
+func decodeGetCustomerByIDResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var resp struct {
+		Customer customer.Customer `json:"customer"`
+	}
+	err := httpjson.DecodeResponse(r, &resp)
+	return resp.Customer, err
+}
@@ -3,6 +3,7 @@ package customer
 import (
 	"context"
 	"github.com/go-kit/kit/endpoint"
+	"reservations/pkg/authz"
 	"reservations/pkg/storage"
 )
 
@@ -13,13 +14,35 @@ type Endpoints struct {
 	GetCustomerByIDEndpoint    endpoint.Endpoint
 }
 
-func MakeServerEndpoints(s Service) Endpoints {
-	return Endpoints{
-		RegisterCustomerEndpoint:   MakeRegisterCustomerEndpoint(s),
-		UnregisterCustomerEndpoint: MakeUnregisterCustomerEndpoint(s),
-		GetAllCustomersEndpoint:    MakeGetAllCustomersEndpoint(s),
-		GetCustomerByIDEndpoint:    MakeGetCustomerByIDEndpoint(s),
+// MakeServerEndpoints returns the service endpoints, each checked against
+// the policy by az and wrapped in the given middlewares, the first one
+// outermost.
+func MakeServerEndpoints(s Service, az authz.Authorizer, mw ...endpoint.Middleware) Endpoints {
+	e := Endpoints{
+		RegisterCustomerEndpoint:   az.Require(authz.PermCustomerWrite, nil)(MakeRegisterCustomerEndpoint(s)),
+		UnregisterCustomerEndpoint: az.Require(authz.PermCustomerPurge, customerOwner)(MakeUnregisterCustomerEndpoint(s)),
+		GetAllCustomersEndpoint:    az.Require(authz.PermCustomerRead, nil)(MakeGetAllCustomersEndpoint(s)),
+		GetCustomerByIDEndpoint:    az.Require(authz.PermCustomerRead, customerOwner)(MakeGetCustomerByIDEndpoint(s)),
 	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		m := mw[i]
+		e.RegisterCustomerEndpoint = m(e.RegisterCustomerEndpoint)
+		e.UnregisterCustomerEndpoint = m(e.UnregisterCustomerEndpoint)
+		e.GetAllCustomersEndpoint = m(e.GetAllCustomersEndpoint)
+		e.GetCustomerByIDEndpoint = m(e.GetCustomerByIDEndpoint)
+	}
+	return e
+}
+
+// customerOwner returns the customer a request addresses.
+func customerOwner(_ context.Context, request interface{}) (int, error) {
+	switch req := request.(type) {
+	case unregisterCustomerRequest:
+		return req.CustomerID, nil
+	case getCustomerByIDRequest:
+		return req.CustomerID, nil
+	}
+	return 0, nil
 }
 
 type unregisterCustomerRequest struct {
@@ -34,7 +57,7 @@ func (r unregisterCustomerResponse) HTTPError() error { return r.Err }
 
 // UnregisterCustomer godoc
 // @Summary Unregister an existing customer
-// @Description Unregister an existing customer
+// @Description Unregister an existing customer. Customers with reservations, series, fees or deposits cannot be unregistered.
 // @Tags customer
 // @Param id path string true "Customer ID"
 // @Accept  json
@@ -2,8 +2,12 @@ package customer
 
 import (
 	"context"
+	"fmt"
 	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	errors "reservations/pkg/error"
 	"reservations/pkg/storage"
+	"reservations/pkg/tracing"
 	"time"
 )
 
@@ -51,3 +55,87 @@ func (mw loggingMiddleware) GetCustomerByID(ctx context.Context, cID int) (resul
 	}(time.Now())
 	return mw.next.GetCustomerByID(ctx, cID)
 }
+
+// InstrumentingMiddleware records the count and latency of every call and
+// counts failed calls by error type.
+func InstrumentingMiddleware(requestCount metrics.Counter, requestLatency metrics.Histogram, errorCount metrics.Counter) Middleware {
+	return func(next Service) Service {
+		return &instrumentingMiddleware{
+			next:           next,
+			requestCount:   requestCount,
+			requestLatency: requestLatency,
+			errorCount:     errorCount,
+		}
+	}
+}
+
+type instrumentingMiddleware struct {
+	next           Service
+	requestCount   metrics.Counter
+	requestLatency metrics.Histogram
+	errorCount     metrics.Counter
+}
+
+func (mw instrumentingMiddleware) observe(method string, begin time.Time, err error) {
+	lvs := []string{"method", method, "error", fmt.Sprint(err != nil)}
+	mw.requestCount.With(lvs...).Add(1)
+	mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	if err != nil {
+		mw.errorCount.With("method", method, "type", errors.GetType(err).String()).Add(1)
+	}
+}
+
+func (mw instrumentingMiddleware) RegisterCustomer(ctx context.Context, c *Customer) (result *Customer, err error) {
+	defer func(begin time.Time) { mw.observe("RegisterCustomer", begin, err) }(time.Now())
+	return mw.next.RegisterCustomer(ctx, c)
+}
+
+func (mw instrumentingMiddleware) UnregisterCustomer(ctx context.Context, cID int) (err error) {
+	defer func(begin time.Time) { mw.observe("UnregisterCustomer", begin, err) }(time.Now())
+	return mw.next.UnregisterCustomer(ctx, cID)
+}
+
+func (mw instrumentingMiddleware) GetAllCustomers(ctx context.Context, opts *storage.QueryOptions) (result []Customer, err error) {
+	defer func(begin time.Time) { mw.observe("GetAllCustomers", begin, err) }(time.Now())
+	return mw.next.GetAllCustomers(ctx, opts)
+}
+
+func (mw instrumentingMiddleware) GetCustomerByID(ctx context.Context, cID int) (result Customer, err error) {
+	defer func(begin time.Time) { mw.observe("GetCustomerByID", begin, err) }(time.Now())
+	return mw.next.GetCustomerByID(ctx, cID)
+}
+
+// TracingMiddleware wraps every call in a span.
+func TracingMiddleware() Middleware {
+	return func(next Service) Service {
+		return &tracingMiddleware{next: next}
+	}
+}
+
+type tracingMiddleware struct {
+	next Service
+}
+
+func (mw tracingMiddleware) RegisterCustomer(ctx context.Context, c *Customer) (result *Customer, err error) {
+	ctx, span := tracing.StartSpan(ctx, "customer.RegisterCustomer")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.RegisterCustomer(ctx, c)
+}
+
+func (mw tracingMiddleware) UnregisterCustomer(ctx context.Context, cID int) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "customer.UnregisterCustomer")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.UnregisterCustomer(ctx, cID)
+}
+
+func (mw tracingMiddleware) GetAllCustomers(ctx context.Context, opts *storage.QueryOptions) (result []Customer, err error) {
+	ctx, span := tracing.StartSpan(ctx, "customer.GetAllCustomers")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.GetAllCustomers(ctx, opts)
+}
+
+func (mw tracingMiddleware) GetCustomerByID(ctx context.Context, cID int) (result Customer, err error) {
+	ctx, span := tracing.StartSpan(ctx, "customer.GetCustomerByID")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.GetCustomerByID(ctx, cID)
+}
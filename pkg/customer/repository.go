@@ -1,23 +1,34 @@
 package customer
 
 import (
+	"context"
 	"github.com/doug-martin/goqu/v7"
-	"github.com/doug-martin/goqu/v7/exec"
+	"reservations/pkg/audit"
 	errors "reservations/pkg/error"
+	"reservations/pkg/event"
+	"reservations/pkg/outbox"
 	"reservations/pkg/storage"
+	"reservations/pkg/tenant"
+	"reservations/pkg/tracing"
+	"strings"
 	"time"
 )
 
 const (
-	defaultLimit  uint = 100
 	defaultOffset uint = 0
 )
 
+const auditEntity = "customer"
+
+// dependents are the tables whose rows refer to the customer, which keep the
+// customer from being removed.
+var dependents = []string{"reservation", "reservation_series", "reservation_fee", "deposit"}
+
 type Repository interface {
-	AddCustomer(c *Customer) (*Customer, error)
-	RemoveCustomer(cID int) error
-	FindAllCustomers(opts *storage.QueryOptions) ([]Customer, error)
-	FindCustomerByID(cID int) (Customer, error)
+	AddCustomer(ctx context.Context, c *Customer) (*Customer, error)
+	RemoveCustomer(ctx context.Context, cID int) error
+	FindAllCustomers(ctx context.Context, opts *storage.QueryOptions) ([]Customer, error)
+	FindCustomerByID(ctx context.Context, cID int) (Customer, error)
 }
 
 type customerRepository struct {
@@ -28,41 +39,91 @@ func NewCustomerRepository(db storage.Persistence) Repository {
 	return &customerRepository{db: db}
 }
 
-func (r *customerRepository) AddCustomer(c *Customer) (*Customer, error) {
+// Insert stores c in the venue of ctx within tx and records the registration
+// in the outbox and the audit log.
+func Insert(ctx context.Context, tx *goqu.TxDatabase, c *Customer, now int64) error {
+	c.Created = now
+	c.LastUpdated = now
+	c.VenueID = tenant.VenueFromContext(ctx)
+	cID, err := storage.InsertReturningID(tx, "customer", "cid", c)
+	if err != nil {
+		return err
+	}
+	c.CustomerID = cID
+
+	if err := audit.Append(ctx, tx, audit.ActionCreate, auditEntity, c.CustomerID, nil, c); err != nil {
+		return err
+	}
+	return outbox.Append(ctx, tx, event.CustomerRegistered, event.AggregateCustomer, c.CustomerID, c)
+}
+
+func (r *customerRepository) AddCustomer(ctx context.Context, c *Customer) (_ *Customer, err error) {
+	ctx, span := tracing.StartSpan(ctx, "customerRepository.AddCustomer", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
 	created := time.Now().Unix()
 
-	result, err := r.db.Tx(func(tx *goqu.TxDatabase) exec.QueryExecutor {
-		c.Created = created
-		c.LastUpdated = created
-		return tx.From("customer").Insert(c)
-	});
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		return Insert(ctx, tx, c, created)
+	})
 	if err != nil {
 		return nil, errors.DBError.Wrap(err, "error adding new customer")
 	}
 
-	cID, _ := result.LastInsertId()
-	c.CustomerID = int(cID)
-
 	return c, nil
 }
 
-func (r *customerRepository) RemoveCustomer(cID int) error {
-	_, err := r.db.Tx(func(tx *goqu.TxDatabase) exec.QueryExecutor {
-		return tx.From("customer").Where(goqu.Ex{"cid": cID}).Delete()
+func (r *customerRepository) RemoveCustomer(ctx context.Context, cID int) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "customerRepository.RemoveCustomer", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	where := goqu.Ex{"cid": cID, "venue_id": tenant.VenueFromContext(ctx)}
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		var c Customer
+		found, err := tx.From("customer").Where(where).ScanStruct(&c)
+		if err != nil || !found {
+			return err
+		}
+
+		for _, table := range dependents {
+			n, err := tx.From(table).Where(goqu.Ex{"customer_id": cID}).Count()
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				return errors.Conflict.Newf("customer with ID %d has a %s", cID, strings.ReplaceAll(table, "_", " ")).
+					AddContext("CustomerID", "has reservations, series, fees or deposits")
+			}
+		}
+
+		if _, err := tx.From("customer").Where(where).Delete().Exec(); err != nil {
+			return err
+		}
+		if err := audit.Append(ctx, tx, audit.ActionDelete, auditEntity, cID, c, nil); err != nil {
+			return err
+		}
+
+		return outbox.Append(ctx, tx, event.CustomerUnregistered, event.AggregateCustomer, cID, c)
 	})
 
+	if errors.GetType(err) == errors.Conflict {
+		return err
+	}
 	if err != nil {
 		return errors.DBError.Wrapf(err, "error deleting customers with id %d", cID)
 	}
 	return nil
 }
 
-func (r *customerRepository) FindAllCustomers(opts *storage.QueryOptions) (cc []Customer, err error) {
-	if opts.Limit == 0 {
-		opts.Limit = defaultLimit
-	}
+func (r *customerRepository) FindAllCustomers(ctx context.Context, opts *storage.QueryOptions) (cc []Customer, err error) {
+	ctx, span := tracing.StartSpan(ctx, "customerRepository.FindAllCustomers", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	opts.Limit = r.db.PageSize(opts.Limit)
 
 	err = r.db.DB.From("customer").
+		Where(goqu.Ex{"venue_id": tenant.VenueFromContext(ctx)}).
 		Limit(opts.Limit).
 		Offset(opts.Offset).
 		ScanStructs(&cc)
@@ -73,9 +134,13 @@ func (r *customerRepository) FindAllCustomers(opts *storage.QueryOptions) (cc []
 	return cc, nil
 }
 
-func (r *customerRepository) FindCustomerByID(cID int) (c Customer, err error) {
+func (r *customerRepository) FindCustomerByID(ctx context.Context, cID int) (c Customer, err error) {
+	ctx, span := tracing.StartSpan(ctx, "customerRepository.FindCustomerByID", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
 	found, err := r.db.DB.From("customer").Where(
 		goqu.C("cid").Eq(cID),
+		goqu.C("venue_id").Eq(tenant.VenueFromContext(ctx)),
 	).ScanStruct(&c)
 
 	if !found {
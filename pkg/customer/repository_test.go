@@ -0,0 +1,123 @@
+package customer
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v7"
+	"reservations/pkg/audit"
+	errors "reservations/pkg/error"
+	"reservations/pkg/event"
+	"reservations/pkg/request"
+	"reservations/pkg/storage"
+	"reservations/pkg/storage/storagetest"
+	"reservations/pkg/tenant"
+	"testing"
+)
+
+func addCustomer(t *testing.T, ctx context.Context, r Repository, first string) *Customer {
+	c, err := r.AddCustomer(ctx, &Customer{FirstName: first, LastName: "Doe", Email: first + "@example.com"})
+	if err != nil {
+		t.Fatalf("AddCustomer(%s): %v", first, err)
+	}
+	return c
+}
+
+func TestCustomerRepository(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		r := NewCustomerRepository(*db)
+		ctx := request.WithActor(context.Background(), "alice")
+
+		v, err := tenant.CreateVenue(ctx, tenant.NewVenueRepository(*db), "other", "Other venue")
+		if err != nil {
+			t.Fatalf("CreateVenue: %v", err)
+		}
+		other := tenant.WithVenue(ctx, v.VenueID)
+
+		ann := addCustomer(t, ctx, r, "ann")
+		bob := addCustomer(t, ctx, r, "bob")
+		addCustomer(t, other, r, "cid")
+		if ann.CustomerID == 0 || ann.CustomerID == bob.CustomerID {
+			t.Fatalf("customers got IDs %d and %d", ann.CustomerID, bob.CustomerID)
+		}
+		if ann.VenueID != tenant.DefaultVenueID || ann.Created == 0 {
+			t.Errorf("AddCustomer = %+v, want it in the default venue", ann)
+		}
+
+		got, err := r.FindCustomerByID(ctx, bob.CustomerID)
+		if err != nil || got != *bob {
+			t.Errorf("FindCustomerByID = %+v, %v, want %+v", got, err, *bob)
+		}
+		if _, err := r.FindCustomerByID(other, bob.CustomerID); errors.GetType(err) != errors.NotFound {
+			t.Errorf("FindCustomerByID in another venue = %v, want a NotFound", err)
+		}
+
+		cc, err := r.FindAllCustomers(ctx, &storage.QueryOptions{})
+		if err != nil || len(cc) != 2 {
+			t.Fatalf("FindAllCustomers = %v, %v, want the 2 customers of the venue", cc, err)
+		}
+		cc, err = r.FindAllCustomers(ctx, &storage.QueryOptions{Limit: 1, Offset: 1})
+		if err != nil || len(cc) != 1 || cc[0].CustomerID != bob.CustomerID {
+			t.Errorf("FindAllCustomers of the second page = %v, %v, want bob", cc, err)
+		}
+
+		if err := r.RemoveCustomer(ctx, ann.CustomerID); err != nil {
+			t.Fatalf("RemoveCustomer: %v", err)
+		}
+		if _, err := r.FindCustomerByID(ctx, ann.CustomerID); errors.GetType(err) != errors.NotFound {
+			t.Errorf("FindCustomerByID after RemoveCustomer = %v, want a NotFound", err)
+		}
+		if err := r.RemoveCustomer(ctx, ann.CustomerID); err != nil {
+			t.Errorf("RemoveCustomer of a removed customer = %v, want no error", err)
+		}
+
+		var entries []audit.Entry
+		if err := db.DB.From("audit_log").Where(goqu.Ex{"entity_id": ann.CustomerID, "entity_type": auditEntity}).
+			Order(goqu.C("aid").Asc()).ScanStructs(&entries); err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 2 || entries[0].Action != audit.ActionCreate || entries[1].Action != audit.ActionDelete || entries[1].Actor != "alice" {
+			t.Errorf("audit entries = %+v, want the creation and deletion by alice", entries)
+		}
+
+		var types []string
+		if err := db.DB.From("outbox").Select("event_type").Where(goqu.Ex{"aggregate_id": ann.CustomerID, "aggregate_type": event.AggregateCustomer}).
+			Order(goqu.C("eid").Asc()).ScanVals(&types); err != nil {
+			t.Fatal(err)
+		}
+		if len(types) != 2 || types[0] != string(event.CustomerRegistered) || types[1] != string(event.CustomerUnregistered) {
+			t.Errorf("outbox events = %v, want the registration and unregistration", types)
+		}
+	})
+}
+
+func TestRemoveCustomerWithDependents(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		r := NewCustomerRepository(*db)
+		ctx := context.Background()
+
+		rows := map[string]goqu.Record{
+			"reservation":        {"seat_count": 2},
+			"reservation_series": {"seat_count": 2, "start_time": "2026-01-01T10:00:00Z", "frequency": "daily", "duration": 7200},
+			"reservation_fee":    {"rid": 1, "kind": "cancellation", "amount": 1500},
+			"deposit":            {"rid": 1, "amount": 3000, "currency": "eur", "status": "pending", "pay_by": 0},
+		}
+		for _, table := range dependents {
+			c := addCustomer(t, ctx, r, table)
+			row := rows[table]
+			row["customer_id"], row["venue_id"] = c.CustomerID, c.VenueID
+			if _, err := db.DB.From(table).Insert(row).Exec(); err != nil {
+				t.Fatalf("inserting into %s: %v", table, err)
+			}
+
+			// Foreign keys are enforced on every connection of the pool, but
+			// the dependents are found first.
+			for i := 0; i < 3; i++ {
+				if err := r.RemoveCustomer(ctx, c.CustomerID); errors.GetType(err) != errors.Conflict {
+					t.Fatalf("RemoveCustomer of a customer with a %s = %v, want a Conflict", table, err)
+				}
+			}
+			if _, err := r.FindCustomerByID(ctx, c.CustomerID); err != nil {
+				t.Errorf("FindCustomerByID after the failed removal = %v", err)
+			}
+		}
+	})
+}
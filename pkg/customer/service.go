@@ -18,6 +18,7 @@ type Customer struct {
 	LastName    string `json:"lastName" db:"last_name"`
 	Email       string `json:"email"`
 	Phone       string `json:"phone"`
+	VenueID     int    `json:"venueId" db:"venue_id"`
 	Created     int64  `json:"created"`
 	LastUpdated int64  `json:"lastUpdated" db:"last_updated"`
 }
@@ -33,17 +34,17 @@ func NewCustomerService(repo Repository) Service {
 }
 
 func (s *customerService) RegisterCustomer(ctx context.Context, c *Customer) (*Customer, error) {
-	return s.custRepo.AddCustomer(c)
+	return s.custRepo.AddCustomer(ctx, c)
 }
 
 func (s *customerService) UnregisterCustomer(ctx context.Context, cID int) error {
-	return s.custRepo.RemoveCustomer(cID)
+	return s.custRepo.RemoveCustomer(ctx, cID)
 }
 
 func (s *customerService) GetAllCustomers(ctx context.Context, opts *storage.QueryOptions) ([]Customer, error) {
-	return s.custRepo.FindAllCustomers(opts)
+	return s.custRepo.FindAllCustomers(ctx, opts)
 }
 
 func (s *customerService) GetCustomerByID(ctx context.Context, cID int) (Customer, error) {
-	return s.custRepo.FindCustomerByID(cID)
+	return s.custRepo.FindCustomerByID(ctx, cID)
 }
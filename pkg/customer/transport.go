@@ -3,15 +3,17 @@ package customer
 import (
 	"context"
 	"encoding/json"
+	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
 	httptransport "github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
 	"net/http"
+	"reservations/pkg/authz"
 	"reservations/pkg/transport"
 )
 
-func MakeHTTPHandler(r *mux.Router, s Service, logger log.Logger) *mux.Router {
-	e := MakeServerEndpoints(s)
+func MakeHTTPHandler(r *mux.Router, s Service, az authz.Authorizer, logger log.Logger, mw ...endpoint.Middleware) *mux.Router {
+	e := MakeServerEndpoints(s, az, mw...)
 
 	options := httpjson.DefaultServerOptions(logger)
 
@@ -25,7 +27,7 @@ func MakeHTTPHandler(r *mux.Router, s Service, logger log.Logger) *mux.Router {
 
 	r.Methods("DELETE").Path("/customer/{id}").
 		Handler(httptransport.NewServer(
-			e.RegisterCustomerEndpoint,
+			e.UnregisterCustomerEndpoint,
 			decodeUnregisterCustomerRequest,
 			httpjson.EncodeResponse,
 			options...,
@@ -0,0 +1,167 @@
+package customer
+
+import (
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	grpctransport "github.com/go-kit/kit/transport/grpc"
+	"reservations/pkg/authz"
+	"reservations/pkg/pb"
+	"reservations/pkg/transport/grpcutil"
+)
+
+type grpcServer struct {
+	registerCustomer   grpctransport.Handler
+	unregisterCustomer grpctransport.Handler
+	getAllCustomers    grpctransport.Handler
+	getCustomerByID    grpctransport.Handler
+}
+
+// MakeGRPCServer exposes the customer service endpoints as a pb.CustomersServer.
+func MakeGRPCServer(s Service, az authz.Authorizer, logger log.Logger, mw ...endpoint.Middleware) pb.CustomersServer {
+	e := MakeServerEndpoints(s, az, mw...)
+
+	options := grpcutil.DefaultServerOptions(logger)
+
+	return &grpcServer{
+		registerCustomer: grpctransport.NewServer(
+			e.RegisterCustomerEndpoint,
+			decodeGRPCRegisterCustomerRequest,
+			encodeGRPCRegisterCustomerResponse,
+			options...,
+		),
+		unregisterCustomer: grpctransport.NewServer(
+			e.UnregisterCustomerEndpoint,
+			decodeGRPCUnregisterCustomerRequest,
+			encodeGRPCUnregisterCustomerResponse,
+			options...,
+		),
+		getAllCustomers: grpctransport.NewServer(
+			e.GetAllCustomersEndpoint,
+			decodeGRPCGetAllCustomersRequest,
+			encodeGRPCGetAllCustomersResponse,
+			options...,
+		),
+		getCustomerByID: grpctransport.NewServer(
+			e.GetCustomerByIDEndpoint,
+			decodeGRPCGetCustomerByIDRequest,
+			encodeGRPCGetCustomerByIDResponse,
+			options...,
+		),
+	}
+}
+
+func (s *grpcServer) RegisterCustomer(ctx context.Context, req *pb.RegisterCustomerRequest) (*pb.RegisterCustomerReply, error) {
+	_, rep, err := s.registerCustomer.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, grpcutil.EncodeError(err)
+	}
+	return rep.(*pb.RegisterCustomerReply), nil
+}
+
+func (s *grpcServer) UnregisterCustomer(ctx context.Context, req *pb.UnregisterCustomerRequest) (*pb.UnregisterCustomerReply, error) {
+	_, rep, err := s.unregisterCustomer.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, grpcutil.EncodeError(err)
+	}
+	return rep.(*pb.UnregisterCustomerReply), nil
+}
+
+func (s *grpcServer) GetAllCustomers(ctx context.Context, req *pb.GetAllCustomersRequest) (*pb.GetAllCustomersReply, error) {
+	_, rep, err := s.getAllCustomers.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, grpcutil.EncodeError(err)
+	}
+	return rep.(*pb.GetAllCustomersReply), nil
+}
+
+func (s *grpcServer) GetCustomerByID(ctx context.Context, req *pb.GetCustomerByIDRequest) (*pb.GetCustomerByIDReply, error) {
+	_, rep, err := s.getCustomerByID.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, grpcutil.EncodeError(err)
+	}
+	return rep.(*pb.GetCustomerByIDReply), nil
+}
+
+func decodeGRPCRegisterCustomerRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.RegisterCustomerRequest)
+	return registerCustomerRequest{Customer: customerFromPB(req.Customer)}, nil
+}
+
+func encodeGRPCRegisterCustomerResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(registerCustomerResponse)
+	if resp.Err != nil {
+		return nil, grpcutil.EncodeError(resp.Err)
+	}
+	return &pb.RegisterCustomerReply{Customer: customerToPB(*resp.Customer)}, nil
+}
+
+func decodeGRPCUnregisterCustomerRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.UnregisterCustomerRequest)
+	return unregisterCustomerRequest{CustomerID: int(req.CustomerId)}, nil
+}
+
+func encodeGRPCUnregisterCustomerResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(unregisterCustomerResponse)
+	if resp.Err != nil {
+		return nil, grpcutil.EncodeError(resp.Err)
+	}
+	return &pb.UnregisterCustomerReply{}, nil
+}
+
+func decodeGRPCGetAllCustomersRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.GetAllCustomersRequest)
+	return getAllCustomersRequest{Limit: uint(req.Limit), Offset: uint(req.Offset)}, nil
+}
+
+func encodeGRPCGetAllCustomersResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(getAllCustomersResponse)
+	if resp.Err != nil {
+		return nil, grpcutil.EncodeError(resp.Err)
+	}
+	rep := &pb.GetAllCustomersReply{}
+	for _, c := range resp.Customers {
+		rep.Customers = append(rep.Customers, customerToPB(c))
+	}
+	return rep, nil
+}
+
+func decodeGRPCGetCustomerByIDRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.GetCustomerByIDRequest)
+	return getCustomerByIDRequest{CustomerID: int(req.CustomerId)}, nil
+}
+
+func encodeGRPCGetCustomerByIDResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(getCustomerByIDResponse)
+	if resp.Err != nil {
+		return nil, grpcutil.EncodeError(resp.Err)
+	}
+	return &pb.GetCustomerByIDReply{Customer: customerToPB(resp.Customer)}, nil
+}
+
+func customerFromPB(c *pb.Customer) *Customer {
+	if c == nil {
+		return &Customer{}
+	}
+	return &Customer{
+		CustomerID:  int(c.CustomerId),
+		FirstName:   c.FirstName,
+		LastName:    c.LastName,
+		Email:       c.Email,
+		Phone:       c.Phone,
+		Created:     c.Created,
+		LastUpdated: c.LastUpdated,
+	}
+}
+
+func customerToPB(c Customer) *pb.Customer {
+	return &pb.Customer{
+		CustomerId:  int64(c.CustomerID),
+		FirstName:   c.FirstName,
+		LastName:    c.LastName,
+		Email:       c.Email,
+		Phone:       c.Phone,
+		Created:     c.Created,
+		LastUpdated: c.LastUpdated,
+	}
+}
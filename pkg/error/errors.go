@@ -15,6 +15,9 @@ const (
 	DBError
 	ValidationError
 	NotFound
+	Conflict
+	Unauthorized
+	Forbidden
 )
 
 type AppError struct {
@@ -42,6 +45,22 @@ func (e AppError) MarshalJSON() ([]byte, error) {
 	return json.Marshal(jsonErr)
 }
 
+func (e *AppError) UnmarshalJSON(data []byte) error {
+	var jsonErr struct {
+		ErrorType string       `json:"type"`
+		Cause     string       `json:"cause"`
+		Context   errorContext `json:"context,omitempty"`
+	}
+	if err := json.Unmarshal(data, &jsonErr); err != nil {
+		return err
+	}
+
+	e.ErrorType = ParseErrorType(jsonErr.ErrorType)
+	e.OriginalError = errors.New(jsonErr.Cause)
+	e.Context = jsonErr.Context
+	return nil
+}
+
 func (e AppError) error() error {
 	return e.OriginalError
 }
@@ -53,8 +72,20 @@ func (e AppError) AddContext(field, message string) AppError {
 	return e
 }
 
+var errorTypeNames = [...]string{"UnknownError", "DBError", "ValidationError", "NotFound", "Conflict", "Unauthorized", "Forbidden"}
+
 func (errorType ErrorType) String() string {
-	return [...]string{"UnknownError", "DBError", "ValidationError", "NotFound"}[errorType]
+	return errorTypeNames[errorType]
+}
+
+// ParseErrorType returns the ErrorType with the given name, or UnknownError.
+func ParseErrorType(name string) ErrorType {
+	for i, n := range errorTypeNames {
+		if n == name {
+			return ErrorType(i)
+		}
+	}
+	return UnknownError
 }
 
 // New creates a new AppError
@@ -0,0 +1,75 @@
+package event
+
+import (
+	"context"
+	"reservations/pkg/storage"
+)
+
+// Type names a domain event.
+type Type string
+
+const (
+	CustomerRegistered   Type = "CustomerRegistered"
+	CustomerUnregistered Type = "CustomerUnregistered"
+	ReservationBooked    Type = "ReservationBooked"
+	ReservationEdited    Type = "ReservationEdited"
+	ReservationCancelled Type = "ReservationCancelled"
+	TableStateChanged    Type = "TableStateChanged"
+)
+
+// Types lists every domain event type.
+var Types = []Type{
+	CustomerRegistered,
+	CustomerUnregistered,
+	ReservationBooked,
+	ReservationEdited,
+	ReservationCancelled,
+	TableStateChanged,
+}
+
+// Known reports whether t is a known event type.
+func (t Type) Known() bool {
+	for _, k := range Types {
+		if k == t {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	AggregateCustomer    = "customer"
+	AggregateReservation = "reservation"
+	AggregateTable       = "table"
+)
+
+// Event is a domain event recorded together with the state change that
+// caused it. The payload holds the state of the aggregate after the change,
+// or before it for deletions.
+type Event struct {
+	EventID int `json:"eventId" db:"eid" goqu:"skipinsert"`
+	// Sequence orders the events as they were committed, which event IDs
+	// do not as they are taken before. It is assigned by the outbox relay
+	// when it first sees the event, and is 0 until then.
+	Sequence      int          `json:"sequence" db:"seq" goqu:"skipinsert"`
+	Type          Type         `json:"type" db:"event_type"`
+	AggregateType string       `json:"aggregateType" db:"aggregate_type"`
+	AggregateID   int          `json:"aggregateId" db:"aggregate_id"`
+	VenueID       int          `json:"venueId" db:"venue_id"`
+	Payload       storage.JSON `json:"payload"`
+	Created       int64        `json:"created"`
+}
+
+// Publisher delivers events to an external system. Delivery is at least
+// once, so publishers and their consumers must tolerate duplicates, which
+// can be detected by EventID.
+type Publisher interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// PublisherFunc is an adapter to allow the use of ordinary functions as Publishers.
+type PublisherFunc func(ctx context.Context, e Event) error
+
+func (f PublisherFunc) Publish(ctx context.Context, e Event) error {
+	return f(ctx, e)
+}
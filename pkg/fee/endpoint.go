@@ -0,0 +1,150 @@
+package fee
+
+import (
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"reservations/pkg/authz"
+	"reservations/pkg/storage"
+)
+
+type Endpoints struct {
+	GetFeeByIDEndpoint         endpoint.Endpoint
+	GetFeesPerCustomerEndpoint endpoint.Endpoint
+	WaiveFeeEndpoint           endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns the service endpoints, each checked against
+// the policy by az and wrapped in the given middlewares, the first one
+// outermost.
+func MakeServerEndpoints(s Service, az authz.Authorizer, mw ...endpoint.Middleware) Endpoints {
+	owner := feeOwner(s)
+	e := Endpoints{
+		GetFeeByIDEndpoint:         az.Require(authz.PermReservationRead, owner)(MakeGetFeeByIDEndpoint(s)),
+		GetFeesPerCustomerEndpoint: az.Require(authz.PermReservationRead, owner)(MakeGetFeesPerCustomerEndpoint(s)),
+		WaiveFeeEndpoint:           az.Require(authz.PermFeeWaive, nil)(MakeWaiveFeeEndpoint(s)),
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		m := mw[i]
+		e.GetFeeByIDEndpoint = m(e.GetFeeByIDEndpoint)
+		e.GetFeesPerCustomerEndpoint = m(e.GetFeesPerCustomerEndpoint)
+		e.WaiveFeeEndpoint = m(e.WaiveFeeEndpoint)
+	}
+	return e
+}
+
+// feeOwner returns the customer a request addresses, looking up the fee for
+// requests naming one by ID.
+func feeOwner(s Service) authz.OwnerFunc {
+	return func(ctx context.Context, request interface{}) (int, error) {
+		switch req := request.(type) {
+		case getFeesPerCustomerRequest:
+			return req.CustomerID, nil
+		case getFeeByIDRequest:
+			f, err := s.GetFeeByID(ctx, req.FeeID)
+			return f.CustomerID, err
+		}
+		return 0, nil
+	}
+}
+
+type getFeeByIDRequest struct {
+	FeeID int
+}
+
+type getFeeByIDResponse struct {
+	Fee Fee   `json:"fee"`
+	Err error `json:"err,omitempty"`
+}
+
+func (r getFeeByIDResponse) HTTPError() error { return r.Err }
+
+// GetFeeByID godoc
+// @Summary Get a fee
+// @Description Get a fee charged for cancelling or modifying a reservation late
+// @Tags fee
+// @Param id path string true "Fee ID"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} fee.Fee
+// @Router /fee/{id} [get]
+func MakeGetFeeByIDEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getFeeByIDRequest)
+		f, e := s.GetFeeByID(ctx, req.FeeID)
+		return getFeeByIDResponse{
+			Fee: f,
+			Err: e,
+		}, nil
+	}
+}
+
+type getFeesPerCustomerRequest struct {
+	CustomerID int
+	Limit      uint
+	Offset     uint
+}
+
+type getFeesPerCustomerResponse struct {
+	Fees []Fee `json:"fees"`
+	Err  error `json:"err,omitempty"`
+}
+
+func (r getFeesPerCustomerResponse) HTTPError() error { return r.Err }
+
+// GetFeesPerCustomer godoc
+// @Summary List the fees charged to a customer, newest first
+// @Description List the fees charged to a customer for cancelling or modifying reservations late, waived ones included, newest first
+// @Tags fee
+// @Param id path string true "Customer ID"
+// @Param limit query int false "Fee count limit" default(100)
+// @Param offset query int false "Fee count offset" default(0)
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} fee.Fee
+// @Router /customer/{id}/fees [get]
+func MakeGetFeesPerCustomerEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getFeesPerCustomerRequest)
+		ff, e := s.GetFeesPerCustomer(ctx, req.CustomerID, &storage.QueryOptions{
+			Limit:  req.Limit,
+			Offset: req.Offset,
+		})
+		return getFeesPerCustomerResponse{
+			Fees: ff,
+			Err:  e,
+		}, nil
+	}
+}
+
+type waiveFeeRequest struct {
+	FeeID  int    `json:"-"`
+	Reason string `json:"reason"`
+}
+
+type waiveFeeResponse struct {
+	Fee Fee   `json:"fee"`
+	Err error `json:"err,omitempty"`
+}
+
+func (r waiveFeeResponse) HTTPError() error { return r.Err }
+
+// WaiveFee godoc
+// @Summary Waive a fee
+// @Description Let the customer off a fee, recording who waived it and why in the fee and the audit log. What a deposit kept in payment of the fee is refunded.
+// @Tags fee
+// @Param id path string true "Fee ID"
+// @Param waiver body fee.waiveFeeRequest true "Reason for the waiver"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} fee.Fee
+// @Router /fee/{id}/waive [post]
+func MakeWaiveFeeEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(waiveFeeRequest)
+		f, e := s.WaiveFee(ctx, req.FeeID, req.Reason)
+		return waiveFeeResponse{
+			Fee: f,
+			Err: e,
+		}, nil
+	}
+}
@@ -0,0 +1,76 @@
+package fee
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"reservations/pkg/storage"
+	"reservations/pkg/tracing"
+	"time"
+)
+
+// Middleware describes a service (as opposed to endpoint) middleware.
+type Middleware func(Service) Service
+
+func LoggingMiddleware(logger log.Logger) Middleware {
+	return func(next Service) Service {
+		return &loggingMiddleware{
+			next:   next,
+			logger: logger,
+		}
+	}
+}
+
+type loggingMiddleware struct {
+	next   Service
+	logger log.Logger
+}
+
+func (mw loggingMiddleware) GetFeeByID(ctx context.Context, fID int) (f Fee, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "GetFeeByID", "id", fID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetFeeByID(ctx, fID)
+}
+
+func (mw loggingMiddleware) GetFeesPerCustomer(ctx context.Context, cID int, opts *storage.QueryOptions) (ff []Fee, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "GetFeesPerCustomer", "customer", cID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetFeesPerCustomer(ctx, cID, opts)
+}
+
+func (mw loggingMiddleware) WaiveFee(ctx context.Context, fID int, reason string) (f Fee, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "WaiveFee", "id", fID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.WaiveFee(ctx, fID, reason)
+}
+
+// TracingMiddleware wraps every call in a span.
+func TracingMiddleware() Middleware {
+	return func(next Service) Service {
+		return &tracingMiddleware{next: next}
+	}
+}
+
+type tracingMiddleware struct {
+	next Service
+}
+
+func (mw tracingMiddleware) GetFeeByID(ctx context.Context, fID int) (f Fee, err error) {
+	ctx, span := tracing.StartSpan(ctx, "fee.GetFeeByID")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.GetFeeByID(ctx, fID)
+}
+
+func (mw tracingMiddleware) GetFeesPerCustomer(ctx context.Context, cID int, opts *storage.QueryOptions) (ff []Fee, err error) {
+	ctx, span := tracing.StartSpan(ctx, "fee.GetFeesPerCustomer")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.GetFeesPerCustomer(ctx, cID, opts)
+}
+
+func (mw tracingMiddleware) WaiveFee(ctx context.Context, fID int, reason string) (f Fee, err error) {
+	ctx, span := tracing.StartSpan(ctx, "fee.WaiveFee")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.WaiveFee(ctx, fID, reason)
+}
@@ -0,0 +1,147 @@
+package fee
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v7"
+	"reservations/pkg/audit"
+	errors "reservations/pkg/error"
+	"reservations/pkg/payment"
+	"reservations/pkg/request"
+	"reservations/pkg/storage"
+	"reservations/pkg/tenant"
+	"reservations/pkg/tracing"
+	"time"
+)
+
+const auditEntity = "fee"
+
+type Repository interface {
+	FindFeeByID(ctx context.Context, fID int) (Fee, error)
+	FindFeesByCustomerID(ctx context.Context, cID int, opts *storage.QueryOptions) ([]Fee, error)
+	// WaiveFee marks the fee fID waived, storing the refund of a deposit
+	// kept in payment of it, see payment.ReleaseFee.
+	WaiveFee(ctx context.Context, fID int, reason string) (Fee, error)
+}
+
+type feeRepository struct {
+	db storage.Persistence
+}
+
+func NewFeeRepository(db storage.Persistence) Repository {
+	return &feeRepository{db: db}
+}
+
+// Insert charges f within tx, adding modification fees to the fee of the
+// reservation.
+func Insert(tx *goqu.TxDatabase, f *Fee, now int64) error {
+	f.Waived = false
+	f.WaivedBy = ""
+	f.WaiverReason = ""
+	f.Created = now
+	f.LastUpdated = now
+	fID, err := storage.InsertReturningID(tx, "reservation_fee", "fid", f)
+	if err != nil {
+		return err
+	}
+	f.FeeID = fID
+
+	if f.Kind != Modification {
+		return nil
+	}
+	_, err = tx.From("reservation").Where(goqu.Ex{"rid": f.ReservationID, "venue_id": f.VenueID}).
+		Update(goqu.Record{"fee": goqu.L("fee + ?", f.Amount)}).Exec()
+	return err
+}
+
+func (r *feeRepository) FindFeeByID(ctx context.Context, fID int) (f Fee, err error) {
+	_, span := tracing.StartSpan(ctx, "feeRepository.FindFeeByID", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	found, err := r.db.DB.From("reservation_fee").
+		Where(goqu.Ex{"fid": fID, "venue_id": tenant.VenueFromContext(ctx)}).
+		ScanStruct(&f)
+	if err != nil {
+		return f, errors.DBError.Wrapf(err, "error getting fee with ID %d", fID)
+	}
+	if !found {
+		return f, errors.NotFound.Newf("fee with ID %d not found", fID).
+			AddContext("FeeID", "non existent ID")
+	}
+	return f, nil
+}
+
+func (r *feeRepository) FindFeesByCustomerID(ctx context.Context, cID int, opts *storage.QueryOptions) (ff []Fee, err error) {
+	_, span := tracing.StartSpan(ctx, "feeRepository.FindFeesByCustomerID", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	opts.Limit = r.db.PageSize(opts.Limit)
+
+	ff = []Fee{}
+	err = r.db.DB.From("reservation_fee").
+		Where(goqu.Ex{"customer_id": cID, "venue_id": tenant.VenueFromContext(ctx)}).
+		Order(goqu.C("fid").Desc()).
+		Limit(opts.Limit).
+		Offset(opts.Offset).
+		ScanStructs(&ff)
+	if err != nil {
+		return nil, errors.DBError.Wrapf(err, "error getting fees of customer with ID %d", cID)
+	}
+	return ff, nil
+}
+
+func (r *feeRepository) WaiveFee(ctx context.Context, fID int, reason string) (f Fee, err error) {
+	ctx, span := tracing.StartSpan(ctx, "feeRepository.WaiveFee", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	where := goqu.Ex{"fid": fID, "venue_id": tenant.VenueFromContext(ctx)}
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		found, err := tx.From("reservation_fee").Where(where).ScanStruct(&f)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return errors.NotFound.Newf("fee with ID %d not found", fID).
+				AddContext("FeeID", "non existent ID")
+		}
+		if f.Waived {
+			return errors.Conflict.Newf("fee %d was already waived by %s", fID, f.WaivedBy).
+				AddContext("FeeID", "already waived")
+		}
+
+		before := f
+		f.Waived = true
+		f.WaivedBy = request.ActorFromContext(ctx)
+		f.WaiverReason = reason
+		f.LastUpdated = time.Now().Unix()
+		if _, err := tx.From("reservation_fee").Prepared(true).Where(where).Update(goqu.Record{
+			"waived":        f.Waived,
+			"waived_by":     f.WaivedBy,
+			"waiver_reason": f.WaiverReason,
+			"last_updated":  f.LastUpdated,
+		}).Exec(); err != nil {
+			return err
+		}
+		if err := audit.Append(ctx, tx, audit.ActionUpdate, auditEntity, fID, before, f); err != nil {
+			return err
+		}
+		if err := payment.ReleaseFee(ctx, tx, f.VenueID, fID, f.LastUpdated); err != nil {
+			return err
+		}
+
+		if f.Kind != Modification {
+			return nil
+		}
+		_, err = tx.From("reservation").Where(goqu.Ex{"rid": f.ReservationID, "venue_id": f.VenueID}).
+			Update(goqu.Record{"fee": goqu.L("fee - ?", f.Amount)}).Exec()
+		return err
+	})
+
+	if t := errors.GetType(err); t == errors.NotFound || t == errors.Conflict {
+		return f, err
+	}
+	if err != nil {
+		return f, errors.DBError.Wrapf(err, "error waiving fee with ID %d", fID)
+	}
+	return f, nil
+}
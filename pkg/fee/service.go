@@ -0,0 +1,84 @@
+// Package fee keeps the ledger of the fees charged for cancelling or
+// modifying reservations past the free period of the cancellation policy,
+// which the staff may waive.
+package fee
+
+import (
+	"context"
+	errors "reservations/pkg/error"
+	"reservations/pkg/payment"
+	"reservations/pkg/storage"
+	"strings"
+)
+
+type Service interface {
+	GetFeeByID(ctx context.Context, fID int) (Fee, error)
+	GetFeesPerCustomer(ctx context.Context, cID int, opts *storage.QueryOptions) ([]Fee, error)
+	// WaiveFee lets the customer off the fee fID, giving the reason why.
+	// What a deposit kept in payment of the fee is refunded.
+	WaiveFee(ctx context.Context, fID int, reason string) (Fee, error)
+}
+
+// Kind tells what a fee is charged for.
+type Kind string
+
+const (
+	Cancellation Kind = "cancellation"
+	// Modification fees are charged for moving a reservation or reducing
+	// its party, as for cancelling the guests given up.
+	Modification Kind = "modification"
+)
+
+type Fee struct {
+	FeeID int `json:"feeId" db:"fid" goqu:"skipinsert"`
+	// ReservationID is the reservation charged for, which no longer exists
+	// once cancelled.
+	ReservationID int  `json:"reservationId" db:"rid"`
+	CustomerID    int  `json:"customerId" db:"customer_id"`
+	Kind          Kind `json:"kind" swaggertype:"string" enums:"cancellation,modification"`
+	// Policy names the cancellation terms the fee is charged under.
+	Policy string `json:"policy"`
+	// Amount is in the minor unit of the currency, such as cents.
+	Amount       int64  `json:"amount"`
+	Waived       bool   `json:"waived"`
+	WaivedBy     string `json:"waivedBy,omitempty" db:"waived_by"`
+	WaiverReason string `json:"waiverReason,omitempty" db:"waiver_reason"`
+	VenueID      int    `json:"venueId" db:"venue_id"`
+	Created      int64  `json:"created"`
+	LastUpdated  int64  `json:"lastUpdated" db:"last_updated"`
+}
+
+type feeService struct {
+	repo     Repository
+	payments payment.Service
+}
+
+// NewFeeService returns the fee service, refunding deposits kept in payment
+// of fees waived through payments unless nil.
+func NewFeeService(repo Repository, payments payment.Service) Service {
+	return &feeService{repo: repo, payments: payments}
+}
+
+func (s *feeService) GetFeeByID(ctx context.Context, fID int) (Fee, error) {
+	return s.repo.FindFeeByID(ctx, fID)
+}
+
+func (s *feeService) GetFeesPerCustomer(ctx context.Context, cID int, opts *storage.QueryOptions) ([]Fee, error) {
+	return s.repo.FindFeesByCustomerID(ctx, cID, opts)
+}
+
+func (s *feeService) WaiveFee(ctx context.Context, fID int, reason string) (Fee, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return Fee{}, errors.ValidationError.New("no reason given for waiving the fee").
+			AddContext("reason", "must not be empty")
+	}
+	f, err := s.repo.WaiveFee(ctx, fID, reason)
+	if err != nil || s.payments == nil {
+		return f, err
+	}
+	// The refund stored with the waiver is retried by the payment.Settler
+	// should the gateway fail.
+	s.payments.ProcessSettlement(ctx, f.ReservationID)
+	return f, nil
+}
@@ -0,0 +1,102 @@
+package fee
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/doug-martin/goqu/v7"
+	"reservations/pkg/audit"
+	errors "reservations/pkg/error"
+	"reservations/pkg/payment"
+	"reservations/pkg/request"
+	"reservations/pkg/storage"
+	"reservations/pkg/storage/storagetest"
+	"reservations/pkg/tenant"
+	"testing"
+	"time"
+)
+
+// addFee charges a cancellation fee of 3000 for the reservation rID, keeping
+// as much of its deposit of 5000 in payment, paid through payments.
+func addFee(t *testing.T, db *storage.Persistence, payments payment.Service, rID int) Fee {
+	ctx := context.Background()
+	now := time.Now().Unix()
+	f := &Fee{ReservationID: rID, Kind: Cancellation, Policy: "late", Amount: 3000, VenueID: tenant.DefaultVenueID}
+	d := &payment.Deposit{ReservationID: rID, Policy: "large", Amount: 5000, Currency: "eur", PayBy: now + 3600, VenueID: f.VenueID}
+	err := db.WithTx(func(tx *goqu.TxDatabase) error {
+		cID, err := storage.InsertReturningID(tx, "customer", "cid", goqu.Record{"first_name": "Ann", "last_name": "Doe", "email": "ann@example.com", "venue_id": f.VenueID})
+		if err != nil {
+			return err
+		}
+		f.CustomerID, d.CustomerID = cID, cID
+		if err := Insert(tx, f, now); err != nil {
+			return err
+		}
+		return payment.InsertDeposit(tx, d, now)
+	})
+	if err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	if _, err := payments.PayDeposit(ctx, rID, "pm_card_visa"); err != nil {
+		t.Fatalf("PayDeposit: %v", err)
+	}
+	err = db.WithTx(func(tx *goqu.TxDatabase) error {
+		_, err := payment.Settle(ctx, tx, f.VenueID, rID, f.Amount, f.FeeID, now)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Settle: %v", err)
+	}
+	if d, err := payments.ProcessSettlement(ctx, rID); err != nil || d.Captured != f.Amount {
+		t.Fatalf("ProcessSettlement = %+v, %v, want the fee captured", d, err)
+	}
+	return *f
+}
+
+func TestWaiveFee(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		payments := payment.NewPaymentService(payment.NewPaymentRepository(*db), payment.NewFakeGateway())
+		s := NewFeeService(NewFeeRepository(*db), payments)
+		ctx := request.WithActor(context.Background(), "manager")
+		f := addFee(t, db, payments, 1)
+
+		_, err := s.WaiveFee(ctx, f.FeeID, " ")
+		if errors.GetType(err) != errors.ValidationError || errors.GetErrorContext(err)["field"] != "reason" {
+			t.Errorf("WaiveFee without a reason = %v, want a ValidationError", err)
+		}
+
+		waived, err := s.WaiveFee(ctx, f.FeeID, " regular guest ")
+		if err != nil || !waived.Waived || waived.WaivedBy != "manager" || waived.WaiverReason != "regular guest" {
+			t.Fatalf("WaiveFee = %+v, %v, want it waived by manager", waived, err)
+		}
+		if got, err := s.GetFeeByID(ctx, f.FeeID); err != nil || got != waived {
+			t.Errorf("GetFeeByID = %+v, %v, want %+v", got, err, waived)
+		}
+
+		if _, err := s.WaiveFee(ctx, f.FeeID, "twice"); errors.GetType(err) != errors.Conflict {
+			t.Errorf("WaiveFee of a fee waived = %v, want a Conflict", err)
+		}
+		if _, err := s.WaiveFee(ctx, f.FeeID+1, "unknown"); errors.GetType(err) != errors.NotFound {
+			t.Errorf("WaiveFee of an unknown fee = %v, want a NotFound", err)
+		}
+
+		ee, err := audit.NewAuditRepository(*db).FindEntries(ctx, tenant.DefaultVenueID,
+			&audit.Filter{EntityType: auditEntity, EntityID: f.FeeID}, &storage.QueryOptions{})
+		if err != nil || len(ee) != 1 || ee[0].Actor != "manager" || ee[0].Action != audit.ActionUpdate {
+			t.Fatalf("audit entries = %+v, %v, want the waiver by manager", ee, err)
+		}
+		var before, after Fee
+		if err := json.Unmarshal(ee[0].Before, &before); err != nil || before.Waived {
+			t.Errorf("audit entry before = %s, %v, want the fee charged", ee[0].Before, err)
+		}
+		if err := json.Unmarshal(ee[0].After, &after); err != nil || after != waived {
+			t.Errorf("audit entry after = %s, %v, want the fee waived", ee[0].After, err)
+		}
+
+		// The deposit kept in payment of the fee is refunded.
+		d, err := payments.GetDeposit(ctx, f.ReservationID)
+		if err != nil || d.Status != payment.StatusRefunded || d.Refunded != f.Amount || d.Keep != 0 {
+			t.Errorf("deposit after the fee was waived = %+v, %v, want %d refunded", d, err, f.Amount)
+		}
+	})
+}
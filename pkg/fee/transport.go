@@ -0,0 +1,81 @@
+package fee
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"net/http"
+	"reservations/pkg/authz"
+	"reservations/pkg/transport"
+)
+
+func MakeHTTPHandler(r *mux.Router, s Service, az authz.Authorizer, logger log.Logger, mw ...endpoint.Middleware) *mux.Router {
+	e := MakeServerEndpoints(s, az, mw...)
+
+	options := httpjson.DefaultServerOptions(logger)
+
+	r.Methods("GET").Path("/fee/{id}").
+		Handler(httptransport.NewServer(
+			e.GetFeeByIDEndpoint,
+			decodeGetFeeByIDRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("GET").Path("/customer/{id}/fees").
+		Handler(httptransport.NewServer(
+			e.GetFeesPerCustomerEndpoint,
+			decodeGetFeesPerCustomerRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("POST").Path("/fee/{id}/waive").
+		Handler(httptransport.NewServer(
+			e.WaiveFeeEndpoint,
+			decodeWaiveFeeRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	return r
+}
+
+func decodeGetFeeByIDRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "fee ID")
+	if err != nil {
+		return nil, err
+	}
+	return getFeeByIDRequest{FeeID: id}, nil
+}
+
+func decodeGetFeesPerCustomerRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "customer ID")
+	if err != nil {
+		return nil, err
+	}
+
+	return getFeesPerCustomerRequest{
+		CustomerID: id,
+		Limit:      httpjson.ParseUintQueryParam(r, "limit"),
+		Offset:     httpjson.ParseUintQueryParam(r, "offset"),
+	}, nil
+}
+
+func decodeWaiveFeeRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req waiveFeeRequest
+
+	id, err := httpjson.ParseIntPathParam(r, "id", "fee ID")
+	if err != nil {
+		return nil, err
+	}
+
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, e
+	}
+	req.FeeID = id
+	return req, nil
+}
@@ -0,0 +1,61 @@
+package hoststand
+
+import (
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"reservations/pkg/auth"
+	"reservations/pkg/authz"
+	"reservations/pkg/request"
+	"reservations/pkg/tenant"
+)
+
+type Endpoints struct {
+	JoinEndpoint endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns the hub endpoints, each checked against the
+// policy by az and wrapped in the given middlewares, the first one
+// outermost.
+func MakeServerEndpoints(h *Hub, az authz.Authorizer, mw ...endpoint.Middleware) Endpoints {
+	e := Endpoints{
+		JoinEndpoint: az.Require(authz.PermFloorHost, nil)(MakeJoinEndpoint(h)),
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		m := mw[i]
+		e.JoinEndpoint = m(e.JoinEndpoint)
+	}
+	return e
+}
+
+type joinRequest struct{}
+
+// joinResponse tells the transport who is joining where; the client is only
+// registered once the connection is upgraded.
+type joinResponse struct {
+	VenueID int
+	Name    string
+}
+
+// Join godoc
+// @Summary Join the host stand channel
+// @Description Open a WebSocket relaying reservation changes, the hosts present and their edit locks to every host of the venue. Clients send {"type":"lock","reservationId":n} to take or refresh a lock, which expires unless refreshed, and {"type":"unlock","reservationId":n} to release it. Changes to a reservation release its lock.
+// @Tags hoststand
+// @Success 101 {object} hoststand.Message
+// @Router /hoststand [get]
+func MakeJoinEndpoint(_ *Hub) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		return joinResponse{
+			VenueID: tenant.VenueFromContext(ctx),
+			Name:    hostName(ctx),
+		}, nil
+	}
+}
+
+// hostName names the caller by their principal, or by the actor they claim
+// when authentication is disabled.
+func hostName(ctx context.Context) string {
+	if p, ok := auth.PrincipalFromContext(ctx); ok {
+		return p.Subject
+	}
+	return request.ActorFromContext(ctx)
+}
@@ -0,0 +1,298 @@
+package hoststand
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	errors "reservations/pkg/error"
+	"reservations/pkg/event"
+	"reservations/pkg/request"
+	"reservations/pkg/reservation"
+	"reservations/pkg/tenant"
+	"sort"
+	"sync"
+	"time"
+)
+
+const sendBuffer = 64
+
+// Types of the messages exchanged with the clients.
+const (
+	// MessageWelcome is sent to a client joining, with the hosts present
+	// and the locks held.
+	MessageWelcome = "welcome"
+	// MessagePresence is sent when a host joins or leaves.
+	MessagePresence    = "presence"
+	MessageReservation = "reservation"
+	MessageLocked      = "locked"
+	MessageUnlocked    = "unlocked"
+	MessageError       = "error"
+
+	// MessageLock is sent by a client to take or refresh the lock of a
+	// reservation, and MessageUnlock to release it.
+	MessageLock   = "lock"
+	MessageUnlock = "unlock"
+)
+
+// Message is a message sent to the clients.
+type Message struct {
+	Type string `json:"type"`
+	// You is the host a welcome message is sent to.
+	You           *Host                    `json:"you,omitempty"`
+	Hosts         []Host                   `json:"hosts,omitempty"`
+	Locks         []Lock                   `json:"locks,omitempty"`
+	Lock          *Lock                    `json:"lock,omitempty"`
+	ReservationID int                      `json:"reservationId,omitempty"`
+	Event         event.Type               `json:"event,omitempty"`
+	Reservation   *reservation.Reservation `json:"reservation,omitempty"`
+	Error         error                    `json:"error,omitempty"`
+}
+
+// Host is a connected client.
+type Host struct {
+	ClientID string `json:"clientId"`
+	Name     string `json:"name"`
+	Since    int64  `json:"since"`
+}
+
+// Lock tells the other hosts that a reservation is being edited. Locks are
+// advisory: they are not checked on changes, but released by them.
+type Lock struct {
+	ReservationID int   `json:"reservationId"`
+	Host          Host  `json:"host"`
+	ExpiresAt     int64 `json:"expiresAt"`
+}
+
+type heldLock struct {
+	Lock
+	holder *Client
+}
+
+// Client is the connection of a host.
+type Client struct {
+	host    Host
+	venueID int
+	send    chan Message
+}
+
+// room holds the hosts connected to one venue.
+type room struct {
+	clients map[*Client]struct{}
+	locks   map[int]heldLock
+}
+
+// Hub relays reservation changes, presence and edit locks between the hosts
+// of each venue. It implements reservation.Notifier, so that changes made
+// through the API are relayed as well.
+type Hub struct {
+	mu      sync.Mutex
+	rooms   map[int]*room
+	lockTTL time.Duration
+	logger  log.Logger
+}
+
+// NewHub returns a hub whose locks expire after lockTTL unless refreshed.
+func NewHub(lockTTL time.Duration, logger log.Logger) *Hub {
+	return &Hub{
+		rooms:   make(map[int]*room),
+		lockTTL: lockTTL,
+		logger:  logger,
+	}
+}
+
+// Join registers a client for the venue vID, welcomes it and tells the
+// other hosts.
+func (h *Hub) Join(vID int, name string) *Client {
+	c := &Client{
+		host: Host{
+			ClientID: request.NewID(),
+			Name:     name,
+			Since:    time.Now().Unix(),
+		},
+		venueID: vID,
+		send:    make(chan Message, sendBuffer),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rm, ok := h.rooms[vID]
+	if !ok {
+		rm = &room{clients: make(map[*Client]struct{}), locks: make(map[int]heldLock)}
+		h.rooms[vID] = rm
+	}
+	rm.clients[c] = struct{}{}
+
+	h.sendTo(c, Message{Type: MessageWelcome, You: &c.host, Hosts: rm.hosts(), Locks: rm.lockList()})
+	h.broadcast(rm, Message{Type: MessagePresence, Hosts: rm.hosts()})
+	return c
+}
+
+// Leave unregisters c, releasing its locks. It may be called more than
+// once.
+func (h *Hub) Leave(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.drop(c)
+}
+
+// drop unregisters c, which is also done to clients not keeping up.
+func (h *Hub) drop(c *Client) {
+	rm := h.rooms[c.venueID]
+	if rm == nil {
+		return
+	}
+	if _, ok := rm.clients[c]; !ok {
+		return
+	}
+	delete(rm.clients, c)
+	close(c.send)
+
+	for rID, l := range rm.locks {
+		if l.holder == c {
+			delete(rm.locks, rID)
+			h.broadcast(rm, Message{Type: MessageUnlocked, ReservationID: rID})
+		}
+	}
+	if len(rm.clients) == 0 {
+		delete(h.rooms, c.venueID)
+		return
+	}
+	h.broadcast(rm, Message{Type: MessagePresence, Hosts: rm.hosts()})
+}
+
+// Lock takes or refreshes the lock of c on the reservation rID.
+func (h *Hub) Lock(c *Client, rID int) error {
+	if rID <= 0 {
+		return errors.ValidationError.Newf("invalid reservation ID %d", rID).
+			AddContext("reservationId", "must be positive")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rm := h.rooms[c.venueID]
+	if rm == nil {
+		return nil
+	}
+	if l, ok := rm.locks[rID]; ok && l.holder != c {
+		return errors.Conflict.Newf("reservation %d is being edited by %s", rID, l.Host.Name).
+			AddContext("reservationId", "locked")
+	}
+
+	l := Lock{
+		ReservationID: rID,
+		Host:          c.host,
+		ExpiresAt:     time.Now().Add(h.lockTTL).Unix(),
+	}
+	rm.locks[rID] = heldLock{Lock: l, holder: c}
+	h.broadcast(rm, Message{Type: MessageLocked, Lock: &l})
+	return nil
+}
+
+// Unlock releases the lock of c on the reservation rID, if it holds it.
+func (h *Hub) Unlock(c *Client, rID int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rm := h.rooms[c.venueID]
+	if rm == nil {
+		return
+	}
+	if l, ok := rm.locks[rID]; ok && l.holder == c {
+		delete(rm.locks, rID)
+		h.broadcast(rm, Message{Type: MessageUnlocked, ReservationID: rID})
+	}
+}
+
+// ReservationChanged relays a change to the hosts of the venue of ctx,
+// releasing the lock on the reservation as the edit it guarded is done.
+func (h *Hub) ReservationChanged(ctx context.Context, t event.Type, res reservation.Reservation) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rm := h.rooms[tenant.VenueFromContext(ctx)]
+	if rm == nil {
+		return
+	}
+	h.broadcast(rm, Message{Type: MessageReservation, Event: t, ReservationID: res.ReservationID, Reservation: &res})
+	if _, ok := rm.locks[res.ReservationID]; ok {
+		delete(rm.locks, res.ReservationID)
+		h.broadcast(rm, Message{Type: MessageUnlocked, ReservationID: res.ReservationID})
+	}
+}
+
+// Run expires locks not refreshed in time until ctx is cancelled.
+func (h *Hub) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			h.expire(now.Unix())
+		}
+	}
+}
+
+func (h *Hub) expire(now int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, rm := range h.rooms {
+		for rID, l := range rm.locks {
+			if l.ExpiresAt <= now {
+				delete(rm.locks, rID)
+				h.broadcast(rm, Message{Type: MessageUnlocked, ReservationID: rID})
+			}
+		}
+	}
+}
+
+// reply sends m to c alone.
+func (h *Hub) reply(c *Client, m Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if rm := h.rooms[c.venueID]; rm != nil {
+		if _, ok := rm.clients[c]; ok {
+			h.sendTo(c, m)
+		}
+	}
+}
+
+func (h *Hub) broadcast(rm *room, m Message) {
+	for c := range rm.clients {
+		h.sendTo(c, m)
+	}
+}
+
+// sendTo queues m for c, dropping c if it does not keep up. The lock must
+// be held.
+func (h *Hub) sendTo(c *Client, m Message) {
+	select {
+	case c.send <- m:
+	default:
+		h.logger.Log("component", "hoststand", "client", c.host.ClientID, "err", "dropped as it fell behind")
+		h.drop(c)
+	}
+}
+
+func (rm *room) hosts() []Host {
+	hh := make([]Host, 0, len(rm.clients))
+	for c := range rm.clients {
+		hh = append(hh, c.host)
+	}
+	sort.Slice(hh, func(i, j int) bool { return hh[i].Since < hh[j].Since })
+	return hh
+}
+
+func (rm *room) lockList() []Lock {
+	ll := make([]Lock, 0, len(rm.locks))
+	for _, l := range rm.locks {
+		ll = append(ll, l.Lock)
+	}
+	sort.Slice(ll, func(i, j int) bool { return ll[i].ReservationID < ll[j].ReservationID })
+	return ll
+}
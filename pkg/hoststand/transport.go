@@ -0,0 +1,137 @@
+package hoststand
+
+import (
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"reservations/pkg/authz"
+	errors "reservations/pkg/error"
+	"reservations/pkg/transport"
+	"time"
+)
+
+const (
+	writeWait  = 10 * time.Second
+	pongWait   = 60 * time.Second
+	pingPeriod = pongWait * 9 / 10
+	// maxMessageSize bounds the messages read from clients, which only
+	// send small commands.
+	maxMessageSize = 4096
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+func MakeHTTPHandler(r *mux.Router, h *Hub, az authz.Authorizer, logger log.Logger, mw ...endpoint.Middleware) *mux.Router {
+	e := MakeServerEndpoints(h, az, mw...)
+
+	options := httpjson.DefaultServerOptions(logger)
+
+	r.Methods("GET").Path("/hoststand").
+		Handler(httpjson.KeepConnection(httptransport.NewServer(
+			e.JoinEndpoint,
+			decodeJoinRequest,
+			encodeJoinResponse(h, logger),
+			options...,
+		)))
+
+	return r
+}
+
+func decodeJoinRequest(_ context.Context, _ *http.Request) (request interface{}, err error) {
+	return joinRequest{}, nil
+}
+
+// command is a message received from a client.
+type command struct {
+	Type          string `json:"type"`
+	ReservationID int    `json:"reservationId"`
+}
+
+// encodeJoinResponse upgrades the connection and serves the client until it
+// goes away.
+func encodeJoinResponse(h *Hub, logger log.Logger) httptransport.EncodeResponseFunc {
+	return func(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+		resp := response.(joinResponse)
+
+		rw, r, ok := httpjson.ConnectionFromContext(ctx)
+		if !ok {
+			return errors.New("connection not available for upgrade")
+		}
+		conn, err := upgrader.Upgrade(rw, r, nil)
+		if err != nil {
+			// The upgrader has replied already.
+			logger.Log("component", "hoststand", "err", err)
+			return nil
+		}
+		defer conn.Close()
+
+		c := h.Join(resp.VenueID, resp.Name)
+		defer h.Leave(c)
+		go writePump(conn, c)
+
+		conn.SetReadLimit(maxMessageSize)
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(pongWait))
+		})
+
+		for {
+			var cmd command
+			if err := conn.ReadJSON(&cmd); err != nil {
+				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+					logger.Log("component", "hoststand", "client", c.host.ClientID, "err", err)
+				}
+				return nil
+			}
+
+			switch cmd.Type {
+			case MessageLock:
+				err = h.Lock(c, cmd.ReservationID)
+			case MessageUnlock:
+				h.Unlock(c, cmd.ReservationID)
+			default:
+				err = errors.ValidationError.Newf("unknown message type %q", cmd.Type).
+					AddContext("type", "must be lock or unlock")
+			}
+			if err != nil {
+				h.reply(c, Message{Type: MessageError, ReservationID: cmd.ReservationID, Error: err})
+			}
+		}
+	}
+}
+
+// writePump sends the messages queued for c and keeps the connection alive,
+// closing it once c has left or been dropped.
+func writePump(conn *websocket.Conn, c *Client) {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+
+	for {
+		select {
+		case m, ok := <-c.send:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := conn.WriteJSON(m); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
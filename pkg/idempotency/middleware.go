@@ -0,0 +1,248 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/go-kit/kit/log"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"reservations/pkg/auth"
+	errors "reservations/pkg/error"
+	"reservations/pkg/tenant"
+	"reservations/pkg/transport"
+	"time"
+)
+
+const (
+	// HeaderKey is the request header carrying the client supplied idempotency key.
+	HeaderKey = "Idempotency-Key"
+	// HeaderReplayed is set on responses served from a stored record.
+	HeaderReplayed = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+	// pendingTTL is how long a key is held by a request being served, after
+	// which it is released should the server have died meanwhile.
+	pendingTTL = time.Minute
+)
+
+// ClientFunc resolves the identity of the client issuing a request. Keys are
+// scoped per client and venue so two clients can never replay each other's
+// responses, nor a client a response of another venue.
+// Requests of clients without an identity are served without idempotency.
+type ClientFunc func(r *http.Request) string
+
+// Middleware stores the first response to a POST request carrying an
+// Idempotency-Key header and replays it on retries with the same key. Reusing
+// a key with a different request body, or while the first request is still
+// being served, is rejected.
+func Middleware(repo Repository, ttl time.Duration, clientFunc ClientFunc, logger log.Logger) func(http.Handler) http.Handler {
+	if clientFunc == nil {
+		clientFunc = DefaultClientFunc
+	}
+
+	return func(next http.Handler) http.Handler {
+		return &idempotencyHandler{
+			next:       next,
+			repo:       repo,
+			ttl:        ttl,
+			clientFunc: clientFunc,
+			logger:     logger,
+		}
+	}
+}
+
+// DefaultClientFunc identifies clients by their remote host, for deployments
+// without authentication.
+func DefaultClientFunc(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// PrincipalClientFunc identifies clients by the principal authenticated by
+// auth.HTTPMiddleware, which must run first.
+func PrincipalClientFunc(r *http.Request) string {
+	p, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		return ""
+	}
+	return p.Subject
+}
+
+type idempotencyHandler struct {
+	next       http.Handler
+	repo       Repository
+	ttl        time.Duration
+	clientFunc ClientFunc
+	logger     log.Logger
+}
+
+func (h *idempotencyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get(HeaderKey)
+	if r.Method != http.MethodPost || key == "" {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	if len(key) > maxKeyLength {
+		httpjson.EncodeError(r.Context(), errors.ValidationError.Newf("idempotency key longer than %d characters", maxKeyLength).
+			AddContext(HeaderKey, "too long"), w)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		httpjson.EncodeError(r.Context(), errors.ValidationError.Wrap(err, "error reading request body"), w)
+		return
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	clientID := h.clientFunc(r)
+	if clientID == "" {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+	venue := venueOf(r)
+	hash := requestHash(r, venue, body)
+	now := time.Now()
+
+	rec := &Record{
+		ClientID:    clientID,
+		Venue:       venue,
+		Key:         key,
+		RequestHash: hash,
+		StatusCode:  StatusPending,
+		Created:     now.Unix(),
+		ExpiresAt:   now.Add(pendingTTL).Unix(),
+	}
+	if !h.reserve(w, r, rec) {
+		return
+	}
+
+	rw := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+	h.next.ServeHTTP(rw, r)
+
+	// Server errors are not stored so that the client can safely retry them.
+	if rw.statusCode >= http.StatusInternalServerError {
+		if err := h.repo.ReleaseRecord(r.Context(), clientID, venue, key); err != nil {
+			h.logger.Log("component", "idempotency", "key", key, "client", clientID, "err", err)
+		}
+		return
+	}
+
+	rec.StatusCode = rw.statusCode
+	rec.ContentType = rw.Header().Get("Content-Type")
+	rec.Body = rw.body.Bytes()
+	rec.ExpiresAt = now.Add(h.ttl).Unix()
+	if err := h.repo.CompleteRecord(r.Context(), rec); err != nil {
+		h.logger.Log("component", "idempotency", "key", key, "client", clientID, "err", err)
+	}
+}
+
+// reserve holds the key of rec for the request r, reporting whether it did.
+// Otherwise the response stored for the key is replayed, or the reuse of
+// the key rejected.
+func (h *idempotencyHandler) reserve(w http.ResponseWriter, r *http.Request, rec *Record) bool {
+	// A second attempt follows the removal of an expired record.
+	for attempt := 0; attempt < 2; attempt++ {
+		ok, err := h.repo.ReserveRecord(r.Context(), rec)
+		if err != nil {
+			httpjson.EncodeError(r.Context(), err, w)
+			return false
+		}
+		if ok {
+			return true
+		}
+
+		stored, err := h.repo.FindRecord(r.Context(), rec.ClientID, rec.Venue, rec.Key)
+		switch {
+		case errors.GetType(err) == errors.NotFound:
+			// Released by a request failing meanwhile.
+			continue
+		case err != nil:
+			httpjson.EncodeError(r.Context(), err, w)
+			return false
+		case stored.ExpiresAt <= rec.Created:
+			if err := h.repo.RemoveRecord(r.Context(), rec.ClientID, rec.Venue, rec.Key, rec.Created); err != nil {
+				httpjson.EncodeError(r.Context(), err, w)
+				return false
+			}
+			continue
+		case stored.RequestHash != rec.RequestHash:
+			httpjson.EncodeError(r.Context(), errors.Conflict.Newf("idempotency key %s was already used with a different request", rec.Key).
+				AddContext(HeaderKey, "reused with different request"), w)
+			return false
+		case stored.StatusCode == StatusPending:
+			httpjson.EncodeError(r.Context(), errors.Conflict.Newf("request with idempotency key %s is still being served", rec.Key).
+				AddContext(HeaderKey, "in use by a concurrent request"), w)
+			return false
+		}
+		replay(w, stored)
+		return false
+	}
+
+	httpjson.EncodeError(r.Context(), errors.Conflict.Newf("request with idempotency key %s is still being served", rec.Key).
+		AddContext(HeaderKey, "in use by a concurrent request"), w)
+	return false
+}
+
+func replay(w http.ResponseWriter, rec Record) {
+	if rec.ContentType != "" {
+		w.Header().Set("Content-Type", rec.ContentType)
+	}
+	w.Header().Set(HeaderReplayed, "true")
+	w.WriteHeader(rec.StatusCode)
+	w.Write(rec.Body)
+}
+
+// venueOf returns the slug of the venue r is made for, as tenant.Middleware
+// resolves it later on: named by the venue header, which tenant.StripPathPrefix
+// sets from the path, or else by the venue claim of the token. It is empty for
+// the default venue.
+func venueOf(r *http.Request) string {
+	if slug := r.Header.Get(tenant.HeaderVenue); slug != "" {
+		return slug
+	}
+	if p, ok := auth.PrincipalFromContext(r.Context()); ok {
+		if claim, ok := p.Claims[tenant.ClaimVenue].(string); ok {
+			return claim
+		}
+	}
+	return ""
+}
+
+func requestHash(r *http.Request, venue string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte(venue))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder passes a response through to the client while keeping a
+// copy of the status code and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (rw *responseRecorder) WriteHeader(code int) {
+	if !rw.wroteHeader {
+		rw.statusCode = code
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *responseRecorder) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
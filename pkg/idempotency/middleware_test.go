@@ -0,0 +1,169 @@
+package idempotency
+
+import (
+	"fmt"
+	"github.com/doug-martin/goqu/v7"
+	"github.com/go-kit/kit/log"
+	"net/http"
+	"net/http/httptest"
+	"reservations/pkg/storage"
+	"reservations/pkg/storage/storagetest"
+	"reservations/pkg/tenant"
+	"strings"
+	"testing"
+	"time"
+)
+
+// counter serves requests with the number of requests it served so far.
+type counter struct {
+	served int
+	status int
+}
+
+func (c *counter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.served++
+	w.Header().Set("Content-Type", "application/json")
+	if c.status != 0 {
+		w.WriteHeader(c.status)
+	}
+	fmt.Fprintf(w, `{"served":%d}`, c.served)
+}
+
+func newTestHandler(db *storage.Persistence, next http.Handler) http.Handler {
+	return Middleware(NewIdempotencyRepository(*db), time.Hour, nil, log.NewNopLogger())(next)
+}
+
+func post(h http.Handler, path string, key string, body string, header ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	r.Header.Set(HeaderKey, key)
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestKeysAreScopedPerVenue(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		c := &counter{}
+		h := tenant.StripPathPrefix(newTestHandler(db, c))
+
+		for _, tc := range []struct {
+			path, venue, want string
+		}{
+			{"/reservation", "", `{"served":1}`},
+			{"/venue/bistro/reservation", "", `{"served":2}`},
+			{"/reservation", "bistro", `{"served":2}`},
+			{"/reservation", "cafe", `{"served":3}`},
+			{"/venue/cafe/reservation", "", `{"served":3}`},
+		} {
+			w := post(h, tc.path, "key-1", `{"seatCount":2}`, tenant.HeaderVenue, tc.venue)
+			if w.Code != http.StatusOK || w.Body.String() != tc.want {
+				t.Errorf("POST %s for venue %q = %d %s, want %s", tc.path, tc.venue, w.Code, w.Body, tc.want)
+			}
+		}
+	})
+}
+
+func TestReplaysStoredResponses(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		c := &counter{status: http.StatusCreated}
+		h := newTestHandler(db, c)
+
+		w := post(h, "/reservation", "key-1", `{"seatCount":2}`)
+		if w.Code != http.StatusCreated || w.Header().Get(HeaderReplayed) != "" {
+			t.Fatalf("first POST = %d %s, want it served", w.Code, w.Body)
+		}
+		w = post(h, "/reservation", "key-1", `{"seatCount":2}`)
+		if w.Code != http.StatusCreated || w.Body.String() != `{"served":1}` ||
+			w.Header().Get(HeaderReplayed) != "true" || w.Header().Get("Content-Type") != "application/json" {
+			t.Errorf("retried POST = %d %v %s, want the first response replayed", w.Code, w.Header(), w.Body)
+		}
+
+		w = post(h, "/reservation", "key-1", `{"seatCount":4}`)
+		if w.Code != http.StatusConflict {
+			t.Errorf("POST with a different body = %d %s, want a Conflict", w.Code, w.Body)
+		}
+		w = post(h, "/customer", "key-1", `{"seatCount":2}`)
+		if w.Code != http.StatusConflict {
+			t.Errorf("POST to a different path = %d %s, want a Conflict", w.Code, w.Body)
+		}
+		if c.served != 1 {
+			t.Errorf("served %d requests, want 1", c.served)
+		}
+	})
+}
+
+// blocking serves requests once released, signalling when it started.
+type blocking struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blocking) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	close(b.started)
+	<-b.release
+	w.WriteHeader(http.StatusCreated)
+}
+
+func TestRejectsKeysInUse(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		b := &blocking{started: make(chan struct{}), release: make(chan struct{})}
+		h := newTestHandler(db, b)
+
+		done := make(chan *httptest.ResponseRecorder)
+		go func() {
+			done <- post(h, "/reservation", "key-1", `{"seatCount":2}`)
+		}()
+		<-b.started
+
+		w := post(h, "/reservation", "key-1", `{"seatCount":2}`)
+		if w.Code != http.StatusConflict {
+			t.Errorf("POST while the key is pending = %d %s, want a Conflict", w.Code, w.Body)
+		}
+
+		close(b.release)
+		if w := <-done; w.Code != http.StatusCreated {
+			t.Errorf("first POST = %d %s, want it served", w.Code, w.Body)
+		}
+	})
+}
+
+func TestReleasesKeysOnServerErrors(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		c := &counter{status: http.StatusServiceUnavailable}
+		h := newTestHandler(db, c)
+
+		if w := post(h, "/reservation", "key-1", `{"seatCount":2}`); w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("first POST = %d %s, want it failed", w.Code, w.Body)
+		}
+		c.status = http.StatusCreated
+		w := post(h, "/reservation", "key-1", `{"seatCount":2}`)
+		if w.Code != http.StatusCreated || w.Body.String() != `{"served":2}` || w.Header().Get(HeaderReplayed) != "" {
+			t.Errorf("retried POST = %d %s, want it served again", w.Code, w.Body)
+		}
+	})
+}
+
+func TestServesExpiredKeysAgain(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		c := &counter{}
+		h := newTestHandler(db, c)
+
+		post(h, "/reservation", "key-1", `{"seatCount":2}`)
+		if _, err := db.DB.From("idempotency_key").Update(goqu.Record{"expires_at": time.Now().Add(-time.Minute).Unix()}).Exec(); err != nil {
+			t.Fatal(err)
+		}
+
+		// The record expired, so a different body may reuse the key.
+		w := post(h, "/reservation", "key-1", `{"seatCount":4}`)
+		if w.Code != http.StatusOK || w.Body.String() != `{"served":2}` || w.Header().Get(HeaderReplayed) != "" {
+			t.Errorf("POST with an expired key = %d %s, want it served again", w.Code, w.Body)
+		}
+		w = post(h, "/reservation", "key-1", `{"seatCount":4}`)
+		if w.Body.String() != `{"served":2}` || w.Header().Get(HeaderReplayed) != "true" {
+			t.Errorf("retried POST = %d %s, want the new response replayed", w.Code, w.Body)
+		}
+	})
+}
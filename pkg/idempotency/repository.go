@@ -0,0 +1,158 @@
+package idempotency
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v7"
+	"github.com/doug-martin/goqu/v7/exec"
+	errors "reservations/pkg/error"
+	"reservations/pkg/storage"
+	"reservations/pkg/tracing"
+)
+
+// StatusPending is the status code of a record whose request is still being
+// served.
+const StatusPending = 0
+
+// Record is a stored response for a client supplied Idempotency-Key.
+type Record struct {
+	ClientID string `db:"client_id"`
+	// Venue is the slug of the venue the request was made for, or empty for
+	// the default venue.
+	Venue       string `db:"venue"`
+	Key         string `db:"idempotency_key"`
+	RequestHash string `db:"request_hash"`
+	StatusCode  int    `db:"status_code"`
+	ContentType string `db:"content_type"`
+	Body        []byte `db:"body"`
+	Created     int64  `db:"created"`
+	ExpiresAt   int64  `db:"expires_at"`
+}
+
+type Repository interface {
+	// ReserveRecord stores the pending record rec unless one exists for
+	// its client, venue and key, reporting whether it did.
+	ReserveRecord(ctx context.Context, rec *Record) (bool, error)
+	// CompleteRecord stores the response of the pending record rec.
+	CompleteRecord(ctx context.Context, rec *Record) error
+	// ReleaseRecord deletes the record of clientID, venue and key while
+	// pending.
+	ReleaseRecord(ctx context.Context, clientID string, venue string, key string) error
+	// RemoveRecord deletes the record of clientID, venue and key if expired
+	// at now.
+	RemoveRecord(ctx context.Context, clientID string, venue string, key string, now int64) error
+	FindRecord(ctx context.Context, clientID string, venue string, key string) (Record, error)
+}
+
+type idempotencyRepository struct {
+	db storage.Persistence
+}
+
+func NewIdempotencyRepository(db storage.Persistence) Repository {
+	return &idempotencyRepository{db: db}
+}
+
+func (r *idempotencyRepository) ReserveRecord(ctx context.Context, rec *Record) (_ bool, err error) {
+	_, span := tracing.StartSpan(ctx, "idempotencyRepository.ReserveRecord", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	_, err = r.db.Tx(func(tx *goqu.TxDatabase) exec.QueryExecutor {
+		return tx.From("idempotency_key").Prepared(true).Insert(rec)
+	})
+	if err == nil {
+		return true, nil
+	}
+
+	// The insert failing on the primary key, which the dialects report
+	// differently, leaves a record to be found.
+	n, e := r.db.DB.From("idempotency_key").Where(goqu.Ex{
+		"client_id":       rec.ClientID,
+		"venue":           rec.Venue,
+		"idempotency_key": rec.Key,
+	}).Count()
+	if e == nil && n > 0 {
+		return false, nil
+	}
+	return false, errors.DBError.Wrapf(err, "error storing idempotency key %s", rec.Key)
+}
+
+func (r *idempotencyRepository) CompleteRecord(ctx context.Context, rec *Record) (err error) {
+	_, span := tracing.StartSpan(ctx, "idempotencyRepository.CompleteRecord", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	_, err = r.db.Tx(func(tx *goqu.TxDatabase) exec.QueryExecutor {
+		return tx.From("idempotency_key").Prepared(true).Where(goqu.Ex{
+			"client_id":       rec.ClientID,
+			"venue":           rec.Venue,
+			"idempotency_key": rec.Key,
+		}).Update(goqu.Record{
+			"status_code":  rec.StatusCode,
+			"content_type": rec.ContentType,
+			"body":         rec.Body,
+			"expires_at":   rec.ExpiresAt,
+		})
+	})
+
+	if err != nil {
+		return errors.DBError.Wrapf(err, "error storing response for idempotency key %s", rec.Key)
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) ReleaseRecord(ctx context.Context, clientID string, venue string, key string) (err error) {
+	_, span := tracing.StartSpan(ctx, "idempotencyRepository.ReleaseRecord", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	_, err = r.db.Tx(func(tx *goqu.TxDatabase) exec.QueryExecutor {
+		return tx.From("idempotency_key").Prepared(true).Where(goqu.Ex{
+			"client_id":       clientID,
+			"venue":           venue,
+			"idempotency_key": key,
+			"status_code":     StatusPending,
+		}).Delete()
+	})
+
+	if err != nil {
+		return errors.DBError.Wrapf(err, "error deleting idempotency key %s", key)
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) RemoveRecord(ctx context.Context, clientID string, venue string, key string, now int64) (err error) {
+	_, span := tracing.StartSpan(ctx, "idempotencyRepository.RemoveRecord", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	_, err = r.db.Tx(func(tx *goqu.TxDatabase) exec.QueryExecutor {
+		return tx.From("idempotency_key").Prepared(true).Where(
+			goqu.C("client_id").Eq(clientID),
+			goqu.C("venue").Eq(venue),
+			goqu.C("idempotency_key").Eq(key),
+			goqu.C("expires_at").Lte(now),
+		).Delete()
+	})
+
+	if err != nil {
+		return errors.DBError.Wrapf(err, "error deleting idempotency key %s", key)
+	}
+	return nil
+}
+
+func (r *idempotencyRepository) FindRecord(ctx context.Context, clientID string, venue string, key string) (rec Record, err error) {
+	_, span := tracing.StartSpan(ctx, "idempotencyRepository.FindRecord", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	found, err := r.db.DB.From("idempotency_key").Prepared(true).Where(goqu.Ex{
+		"client_id":       clientID,
+		"venue":           venue,
+		"idempotency_key": key,
+	}).ScanStruct(&rec)
+
+	if err != nil {
+		return rec, errors.DBError.Wrapf(err, "error getting idempotency key %s", key)
+	}
+
+	if !found {
+		return rec, errors.NotFound.Newf("idempotency key %s not found", key)
+	}
+
+	return rec, nil
+}
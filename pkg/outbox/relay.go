@@ -0,0 +1,118 @@
+package outbox
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"reservations/pkg/event"
+	"sort"
+	"time"
+)
+
+const defaultBatchSize uint = 100
+
+// Relay delivers events from the outbox to a set of named publishers. Events
+// are sequenced in the order they were committed and queued for every
+// publisher, which receives them in that order: an event failing for one
+// publisher blocks the ones after it for that publisher only, until it is
+// delivered. Publishers may see an event more than once.
+//
+// The name of a publisher identifies its queue, so it must stay the same
+// across restarts; events queued for a publisher no longer registered are
+// not delivered.
+type Relay struct {
+	repo       Repository
+	publishers map[string]event.Publisher
+	names      []string
+	interval   time.Duration
+	batchSize  uint
+	logger     log.Logger
+}
+
+func NewRelay(repo Repository, interval time.Duration, logger log.Logger, publishers map[string]event.Publisher) *Relay {
+	names := make([]string, 0, len(publishers))
+	for name := range publishers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return &Relay{
+		repo:       repo,
+		publishers: publishers,
+		names:      names,
+		interval:   interval,
+		batchSize:  defaultBatchSize,
+		logger:     logger,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Flush(ctx); err != nil {
+			r.logger.Log("component", "outbox", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush queues the events recorded since the last flush and delivers all
+// pending events to every publisher.
+func (r *Relay) Flush(ctx context.Context) error {
+	for {
+		n, err := r.repo.DispatchPendingEvents(ctx, r.names, r.batchSize)
+		if err != nil {
+			return err
+		}
+		if uint(n) < r.batchSize {
+			break
+		}
+	}
+
+	for _, name := range r.names {
+		if err := r.deliver(ctx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver sends the events queued for the publisher name to it, stopping at
+// the first one it fails to accept.
+func (r *Relay) deliver(ctx context.Context, name string) error {
+	p := r.publishers[name]
+	for {
+		ee, err := r.repo.FindPendingDeliveries(ctx, name, r.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range ee {
+			if err := p.Publish(ctx, e); err != nil {
+				r.logger.Log("component", "outbox", "publisher", name, "event", e.EventID, "type", e.Type, "err", err)
+				return r.repo.MarkFailed(ctx, name, e.EventID, err)
+			}
+			if err := r.repo.MarkDelivered(ctx, name, e.EventID); err != nil {
+				return err
+			}
+		}
+
+		if uint(len(ee)) < r.batchSize {
+			return nil
+		}
+	}
+}
+
+// LogPublisher writes every event to the logger.
+func LogPublisher(logger log.Logger) event.Publisher {
+	return event.PublisherFunc(func(_ context.Context, e event.Event) error {
+		return logger.Log("event", e.EventID, "type", e.Type, "aggregate", e.AggregateType, "id", e.AggregateID)
+	})
+}
@@ -0,0 +1,171 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/doug-martin/goqu/v7"
+	"github.com/doug-martin/goqu/v7/exec"
+	errors "reservations/pkg/error"
+	"reservations/pkg/event"
+	"reservations/pkg/storage"
+	"reservations/pkg/tenant"
+	"reservations/pkg/tracing"
+	"time"
+)
+
+// Append writes an event to the outbox as part of the transaction that
+// performs the corresponding state change, in the venue of ctx.
+func Append(ctx context.Context, tx *goqu.TxDatabase, t event.Type, aggregateType string, aggregateID int, payload interface{}) error {
+	p, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrapf(err, "error encoding %s event payload", t)
+	}
+
+	_, err = tx.From("outbox").Prepared(true).Insert(&event.Event{
+		Type:          t,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		VenueID:       tenant.VenueFromContext(ctx),
+		Payload:       p,
+		Created:       time.Now().Unix(),
+	}).Exec()
+
+	if err != nil {
+		return errors.DBError.Wrapf(err, "error appending %s event to outbox", t)
+	}
+	return nil
+}
+
+type Repository interface {
+	// DispatchPendingEvents sequences up to limit events not yet seen by the
+	// relay, in the order they were committed, and queues each of them for
+	// delivery to every publisher. It returns the number of events queued.
+	DispatchPendingEvents(ctx context.Context, publishers []string, limit uint) (int, error)
+	// FindPendingDeliveries returns the events queued for the publisher, in
+	// sequence.
+	FindPendingDeliveries(ctx context.Context, publisher string, limit uint) ([]event.Event, error)
+	MarkDelivered(ctx context.Context, publisher string, eID int) error
+	MarkFailed(ctx context.Context, publisher string, eID int, cause error) error
+}
+
+type outboxRepository struct {
+	db storage.Persistence
+}
+
+func NewOutboxRepository(db storage.Persistence) Repository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) DispatchPendingEvents(ctx context.Context, publishers []string, limit uint) (n int, err error) {
+	_, span := tracing.StartSpan(ctx, "outboxRepository.DispatchPendingEvents", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		var ee []event.Event
+		err := tx.From("outbox").
+			Select("eid").
+			Where(goqu.C("published_at").IsNull()).
+			Order(goqu.C("eid").Asc()).
+			Limit(limit).
+			ScanStructs(&ee)
+		if err != nil || len(ee) == 0 {
+			return err
+		}
+
+		var seq int
+		if _, err := tx.From("outbox").Select(goqu.COALESCE(goqu.MAX("seq"), 0)).ScanVal(&seq); err != nil {
+			return err
+		}
+
+		now := time.Now().Unix()
+		for _, e := range ee {
+			seq++
+			// Another relay sequencing the same event makes the update miss
+			// or the sequence collide, rolling the whole batch back.
+			res, err := tx.From("outbox").
+				Where(goqu.C("eid").Eq(e.EventID), goqu.C("published_at").IsNull()).
+				Update(goqu.Record{"seq": seq, "published_at": now}).
+				Exec()
+			if err != nil {
+				return err
+			}
+			updated, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if updated == 0 {
+				return errors.Newf("outbox event %d was dispatched concurrently", e.EventID)
+			}
+
+			for _, p := range publishers {
+				_, err := tx.From("outbox_delivery").Insert(goqu.Record{
+					"publisher": p,
+					"eid":       e.EventID,
+					"seq":       seq,
+				}).Exec()
+				if err != nil {
+					return err
+				}
+			}
+		}
+		n = len(ee)
+		return nil
+	})
+
+	if err != nil {
+		return 0, errors.DBError.Wrap(err, "error dispatching pending outbox events")
+	}
+	return n, nil
+}
+
+func (r *outboxRepository) FindPendingDeliveries(ctx context.Context, publisher string, limit uint) (ee []event.Event, err error) {
+	_, span := tracing.StartSpan(ctx, "outboxRepository.FindPendingDeliveries", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	err = r.db.DB.From("outbox_delivery").
+		Join(goqu.T("outbox"), goqu.On(goqu.I("outbox.eid").Eq(goqu.I("outbox_delivery.eid")))).
+		Select(
+			goqu.I("outbox.eid"), goqu.I("outbox.seq"), "event_type", "aggregate_type", "aggregate_id", "venue_id",
+			"payload", "created",
+		).
+		Where(goqu.I("outbox_delivery.publisher").Eq(publisher)).
+		Order(goqu.I("outbox_delivery.seq").Asc()).
+		Limit(limit).
+		ScanStructs(&ee)
+
+	if err != nil {
+		return nil, errors.DBError.Wrapf(err, "error getting outbox events pending for %s", publisher)
+	}
+	return ee, nil
+}
+
+func (r *outboxRepository) MarkDelivered(ctx context.Context, publisher string, eID int) (err error) {
+	_, span := tracing.StartSpan(ctx, "outboxRepository.MarkDelivered", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	_, err = r.db.Tx(func(tx *goqu.TxDatabase) exec.QueryExecutor {
+		return tx.From("outbox_delivery").Where(goqu.Ex{"publisher": publisher, "eid": eID}).Delete()
+	})
+
+	if err != nil {
+		return errors.DBError.Wrapf(err, "error marking outbox event %d as delivered to %s", eID, publisher)
+	}
+	return nil
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, publisher string, eID int, cause error) (err error) {
+	_, span := tracing.StartSpan(ctx, "outboxRepository.MarkFailed", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	_, err = r.db.Tx(func(tx *goqu.TxDatabase) exec.QueryExecutor {
+		return tx.From("outbox_delivery").Prepared(true).Where(goqu.Ex{"publisher": publisher, "eid": eID}).Update(goqu.Record{
+			"attempts":   goqu.L("attempts + 1"),
+			"last_error": cause.Error(),
+		})
+	})
+
+	if err != nil {
+		return errors.DBError.Wrapf(err, "error recording failed delivery of outbox event %d to %s", eID, publisher)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"github.com/doug-martin/goqu/v7"
+	"reservations/pkg/event"
+	"reservations/pkg/storage"
+	"reservations/pkg/storage/storagetest"
+	"testing"
+)
+
+func appendEvents(t *testing.T, db *storage.Persistence, ids ...int) {
+	err := db.WithTx(func(tx *goqu.TxDatabase) error {
+		for _, id := range ids {
+			if err := Append(context.Background(), tx, event.ReservationBooked, event.AggregateReservation, id, map[string]int{"reservationId": id}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+}
+
+func aggregates(ee []event.Event) []int {
+	ids := make([]int, len(ee))
+	for i, e := range ee {
+		ids[i] = e.AggregateID
+	}
+	return ids
+}
+
+func TestDispatchPendingEvents(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		r := NewOutboxRepository(*db)
+		ctx := context.Background()
+		appendEvents(t, db, 1, 2, 3)
+
+		n, err := r.DispatchPendingEvents(ctx, []string{"log", "webhook"}, 2)
+		if err != nil || n != 2 {
+			t.Fatalf("DispatchPendingEvents = %d, %v, want 2 events", n, err)
+		}
+		appendEvents(t, db, 4)
+		if n, err := r.DispatchPendingEvents(ctx, []string{"log", "webhook"}, 10); err != nil || n != 2 {
+			t.Fatalf("DispatchPendingEvents = %d, %v, want the 2 remaining events", n, err)
+		}
+		if n, err := r.DispatchPendingEvents(ctx, []string{"log", "webhook"}, 10); err != nil || n != 0 {
+			t.Fatalf("DispatchPendingEvents with nothing pending = %d, %v", n, err)
+		}
+
+		ee, err := r.FindPendingDeliveries(ctx, "log", 10)
+		if err != nil {
+			t.Fatalf("FindPendingDeliveries: %v", err)
+		}
+		if got := aggregates(ee); len(got) != 4 || got[0] != 1 || got[3] != 4 {
+			t.Fatalf("events pending for log = %v, want 1 to 4", got)
+		}
+		for i, e := range ee {
+			if e.Sequence != i+1 || e.Type != event.ReservationBooked || len(e.Payload) == 0 {
+				t.Errorf("event %d = %+v, want sequence %d", i, e, i+1)
+			}
+		}
+	})
+}
+
+func TestDeliveriesArePerPublisher(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		r := NewOutboxRepository(*db)
+		ctx := context.Background()
+		appendEvents(t, db, 1, 2)
+		if _, err := r.DispatchPendingEvents(ctx, []string{"log", "webhook"}, 10); err != nil {
+			t.Fatalf("DispatchPendingEvents: %v", err)
+		}
+
+		ee, err := r.FindPendingDeliveries(ctx, "log", 10)
+		if err != nil {
+			t.Fatalf("FindPendingDeliveries: %v", err)
+		}
+		if err := r.MarkDelivered(ctx, "log", ee[0].EventID); err != nil {
+			t.Fatalf("MarkDelivered: %v", err)
+		}
+		if err := r.MarkFailed(ctx, "webhook", ee[0].EventID, errors.New("unreachable")); err != nil {
+			t.Fatalf("MarkFailed: %v", err)
+		}
+		if err := r.MarkFailed(ctx, "webhook", ee[0].EventID, errors.New("timeout")); err != nil {
+			t.Fatalf("MarkFailed: %v", err)
+		}
+
+		if ee, _ := r.FindPendingDeliveries(ctx, "log", 10); len(ee) != 1 || ee[0].AggregateID != 2 {
+			t.Errorf("events pending for log = %v, want only the second", aggregates(ee))
+		}
+		if ee, _ := r.FindPendingDeliveries(ctx, "webhook", 10); len(ee) != 2 || ee[0].AggregateID != 1 {
+			t.Errorf("events pending for webhook = %v, want both, the failed one first", aggregates(ee))
+		}
+
+		var failure struct {
+			Attempts  int    `db:"attempts"`
+			LastError string `db:"last_error"`
+		}
+		found, err := db.DB.From("outbox_delivery").Select("attempts", "last_error").
+			Where(goqu.Ex{"publisher": "webhook", "eid": ee[0].EventID}).ScanStruct(&failure)
+		if err != nil || !found {
+			t.Fatalf("reading delivery: %v", err)
+		}
+		if failure.Attempts != 2 || failure.LastError != "timeout" {
+			t.Errorf("delivery = %+v, want 2 attempts failing with the last error", failure)
+		}
+	})
+}
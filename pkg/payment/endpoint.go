@@ -0,0 +1,150 @@
+package payment
+
+import (
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"reservations/pkg/authz"
+)
+
+type Endpoints struct {
+	GetDepositEndpoint    endpoint.Endpoint
+	PayDepositEndpoint    endpoint.Endpoint
+	SettleDepositEndpoint endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns the service endpoints, each checked against
+// the policy by az and wrapped in the given middlewares, the first one
+// outermost.
+func MakeServerEndpoints(s Service, az authz.Authorizer, mw ...endpoint.Middleware) Endpoints {
+	owner := depositOwner(s)
+	e := Endpoints{
+		GetDepositEndpoint:    az.Require(authz.PermReservationRead, owner)(MakeGetDepositEndpoint(s)),
+		PayDepositEndpoint:    az.Require(authz.PermReservationWrite, owner)(MakePayDepositEndpoint(s)),
+		SettleDepositEndpoint: az.Require(authz.PermDepositSettle, nil)(MakeSettleDepositEndpoint(s)),
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		m := mw[i]
+		e.GetDepositEndpoint = m(e.GetDepositEndpoint)
+		e.PayDepositEndpoint = m(e.PayDepositEndpoint)
+		e.SettleDepositEndpoint = m(e.SettleDepositEndpoint)
+	}
+	return e
+}
+
+// depositOwner returns the customer owing the deposit a request addresses.
+func depositOwner(s Service) authz.OwnerFunc {
+	return func(ctx context.Context, request interface{}) (int, error) {
+		var rID int
+		switch req := request.(type) {
+		case getDepositRequest:
+			rID = req.ReservationID
+		case payDepositRequest:
+			rID = req.ReservationID
+		default:
+			return 0, nil
+		}
+		d, err := s.GetDeposit(ctx, rID)
+		return d.CustomerID, err
+	}
+}
+
+type getDepositRequest struct {
+	ReservationID int
+}
+
+type getDepositResponse struct {
+	Deposit Deposit `json:"deposit"`
+	Err     error   `json:"err,omitempty"`
+}
+
+func (r getDepositResponse) HTTPError() error { return r.Err }
+
+// GetDeposit godoc
+// @Summary Get the deposit of a reservation
+// @Description Get the deposit required to hold a reservation, with how much of it was paid, captured and refunded
+// @Tags payment
+// @Param id path string true "Reservation ID"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} payment.Deposit
+// @Router /reservation/{id}/deposit [get]
+func MakeGetDepositEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getDepositRequest)
+		d, e := s.GetDeposit(ctx, req.ReservationID)
+		return getDepositResponse{
+			Deposit: d,
+			Err:     e,
+		}, nil
+	}
+}
+
+type payDepositRequest struct {
+	ReservationID int `json:"-"`
+	// PaymentMethod is the token the payment provider gave the client for
+	// the card of the guest.
+	PaymentMethod string `json:"paymentMethod"`
+}
+
+type payDepositResponse struct {
+	Deposit Deposit `json:"deposit"`
+	Err     error   `json:"err,omitempty"`
+}
+
+func (r payDepositResponse) HTTPError() error { return r.Err }
+
+// PayDeposit godoc
+// @Summary Pay the deposit of a reservation
+// @Description Authorize the deposit of a reservation on a payment method before it is due, after which the reservation is cancelled
+// @Tags payment
+// @Param id path string true "Reservation ID"
+// @Param payment body payment.payDepositRequest true "Payment method"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} payment.Deposit
+// @Router /reservation/{id}/deposit [post]
+func MakePayDepositEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(payDepositRequest)
+		d, e := s.PayDeposit(ctx, req.ReservationID, req.PaymentMethod)
+		return payDepositResponse{
+			Deposit: d,
+			Err:     e,
+		}, nil
+	}
+}
+
+type settleDepositRequest struct {
+	ReservationID int `json:"-"`
+	// Keep is the amount of the deposit to capture, e.g. for a no-show,
+	// the rest being released or refunded.
+	Keep int64 `json:"keep"`
+}
+
+type settleDepositResponse struct {
+	Deposit Deposit `json:"deposit"`
+	Err     error   `json:"err,omitempty"`
+}
+
+func (r settleDepositResponse) HTTPError() error { return r.Err }
+
+// SettleDeposit godoc
+// @Summary Settle the deposit of a reservation
+// @Description Capture part of the deposit of a reservation, such as for a no-show, releasing or refunding the rest. A deposit captured already is only refunded further. The settlement is stored before the payment gateway is called; should the gateway fail, the deposit stays settling and the settlement is retried.
+// @Tags payment
+// @Param id path string true "Reservation ID"
+// @Param settlement body payment.settleDepositRequest true "Amount to keep"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} payment.Deposit
+// @Router /reservation/{id}/deposit/settle [post]
+func MakeSettleDepositEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(settleDepositRequest)
+		d, e := s.SettleDeposit(ctx, req.ReservationID, req.Keep)
+		return settleDepositResponse{
+			Deposit: d,
+			Err:     e,
+		}, nil
+	}
+}
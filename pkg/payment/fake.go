@@ -0,0 +1,153 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	errors "reservations/pkg/error"
+	"sync"
+)
+
+// FakeDeclined is the payment method the fake gateway declines, after the
+// test card tokens of Stripe.
+const FakeDeclined = "pm_card_declined"
+
+type fakePayment struct {
+	amount   int64
+	captured int64
+	refunded int64
+	state    string
+}
+
+const (
+	fakeAuthorized = "authorized"
+	fakeCaptured   = "captured"
+	fakeVoided     = "voided"
+)
+
+// FakeGateway is an in-process gateway accepting every payment method but
+// FakeDeclined, for development and for the local Stripe stub.
+type FakeGateway struct {
+	mu       sync.Mutex
+	payments map[string]*fakePayment
+	keys     map[string]string
+	// done holds the idempotency keys of the operations carried out on
+	// payments.
+	done map[string]bool
+	next int
+}
+
+func NewFakeGateway() *FakeGateway {
+	return &FakeGateway{
+		payments: map[string]*fakePayment{},
+		keys:     map[string]string{},
+		done:     map[string]bool{},
+	}
+}
+
+func (g *FakeGateway) Authorize(_ context.Context, r Request) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if id, ok := g.keys[r.IdempotencyKey]; ok && r.IdempotencyKey != "" {
+		return id, nil
+	}
+	if r.PaymentMethod == "" {
+		return "", errors.ValidationError.New("no payment method given").
+			AddContext("paymentMethod", "must not be empty")
+	}
+	if r.PaymentMethod == FakeDeclined {
+		return "", declined("card_declined")
+	}
+	if r.Amount <= 0 {
+		return "", errors.ValidationError.Newf("invalid amount %d", r.Amount).
+			AddContext("amount", "must be positive")
+	}
+
+	g.next++
+	id := fmt.Sprintf("pi_fake_%d", g.next)
+	g.payments[id] = &fakePayment{amount: r.Amount, state: fakeAuthorized}
+	if r.IdempotencyKey != "" {
+		g.keys[r.IdempotencyKey] = id
+	}
+	return id, nil
+}
+
+func (g *FakeGateway) Capture(_ context.Context, id string, amount int64, idempotencyKey string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.done[idempotencyKey] {
+		return nil
+	}
+	p, err := g.payment(id, fakeAuthorized)
+	if err != nil {
+		return err
+	}
+	if amount <= 0 || amount > p.amount {
+		return errors.ValidationError.Newf("cannot capture %d of payment %s authorized for %d", amount, id, p.amount).
+			AddContext("amount", "must be positive and at most the amount authorized")
+	}
+	p.captured = amount
+	p.state = fakeCaptured
+	g.succeeded(idempotencyKey)
+	return nil
+}
+
+func (g *FakeGateway) Refund(_ context.Context, id string, amount int64, idempotencyKey string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.done[idempotencyKey] {
+		return nil
+	}
+	p, err := g.payment(id, fakeCaptured)
+	if err != nil {
+		return err
+	}
+	if amount <= 0 || p.refunded+amount > p.captured {
+		return errors.ValidationError.Newf("cannot refund %d of payment %s with %d captured and %d refunded", amount, id, p.captured, p.refunded).
+			AddContext("amount", "must be positive and at most the amount captured and not refunded yet")
+	}
+	p.refunded += amount
+	g.succeeded(idempotencyKey)
+	return nil
+}
+
+func (g *FakeGateway) Void(_ context.Context, id string, idempotencyKey string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.done[idempotencyKey] {
+		return nil
+	}
+	p, err := g.payment(id, fakeAuthorized)
+	if err != nil {
+		return err
+	}
+	p.state = fakeVoided
+	g.succeeded(idempotencyKey)
+	return nil
+}
+
+// succeeded records the operation with the idempotency key as carried out,
+// for its retries to do nothing. Operations failing are carried out again
+// by their retries, as with Stripe.
+func (g *FakeGateway) succeeded(key string) {
+	if key != "" {
+		g.done[key] = true
+	}
+}
+
+// payment returns the payment id, which must be in state.
+func (g *FakeGateway) payment(id string, state string) (*fakePayment, error) {
+	p, ok := g.payments[id]
+	if !ok {
+		return nil, errors.NotFound.Newf("payment %s not found", id).
+			AddContext("payment", "non existent ID")
+	}
+	if p.state != state {
+		return nil, errors.Conflict.Newf("payment %s is %s, not %s", id, p.state, state).
+			AddContext("payment", "is "+p.state)
+	}
+	return p, nil
+}
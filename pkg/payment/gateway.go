@@ -0,0 +1,62 @@
+// Package payment takes the deposits required for some bookings through a
+// pluggable payment gateway. A deposit is authorized when the guest pays
+// it, and captured, voided or refunded depending on how the reservation
+// ends.
+package payment
+
+import (
+	"context"
+	errors "reservations/pkg/error"
+)
+
+// Gateway names.
+const (
+	GatewayFake   = "fake"
+	GatewayStripe = "stripe"
+)
+
+// NewGateway returns the gateway named name, calling the Stripe API at
+// stripeURL with stripeKey for GatewayStripe, and the in-process fake one
+// otherwise.
+func NewGateway(name string, stripeURL string, stripeKey string) Gateway {
+	if name == GatewayStripe {
+		return NewStripeGateway(stripeURL, stripeKey)
+	}
+	return NewFakeGateway()
+}
+
+// Gateway moves money through a payment provider. Amounts are in the minor
+// unit of the currency, such as cents. Declined payment methods fail with a
+// ValidationError, and operations the payment is not in the state for with
+// a Conflict. Retries of an operation with the same idempotency key, unless
+// empty, carry it out once.
+type Gateway interface {
+	// Authorize reserves r.Amount on r.PaymentMethod, returning the ID of
+	// the payment to capture or void.
+	Authorize(ctx context.Context, r Request) (string, error)
+	// Capture takes amount of the authorized payment id, and releases the
+	// rest.
+	Capture(ctx context.Context, id string, amount int64, idempotencyKey string) error
+	// Refund pays amount of the captured payment id back.
+	Refund(ctx context.Context, id string, amount int64, idempotencyKey string) error
+	// Void releases the authorized payment id without taking anything.
+	Void(ctx context.Context, id string, idempotencyKey string) error
+}
+
+// Request asks for the authorization of a payment.
+type Request struct {
+	Amount   int64
+	Currency string
+	// PaymentMethod is the token the provider gave the client for the
+	// card of the guest.
+	PaymentMethod string
+	Description   string
+	// IdempotencyKey makes retries of the same request authorize once.
+	IdempotencyKey string
+}
+
+// declined returns the error for a payment method the provider turned down.
+func declined(reason string) error {
+	return errors.ValidationError.Newf("payment method declined: %s", reason).
+		AddContext("paymentMethod", reason)
+}
@@ -0,0 +1,88 @@
+package payment
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"reservations/pkg/tracing"
+	"time"
+)
+
+// Middleware describes a service (as opposed to endpoint) middleware.
+type Middleware func(Service) Service
+
+func LoggingMiddleware(logger log.Logger) Middleware {
+	return func(next Service) Service {
+		return &loggingMiddleware{
+			next:   next,
+			logger: logger,
+		}
+	}
+}
+
+type loggingMiddleware struct {
+	next   Service
+	logger log.Logger
+}
+
+func (mw loggingMiddleware) GetDeposit(ctx context.Context, rID int) (d Deposit, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "GetDeposit", "reservation", rID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetDeposit(ctx, rID)
+}
+
+func (mw loggingMiddleware) PayDeposit(ctx context.Context, rID int, paymentMethod string) (d Deposit, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "PayDeposit", "reservation", rID, "status", d.Status, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.PayDeposit(ctx, rID, paymentMethod)
+}
+
+func (mw loggingMiddleware) SettleDeposit(ctx context.Context, rID int, keep int64) (d Deposit, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "SettleDeposit", "reservation", rID, "keep", keep, "status", d.Status, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.SettleDeposit(ctx, rID, keep)
+}
+
+func (mw loggingMiddleware) ProcessSettlement(ctx context.Context, rID int) (d Deposit, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "ProcessSettlement", "reservation", rID, "status", d.Status, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.ProcessSettlement(ctx, rID)
+}
+
+// TracingMiddleware wraps every call in a span.
+func TracingMiddleware() Middleware {
+	return func(next Service) Service {
+		return &tracingMiddleware{next: next}
+	}
+}
+
+type tracingMiddleware struct {
+	next Service
+}
+
+func (mw tracingMiddleware) GetDeposit(ctx context.Context, rID int) (d Deposit, err error) {
+	ctx, span := tracing.StartSpan(ctx, "payment.GetDeposit")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.GetDeposit(ctx, rID)
+}
+
+func (mw tracingMiddleware) PayDeposit(ctx context.Context, rID int, paymentMethod string) (d Deposit, err error) {
+	ctx, span := tracing.StartSpan(ctx, "payment.PayDeposit")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.PayDeposit(ctx, rID, paymentMethod)
+}
+
+func (mw tracingMiddleware) SettleDeposit(ctx context.Context, rID int, keep int64) (d Deposit, err error) {
+	ctx, span := tracing.StartSpan(ctx, "payment.SettleDeposit")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.SettleDeposit(ctx, rID, keep)
+}
+
+func (mw tracingMiddleware) ProcessSettlement(ctx context.Context, rID int) (d Deposit, err error) {
+	ctx, span := tracing.StartSpan(ctx, "payment.ProcessSettlement")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.ProcessSettlement(ctx, rID)
+}
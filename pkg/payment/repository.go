@@ -0,0 +1,310 @@
+package payment
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v7"
+	"reservations/pkg/audit"
+	errors "reservations/pkg/error"
+	"reservations/pkg/storage"
+	"reservations/pkg/tenant"
+	"reservations/pkg/tracing"
+	"time"
+)
+
+const auditEntity = "deposit"
+
+type Repository interface {
+	FindDepositByReservationID(ctx context.Context, rID int) (Deposit, error)
+	// FindOverdueDeposits returns the deposits of every venue still pending
+	// at now past their due time.
+	FindOverdueDeposits(ctx context.Context, now int64) ([]Deposit, error)
+	// FindSettlingDeposits returns the deposits of every venue whose
+	// settlement, stored before the time before, is still outstanding.
+	FindSettlingDeposits(ctx context.Context, before int64) ([]Deposit, error)
+	// UpdateDeposit stores the changes to d, failing with a Conflict unless
+	// it is still in the status from.
+	UpdateDeposit(ctx context.Context, d Deposit, from Status) (Deposit, error)
+	// SettleDeposit stores the settlement of the deposit of the reservation
+	// rID keeping keep of it, see Settle.
+	SettleDeposit(ctx context.Context, rID int, keep int64) (Deposit, error)
+}
+
+type paymentRepository struct {
+	db storage.Persistence
+}
+
+func NewPaymentRepository(db storage.Persistence) Repository {
+	return &paymentRepository{db: db}
+}
+
+// InsertDeposit requires the pending deposit d within tx.
+func InsertDeposit(tx *goqu.TxDatabase, d *Deposit, now int64) error {
+	d.Status = StatusPending
+	d.PaymentID = ""
+	d.Captured = 0
+	d.Refunded = 0
+	d.Created = now
+	d.LastUpdated = now
+	dID, err := storage.InsertReturningID(tx, "deposit", "did", d)
+	if err != nil {
+		return err
+	}
+	d.DepositID = dID
+	return nil
+}
+
+// RequireDeposit requires the deposit d for its reservation, edited, within
+// tx and returns the deposit as stored. A deposit already required is kept,
+// though raised to d while pending, and due by the sooner of both. It fails
+// with a Conflict if a smaller deposit was paid already.
+func RequireDeposit(ctx context.Context, tx *goqu.TxDatabase, d *Deposit, now int64) (Deposit, error) {
+	var cur Deposit
+	found, err := tx.From("deposit").Where(goqu.Ex{"rid": d.ReservationID, "venue_id": d.VenueID}).ScanStruct(&cur)
+	if err != nil {
+		return cur, err
+	}
+	if !found {
+		err := InsertDeposit(tx, d, now)
+		return *d, err
+	}
+	if cur.Amount >= d.Amount {
+		return cur, nil
+	}
+	if cur.Status != StatusPending {
+		return cur, errors.Conflict.Newf("deposit of %d %s for reservation %d is %s, %d is required", cur.Amount, cur.Currency, cur.ReservationID, cur.Status, d.Amount).
+			AddContext("ReservationID", "deposit paid is too small")
+	}
+
+	next := cur
+	next.Policy = d.Policy
+	next.Amount = d.Amount
+	if d.PayBy < next.PayBy {
+		next.PayBy = d.PayBy
+	}
+	return update(ctx, tx, d.VenueID, next, StatusPending, now)
+}
+
+// ExpireDeposit marks the deposit dID expired within tx, reporting whether
+// it was still pending past its due time at now.
+func ExpireDeposit(tx *goqu.TxDatabase, dID int, now int64) (bool, error) {
+	res, err := tx.From("deposit").
+		Where(
+			goqu.C("did").Eq(dID),
+			goqu.C("status").Eq(StatusPending),
+			goqu.C("pay_by").Lte(now),
+		).
+		Update(goqu.Record{"status": StatusExpired, "last_updated": now}).
+		Exec()
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *paymentRepository) FindDepositByReservationID(ctx context.Context, rID int) (d Deposit, err error) {
+	_, span := tracing.StartSpan(ctx, "paymentRepository.FindDepositByReservationID", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	found, err := r.db.DB.From("deposit").
+		Where(goqu.Ex{"rid": rID, "venue_id": tenant.VenueFromContext(ctx)}).
+		ScanStruct(&d)
+	if err != nil {
+		return d, errors.DBError.Wrapf(err, "error getting deposit of reservation with ID %d", rID)
+	}
+	if !found {
+		return d, errors.NotFound.Newf("no deposit for reservation with ID %d", rID).
+			AddContext("ReservationID", "no deposit required")
+	}
+	return d, nil
+}
+
+func (r *paymentRepository) FindOverdueDeposits(ctx context.Context, now int64) (dd []Deposit, err error) {
+	_, span := tracing.StartSpan(ctx, "paymentRepository.FindOverdueDeposits", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	dd = []Deposit{}
+	err = r.db.DB.From("deposit").
+		Where(
+			goqu.C("status").Eq(StatusPending),
+			goqu.C("pay_by").Lte(now),
+		).
+		Order(goqu.C("pay_by").Asc()).
+		ScanStructs(&dd)
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error getting overdue deposits")
+	}
+	return dd, nil
+}
+
+func (r *paymentRepository) FindSettlingDeposits(ctx context.Context, before int64) (dd []Deposit, err error) {
+	_, span := tracing.StartSpan(ctx, "paymentRepository.FindSettlingDeposits", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	dd = []Deposit{}
+	err = r.db.DB.From("deposit").
+		Where(
+			goqu.C("status").Eq(StatusSettling),
+			goqu.C("last_updated").Lte(before),
+		).
+		Order(goqu.C("last_updated").Asc()).
+		ScanStructs(&dd)
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error getting deposits being settled")
+	}
+	return dd, nil
+}
+
+func (r *paymentRepository) UpdateDeposit(ctx context.Context, d Deposit, from Status) (result Deposit, err error) {
+	ctx, span := tracing.StartSpan(ctx, "paymentRepository.UpdateDeposit", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		result, err = update(ctx, tx, tenant.VenueFromContext(ctx), d, from, time.Now().Unix())
+		return err
+	})
+
+	if errors.GetType(err) == errors.Conflict {
+		return d, err
+	}
+	if err != nil {
+		return d, errors.DBError.Wrapf(err, "error updating deposit with ID %d", d.DepositID)
+	}
+	return result, nil
+}
+
+func (r *paymentRepository) SettleDeposit(ctx context.Context, rID int, keep int64) (d Deposit, err error) {
+	ctx, span := tracing.StartSpan(ctx, "paymentRepository.SettleDeposit", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		d, err = Settle(ctx, tx, tenant.VenueFromContext(ctx), rID, keep, 0, time.Now().Unix())
+		return err
+	})
+
+	if t := errors.GetType(err); t == errors.NotFound || t == errors.Conflict {
+		return d, err
+	}
+	if err != nil {
+		return d, errors.DBError.Wrapf(err, "error settling deposit of reservation with ID %d", rID)
+	}
+	return d, nil
+}
+
+// Settle stores within tx the settlement of the deposit of the reservation
+// rID of the venue vID keeping keep of it, in payment of the fee fID unless
+// 0. Pending deposits, of which nothing was paid, are voided at once. The
+// others are left settling, for the gateway to be called once tx commits
+// through Service.ProcessSettlement. It fails with a NotFound if there is
+// no deposit, and with a Conflict if it is settled or being settled.
+func Settle(ctx context.Context, tx *goqu.TxDatabase, vID int, rID int, keep int64, fID int, now int64) (Deposit, error) {
+	var d Deposit
+	found, err := tx.From("deposit").Where(goqu.Ex{"rid": rID, "venue_id": vID}).ScanStruct(&d)
+	if err != nil {
+		return d, err
+	}
+	if !found {
+		return d, errors.NotFound.Newf("no deposit for reservation with ID %d", rID).
+			AddContext("ReservationID", "no deposit required")
+	}
+
+	next, ok, err := settlement(d, keep, fID)
+	if err != nil || !ok {
+		return d, err
+	}
+	return update(ctx, tx, vID, next, d.Status, now)
+}
+
+// ReleaseFee stores within tx the settlement refunding the deposit kept in
+// payment of the fee fID of the venue vID, waived, unless none was.
+func ReleaseFee(ctx context.Context, tx *goqu.TxDatabase, vID int, fID int, now int64) error {
+	var d Deposit
+	found, err := tx.From("deposit").Where(goqu.Ex{"fee_id": fID, "venue_id": vID}).ScanStruct(&d)
+	if err != nil || !found {
+		return err
+	}
+
+	next, ok, err := settlement(d, 0, 0)
+	if err != nil || !ok {
+		return err
+	}
+	_, err = update(ctx, tx, vID, next, d.Status, now)
+	return err
+}
+
+// settlement returns d settled keeping keep of it in payment of the fee fID,
+// reporting whether that changes anything. Captured deposits are only
+// refunded further.
+func settlement(d Deposit, keep int64, fID int) (Deposit, bool, error) {
+	if keep > d.Amount {
+		keep = d.Amount
+	}
+
+	switch d.Status {
+	case StatusPending:
+		d.Status = StatusVoided
+		return d, true, nil
+	case StatusAuthorized:
+	case StatusCaptured, StatusRefunded:
+		if keep >= d.Captured-d.Refunded {
+			return d, false, nil
+		}
+	default:
+		return d, false, errors.Conflict.Newf("deposit of reservation %d is already %s", d.ReservationID, d.Status).
+			AddContext("ReservationID", "deposit is "+string(d.Status))
+	}
+
+	d.Status = StatusSettling
+	d.Keep = keep
+	d.FeeID = fID
+	d.Settlement++
+	return d, true, nil
+}
+
+// update stores the changes to d within tx, failing with a Conflict unless
+// it is still in the status from, and returns the deposit as stored.
+func update(ctx context.Context, tx *goqu.TxDatabase, vID int, d Deposit, from Status, now int64) (after Deposit, err error) {
+	where := goqu.Ex{"did": d.DepositID, "venue_id": vID}
+
+	var before Deposit
+	found, err := tx.From("deposit").Where(where).ScanStruct(&before)
+	if err != nil {
+		return after, err
+	}
+	if !found || before.Status != from {
+		return after, errors.Conflict.Newf("deposit with ID %d is no longer %s", d.DepositID, from).
+			AddContext("ReservationID", "deposit changed meanwhile")
+	}
+
+	res, err := tx.From("deposit").Prepared(true).
+		Where(where, goqu.C("status").Eq(from)).
+		Update(goqu.Record{
+			"status":       d.Status,
+			"policy":       d.Policy,
+			"amount":       d.Amount,
+			"pay_by":       d.PayBy,
+			"payment_id":   d.PaymentID,
+			"captured":     d.Captured,
+			"refunded":     d.Refunded,
+			"keep":         d.Keep,
+			"fee_id":       d.FeeID,
+			"settlement":   d.Settlement,
+			"last_updated": now,
+		}).Exec()
+	if err != nil {
+		return after, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return after, err
+	}
+	if n == 0 {
+		return after, errors.Conflict.Newf("deposit with ID %d is no longer %s", d.DepositID, from).
+			AddContext("ReservationID", "deposit changed meanwhile")
+	}
+
+	if _, err := tx.From("deposit").Where(where).ScanStruct(&after); err != nil {
+		return after, err
+	}
+	return after, audit.Append(ctx, tx, audit.ActionUpdate, auditEntity, d.DepositID, before, after)
+}
@@ -0,0 +1,87 @@
+package payment
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v7"
+	errors "reservations/pkg/error"
+	"reservations/pkg/storage"
+	"reservations/pkg/storage/storagetest"
+	"reservations/pkg/tenant"
+	"testing"
+)
+
+func addDeposit(t *testing.T, db *storage.Persistence, rID int, payBy int64) *Deposit {
+	d := &Deposit{ReservationID: rID, Policy: "large", Amount: 5000, Currency: "eur", PayBy: payBy, VenueID: tenant.DefaultVenueID}
+	err := db.WithTx(func(tx *goqu.TxDatabase) error {
+		cID, err := storage.InsertReturningID(tx, "customer", "cid", goqu.Record{"first_name": "Ann", "last_name": "Doe", "email": "ann@example.com", "venue_id": d.VenueID})
+		if err != nil {
+			return err
+		}
+		d.CustomerID = cID
+		return InsertDeposit(tx, d, 1000)
+	})
+	if err != nil {
+		t.Fatalf("InsertDeposit: %v", err)
+	}
+	return d
+}
+
+func TestUpdateDeposit(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		r := NewPaymentRepository(*db)
+		ctx := context.Background()
+		d := addDeposit(t, db, 1, 2000)
+
+		paid := *d
+		paid.Status = StatusAuthorized
+		paid.PaymentID = "pi_1"
+		got, err := r.UpdateDeposit(ctx, paid, StatusPending)
+		if err != nil {
+			t.Fatalf("UpdateDeposit: %v", err)
+		}
+		if got.Status != StatusAuthorized || got.PaymentID != "pi_1" {
+			t.Errorf("UpdateDeposit = %+v, want it authorized", got)
+		}
+
+		expired := *d
+		expired.Status = StatusExpired
+		if _, err := r.UpdateDeposit(ctx, expired, StatusPending); errors.GetType(err) != errors.Conflict {
+			t.Errorf("UpdateDeposit of a deposit no longer pending = %v, want a Conflict", err)
+		}
+		if got, _ := r.FindDepositByReservationID(ctx, 1); got.Status != StatusAuthorized {
+			t.Errorf("deposit after the conflict = %+v, want it still authorized", got)
+		}
+
+		other := tenant.WithVenue(ctx, 2)
+		if _, err := r.UpdateDeposit(other, expired, StatusAuthorized); errors.GetType(err) != errors.Conflict {
+			t.Errorf("UpdateDeposit in another venue = %v, want a Conflict", err)
+		}
+		if _, err := r.FindDepositByReservationID(other, 1); errors.GetType(err) != errors.NotFound {
+			t.Errorf("FindDepositByReservationID in another venue = %v, want a NotFound", err)
+		}
+	})
+}
+
+func TestFindOverdueDeposits(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		r := NewPaymentRepository(*db)
+		ctx := context.Background()
+		late := addDeposit(t, db, 1, 3000)
+		later := addDeposit(t, db, 2, 2000)
+		addDeposit(t, db, 3, 9000)
+		paid := addDeposit(t, db, 4, 1000)
+
+		paid.Status = StatusAuthorized
+		if _, err := r.UpdateDeposit(ctx, *paid, StatusPending); err != nil {
+			t.Fatalf("UpdateDeposit: %v", err)
+		}
+
+		dd, err := r.FindOverdueDeposits(ctx, 5000)
+		if err != nil {
+			t.Fatalf("FindOverdueDeposits: %v", err)
+		}
+		if len(dd) != 2 || dd[0].DepositID != later.DepositID || dd[1].DepositID != late.DepositID {
+			t.Errorf("FindOverdueDeposits = %+v, want the 2 pending deposits due, earliest first", dd)
+		}
+	})
+}
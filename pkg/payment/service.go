@@ -0,0 +1,188 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	errors "reservations/pkg/error"
+	"time"
+)
+
+type Service interface {
+	GetDeposit(ctx context.Context, rID int) (Deposit, error)
+	// PayDeposit authorizes the deposit of the reservation rID on the
+	// payment method of the guest, before it is due.
+	PayDeposit(ctx context.Context, rID int, paymentMethod string) (Deposit, error)
+	// SettleDeposit ends the deposit of the reservation rID, capturing keep
+	// of it at most and releasing or refunding the rest. Deposits captured
+	// already are only refunded further. The settlement is stored before
+	// the gateway is called, and retried should the gateway fail.
+	SettleDeposit(ctx context.Context, rID int, keep int64) (Deposit, error)
+	// ProcessSettlement carries out with the gateway the settlement stored
+	// for the deposit of the reservation rID, if one is outstanding.
+	ProcessSettlement(ctx context.Context, rID int) (Deposit, error)
+}
+
+// Status tells where a deposit is in its lifecycle.
+type Status string
+
+const (
+	// StatusPending deposits are due by their PayBy time, after which the
+	// reservation is cancelled.
+	StatusPending    Status = "pending"
+	StatusAuthorized Status = "authorized"
+	// StatusSettling deposits have a settlement stored which the gateway
+	// has yet to carry out.
+	StatusSettling Status = "settling"
+	StatusCaptured Status = "captured"
+	// StatusRefunded deposits had some of what was captured refunded.
+	StatusRefunded Status = "refunded"
+	// StatusVoided deposits were released without taking anything, or
+	// settled before being paid.
+	StatusVoided  Status = "voided"
+	StatusExpired Status = "expired"
+)
+
+type Deposit struct {
+	DepositID int `json:"depositId" db:"did" goqu:"skipinsert"`
+	// ReservationID is the reservation held by the deposit, which no longer
+	// exists once cancelled.
+	ReservationID int `json:"reservationId" db:"rid"`
+	CustomerID    int `json:"customerId" db:"customer_id"`
+	// Policy names the deposit terms requiring the deposit.
+	Policy string `json:"policy"`
+	// Amount, Captured and Refunded are in the minor unit of Currency, such
+	// as cents.
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	Status   Status `json:"status" swaggertype:"string" enums:"pending,authorized,settling,captured,refunded,voided,expired"`
+	// PaymentID is the payment of the gateway authorized for the deposit.
+	PaymentID string `json:"paymentId,omitempty" db:"payment_id"`
+	Captured  int64  `json:"captured"`
+	Refunded  int64  `json:"refunded"`
+	// Keep is what the latest settlement keeps of the deposit, in payment
+	// of the fee FeeID unless 0, which is refunded when waived.
+	Keep  int64 `json:"keep"`
+	FeeID int   `json:"feeId,omitempty" db:"fee_id"`
+	// Settlement counts the settlements stored, naming the gateway
+	// operation of the latest for retries.
+	Settlement int `json:"-"`
+	// PayBy is the time the deposit is due by.
+	PayBy       int64 `json:"payBy" db:"pay_by"`
+	VenueID     int   `json:"venueId" db:"venue_id"`
+	Created     int64 `json:"created"`
+	LastUpdated int64 `json:"lastUpdated" db:"last_updated"`
+}
+
+type paymentService struct {
+	repo    Repository
+	gateway Gateway
+}
+
+func NewPaymentService(repo Repository, gateway Gateway) Service {
+	return &paymentService{
+		repo:    repo,
+		gateway: gateway,
+	}
+}
+
+func (s *paymentService) GetDeposit(ctx context.Context, rID int) (Deposit, error) {
+	return s.repo.FindDepositByReservationID(ctx, rID)
+}
+
+func (s *paymentService) PayDeposit(ctx context.Context, rID int, paymentMethod string) (Deposit, error) {
+	d, err := s.repo.FindDepositByReservationID(ctx, rID)
+	if err != nil {
+		return d, err
+	}
+	if d.Status != StatusPending {
+		return d, errors.Conflict.Newf("deposit of reservation %d is %s", rID, d.Status).
+			AddContext("ReservationID", "deposit is "+string(d.Status))
+	}
+	if time.Now().Unix() >= d.PayBy {
+		return d, errors.Conflict.Newf("deposit of reservation %d was due by %s", rID, time.Unix(d.PayBy, 0).UTC().Format(time.RFC3339)).
+			AddContext("ReservationID", "deposit is overdue")
+	}
+
+	id, err := s.gateway.Authorize(ctx, Request{
+		Amount:        d.Amount,
+		Currency:      d.Currency,
+		PaymentMethod: paymentMethod,
+		Description:   fmt.Sprintf("Deposit for reservation %d", rID),
+		// Retries with the same payment method authorize once, while
+		// another one may be tried after a decline.
+		IdempotencyKey: fmt.Sprintf("deposit-%d-%s", d.DepositID, paymentMethod),
+	})
+	if err != nil {
+		return d, err
+	}
+
+	d.PaymentID = id
+	d.Status = StatusAuthorized
+	result, err := s.repo.UpdateDeposit(ctx, d, StatusPending)
+	if errors.GetType(err) == errors.Conflict {
+		// The deposit expired or was settled meanwhile. Should the void
+		// fail too, the authorization lapses with the provider.
+		s.gateway.Void(ctx, id, "")
+	}
+	return result, err
+}
+
+func (s *paymentService) SettleDeposit(ctx context.Context, rID int, keep int64) (Deposit, error) {
+	if keep < 0 {
+		return Deposit{}, errors.ValidationError.Newf("invalid amount to keep %d", keep).
+			AddContext("keep", "must not be negative")
+	}
+
+	d, err := s.repo.SettleDeposit(ctx, rID, keep)
+	if err != nil || d.Status != StatusSettling {
+		return d, err
+	}
+	return s.process(ctx, d)
+}
+
+func (s *paymentService) ProcessSettlement(ctx context.Context, rID int) (Deposit, error) {
+	d, err := s.repo.FindDepositByReservationID(ctx, rID)
+	if err != nil || d.Status != StatusSettling {
+		return d, err
+	}
+	return s.process(ctx, d)
+}
+
+// process carries out the settlement stored for d with the gateway and
+// stores the outcome. The settlement is one gateway operation, retried with
+// the same idempotency key until stored as done.
+func (s *paymentService) process(ctx context.Context, d Deposit) (Deposit, error) {
+	key := fmt.Sprintf("deposit-%d-settlement-%d", d.DepositID, d.Settlement)
+
+	var err error
+	switch held := d.Captured - d.Refunded; {
+	case d.Captured == 0 && d.Keep > 0:
+		err = s.gateway.Capture(ctx, d.PaymentID, d.Keep, key)
+		d.Captured = d.Keep
+	case d.Captured == 0:
+		err = s.gateway.Void(ctx, d.PaymentID, key)
+	case held > d.Keep:
+		err = s.gateway.Refund(ctx, d.PaymentID, held-d.Keep, key)
+		d.Refunded += held - d.Keep
+	}
+	if err != nil {
+		return d, err
+	}
+
+	switch {
+	case d.Captured == 0:
+		d.Status = StatusVoided
+	case d.Refunded == 0:
+		d.Status = StatusCaptured
+	default:
+		d.Status = StatusRefunded
+	}
+	result, err := s.repo.UpdateDeposit(ctx, d, StatusSettling)
+	if errors.GetType(err) == errors.Conflict {
+		// Carried out by a retry meanwhile.
+		if result, err := s.repo.FindDepositByReservationID(ctx, d.ReservationID); err == nil && result.Status != StatusSettling {
+			return result, nil
+		}
+	}
+	return result, err
+}
@@ -0,0 +1,129 @@
+package payment
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v7"
+	"github.com/go-kit/kit/log"
+	errors "reservations/pkg/error"
+	"reservations/pkg/storage"
+	"reservations/pkg/storage/storagetest"
+	"testing"
+	"time"
+)
+
+// lossyGateway carries out operations but then fails the next failures of
+// them, as when the connection drops before the response arrives.
+type lossyGateway struct {
+	*FakeGateway
+	failures int
+}
+
+func (g *lossyGateway) lost() error {
+	if g.failures == 0 {
+		return nil
+	}
+	g.failures--
+	return errors.New("connection reset by peer")
+}
+
+func (g *lossyGateway) Capture(ctx context.Context, id string, amount int64, idempotencyKey string) error {
+	if err := g.FakeGateway.Capture(ctx, id, amount, idempotencyKey); err != nil {
+		return err
+	}
+	return g.lost()
+}
+
+func (g *lossyGateway) Refund(ctx context.Context, id string, amount int64, idempotencyKey string) error {
+	if err := g.FakeGateway.Refund(ctx, id, amount, idempotencyKey); err != nil {
+		return err
+	}
+	return g.lost()
+}
+
+func paidDeposit(t *testing.T, db *storage.Persistence, s Service, rID int) Deposit {
+	addDeposit(t, db, rID, time.Now().Add(time.Hour).Unix())
+	d, err := s.PayDeposit(context.Background(), rID, "pm_card_visa")
+	if err != nil {
+		t.Fatalf("PayDeposit: %v", err)
+	}
+	return d
+}
+
+func TestSettleDepositRetries(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		repo := NewPaymentRepository(*db)
+		g := &lossyGateway{FakeGateway: NewFakeGateway()}
+		s := NewPaymentService(repo, g)
+		ctx := context.Background()
+		paidDeposit(t, db, s, 1)
+
+		g.failures = 1
+		if _, err := s.SettleDeposit(ctx, 1, 2000); err == nil {
+			t.Fatal("SettleDeposit with the gateway failing succeeded")
+		}
+		d, err := s.GetDeposit(ctx, 1)
+		if err != nil || d.Status != StatusSettling || d.Keep != 2000 {
+			t.Fatalf("deposit after the gateway failed = %+v, %v, want it settling keeping 2000", d, err)
+		}
+		if _, err := s.SettleDeposit(ctx, 1, 0); errors.GetType(err) != errors.Conflict {
+			t.Errorf("SettleDeposit of a deposit being settled = %v, want a Conflict", err)
+		}
+
+		// The capture went through before the response was lost: the retry
+		// must not capture again, which the gateway would refuse.
+		d, err = s.ProcessSettlement(ctx, 1)
+		if err != nil || d.Status != StatusCaptured || d.Captured != 2000 {
+			t.Fatalf("ProcessSettlement = %+v, %v, want 2000 captured", d, err)
+		}
+
+		// Deposits captured already are only refunded further.
+		if d, err = s.SettleDeposit(ctx, 1, 4000); err != nil || d.Status != StatusCaptured {
+			t.Errorf("SettleDeposit keeping more = %+v, %v, want it unchanged", d, err)
+		}
+		d, err = s.SettleDeposit(ctx, 1, 500)
+		if err != nil || d.Status != StatusRefunded || d.Captured != 2000 || d.Refunded != 1500 || d.Settlement != 2 {
+			t.Errorf("SettleDeposit keeping less = %+v, %v, want 1500 refunded", d, err)
+		}
+	})
+}
+
+func TestSettlerCompletesSettlements(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		repo := NewPaymentRepository(*db)
+		g := &lossyGateway{FakeGateway: NewFakeGateway()}
+		s := NewPaymentService(repo, g)
+		ctx := context.Background()
+		paidDeposit(t, db, s, 1)
+
+		g.failures = 1
+		if _, err := s.SettleDeposit(ctx, 1, 0); err != nil {
+			t.Fatalf("SettleDeposit: %v", err)
+		}
+		d, _ := s.GetDeposit(ctx, 1)
+		if d.Status != StatusVoided {
+			t.Fatalf("deposit voided = %+v", d)
+		}
+
+		paidDeposit(t, db, s, 2)
+		g.failures = 1
+		s.SettleDeposit(ctx, 2, 1000)
+
+		settler := NewSettler(repo, s, time.Minute, log.NewNopLogger())
+		if err := settler.settle(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if d, _ := s.GetDeposit(ctx, 2); d.Status != StatusSettling {
+			t.Errorf("deposit settling for less than the interval = %+v, want it left to the request", d)
+		}
+
+		if _, err := db.DB.From("deposit").Where(goqu.Ex{"rid": 2}).Update(goqu.Record{"last_updated": time.Now().Add(-2 * time.Minute).Unix()}).Exec(); err != nil {
+			t.Fatal(err)
+		}
+		if err := settler.settle(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if d, _ := s.GetDeposit(ctx, 2); d.Status != StatusCaptured || d.Captured != 1000 {
+			t.Errorf("deposit after the settler ran = %+v, want 1000 captured", d)
+		}
+	})
+}
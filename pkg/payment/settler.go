@@ -0,0 +1,64 @@
+package payment
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"reservations/pkg/tenant"
+	"time"
+)
+
+// Settler carries out the settlements of deposits left outstanding, because
+// the gateway failed or the server stopped after they were stored.
+type Settler struct {
+	repo     Repository
+	svc      Service
+	interval time.Duration
+	logger   log.Logger
+}
+
+func NewSettler(repo Repository, svc Service, interval time.Duration, logger log.Logger) *Settler {
+	return &Settler{
+		repo:     repo,
+		svc:      svc,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run retries the outstanding settlements until ctx is cancelled.
+func (s *Settler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.settle(ctx); err != nil {
+			s.logger.Log("component", "deposits", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Settler) settle(ctx context.Context) error {
+	// Settlements stored within the last interval are likely still being
+	// carried out by the request storing them.
+	dd, err := s.repo.FindSettlingDeposits(ctx, time.Now().Add(-s.interval).Unix())
+	if err != nil {
+		return err
+	}
+
+	for _, d := range dd {
+		// A deposit failing does not hold the others back.
+		settled, err := s.svc.ProcessSettlement(tenant.WithVenue(ctx, d.VenueID), d.ReservationID)
+		if err != nil {
+			s.logger.Log("component", "deposits", "reservation", d.ReservationID, "err", err)
+			continue
+		}
+		s.logger.Log("component", "deposits", "reservation", d.ReservationID, "settled", settled.Status)
+	}
+	return nil
+}
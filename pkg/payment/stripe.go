@@ -0,0 +1,147 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	errors "reservations/pkg/error"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultStripeURL is the base URL of the Stripe API.
+const DefaultStripeURL = "https://api.stripe.com"
+
+const defaultTimeout = 10 * time.Second
+
+// StripeGateway takes payments through the PaymentIntents API of Stripe, or
+// of anything speaking it such as the local stub of NewStripeStub.
+// Authorizations are payment intents confirmed with manual capture.
+type StripeGateway struct {
+	url    string
+	key    string
+	client *http.Client
+}
+
+// NewStripeGateway returns a gateway calling the API at baseURL with the
+// secret key.
+func NewStripeGateway(baseURL string, key string) *StripeGateway {
+	return &StripeGateway{
+		url:    strings.TrimSuffix(baseURL, "/"),
+		key:    key,
+		client: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// paymentIntent is the part of a payment intent the gateway reads.
+type paymentIntent struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// stripeError is the body of failed API calls.
+type stripeError struct {
+	Error struct {
+		Type        string `json:"type"`
+		Code        string `json:"code"`
+		DeclineCode string `json:"decline_code"`
+		Message     string `json:"message"`
+	} `json:"error"`
+}
+
+func (g *StripeGateway) Authorize(ctx context.Context, r Request) (string, error) {
+	form := url.Values{
+		"amount":         {strconv.FormatInt(r.Amount, 10)},
+		"currency":       {r.Currency},
+		"payment_method": {r.PaymentMethod},
+		"description":    {r.Description},
+		"capture_method": {"manual"},
+		"confirm":        {"true"},
+	}
+	var pi paymentIntent
+	if err := g.post(ctx, "/v1/payment_intents", form, r.IdempotencyKey, &pi); err != nil {
+		return "", err
+	}
+	if pi.Status != "requires_capture" {
+		return "", errors.ValidationError.Newf("payment %s is %s instead of authorized", pi.ID, pi.Status).
+			AddContext("paymentMethod", "needs further action from the guest")
+	}
+	return pi.ID, nil
+}
+
+func (g *StripeGateway) Capture(ctx context.Context, id string, amount int64, idempotencyKey string) error {
+	form := url.Values{"amount_to_capture": {strconv.FormatInt(amount, 10)}}
+	return g.post(ctx, "/v1/payment_intents/"+url.PathEscape(id)+"/capture", form, idempotencyKey, nil)
+}
+
+func (g *StripeGateway) Refund(ctx context.Context, id string, amount int64, idempotencyKey string) error {
+	form := url.Values{
+		"payment_intent": {id},
+		"amount":         {strconv.FormatInt(amount, 10)},
+	}
+	return g.post(ctx, "/v1/refunds", form, idempotencyKey, nil)
+}
+
+func (g *StripeGateway) Void(ctx context.Context, id string, idempotencyKey string) error {
+	return g.post(ctx, "/v1/payment_intents/"+url.PathEscape(id)+"/cancel", url.Values{}, idempotencyKey, nil)
+}
+
+// post sends form to path and decodes the response into out unless nil.
+func (g *StripeGateway) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodPost, g.url+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+g.key)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "error calling payment gateway")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var e stripeError
+		json.NewDecoder(resp.Body).Decode(&e)
+		return gatewayError(resp.StatusCode, e)
+	}
+	if out == nil {
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.Wrapf(err, "error decoding payment gateway response")
+	}
+	return nil
+}
+
+// gatewayError maps a failed API call to the error types of Gateway.
+func gatewayError(status int, e stripeError) error {
+	switch {
+	case e.Error.Type == "card_error":
+		reason := e.Error.DeclineCode
+		if reason == "" {
+			reason = e.Error.Code
+		}
+		return declined(reason)
+	case status == http.StatusNotFound:
+		return errors.NotFound.Newf("payment gateway: %s", e.Error.Message).
+			AddContext("payment", "non existent ID")
+	case e.Error.Code == "payment_intent_unexpected_state":
+		return errors.Conflict.Newf("payment gateway: %s", e.Error.Message).
+			AddContext("payment", e.Error.Code)
+	case status == http.StatusBadRequest:
+		return errors.ValidationError.Newf("payment gateway: %s", e.Error.Message).
+			AddContext("payment", e.Error.Code)
+	}
+	return errors.Newf("payment gateway responded with %d: %s", status, e.Error.Message)
+}
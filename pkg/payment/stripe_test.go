@@ -0,0 +1,145 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	errors "reservations/pkg/error"
+	"testing"
+)
+
+func newTestStripeGateway(t *testing.T) *StripeGateway {
+	srv := httptest.NewServer(NewStripeStub())
+	t.Cleanup(srv.Close)
+	return NewStripeGateway(srv.URL+"/", "sk_test_key")
+}
+
+func authorize(t *testing.T, g *StripeGateway, key string) string {
+	id, err := g.Authorize(context.Background(), Request{
+		Amount:         2500,
+		Currency:       "eur",
+		PaymentMethod:  "pm_card_visa",
+		Description:    "Deposit for reservation 1",
+		IdempotencyKey: key,
+	})
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	if id == "" {
+		t.Fatal("Authorize returned no payment ID")
+	}
+	return id
+}
+
+func TestStripeGatewayCaptureAndRefund(t *testing.T) {
+	g := newTestStripeGateway(t)
+	ctx := context.Background()
+	id := authorize(t, g, "deposit-1")
+
+	if err := g.Capture(ctx, id, 1000, ""); err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+	if err := g.Refund(ctx, id, 400, ""); err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	if err := g.Refund(ctx, id, 600, ""); err != nil {
+		t.Fatalf("Refund of the rest: %v", err)
+	}
+	if err := g.Refund(ctx, id, 1, ""); errors.GetType(err) != errors.ValidationError {
+		t.Errorf("Refund beyond the captured amount = %v, want a ValidationError", err)
+	}
+}
+
+func TestStripeGatewayVoid(t *testing.T) {
+	g := newTestStripeGateway(t)
+	ctx := context.Background()
+	id := authorize(t, g, "")
+
+	if err := g.Void(ctx, id, ""); err != nil {
+		t.Fatalf("Void: %v", err)
+	}
+	if err := g.Capture(ctx, id, 100, ""); errors.GetType(err) != errors.Conflict {
+		t.Errorf("Capture after Void = %v, want a Conflict", err)
+	}
+	if err := g.Void(ctx, id, ""); errors.GetType(err) != errors.Conflict {
+		t.Errorf("second Void = %v, want a Conflict", err)
+	}
+}
+
+func TestStripeGatewayAuthorizeIsIdempotent(t *testing.T) {
+	g := newTestStripeGateway(t)
+
+	first := authorize(t, g, "deposit-2-pm_card_visa")
+	if again := authorize(t, g, "deposit-2-pm_card_visa"); again != first {
+		t.Errorf("retried Authorize = %s, want %s", again, first)
+	}
+	if other := authorize(t, g, "deposit-3-pm_card_visa"); other == first {
+		t.Errorf("Authorize with another key = %s, want a new payment", other)
+	}
+}
+
+func TestStripeGatewaySettlementIsIdempotent(t *testing.T) {
+	g := newTestStripeGateway(t)
+	ctx := context.Background()
+	id := authorize(t, g, "")
+
+	for i := 0; i < 2; i++ {
+		if err := g.Capture(ctx, id, 2000, "deposit-1-settlement-1"); err != nil {
+			t.Fatalf("Capture #%d: %v", i+1, err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := g.Refund(ctx, id, 1500, "deposit-1-settlement-2"); err != nil {
+			t.Fatalf("Refund #%d: %v", i+1, err)
+		}
+	}
+	// Refunding 1500 twice would have exceeded the amount captured.
+	if err := g.Refund(ctx, id, 500, "deposit-1-settlement-3"); err != nil {
+		t.Errorf("Refund of the rest: %v", err)
+	}
+}
+
+func TestStripeGatewayErrors(t *testing.T) {
+	g := newTestStripeGateway(t)
+	ctx := context.Background()
+
+	_, err := g.Authorize(ctx, Request{Amount: 2500, Currency: "eur", PaymentMethod: FakeDeclined})
+	if errors.GetType(err) != errors.ValidationError {
+		t.Fatalf("Authorize with a declined card = %v, want a ValidationError", err)
+	}
+	if e, ok := err.(errors.AppError); !ok || e.Context.Field != "paymentMethod" || e.Context.Message != "card_declined" {
+		t.Errorf("declined card error = %#v, want the decline code on paymentMethod", err)
+	}
+
+	if _, err := g.Authorize(ctx, Request{Amount: 2500, Currency: "eur"}); errors.GetType(err) != errors.ValidationError {
+		t.Errorf("Authorize without a payment method = %v, want a ValidationError", err)
+	}
+	if err := g.Capture(ctx, "pi_missing", 100, ""); errors.GetType(err) != errors.NotFound {
+		t.Errorf("Capture of an unknown payment = %v, want a NotFound", err)
+	}
+	if err := g.Refund(ctx, "pi_missing", 100, ""); errors.GetType(err) != errors.NotFound {
+		t.Errorf("Refund of an unknown payment = %v, want a NotFound", err)
+	}
+
+	id := authorize(t, g, "")
+	if err := g.Refund(ctx, id, 100, ""); errors.GetType(err) != errors.Conflict {
+		t.Errorf("Refund before Capture = %v, want a Conflict", err)
+	}
+	if err := g.Capture(ctx, id, 5000, ""); errors.GetType(err) != errors.ValidationError {
+		t.Errorf("Capture beyond the authorized amount = %v, want a ValidationError", err)
+	}
+}
+
+func TestStripeStubRequiresAPIKey(t *testing.T) {
+	srv := httptest.NewServer(NewStripeStub())
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/v1/payment_intents", "application/x-www-form-urlencoded", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status without an API key = %d, want %d", resp.StatusCode, http.StatusUnauthorized)
+	}
+}
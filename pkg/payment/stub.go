@@ -0,0 +1,164 @@
+package payment
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	errors "reservations/pkg/error"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// NewStripeStub returns a local stand-in for the parts of the Stripe API
+// used by StripeGateway, keeping payments in memory like FakeGateway. It
+// accepts any secret key, and declines the payment method FakeDeclined.
+func NewStripeStub() http.Handler {
+	s := &stripeStub{gateway: NewFakeGateway()}
+
+	r := mux.NewRouter()
+	r.Methods("POST").Path("/v1/payment_intents").HandlerFunc(s.authorize)
+	r.Methods("POST").Path("/v1/payment_intents/{id}/capture").HandlerFunc(s.capture)
+	r.Methods("POST").Path("/v1/payment_intents/{id}/cancel").HandlerFunc(s.void)
+	r.Methods("POST").Path("/v1/refunds").HandlerFunc(s.refund)
+	return r
+}
+
+type stripeStub struct {
+	gateway *FakeGateway
+	refunds int64
+}
+
+func (s *stripeStub) authorize(w http.ResponseWriter, r *http.Request) {
+	if !s.parse(w, r) {
+		return
+	}
+	if r.PostForm.Get("capture_method") != "manual" || r.PostForm.Get("confirm") != "true" {
+		stubError(w, http.StatusBadRequest, "invalid_request_error", "parameter_invalid",
+			"the stub only supports confirmed payment intents with manual capture")
+		return
+	}
+
+	amount, _ := strconv.ParseInt(r.PostForm.Get("amount"), 10, 64)
+	id, err := s.gateway.Authorize(r.Context(), Request{
+		Amount:         amount,
+		Currency:       r.PostForm.Get("currency"),
+		PaymentMethod:  r.PostForm.Get("payment_method"),
+		Description:    r.PostForm.Get("description"),
+		IdempotencyKey: r.Header.Get("Idempotency-Key"),
+	})
+	if err != nil {
+		stubGatewayError(w, err)
+		return
+	}
+	stubJSON(w, map[string]interface{}{
+		"id":       id,
+		"object":   "payment_intent",
+		"amount":   amount,
+		"currency": r.PostForm.Get("currency"),
+		"status":   "requires_capture",
+	})
+}
+
+func (s *stripeStub) capture(w http.ResponseWriter, r *http.Request) {
+	if !s.parse(w, r) {
+		return
+	}
+	id := mux.Vars(r)["id"]
+	amount, _ := strconv.ParseInt(r.PostForm.Get("amount_to_capture"), 10, 64)
+	if err := s.gateway.Capture(r.Context(), id, amount, r.Header.Get("Idempotency-Key")); err != nil {
+		stubGatewayError(w, err)
+		return
+	}
+	stubJSON(w, map[string]interface{}{
+		"id":              id,
+		"object":          "payment_intent",
+		"amount_received": amount,
+		"status":          "succeeded",
+	})
+}
+
+func (s *stripeStub) void(w http.ResponseWriter, r *http.Request) {
+	if !s.parse(w, r) {
+		return
+	}
+	id := mux.Vars(r)["id"]
+	if err := s.gateway.Void(r.Context(), id, r.Header.Get("Idempotency-Key")); err != nil {
+		stubGatewayError(w, err)
+		return
+	}
+	stubJSON(w, map[string]interface{}{
+		"id":     id,
+		"object": "payment_intent",
+		"status": "canceled",
+	})
+}
+
+func (s *stripeStub) refund(w http.ResponseWriter, r *http.Request) {
+	if !s.parse(w, r) {
+		return
+	}
+	id := r.PostForm.Get("payment_intent")
+	amount, _ := strconv.ParseInt(r.PostForm.Get("amount"), 10, 64)
+	if err := s.gateway.Refund(r.Context(), id, amount, r.Header.Get("Idempotency-Key")); err != nil {
+		stubGatewayError(w, err)
+		return
+	}
+	stubJSON(w, map[string]interface{}{
+		"id":             fmt.Sprintf("re_fake_%d", atomic.AddInt64(&s.refunds, 1)),
+		"object":         "refund",
+		"payment_intent": id,
+		"amount":         amount,
+		"status":         "succeeded",
+	})
+}
+
+// parse checks the secret key and parses the form of r, responding with an
+// error and returning false if either fails.
+func (s *stripeStub) parse(w http.ResponseWriter, r *http.Request) bool {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		stubError(w, http.StatusUnauthorized, "invalid_request_error", "", "no API key provided")
+		return false
+	}
+	if err := r.ParseForm(); err != nil {
+		stubError(w, http.StatusBadRequest, "invalid_request_error", "parameter_invalid", err.Error())
+		return false
+	}
+	return true
+}
+
+// stubGatewayError responds with the Stripe error for an error of the fake
+// gateway, which StripeGateway maps back to the same error type.
+func stubGatewayError(w http.ResponseWriter, err error) {
+	e, _ := err.(errors.AppError)
+	switch errors.GetType(err) {
+	case errors.ValidationError:
+		if e.Context.Field == "paymentMethod" && e.Context.Message != "must not be empty" {
+			stubError(w, http.StatusPaymentRequired, "card_error", "card_declined", err.Error())
+			return
+		}
+		stubError(w, http.StatusBadRequest, "invalid_request_error", "parameter_invalid", err.Error())
+	case errors.NotFound:
+		stubError(w, http.StatusNotFound, "invalid_request_error", "resource_missing", err.Error())
+	case errors.Conflict:
+		stubError(w, http.StatusBadRequest, "invalid_request_error", "payment_intent_unexpected_state", err.Error())
+	default:
+		stubError(w, http.StatusInternalServerError, "api_error", "", err.Error())
+	}
+}
+
+func stubError(w http.ResponseWriter, status int, typ string, code string, msg string) {
+	var e stripeError
+	e.Error.Type = typ
+	e.Error.Code = code
+	e.Error.Message = msg
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(e)
+}
+
+func stubJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,83 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"net/http"
+	"reservations/pkg/authz"
+	"reservations/pkg/transport"
+)
+
+func MakeHTTPHandler(r *mux.Router, s Service, az authz.Authorizer, logger log.Logger, mw ...endpoint.Middleware) *mux.Router {
+	e := MakeServerEndpoints(s, az, mw...)
+
+	options := httpjson.DefaultServerOptions(logger)
+
+	r.Methods("GET").Path("/reservation/{id}/deposit").
+		Handler(httptransport.NewServer(
+			e.GetDepositEndpoint,
+			decodeGetDepositRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("POST").Path("/reservation/{id}/deposit").
+		Handler(httptransport.NewServer(
+			e.PayDepositEndpoint,
+			decodePayDepositRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("POST").Path("/reservation/{id}/deposit/settle").
+		Handler(httptransport.NewServer(
+			e.SettleDepositEndpoint,
+			decodeSettleDepositRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	return r
+}
+
+func decodeGetDepositRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "reservation ID")
+	if err != nil {
+		return nil, err
+	}
+	return getDepositRequest{ReservationID: id}, nil
+}
+
+func decodePayDepositRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req payDepositRequest
+
+	id, err := httpjson.ParseIntPathParam(r, "id", "reservation ID")
+	if err != nil {
+		return nil, err
+	}
+
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, e
+	}
+	req.ReservationID = id
+	return req, nil
+}
+
+func decodeSettleDepositRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req settleDepositRequest
+
+	id, err := httpjson.ParseIntPathParam(r, "id", "reservation ID")
+	if err != nil {
+		return nil, err
+	}
+
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, e
+	}
+	req.ReservationID = id
+	return req, nil
+}
@@ -0,0 +1,657 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: customer.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Customer struct {
+	CustomerId           int64    `protobuf:"varint,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	FirstName            string   `protobuf:"bytes,2,opt,name=first_name,json=firstName,proto3" json:"first_name,omitempty"`
+	LastName             string   `protobuf:"bytes,3,opt,name=last_name,json=lastName,proto3" json:"last_name,omitempty"`
+	Email                string   `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	Phone                string   `protobuf:"bytes,5,opt,name=phone,proto3" json:"phone,omitempty"`
+	Created              int64    `protobuf:"varint,6,opt,name=created,proto3" json:"created,omitempty"`
+	LastUpdated          int64    `protobuf:"varint,7,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Customer) Reset()         { *m = Customer{} }
+func (m *Customer) String() string { return proto.CompactTextString(m) }
+func (*Customer) ProtoMessage()    {}
+func (*Customer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{0}
+}
+
+func (m *Customer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Customer.Unmarshal(m, b)
+}
+func (m *Customer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Customer.Marshal(b, m, deterministic)
+}
+func (m *Customer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Customer.Merge(m, src)
+}
+func (m *Customer) XXX_Size() int {
+	return xxx_messageInfo_Customer.Size(m)
+}
+func (m *Customer) XXX_DiscardUnknown() {
+	xxx_messageInfo_Customer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Customer proto.InternalMessageInfo
+
+func (m *Customer) GetCustomerId() int64 {
+	if m != nil {
+		return m.CustomerId
+	}
+	return 0
+}
+
+func (m *Customer) GetFirstName() string {
+	if m != nil {
+		return m.FirstName
+	}
+	return ""
+}
+
+func (m *Customer) GetLastName() string {
+	if m != nil {
+		return m.LastName
+	}
+	return ""
+}
+
+func (m *Customer) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *Customer) GetPhone() string {
+	if m != nil {
+		return m.Phone
+	}
+	return ""
+}
+
+func (m *Customer) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *Customer) GetLastUpdated() int64 {
+	if m != nil {
+		return m.LastUpdated
+	}
+	return 0
+}
+
+type RegisterCustomerRequest struct {
+	Customer             *Customer `protobuf:"bytes,1,opt,name=customer,proto3" json:"customer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *RegisterCustomerRequest) Reset()         { *m = RegisterCustomerRequest{} }
+func (m *RegisterCustomerRequest) String() string { return proto.CompactTextString(m) }
+func (*RegisterCustomerRequest) ProtoMessage()    {}
+func (*RegisterCustomerRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{1}
+}
+
+func (m *RegisterCustomerRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterCustomerRequest.Unmarshal(m, b)
+}
+func (m *RegisterCustomerRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterCustomerRequest.Marshal(b, m, deterministic)
+}
+func (m *RegisterCustomerRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterCustomerRequest.Merge(m, src)
+}
+func (m *RegisterCustomerRequest) XXX_Size() int {
+	return xxx_messageInfo_RegisterCustomerRequest.Size(m)
+}
+func (m *RegisterCustomerRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterCustomerRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterCustomerRequest proto.InternalMessageInfo
+
+func (m *RegisterCustomerRequest) GetCustomer() *Customer {
+	if m != nil {
+		return m.Customer
+	}
+	return nil
+}
+
+type RegisterCustomerReply struct {
+	Customer             *Customer `protobuf:"bytes,1,opt,name=customer,proto3" json:"customer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *RegisterCustomerReply) Reset()         { *m = RegisterCustomerReply{} }
+func (m *RegisterCustomerReply) String() string { return proto.CompactTextString(m) }
+func (*RegisterCustomerReply) ProtoMessage()    {}
+func (*RegisterCustomerReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{2}
+}
+
+func (m *RegisterCustomerReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterCustomerReply.Unmarshal(m, b)
+}
+func (m *RegisterCustomerReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterCustomerReply.Marshal(b, m, deterministic)
+}
+func (m *RegisterCustomerReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterCustomerReply.Merge(m, src)
+}
+func (m *RegisterCustomerReply) XXX_Size() int {
+	return xxx_messageInfo_RegisterCustomerReply.Size(m)
+}
+func (m *RegisterCustomerReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterCustomerReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterCustomerReply proto.InternalMessageInfo
+
+func (m *RegisterCustomerReply) GetCustomer() *Customer {
+	if m != nil {
+		return m.Customer
+	}
+	return nil
+}
+
+type UnregisterCustomerRequest struct {
+	CustomerId           int64    `protobuf:"varint,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnregisterCustomerRequest) Reset()         { *m = UnregisterCustomerRequest{} }
+func (m *UnregisterCustomerRequest) String() string { return proto.CompactTextString(m) }
+func (*UnregisterCustomerRequest) ProtoMessage()    {}
+func (*UnregisterCustomerRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{3}
+}
+
+func (m *UnregisterCustomerRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnregisterCustomerRequest.Unmarshal(m, b)
+}
+func (m *UnregisterCustomerRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnregisterCustomerRequest.Marshal(b, m, deterministic)
+}
+func (m *UnregisterCustomerRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnregisterCustomerRequest.Merge(m, src)
+}
+func (m *UnregisterCustomerRequest) XXX_Size() int {
+	return xxx_messageInfo_UnregisterCustomerRequest.Size(m)
+}
+func (m *UnregisterCustomerRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnregisterCustomerRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnregisterCustomerRequest proto.InternalMessageInfo
+
+func (m *UnregisterCustomerRequest) GetCustomerId() int64 {
+	if m != nil {
+		return m.CustomerId
+	}
+	return 0
+}
+
+type UnregisterCustomerReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnregisterCustomerReply) Reset()         { *m = UnregisterCustomerReply{} }
+func (m *UnregisterCustomerReply) String() string { return proto.CompactTextString(m) }
+func (*UnregisterCustomerReply) ProtoMessage()    {}
+func (*UnregisterCustomerReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{4}
+}
+
+func (m *UnregisterCustomerReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnregisterCustomerReply.Unmarshal(m, b)
+}
+func (m *UnregisterCustomerReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnregisterCustomerReply.Marshal(b, m, deterministic)
+}
+func (m *UnregisterCustomerReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnregisterCustomerReply.Merge(m, src)
+}
+func (m *UnregisterCustomerReply) XXX_Size() int {
+	return xxx_messageInfo_UnregisterCustomerReply.Size(m)
+}
+func (m *UnregisterCustomerReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnregisterCustomerReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnregisterCustomerReply proto.InternalMessageInfo
+
+type GetAllCustomersRequest struct {
+	Limit                uint64   `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset               uint64   `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetAllCustomersRequest) Reset()         { *m = GetAllCustomersRequest{} }
+func (m *GetAllCustomersRequest) String() string { return proto.CompactTextString(m) }
+func (*GetAllCustomersRequest) ProtoMessage()    {}
+func (*GetAllCustomersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{5}
+}
+
+func (m *GetAllCustomersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAllCustomersRequest.Unmarshal(m, b)
+}
+func (m *GetAllCustomersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAllCustomersRequest.Marshal(b, m, deterministic)
+}
+func (m *GetAllCustomersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAllCustomersRequest.Merge(m, src)
+}
+func (m *GetAllCustomersRequest) XXX_Size() int {
+	return xxx_messageInfo_GetAllCustomersRequest.Size(m)
+}
+func (m *GetAllCustomersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAllCustomersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAllCustomersRequest proto.InternalMessageInfo
+
+func (m *GetAllCustomersRequest) GetLimit() uint64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *GetAllCustomersRequest) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type GetAllCustomersReply struct {
+	Customers            []*Customer `protobuf:"bytes,1,rep,name=customers,proto3" json:"customers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *GetAllCustomersReply) Reset()         { *m = GetAllCustomersReply{} }
+func (m *GetAllCustomersReply) String() string { return proto.CompactTextString(m) }
+func (*GetAllCustomersReply) ProtoMessage()    {}
+func (*GetAllCustomersReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{6}
+}
+
+func (m *GetAllCustomersReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAllCustomersReply.Unmarshal(m, b)
+}
+func (m *GetAllCustomersReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAllCustomersReply.Marshal(b, m, deterministic)
+}
+func (m *GetAllCustomersReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAllCustomersReply.Merge(m, src)
+}
+func (m *GetAllCustomersReply) XXX_Size() int {
+	return xxx_messageInfo_GetAllCustomersReply.Size(m)
+}
+func (m *GetAllCustomersReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAllCustomersReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAllCustomersReply proto.InternalMessageInfo
+
+func (m *GetAllCustomersReply) GetCustomers() []*Customer {
+	if m != nil {
+		return m.Customers
+	}
+	return nil
+}
+
+type GetCustomerByIDRequest struct {
+	CustomerId           int64    `protobuf:"varint,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetCustomerByIDRequest) Reset()         { *m = GetCustomerByIDRequest{} }
+func (m *GetCustomerByIDRequest) String() string { return proto.CompactTextString(m) }
+func (*GetCustomerByIDRequest) ProtoMessage()    {}
+func (*GetCustomerByIDRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{7}
+}
+
+func (m *GetCustomerByIDRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetCustomerByIDRequest.Unmarshal(m, b)
+}
+func (m *GetCustomerByIDRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetCustomerByIDRequest.Marshal(b, m, deterministic)
+}
+func (m *GetCustomerByIDRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetCustomerByIDRequest.Merge(m, src)
+}
+func (m *GetCustomerByIDRequest) XXX_Size() int {
+	return xxx_messageInfo_GetCustomerByIDRequest.Size(m)
+}
+func (m *GetCustomerByIDRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetCustomerByIDRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetCustomerByIDRequest proto.InternalMessageInfo
+
+func (m *GetCustomerByIDRequest) GetCustomerId() int64 {
+	if m != nil {
+		return m.CustomerId
+	}
+	return 0
+}
+
+type GetCustomerByIDReply struct {
+	Customer             *Customer `protobuf:"bytes,1,opt,name=customer,proto3" json:"customer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *GetCustomerByIDReply) Reset()         { *m = GetCustomerByIDReply{} }
+func (m *GetCustomerByIDReply) String() string { return proto.CompactTextString(m) }
+func (*GetCustomerByIDReply) ProtoMessage()    {}
+func (*GetCustomerByIDReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9efa92dae3d6ec46, []int{8}
+}
+
+func (m *GetCustomerByIDReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetCustomerByIDReply.Unmarshal(m, b)
+}
+func (m *GetCustomerByIDReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetCustomerByIDReply.Marshal(b, m, deterministic)
+}
+func (m *GetCustomerByIDReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetCustomerByIDReply.Merge(m, src)
+}
+func (m *GetCustomerByIDReply) XXX_Size() int {
+	return xxx_messageInfo_GetCustomerByIDReply.Size(m)
+}
+func (m *GetCustomerByIDReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetCustomerByIDReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetCustomerByIDReply proto.InternalMessageInfo
+
+func (m *GetCustomerByIDReply) GetCustomer() *Customer {
+	if m != nil {
+		return m.Customer
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Customer)(nil), "pb.Customer")
+	proto.RegisterType((*RegisterCustomerRequest)(nil), "pb.RegisterCustomerRequest")
+	proto.RegisterType((*RegisterCustomerReply)(nil), "pb.RegisterCustomerReply")
+	proto.RegisterType((*UnregisterCustomerRequest)(nil), "pb.UnregisterCustomerRequest")
+	proto.RegisterType((*UnregisterCustomerReply)(nil), "pb.UnregisterCustomerReply")
+	proto.RegisterType((*GetAllCustomersRequest)(nil), "pb.GetAllCustomersRequest")
+	proto.RegisterType((*GetAllCustomersReply)(nil), "pb.GetAllCustomersReply")
+	proto.RegisterType((*GetCustomerByIDRequest)(nil), "pb.GetCustomerByIDRequest")
+	proto.RegisterType((*GetCustomerByIDReply)(nil), "pb.GetCustomerByIDReply")
+}
+
+func init() { proto.RegisterFile("customer.proto", fileDescriptor_9efa92dae3d6ec46) }
+
+var fileDescriptor_9efa92dae3d6ec46 = []byte{
+	// 423 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x94, 0x4f, 0xcf, 0xd2, 0x40,
+	0x10, 0xc6, 0x2d, 0xff, 0x3b, 0x10, 0x35, 0x9b, 0x0a, 0x4b, 0x09, 0x11, 0x7b, 0x22, 0x1e, 0x20,
+	0xc1, 0x93, 0xd1, 0x83, 0x80, 0xd1, 0x90, 0x18, 0x0f, 0x4d, 0xb8, 0x78, 0x21, 0x2d, 0x5d, 0xb0,
+	0x71, 0xdb, 0xae, 0xbb, 0x8b, 0x09, 0x9f, 0xcf, 0x2f, 0xe0, 0x47, 0x32, 0xdd, 0xb2, 0x7d, 0x09,
+	0x6d, 0xf3, 0xbe, 0x1c, 0xe7, 0xf9, 0xed, 0x3c, 0xcc, 0x3c, 0x43, 0x0a, 0xcf, 0xf7, 0x27, 0x21,
+	0x93, 0x88, 0xf0, 0x19, 0xe3, 0x89, 0x4c, 0x50, 0x8d, 0xf9, 0xce, 0x3f, 0x03, 0x3a, 0xeb, 0x8b,
+	0x8c, 0x5e, 0x43, 0x57, 0x3f, 0xd9, 0x85, 0x01, 0x36, 0x26, 0xc6, 0xb4, 0xee, 0x82, 0x96, 0x36,
+	0x01, 0x1a, 0x03, 0x1c, 0x42, 0x2e, 0xe4, 0x2e, 0xf6, 0x22, 0x82, 0x6b, 0x13, 0x63, 0x6a, 0xba,
+	0xa6, 0x52, 0xbe, 0x7b, 0x11, 0x41, 0x23, 0x30, 0xa9, 0xa7, 0x69, 0x5d, 0xd1, 0x0e, 0xf5, 0x2e,
+	0xd0, 0x82, 0x26, 0x89, 0xbc, 0x90, 0xe2, 0x86, 0x02, 0x59, 0x91, 0xaa, 0xec, 0x67, 0x12, 0x13,
+	0xdc, 0xcc, 0x54, 0x55, 0x20, 0x0c, 0xed, 0x3d, 0x27, 0x9e, 0x24, 0x01, 0x6e, 0xa9, 0x21, 0x74,
+	0x89, 0xde, 0x40, 0x4f, 0xfd, 0xc4, 0x89, 0x05, 0x0a, 0xb7, 0x15, 0xee, 0xa6, 0xda, 0x36, 0x93,
+	0x9c, 0x35, 0x0c, 0x5c, 0x72, 0x0c, 0x85, 0x24, 0x5c, 0x6f, 0xe6, 0x92, 0xdf, 0x27, 0x22, 0x24,
+	0x9a, 0x42, 0x47, 0x6f, 0xa3, 0xb6, 0xeb, 0x2e, 0x7a, 0x33, 0xe6, 0xcf, 0xf2, 0x67, 0x39, 0x75,
+	0x96, 0xf0, 0xaa, 0x68, 0xc2, 0xe8, 0xf9, 0x0e, 0x8b, 0x8f, 0x30, 0xdc, 0xc6, 0xbc, 0x62, 0x92,
+	0xc7, 0xa2, 0x76, 0x86, 0x30, 0x28, 0xeb, 0x66, 0xf4, 0xec, 0x7c, 0x81, 0xfe, 0x57, 0x22, 0x97,
+	0x94, 0x6a, 0x59, 0x68, 0x57, 0x0b, 0x9a, 0x34, 0x8c, 0x42, 0xa9, 0xfc, 0x1a, 0x6e, 0x56, 0xa0,
+	0x3e, 0xb4, 0x92, 0xc3, 0x41, 0x10, 0xa9, 0x2e, 0xd6, 0x70, 0x2f, 0x95, 0xb3, 0x02, 0xab, 0xe0,
+	0x93, 0xae, 0xf8, 0x16, 0x4c, 0x3d, 0x88, 0xc0, 0xc6, 0xa4, 0x5e, 0xd8, 0xf1, 0x01, 0x3b, 0xef,
+	0xd5, 0x2c, 0x9a, 0xac, 0xce, 0x9b, 0xcf, 0x4f, 0xde, 0xf0, 0x13, 0x58, 0x85, 0xd6, 0xbb, 0x12,
+	0x5e, 0xfc, 0xad, 0x81, 0x99, 0xcf, 0x8e, 0xbe, 0xc1, 0xcb, 0xdb, 0x93, 0xa1, 0x51, 0xda, 0x59,
+	0xf1, 0x6f, 0xb0, 0x87, 0xe5, 0x30, 0x8d, 0xf8, 0x19, 0x72, 0x01, 0x15, 0xf3, 0x47, 0xe3, 0xb4,
+	0xa5, 0xf2, 0xaa, 0xf6, 0xa8, 0x0a, 0x67, 0x9e, 0x1b, 0x78, 0x71, 0x13, 0x38, 0xb2, 0xd3, 0x8e,
+	0xf2, 0x6b, 0xda, 0xb8, 0x94, 0x5d, 0x5b, 0x5d, 0x87, 0x97, 0x5b, 0x95, 0x1c, 0xc3, 0xc6, 0xa5,
+	0x4c, 0x59, 0xad, 0xf0, 0x8f, 0x3e, 0x27, 0x82, 0xf0, 0x3f, 0x9e, 0x0c, 0x93, 0x58, 0xcc, 0xd9,
+	0xaf, 0xe3, 0x9c, 0xf9, 0x1f, 0x98, 0xef, 0xb7, 0xd4, 0x77, 0xe2, 0xdd, 0xff, 0x01, 0x00, 0xe7,
+	0x33, 0xb3, 0xd2, 0x39, 0x04, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// CustomersClient is the client API for Customers service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CustomersClient interface {
+	RegisterCustomer(ctx context.Context, in *RegisterCustomerRequest, opts ...grpc.CallOption) (*RegisterCustomerReply, error)
+	UnregisterCustomer(ctx context.Context, in *UnregisterCustomerRequest, opts ...grpc.CallOption) (*UnregisterCustomerReply, error)
+	GetAllCustomers(ctx context.Context, in *GetAllCustomersRequest, opts ...grpc.CallOption) (*GetAllCustomersReply, error)
+	GetCustomerByID(ctx context.Context, in *GetCustomerByIDRequest, opts ...grpc.CallOption) (*GetCustomerByIDReply, error)
+}
+
+type customersClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCustomersClient(cc *grpc.ClientConn) CustomersClient {
+	return &customersClient{cc}
+}
+
+func (c *customersClient) RegisterCustomer(ctx context.Context, in *RegisterCustomerRequest, opts ...grpc.CallOption) (*RegisterCustomerReply, error) {
+	out := new(RegisterCustomerReply)
+	err := c.cc.Invoke(ctx, "/pb.Customers/RegisterCustomer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customersClient) UnregisterCustomer(ctx context.Context, in *UnregisterCustomerRequest, opts ...grpc.CallOption) (*UnregisterCustomerReply, error) {
+	out := new(UnregisterCustomerReply)
+	err := c.cc.Invoke(ctx, "/pb.Customers/UnregisterCustomer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customersClient) GetAllCustomers(ctx context.Context, in *GetAllCustomersRequest, opts ...grpc.CallOption) (*GetAllCustomersReply, error) {
+	out := new(GetAllCustomersReply)
+	err := c.cc.Invoke(ctx, "/pb.Customers/GetAllCustomers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customersClient) GetCustomerByID(ctx context.Context, in *GetCustomerByIDRequest, opts ...grpc.CallOption) (*GetCustomerByIDReply, error) {
+	out := new(GetCustomerByIDReply)
+	err := c.cc.Invoke(ctx, "/pb.Customers/GetCustomerByID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CustomersServer is the server API for Customers service.
+type CustomersServer interface {
+	RegisterCustomer(context.Context, *RegisterCustomerRequest) (*RegisterCustomerReply, error)
+	UnregisterCustomer(context.Context, *UnregisterCustomerRequest) (*UnregisterCustomerReply, error)
+	GetAllCustomers(context.Context, *GetAllCustomersRequest) (*GetAllCustomersReply, error)
+	GetCustomerByID(context.Context, *GetCustomerByIDRequest) (*GetCustomerByIDReply, error)
+}
+
+// UnimplementedCustomersServer can be embedded to have forward compatible implementations.
+type UnimplementedCustomersServer struct {
+}
+
+func (*UnimplementedCustomersServer) RegisterCustomer(ctx context.Context, req *RegisterCustomerRequest) (*RegisterCustomerReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterCustomer not implemented")
+}
+func (*UnimplementedCustomersServer) UnregisterCustomer(ctx context.Context, req *UnregisterCustomerRequest) (*UnregisterCustomerReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnregisterCustomer not implemented")
+}
+func (*UnimplementedCustomersServer) GetAllCustomers(ctx context.Context, req *GetAllCustomersRequest) (*GetAllCustomersReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllCustomers not implemented")
+}
+func (*UnimplementedCustomersServer) GetCustomerByID(ctx context.Context, req *GetCustomerByIDRequest) (*GetCustomerByIDReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCustomerByID not implemented")
+}
+
+func RegisterCustomersServer(s *grpc.Server, srv CustomersServer) {
+	s.RegisterService(&_Customers_serviceDesc, srv)
+}
+
+func _Customers_RegisterCustomer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterCustomerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomersServer).RegisterCustomer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Customers/RegisterCustomer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomersServer).RegisterCustomer(ctx, req.(*RegisterCustomerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Customers_UnregisterCustomer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnregisterCustomerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomersServer).UnregisterCustomer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Customers/UnregisterCustomer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomersServer).UnregisterCustomer(ctx, req.(*UnregisterCustomerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Customers_GetAllCustomers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAllCustomersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomersServer).GetAllCustomers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Customers/GetAllCustomers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomersServer).GetAllCustomers(ctx, req.(*GetAllCustomersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Customers_GetCustomerByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCustomerByIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomersServer).GetCustomerByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Customers/GetCustomerByID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomersServer).GetCustomerByID(ctx, req.(*GetCustomerByIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Customers_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Customers",
+	HandlerType: (*CustomersServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterCustomer",
+			Handler:    _Customers_RegisterCustomer_Handler,
+		},
+		{
+			MethodName: "UnregisterCustomer",
+			Handler:    _Customers_UnregisterCustomer_Handler,
+		},
+		{
+			MethodName: "GetAllCustomers",
+			Handler:    _Customers_GetAllCustomers_Handler,
+		},
+		{
+			MethodName: "GetCustomerByID",
+			Handler:    _Customers_GetCustomerByID_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "customer.proto",
+}
@@ -0,0 +1,5 @@
+// Package pb contains the protobuf definitions of the gRPC transport and the
+// code generated from them.
+package pb
+
+//go:generate buf generate
@@ -0,0 +1,954 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: reservation.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Reservation struct {
+	ReservationId   int64  `protobuf:"varint,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	SeatCount       int64  `protobuf:"varint,2,opt,name=seat_count,json=seatCount,proto3" json:"seat_count,omitempty"`
+	StartTime       string `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	ReservationName string `protobuf:"bytes,4,opt,name=reservation_name,json=reservationName,proto3" json:"reservation_name,omitempty"`
+	CustomerId      int64  `protobuf:"varint,5,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	Phone           string `protobuf:"bytes,6,opt,name=phone,proto3" json:"phone,omitempty"`
+	Comments        string `protobuf:"bytes,7,opt,name=comments,proto3" json:"comments,omitempty"`
+	Created         int64  `protobuf:"varint,8,opt,name=created,proto3" json:"created,omitempty"`
+	LastUpdated     int64  `protobuf:"varint,9,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	// area_id is the seating area asked for, or the one of the table allocated.
+	AreaId  int64 `protobuf:"varint,10,opt,name=area_id,json=areaId,proto3" json:"area_id,omitempty"`
+	TableId int64 `protobuf:"varint,11,opt,name=table_id,json=tableId,proto3" json:"table_id,omitempty"`
+	// combination_id is set instead of table_id for parties seated at tables
+	// pushed together.
+	CombinationId     int64 `protobuf:"varint,12,opt,name=combination_id,json=combinationId,proto3" json:"combination_id,omitempty"`
+	NeedsReassignment bool  `protobuf:"varint,13,opt,name=needs_reassignment,json=needsReassignment,proto3" json:"needs_reassignment,omitempty"`
+	// status is booked, seated or completed.
+	Status string `protobuf:"bytes,14,opt,name=status,proto3" json:"status,omitempty"`
+	// hold_token redeems a hold taken during checkout when booking.
+	HoldToken string `protobuf:"bytes,15,opt,name=hold_token,json=holdToken,proto3" json:"hold_token,omitempty"`
+	// series_id is the recurring series the reservation is an occurrence of,
+	// scheduled on the date occurrence.
+	SeriesId   int64  `protobuf:"varint,16,opt,name=series_id,json=seriesId,proto3" json:"series_id,omitempty"`
+	Occurrence string `protobuf:"bytes,17,opt,name=occurrence,proto3" json:"occurrence,omitempty"`
+	// fee and deposit are in the minor unit of the currency, the deposit
+	// being due by pay_by.
+	Fee                  int64    `protobuf:"varint,18,opt,name=fee,proto3" json:"fee,omitempty"`
+	Deposit              int64    `protobuf:"varint,19,opt,name=deposit,proto3" json:"deposit,omitempty"`
+	PayBy                int64    `protobuf:"varint,20,opt,name=pay_by,json=payBy,proto3" json:"pay_by,omitempty"`
+	StartsAt             int64    `protobuf:"varint,21,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	EndsAt               int64    `protobuf:"varint,22,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	VenueId              int64    `protobuf:"varint,23,opt,name=venue_id,json=venueId,proto3" json:"venue_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Reservation) Reset()         { *m = Reservation{} }
+func (m *Reservation) String() string { return proto.CompactTextString(m) }
+func (*Reservation) ProtoMessage()    {}
+func (*Reservation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c1b272c5347b2042, []int{0}
+}
+
+func (m *Reservation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Reservation.Unmarshal(m, b)
+}
+func (m *Reservation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Reservation.Marshal(b, m, deterministic)
+}
+func (m *Reservation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Reservation.Merge(m, src)
+}
+func (m *Reservation) XXX_Size() int {
+	return xxx_messageInfo_Reservation.Size(m)
+}
+func (m *Reservation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Reservation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Reservation proto.InternalMessageInfo
+
+func (m *Reservation) GetReservationId() int64 {
+	if m != nil {
+		return m.ReservationId
+	}
+	return 0
+}
+
+func (m *Reservation) GetSeatCount() int64 {
+	if m != nil {
+		return m.SeatCount
+	}
+	return 0
+}
+
+func (m *Reservation) GetStartTime() string {
+	if m != nil {
+		return m.StartTime
+	}
+	return ""
+}
+
+func (m *Reservation) GetReservationName() string {
+	if m != nil {
+		return m.ReservationName
+	}
+	return ""
+}
+
+func (m *Reservation) GetCustomerId() int64 {
+	if m != nil {
+		return m.CustomerId
+	}
+	return 0
+}
+
+func (m *Reservation) GetPhone() string {
+	if m != nil {
+		return m.Phone
+	}
+	return ""
+}
+
+func (m *Reservation) GetComments() string {
+	if m != nil {
+		return m.Comments
+	}
+	return ""
+}
+
+func (m *Reservation) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *Reservation) GetLastUpdated() int64 {
+	if m != nil {
+		return m.LastUpdated
+	}
+	return 0
+}
+
+func (m *Reservation) GetAreaId() int64 {
+	if m != nil {
+		return m.AreaId
+	}
+	return 0
+}
+
+func (m *Reservation) GetTableId() int64 {
+	if m != nil {
+		return m.TableId
+	}
+	return 0
+}
+
+func (m *Reservation) GetCombinationId() int64 {
+	if m != nil {
+		return m.CombinationId
+	}
+	return 0
+}
+
+func (m *Reservation) GetNeedsReassignment() bool {
+	if m != nil {
+		return m.NeedsReassignment
+	}
+	return false
+}
+
+func (m *Reservation) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *Reservation) GetHoldToken() string {
+	if m != nil {
+		return m.HoldToken
+	}
+	return ""
+}
+
+func (m *Reservation) GetSeriesId() int64 {
+	if m != nil {
+		return m.SeriesId
+	}
+	return 0
+}
+
+func (m *Reservation) GetOccurrence() string {
+	if m != nil {
+		return m.Occurrence
+	}
+	return ""
+}
+
+func (m *Reservation) GetFee() int64 {
+	if m != nil {
+		return m.Fee
+	}
+	return 0
+}
+
+func (m *Reservation) GetDeposit() int64 {
+	if m != nil {
+		return m.Deposit
+	}
+	return 0
+}
+
+func (m *Reservation) GetPayBy() int64 {
+	if m != nil {
+		return m.PayBy
+	}
+	return 0
+}
+
+func (m *Reservation) GetStartsAt() int64 {
+	if m != nil {
+		return m.StartsAt
+	}
+	return 0
+}
+
+func (m *Reservation) GetEndsAt() int64 {
+	if m != nil {
+		return m.EndsAt
+	}
+	return 0
+}
+
+func (m *Reservation) GetVenueId() int64 {
+	if m != nil {
+		return m.VenueId
+	}
+	return 0
+}
+
+type BookReservationRequest struct {
+	CustomerId           int64        `protobuf:"varint,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	Reservation          *Reservation `protobuf:"bytes,2,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *BookReservationRequest) Reset()         { *m = BookReservationRequest{} }
+func (m *BookReservationRequest) String() string { return proto.CompactTextString(m) }
+func (*BookReservationRequest) ProtoMessage()    {}
+func (*BookReservationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c1b272c5347b2042, []int{1}
+}
+
+func (m *BookReservationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BookReservationRequest.Unmarshal(m, b)
+}
+func (m *BookReservationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BookReservationRequest.Marshal(b, m, deterministic)
+}
+func (m *BookReservationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BookReservationRequest.Merge(m, src)
+}
+func (m *BookReservationRequest) XXX_Size() int {
+	return xxx_messageInfo_BookReservationRequest.Size(m)
+}
+func (m *BookReservationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BookReservationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BookReservationRequest proto.InternalMessageInfo
+
+func (m *BookReservationRequest) GetCustomerId() int64 {
+	if m != nil {
+		return m.CustomerId
+	}
+	return 0
+}
+
+func (m *BookReservationRequest) GetReservation() *Reservation {
+	if m != nil {
+		return m.Reservation
+	}
+	return nil
+}
+
+type BookReservationReply struct {
+	Reservation          *Reservation `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *BookReservationReply) Reset()         { *m = BookReservationReply{} }
+func (m *BookReservationReply) String() string { return proto.CompactTextString(m) }
+func (*BookReservationReply) ProtoMessage()    {}
+func (*BookReservationReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c1b272c5347b2042, []int{2}
+}
+
+func (m *BookReservationReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BookReservationReply.Unmarshal(m, b)
+}
+func (m *BookReservationReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BookReservationReply.Marshal(b, m, deterministic)
+}
+func (m *BookReservationReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BookReservationReply.Merge(m, src)
+}
+func (m *BookReservationReply) XXX_Size() int {
+	return xxx_messageInfo_BookReservationReply.Size(m)
+}
+func (m *BookReservationReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_BookReservationReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BookReservationReply proto.InternalMessageInfo
+
+func (m *BookReservationReply) GetReservation() *Reservation {
+	if m != nil {
+		return m.Reservation
+	}
+	return nil
+}
+
+type DiscardReservationRequest struct {
+	ReservationId        int64    `protobuf:"varint,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiscardReservationRequest) Reset()         { *m = DiscardReservationRequest{} }
+func (m *DiscardReservationRequest) String() string { return proto.CompactTextString(m) }
+func (*DiscardReservationRequest) ProtoMessage()    {}
+func (*DiscardReservationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c1b272c5347b2042, []int{3}
+}
+
+func (m *DiscardReservationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiscardReservationRequest.Unmarshal(m, b)
+}
+func (m *DiscardReservationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiscardReservationRequest.Marshal(b, m, deterministic)
+}
+func (m *DiscardReservationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiscardReservationRequest.Merge(m, src)
+}
+func (m *DiscardReservationRequest) XXX_Size() int {
+	return xxx_messageInfo_DiscardReservationRequest.Size(m)
+}
+func (m *DiscardReservationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiscardReservationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiscardReservationRequest proto.InternalMessageInfo
+
+func (m *DiscardReservationRequest) GetReservationId() int64 {
+	if m != nil {
+		return m.ReservationId
+	}
+	return 0
+}
+
+type DiscardReservationReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiscardReservationReply) Reset()         { *m = DiscardReservationReply{} }
+func (m *DiscardReservationReply) String() string { return proto.CompactTextString(m) }
+func (*DiscardReservationReply) ProtoMessage()    {}
+func (*DiscardReservationReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c1b272c5347b2042, []int{4}
+}
+
+func (m *DiscardReservationReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiscardReservationReply.Unmarshal(m, b)
+}
+func (m *DiscardReservationReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiscardReservationReply.Marshal(b, m, deterministic)
+}
+func (m *DiscardReservationReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiscardReservationReply.Merge(m, src)
+}
+func (m *DiscardReservationReply) XXX_Size() int {
+	return xxx_messageInfo_DiscardReservationReply.Size(m)
+}
+func (m *DiscardReservationReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiscardReservationReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiscardReservationReply proto.InternalMessageInfo
+
+type EditReservationRequest struct {
+	ReservationId        int64        `protobuf:"varint,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	Reservation          *Reservation `protobuf:"bytes,2,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *EditReservationRequest) Reset()         { *m = EditReservationRequest{} }
+func (m *EditReservationRequest) String() string { return proto.CompactTextString(m) }
+func (*EditReservationRequest) ProtoMessage()    {}
+func (*EditReservationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c1b272c5347b2042, []int{5}
+}
+
+func (m *EditReservationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EditReservationRequest.Unmarshal(m, b)
+}
+func (m *EditReservationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EditReservationRequest.Marshal(b, m, deterministic)
+}
+func (m *EditReservationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EditReservationRequest.Merge(m, src)
+}
+func (m *EditReservationRequest) XXX_Size() int {
+	return xxx_messageInfo_EditReservationRequest.Size(m)
+}
+func (m *EditReservationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EditReservationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EditReservationRequest proto.InternalMessageInfo
+
+func (m *EditReservationRequest) GetReservationId() int64 {
+	if m != nil {
+		return m.ReservationId
+	}
+	return 0
+}
+
+func (m *EditReservationRequest) GetReservation() *Reservation {
+	if m != nil {
+		return m.Reservation
+	}
+	return nil
+}
+
+type EditReservationReply struct {
+	Reservation          *Reservation `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *EditReservationReply) Reset()         { *m = EditReservationReply{} }
+func (m *EditReservationReply) String() string { return proto.CompactTextString(m) }
+func (*EditReservationReply) ProtoMessage()    {}
+func (*EditReservationReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c1b272c5347b2042, []int{6}
+}
+
+func (m *EditReservationReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EditReservationReply.Unmarshal(m, b)
+}
+func (m *EditReservationReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EditReservationReply.Marshal(b, m, deterministic)
+}
+func (m *EditReservationReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EditReservationReply.Merge(m, src)
+}
+func (m *EditReservationReply) XXX_Size() int {
+	return xxx_messageInfo_EditReservationReply.Size(m)
+}
+func (m *EditReservationReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_EditReservationReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EditReservationReply proto.InternalMessageInfo
+
+func (m *EditReservationReply) GetReservation() *Reservation {
+	if m != nil {
+		return m.Reservation
+	}
+	return nil
+}
+
+type GetReservationByIDRequest struct {
+	ReservationId        int64    `protobuf:"varint,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetReservationByIDRequest) Reset()         { *m = GetReservationByIDRequest{} }
+func (m *GetReservationByIDRequest) String() string { return proto.CompactTextString(m) }
+func (*GetReservationByIDRequest) ProtoMessage()    {}
+func (*GetReservationByIDRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c1b272c5347b2042, []int{7}
+}
+
+func (m *GetReservationByIDRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetReservationByIDRequest.Unmarshal(m, b)
+}
+func (m *GetReservationByIDRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetReservationByIDRequest.Marshal(b, m, deterministic)
+}
+func (m *GetReservationByIDRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetReservationByIDRequest.Merge(m, src)
+}
+func (m *GetReservationByIDRequest) XXX_Size() int {
+	return xxx_messageInfo_GetReservationByIDRequest.Size(m)
+}
+func (m *GetReservationByIDRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetReservationByIDRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetReservationByIDRequest proto.InternalMessageInfo
+
+func (m *GetReservationByIDRequest) GetReservationId() int64 {
+	if m != nil {
+		return m.ReservationId
+	}
+	return 0
+}
+
+type GetReservationByIDReply struct {
+	Reservation          *Reservation `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *GetReservationByIDReply) Reset()         { *m = GetReservationByIDReply{} }
+func (m *GetReservationByIDReply) String() string { return proto.CompactTextString(m) }
+func (*GetReservationByIDReply) ProtoMessage()    {}
+func (*GetReservationByIDReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c1b272c5347b2042, []int{8}
+}
+
+func (m *GetReservationByIDReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetReservationByIDReply.Unmarshal(m, b)
+}
+func (m *GetReservationByIDReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetReservationByIDReply.Marshal(b, m, deterministic)
+}
+func (m *GetReservationByIDReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetReservationByIDReply.Merge(m, src)
+}
+func (m *GetReservationByIDReply) XXX_Size() int {
+	return xxx_messageInfo_GetReservationByIDReply.Size(m)
+}
+func (m *GetReservationByIDReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetReservationByIDReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetReservationByIDReply proto.InternalMessageInfo
+
+func (m *GetReservationByIDReply) GetReservation() *Reservation {
+	if m != nil {
+		return m.Reservation
+	}
+	return nil
+}
+
+type GetReservationHistoryPerCustomerRequest struct {
+	CustomerId           int64    `protobuf:"varint,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	Limit                uint64   `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset               uint64   `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetReservationHistoryPerCustomerRequest) Reset() {
+	*m = GetReservationHistoryPerCustomerRequest{}
+}
+func (m *GetReservationHistoryPerCustomerRequest) String() string { return proto.CompactTextString(m) }
+func (*GetReservationHistoryPerCustomerRequest) ProtoMessage()    {}
+func (*GetReservationHistoryPerCustomerRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c1b272c5347b2042, []int{9}
+}
+
+func (m *GetReservationHistoryPerCustomerRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetReservationHistoryPerCustomerRequest.Unmarshal(m, b)
+}
+func (m *GetReservationHistoryPerCustomerRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetReservationHistoryPerCustomerRequest.Marshal(b, m, deterministic)
+}
+func (m *GetReservationHistoryPerCustomerRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetReservationHistoryPerCustomerRequest.Merge(m, src)
+}
+func (m *GetReservationHistoryPerCustomerRequest) XXX_Size() int {
+	return xxx_messageInfo_GetReservationHistoryPerCustomerRequest.Size(m)
+}
+func (m *GetReservationHistoryPerCustomerRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetReservationHistoryPerCustomerRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetReservationHistoryPerCustomerRequest proto.InternalMessageInfo
+
+func (m *GetReservationHistoryPerCustomerRequest) GetCustomerId() int64 {
+	if m != nil {
+		return m.CustomerId
+	}
+	return 0
+}
+
+func (m *GetReservationHistoryPerCustomerRequest) GetLimit() uint64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *GetReservationHistoryPerCustomerRequest) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type GetReservationHistoryPerCustomerReply struct {
+	Reservations         []*Reservation `protobuf:"bytes,1,rep,name=reservations,proto3" json:"reservations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *GetReservationHistoryPerCustomerReply) Reset()         { *m = GetReservationHistoryPerCustomerReply{} }
+func (m *GetReservationHistoryPerCustomerReply) String() string { return proto.CompactTextString(m) }
+func (*GetReservationHistoryPerCustomerReply) ProtoMessage()    {}
+func (*GetReservationHistoryPerCustomerReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c1b272c5347b2042, []int{10}
+}
+
+func (m *GetReservationHistoryPerCustomerReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetReservationHistoryPerCustomerReply.Unmarshal(m, b)
+}
+func (m *GetReservationHistoryPerCustomerReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetReservationHistoryPerCustomerReply.Marshal(b, m, deterministic)
+}
+func (m *GetReservationHistoryPerCustomerReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetReservationHistoryPerCustomerReply.Merge(m, src)
+}
+func (m *GetReservationHistoryPerCustomerReply) XXX_Size() int {
+	return xxx_messageInfo_GetReservationHistoryPerCustomerReply.Size(m)
+}
+func (m *GetReservationHistoryPerCustomerReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetReservationHistoryPerCustomerReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetReservationHistoryPerCustomerReply proto.InternalMessageInfo
+
+func (m *GetReservationHistoryPerCustomerReply) GetReservations() []*Reservation {
+	if m != nil {
+		return m.Reservations
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Reservation)(nil), "pb.Reservation")
+	proto.RegisterType((*BookReservationRequest)(nil), "pb.BookReservationRequest")
+	proto.RegisterType((*BookReservationReply)(nil), "pb.BookReservationReply")
+	proto.RegisterType((*DiscardReservationRequest)(nil), "pb.DiscardReservationRequest")
+	proto.RegisterType((*DiscardReservationReply)(nil), "pb.DiscardReservationReply")
+	proto.RegisterType((*EditReservationRequest)(nil), "pb.EditReservationRequest")
+	proto.RegisterType((*EditReservationReply)(nil), "pb.EditReservationReply")
+	proto.RegisterType((*GetReservationByIDRequest)(nil), "pb.GetReservationByIDRequest")
+	proto.RegisterType((*GetReservationByIDReply)(nil), "pb.GetReservationByIDReply")
+	proto.RegisterType((*GetReservationHistoryPerCustomerRequest)(nil), "pb.GetReservationHistoryPerCustomerRequest")
+	proto.RegisterType((*GetReservationHistoryPerCustomerReply)(nil), "pb.GetReservationHistoryPerCustomerReply")
+}
+
+func init() { proto.RegisterFile("reservation.proto", fileDescriptor_c1b272c5347b2042) }
+
+var fileDescriptor_c1b272c5347b2042 = []byte{
+	// 718 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x51, 0x4f, 0xdb, 0x3c,
+	0x14, 0xfd, 0x4a, 0x69, 0x69, 0x6f, 0x0b, 0x05, 0x7f, 0xa5, 0x35, 0x45, 0x6c, 0x5d, 0x25, 0x34,
+	0xd0, 0x34, 0xd0, 0xe0, 0x71, 0x4f, 0x14, 0xa6, 0x2d, 0xd2, 0x34, 0x4d, 0x11, 0x7b, 0x99, 0x26,
+	0x45, 0x4e, 0x7c, 0x81, 0x88, 0x26, 0xce, 0x62, 0x17, 0x2d, 0xd2, 0xfe, 0xdb, 0xde, 0xf7, 0xab,
+	0x26, 0x3b, 0x85, 0x99, 0x36, 0x15, 0x5d, 0xdf, 0x7a, 0xcf, 0xb9, 0x39, 0xf7, 0xfa, 0xe4, 0x38,
+	0x85, 0xad, 0x14, 0x25, 0xa6, 0x77, 0x4c, 0x85, 0x22, 0x3e, 0x4a, 0x52, 0xa1, 0x04, 0x59, 0x49,
+	0xfc, 0xc1, 0xaf, 0x0a, 0x34, 0xdc, 0xbf, 0x0c, 0xd9, 0x87, 0x0d, 0xab, 0xd1, 0x0b, 0x39, 0x2d,
+	0xf5, 0x4b, 0x07, 0x65, 0x77, 0xdd, 0x42, 0x1d, 0x4e, 0xf6, 0x00, 0x24, 0x32, 0xe5, 0x05, 0x62,
+	0x1c, 0x2b, 0xba, 0x62, 0x5a, 0xea, 0x1a, 0x39, 0xd7, 0x80, 0xa1, 0x15, 0x4b, 0x95, 0xa7, 0xc2,
+	0x08, 0x69, 0xb9, 0x5f, 0x3a, 0xa8, 0xbb, 0x75, 0x83, 0x5c, 0x86, 0x11, 0x92, 0x43, 0xd8, 0xb4,
+	0x87, 0xc4, 0x2c, 0x42, 0xba, 0x6a, 0x9a, 0x5a, 0x16, 0xfe, 0x89, 0x45, 0x48, 0x9e, 0x43, 0x23,
+	0x18, 0x4b, 0x25, 0x22, 0x4c, 0xf5, 0x32, 0x15, 0x33, 0x09, 0xee, 0x21, 0x87, 0x93, 0x36, 0x54,
+	0x92, 0x1b, 0x11, 0x23, 0xad, 0x1a, 0x81, 0xbc, 0x20, 0x3d, 0xa8, 0x05, 0x22, 0x8a, 0x30, 0x56,
+	0x92, 0xae, 0x19, 0xe2, 0xa1, 0x26, 0x14, 0xd6, 0x82, 0x14, 0x99, 0x42, 0x4e, 0x6b, 0x46, 0xee,
+	0xbe, 0x24, 0x2f, 0xa0, 0x39, 0x62, 0x52, 0x79, 0xe3, 0x84, 0x1b, 0xba, 0x6e, 0xe8, 0x86, 0xc6,
+	0xbe, 0xe4, 0x10, 0xe9, 0xc2, 0x1a, 0x4b, 0x91, 0xe9, 0x5d, 0xc0, 0xb0, 0x55, 0x5d, 0x3a, 0x9c,
+	0xec, 0x40, 0x4d, 0x31, 0x7f, 0x84, 0x9a, 0x69, 0xe4, 0xb2, 0xa6, 0x76, 0xb8, 0xf6, 0x34, 0x10,
+	0x91, 0x1f, 0xc6, 0x0f, 0x9e, 0x36, 0x73, 0x4f, 0x2d, 0xd4, 0xe1, 0xe4, 0x35, 0x90, 0x18, 0x91,
+	0x4b, 0x2f, 0x45, 0x26, 0x65, 0x78, 0x1d, 0xeb, 0x75, 0xe9, 0x7a, 0xbf, 0x74, 0x50, 0x73, 0xb7,
+	0x0c, 0xe3, 0x5a, 0x04, 0xe9, 0x40, 0x55, 0x2a, 0xa6, 0xc6, 0x92, 0x6e, 0x98, 0x03, 0x4e, 0x2a,
+	0xed, 0xfd, 0x8d, 0x18, 0x71, 0x4f, 0x89, 0x5b, 0x8c, 0x69, 0x2b, 0xf7, 0x5e, 0x23, 0x97, 0x1a,
+	0x20, 0xbb, 0x50, 0x97, 0x98, 0x86, 0x28, 0xf5, 0x1e, 0x9b, 0x66, 0x8f, 0x5a, 0x0e, 0x38, 0x9c,
+	0x3c, 0x03, 0x10, 0x41, 0x30, 0x4e, 0x53, 0x8c, 0x03, 0xa4, 0x5b, 0xe6, 0x59, 0x0b, 0x21, 0x9b,
+	0x50, 0xbe, 0x42, 0xa4, 0xc4, 0x3c, 0xa6, 0x7f, 0x6a, 0x33, 0x39, 0x26, 0x42, 0x86, 0x8a, 0xfe,
+	0x9f, 0x9f, 0x7a, 0x52, 0x92, 0x6d, 0xa8, 0x26, 0x2c, 0xf3, 0xfc, 0x8c, 0xb6, 0x0d, 0x51, 0x49,
+	0x58, 0x36, 0xcc, 0xcc, 0x7c, 0x1d, 0x04, 0xe9, 0x31, 0x45, 0xb7, 0x27, 0xf3, 0x0d, 0x70, 0xa6,
+	0xb4, 0xbb, 0x18, 0x73, 0x43, 0x75, 0x72, 0x77, 0x75, 0x79, 0xa6, 0xb4, 0xbb, 0x77, 0x18, 0x8f,
+	0x8d, 0xbb, 0xdd, 0x7c, 0x8e, 0xa9, 0x1d, 0x3e, 0x18, 0x41, 0x67, 0x28, 0xc4, 0xad, 0x15, 0x62,
+	0x17, 0xbf, 0x8f, 0x51, 0xaa, 0xe9, 0xec, 0x94, 0x66, 0xb2, 0xf3, 0x06, 0x1a, 0x56, 0xde, 0x4c,
+	0x8c, 0x1b, 0x27, 0xad, 0xa3, 0xc4, 0x3f, 0xb2, 0xd5, 0xec, 0x9e, 0x81, 0x03, 0xed, 0x99, 0x69,
+	0xc9, 0x28, 0x9b, 0x96, 0x2a, 0x2d, 0x20, 0x35, 0x84, 0x9d, 0x8b, 0x50, 0x06, 0x2c, 0xe5, 0x05,
+	0xbb, 0x2f, 0x76, 0x0f, 0x07, 0x3b, 0xd0, 0x2d, 0xd2, 0x48, 0x46, 0xd9, 0x20, 0x85, 0xce, 0x3b,
+	0x1e, 0xaa, 0xa5, 0xb5, 0x97, 0x74, 0x67, 0x66, 0xe6, 0xf2, 0xee, 0xbc, 0x47, 0x5b, 0x69, 0x98,
+	0x39, 0x17, 0xff, 0xe8, 0xce, 0x47, 0xe8, 0x16, 0x69, 0x2c, 0xb9, 0xd1, 0x0f, 0x78, 0xf9, 0x58,
+	0xed, 0x43, 0x28, 0x95, 0x48, 0xb3, 0xcf, 0x98, 0x9e, 0x4f, 0x32, 0xb5, 0x70, 0xf2, 0xda, 0x50,
+	0x19, 0x85, 0x51, 0x98, 0x7f, 0x3a, 0x57, 0xdd, 0xbc, 0xd0, 0x57, 0x5a, 0x5c, 0x5d, 0x49, 0x54,
+	0xe6, 0x93, 0xb9, 0xea, 0x4e, 0xaa, 0xc1, 0x37, 0xd8, 0x7f, 0x7a, 0xb2, 0x3e, 0xd5, 0x29, 0x34,
+	0xad, 0x8d, 0x25, 0x2d, 0xf5, 0xcb, 0x45, 0xc7, 0x7a, 0xd4, 0x74, 0xf2, 0xbb, 0x0c, 0x4d, 0x8b,
+	0x95, 0xc4, 0x81, 0xd6, 0x54, 0xc6, 0x49, 0x4f, 0x4b, 0x14, 0x5f, 0xb3, 0x1e, 0x2d, 0xe4, 0x74,
+	0x04, 0xff, 0x23, 0x2e, 0x90, 0xd9, 0x7c, 0x92, 0x3d, 0xfd, 0xc4, 0xdc, 0xec, 0xf7, 0x76, 0xe7,
+	0xd1, 0xb9, 0xa6, 0x03, 0xad, 0xa9, 0x90, 0xe5, 0xeb, 0x15, 0xa7, 0xbd, 0x47, 0x0b, 0xb9, 0x87,
+	0xf5, 0x66, 0x03, 0x92, 0xaf, 0x37, 0x37, 0x7c, 0xbd, 0xdd, 0x79, 0x74, 0xae, 0xf9, 0x13, 0xfa,
+	0x4f, 0xbd, 0x2c, 0xf2, 0x6a, 0x56, 0x62, 0x6e, 0x98, 0x7a, 0x87, 0x8b, 0x35, 0x9b, 0xe9, 0x43,
+	0xfa, 0xb5, 0x63, 0xbf, 0xdc, 0xe3, 0xe4, 0xf6, 0xfa, 0x38, 0xf1, 0xdf, 0x26, 0xbe, 0x5f, 0x35,
+	0x7f, 0xfa, 0xa7, 0x7f, 0x06, 0x00, 0x11, 0x7a, 0x56, 0xc5, 0x09, 0x08, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ReservationsClient is the client API for Reservations service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ReservationsClient interface {
+	BookReservation(ctx context.Context, in *BookReservationRequest, opts ...grpc.CallOption) (*BookReservationReply, error)
+	DiscardReservation(ctx context.Context, in *DiscardReservationRequest, opts ...grpc.CallOption) (*DiscardReservationReply, error)
+	EditReservation(ctx context.Context, in *EditReservationRequest, opts ...grpc.CallOption) (*EditReservationReply, error)
+	GetReservationByID(ctx context.Context, in *GetReservationByIDRequest, opts ...grpc.CallOption) (*GetReservationByIDReply, error)
+	GetReservationHistoryPerCustomer(ctx context.Context, in *GetReservationHistoryPerCustomerRequest, opts ...grpc.CallOption) (*GetReservationHistoryPerCustomerReply, error)
+}
+
+type reservationsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewReservationsClient(cc *grpc.ClientConn) ReservationsClient {
+	return &reservationsClient{cc}
+}
+
+func (c *reservationsClient) BookReservation(ctx context.Context, in *BookReservationRequest, opts ...grpc.CallOption) (*BookReservationReply, error) {
+	out := new(BookReservationReply)
+	err := c.cc.Invoke(ctx, "/pb.Reservations/BookReservation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationsClient) DiscardReservation(ctx context.Context, in *DiscardReservationRequest, opts ...grpc.CallOption) (*DiscardReservationReply, error) {
+	out := new(DiscardReservationReply)
+	err := c.cc.Invoke(ctx, "/pb.Reservations/DiscardReservation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationsClient) EditReservation(ctx context.Context, in *EditReservationRequest, opts ...grpc.CallOption) (*EditReservationReply, error) {
+	out := new(EditReservationReply)
+	err := c.cc.Invoke(ctx, "/pb.Reservations/EditReservation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationsClient) GetReservationByID(ctx context.Context, in *GetReservationByIDRequest, opts ...grpc.CallOption) (*GetReservationByIDReply, error) {
+	out := new(GetReservationByIDReply)
+	err := c.cc.Invoke(ctx, "/pb.Reservations/GetReservationByID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reservationsClient) GetReservationHistoryPerCustomer(ctx context.Context, in *GetReservationHistoryPerCustomerRequest, opts ...grpc.CallOption) (*GetReservationHistoryPerCustomerReply, error) {
+	out := new(GetReservationHistoryPerCustomerReply)
+	err := c.cc.Invoke(ctx, "/pb.Reservations/GetReservationHistoryPerCustomer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReservationsServer is the server API for Reservations service.
+type ReservationsServer interface {
+	BookReservation(context.Context, *BookReservationRequest) (*BookReservationReply, error)
+	DiscardReservation(context.Context, *DiscardReservationRequest) (*DiscardReservationReply, error)
+	EditReservation(context.Context, *EditReservationRequest) (*EditReservationReply, error)
+	GetReservationByID(context.Context, *GetReservationByIDRequest) (*GetReservationByIDReply, error)
+	GetReservationHistoryPerCustomer(context.Context, *GetReservationHistoryPerCustomerRequest) (*GetReservationHistoryPerCustomerReply, error)
+}
+
+// UnimplementedReservationsServer can be embedded to have forward compatible implementations.
+type UnimplementedReservationsServer struct {
+}
+
+func (*UnimplementedReservationsServer) BookReservation(ctx context.Context, req *BookReservationRequest) (*BookReservationReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BookReservation not implemented")
+}
+func (*UnimplementedReservationsServer) DiscardReservation(ctx context.Context, req *DiscardReservationRequest) (*DiscardReservationReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscardReservation not implemented")
+}
+func (*UnimplementedReservationsServer) EditReservation(ctx context.Context, req *EditReservationRequest) (*EditReservationReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EditReservation not implemented")
+}
+func (*UnimplementedReservationsServer) GetReservationByID(ctx context.Context, req *GetReservationByIDRequest) (*GetReservationByIDReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReservationByID not implemented")
+}
+func (*UnimplementedReservationsServer) GetReservationHistoryPerCustomer(ctx context.Context, req *GetReservationHistoryPerCustomerRequest) (*GetReservationHistoryPerCustomerReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReservationHistoryPerCustomer not implemented")
+}
+
+func RegisterReservationsServer(s *grpc.Server, srv ReservationsServer) {
+	s.RegisterService(&_Reservations_serviceDesc, srv)
+}
+
+func _Reservations_BookReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationsServer).BookReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Reservations/BookReservation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationsServer).BookReservation(ctx, req.(*BookReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Reservations_DiscardReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscardReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationsServer).DiscardReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Reservations/DiscardReservation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationsServer).DiscardReservation(ctx, req.(*DiscardReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Reservations_EditReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EditReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationsServer).EditReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Reservations/EditReservation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationsServer).EditReservation(ctx, req.(*EditReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Reservations_GetReservationByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReservationByIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationsServer).GetReservationByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Reservations/GetReservationByID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationsServer).GetReservationByID(ctx, req.(*GetReservationByIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Reservations_GetReservationHistoryPerCustomer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReservationHistoryPerCustomerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReservationsServer).GetReservationHistoryPerCustomer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Reservations/GetReservationHistoryPerCustomer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReservationsServer).GetReservationHistoryPerCustomer(ctx, req.(*GetReservationHistoryPerCustomerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Reservations_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Reservations",
+	HandlerType: (*ReservationsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "BookReservation",
+			Handler:    _Reservations_BookReservation_Handler,
+		},
+		{
+			MethodName: "DiscardReservation",
+			Handler:    _Reservations_DiscardReservation_Handler,
+		},
+		{
+			MethodName: "EditReservation",
+			Handler:    _Reservations_EditReservation_Handler,
+		},
+		{
+			MethodName: "GetReservationByID",
+			Handler:    _Reservations_GetReservationByID_Handler,
+		},
+		{
+			MethodName: "GetReservationHistoryPerCustomer",
+			Handler:    _Reservations_GetReservationHistoryPerCustomer_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "reservation.proto",
+}
@@ -0,0 +1,442 @@
+// Package policy restricts bookings by declarative rules, such as how far
+// ahead they may be made or how many guests they may be for, and sets the
+// fees charged for cancelling them late and the deposits required to hold
+// them. Rules, cancellation terms and deposit terms each apply to some
+// booking channels and shifts only or to all of them.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"reservations/pkg/authz"
+	errors "reservations/pkg/error"
+	"strings"
+	"time"
+)
+
+// Channel is the way a booking is made.
+type Channel string
+
+const (
+	// Online bookings are made by guests themselves.
+	Online Channel = "online"
+	// Staff bookings are entered by the staff, e.g. when taken by phone.
+	Staff Channel = "staff"
+)
+
+// ChannelFromContext returns the channel of the bookings made by the caller
+// of ctx: guests book online, and every other role, as well as trusted
+// callers when authorization is disabled, books as staff.
+func ChannelFromContext(ctx context.Context) Channel {
+	if g, ok := authz.GrantFromContext(ctx); ok && g.Role == authz.RoleGuest {
+		return Online
+	}
+	return Staff
+}
+
+// TimeLayout is the format of the times of day bounding shifts.
+const TimeLayout = "15:04"
+
+// DateLayout is the format of the dates of shifts held on some days only.
+const DateLayout = "2006-01-02"
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Shift is a named part of the opening hours, such as lunch or dinner.
+type Shift struct {
+	Name string `yaml:"name"`
+	// Days lists the days the shift runs on, as mon to sun, and is empty
+	// for a shift running every day.
+	Days []string `yaml:"days"`
+	// Dates lists the dates, such as 2006-12-31, of a shift held on those
+	// days only, e.g. for an event, instead of Days.
+	Dates []string `yaml:"dates"`
+	// Start and End are the times of day, such as 17:30, between which
+	// bookings start in the shift, End excluded. A shift ending before it
+	// starts runs past midnight.
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+}
+
+// Rule limits the bookings made through Channels for Shifts, any channel or
+// shift if empty. Limits left at zero are not checked.
+type Rule struct {
+	Name     string    `yaml:"name"`
+	Channels []Channel `yaml:"channels"`
+	Shifts   []string  `yaml:"shifts"`
+	// MinLeadTime is how long ahead of its start a booking is made at the
+	// latest.
+	MinLeadTime time.Duration `yaml:"minLeadTime"`
+	// MaxAdvance is how long ahead of its start a booking is made at the
+	// earliest.
+	MaxAdvance   time.Duration `yaml:"maxAdvance"`
+	MinPartySize int           `yaml:"minPartySize"`
+	MaxPartySize int           `yaml:"maxPartySize"`
+	// Message explains a violation of the rule, such as "parties over 8
+	// must call", instead of the limit broken.
+	Message string `yaml:"message"`
+}
+
+// Cancellation are the terms under which bookings made through Channels
+// for Shifts, any channel or shift if empty, are charged for when cancelled
+// or modified late.
+type Cancellation struct {
+	Name     string    `yaml:"name"`
+	Channels []Channel `yaml:"channels"`
+	Shifts   []string  `yaml:"shifts"`
+	// FreeUntil is how long before its start a booking is cancelled or
+	// modified free of charge at the latest.
+	FreeUntil time.Duration `yaml:"freeUntil"`
+	// NonRefundable bookings, such as for events, are charged whenever
+	// they are cancelled.
+	NonRefundable bool `yaml:"nonRefundable"`
+	// FeePerGuest is charged for every guest cancelled, in the minor unit
+	// of the currency, such as cents.
+	FeePerGuest int64 `yaml:"feePerGuest"`
+}
+
+// Deposit are the terms under which bookings made through Channels for
+// Shifts, any channel or shift if empty, are held only once a deposit is
+// paid.
+type Deposit struct {
+	Name     string    `yaml:"name"`
+	Channels []Channel `yaml:"channels"`
+	Shifts   []string  `yaml:"shifts"`
+	// MinPartySize is the smallest party a deposit is required for, any
+	// party if zero.
+	MinPartySize int `yaml:"minPartySize"`
+	// AmountPerGuest is required for every guest booked, in the minor unit
+	// of the currency, such as cents.
+	AmountPerGuest int64 `yaml:"amountPerGuest"`
+}
+
+// Policy holds the rules every booking and edit is checked against, the
+// cancellation terms, the first applying of which sets the fee, and the
+// deposit terms, the first applying of which sets the deposit.
+type Policy struct {
+	// Currency is the ISO 4217 code, such as eur, of fees and deposits.
+	Currency      string         `yaml:"currency"`
+	Shifts        []Shift        `yaml:"shifts"`
+	Rules         []Rule         `yaml:"rules"`
+	Cancellations []Cancellation `yaml:"cancellations"`
+	Deposits      []Deposit      `yaml:"deposits"`
+}
+
+// Booking is a booking, or the edit of one, checked against a policy.
+type Booking struct {
+	Channel Channel
+	// Start is the start of the booking in the UTC offset of the venue,
+	// which shifts are matched in.
+	Start time.Time
+	// PreviousStart is the start of an edited booking before the edit, and
+	// zero for new ones. Edits keeping the start are not held to the lead
+	// time and advance window.
+	PreviousStart time.Time
+	SeatCount     int
+	// Now is the time the booking is made.
+	Now time.Time
+}
+
+// Check returns a ValidationError, whose context names the rule, for the
+// first rule of p that b violates.
+func (p Policy) Check(b Booking) error {
+	for _, r := range p.Rules {
+		if !r.appliesTo(b, p.Shifts) {
+			continue
+		}
+		if msg := r.violation(b); msg != "" {
+			if r.Message != "" {
+				msg = r.Message
+			}
+			return errors.ValidationError.Newf("booking violates policy %s: %s", r.Name, msg).
+				AddContext(r.Name, msg)
+		}
+	}
+	return nil
+}
+
+// Fee returns the fee charged for cancelling seats of the guests of b at
+// b.Now, and the name of the cancellation terms charging it, if any.
+func (p Policy) Fee(b Booking, seats int) (string, int64) {
+	for _, c := range p.Cancellations {
+		if !applies(c.Channels, c.Shifts, b, p.Shifts) {
+			continue
+		}
+		if c.NonRefundable || b.Start.Sub(b.Now) < c.FreeUntil {
+			return c.Name, c.FeePerGuest * int64(seats)
+		}
+		return c.Name, 0
+	}
+	return "", 0
+}
+
+// Deposit returns the deposit required for b and the name of the deposit
+// terms requiring it, if any.
+func (p Policy) Deposit(b Booking) (string, int64) {
+	for _, d := range p.Deposits {
+		if !applies(d.Channels, d.Shifts, b, p.Shifts) || b.SeatCount < d.MinPartySize {
+			continue
+		}
+		return d.Name, d.AmountPerGuest * int64(b.SeatCount)
+	}
+	return "", 0
+}
+
+func (r Rule) appliesTo(b Booking, shifts []Shift) bool {
+	return applies(r.Channels, r.Shifts, b, shifts)
+}
+
+// applies reports whether b is made through one of channels for one of the
+// shifts named, any channel or shift if empty.
+func applies(channels []Channel, names []string, b Booking, shifts []Shift) bool {
+	if len(channels) > 0 && !containsChannel(channels, b.Channel) {
+		return false
+	}
+	if len(names) == 0 {
+		return true
+	}
+	for _, s := range shifts {
+		if containsString(names, s.Name) && s.contains(b.Start) {
+			return true
+		}
+	}
+	return false
+}
+
+// violation describes the limit of r that b breaks, if any.
+func (r Rule) violation(b Booking) string {
+	if b.PreviousStart.IsZero() || !b.PreviousStart.Equal(b.Start) {
+		ahead := b.Start.Sub(b.Now)
+		if r.MinLeadTime > 0 && ahead < r.MinLeadTime {
+			return fmt.Sprintf("must be booked at least %s ahead", duration(r.MinLeadTime))
+		}
+		if r.MaxAdvance > 0 && ahead > r.MaxAdvance {
+			return fmt.Sprintf("must be booked at most %s ahead", duration(r.MaxAdvance))
+		}
+	}
+	if r.MinPartySize > 0 && b.SeatCount < r.MinPartySize {
+		return fmt.Sprintf("must be for at least %d guests", r.MinPartySize)
+	}
+	if r.MaxPartySize > 0 && b.SeatCount > r.MaxPartySize {
+		return fmt.Sprintf("must be for at most %d guests", r.MaxPartySize)
+	}
+	return ""
+}
+
+// contains reports whether bookings starting at t are in s.
+func (s Shift) contains(t time.Time) bool {
+	day := t
+	if s.overnight() && minutes(t) < s.minutes(s.End) {
+		// The early hours belong to the shift of the day before.
+		day = t.AddDate(0, 0, -1)
+	}
+	if len(s.Dates) > 0 && !containsString(s.Dates, day.Format(DateLayout)) {
+		return false
+	}
+	if len(s.Days) > 0 {
+		found := false
+		for _, d := range s.Days {
+			if weekdays[d] == day.Weekday() {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	m, start, end := minutes(t), s.minutes(s.Start), s.minutes(s.End)
+	if s.overnight() {
+		return m >= start || m < end
+	}
+	return m >= start && m < end
+}
+
+func (s Shift) overnight() bool {
+	return s.minutes(s.End) < s.minutes(s.Start)
+}
+
+// minutes returns the minutes after midnight of the time of day tod, which
+// Validate made sure parses.
+func (s Shift) minutes(tod string) int {
+	t, _ := time.Parse(TimeLayout, tod)
+	return minutes(t)
+}
+
+func minutes(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
+// Validate reports the first invalid shift, rule or terms of p.
+func (p Policy) Validate() error {
+	names := map[string]bool{}
+	for i, s := range p.Shifts {
+		field := fmt.Sprintf("shifts[%d]", i)
+		switch {
+		case s.Name == "":
+			return invalid(field+".name", "must not be empty")
+		case names[s.Name]:
+			return invalid(field+".name", "must be unique")
+		}
+		names[s.Name] = true
+		for _, d := range s.Days {
+			if _, ok := weekdays[d]; !ok {
+				return invalid(field+".days", "must be mon, tue, wed, thu, fri, sat or sun")
+			}
+		}
+		if len(s.Dates) > 0 && len(s.Days) > 0 {
+			return invalid(field+".dates", "must not be given with days")
+		}
+		for _, d := range s.Dates {
+			if _, err := time.Parse(DateLayout, d); err != nil {
+				return invalid(field+".dates", "must be dates such as 2006-12-31")
+			}
+		}
+		if _, err := time.Parse(TimeLayout, s.Start); err != nil {
+			return invalid(field+".start", "must be a time of day such as 17:30")
+		}
+		if _, err := time.Parse(TimeLayout, s.End); err != nil {
+			return invalid(field+".end", "must be a time of day such as 17:30")
+		}
+		if s.Start == s.End {
+			return invalid(field+".end", "must differ from the start")
+		}
+	}
+
+	rules := map[string]bool{}
+	for i, r := range p.Rules {
+		field := fmt.Sprintf("rules[%d]", i)
+		switch {
+		case r.Name == "":
+			return invalid(field+".name", "must not be empty")
+		case rules[r.Name]:
+			return invalid(field+".name", "must be unique")
+		case r.MinLeadTime < 0:
+			return invalid(field+".minLeadTime", "must not be negative")
+		case r.MaxAdvance < 0:
+			return invalid(field+".maxAdvance", "must not be negative")
+		case r.MaxAdvance > 0 && r.MaxAdvance < r.MinLeadTime:
+			return invalid(field+".maxAdvance", "must not be less than the minimum lead time")
+		case r.MinPartySize < 0:
+			return invalid(field+".minPartySize", "must not be negative")
+		case r.MaxPartySize < 0:
+			return invalid(field+".maxPartySize", "must not be negative")
+		case r.MaxPartySize > 0 && r.MaxPartySize < r.MinPartySize:
+			return invalid(field+".maxPartySize", "must not be less than the minimum party size")
+		case r.MinLeadTime == 0 && r.MaxAdvance == 0 && r.MinPartySize == 0 && r.MaxPartySize == 0:
+			return invalid(field, "must set a limit")
+		}
+		rules[r.Name] = true
+		if err := validateScope(field, r.Channels, r.Shifts, names); err != nil {
+			return err
+		}
+	}
+
+	terms := map[string]bool{}
+	for i, c := range p.Cancellations {
+		field := fmt.Sprintf("cancellations[%d]", i)
+		switch {
+		case c.Name == "":
+			return invalid(field+".name", "must not be empty")
+		case terms[c.Name]:
+			return invalid(field+".name", "must be unique")
+		case c.FreeUntil < 0:
+			return invalid(field+".freeUntil", "must not be negative")
+		case c.FeePerGuest < 0:
+			return invalid(field+".feePerGuest", "must not be negative")
+		}
+		terms[c.Name] = true
+		if err := validateScope(field, c.Channels, c.Shifts, names); err != nil {
+			return err
+		}
+	}
+
+	deposits := map[string]bool{}
+	for i, d := range p.Deposits {
+		field := fmt.Sprintf("deposits[%d]", i)
+		switch {
+		case d.Name == "":
+			return invalid(field+".name", "must not be empty")
+		case deposits[d.Name]:
+			return invalid(field+".name", "must be unique")
+		case d.MinPartySize < 0:
+			return invalid(field+".minPartySize", "must not be negative")
+		case d.AmountPerGuest <= 0:
+			return invalid(field+".amountPerGuest", "must be positive")
+		}
+		deposits[d.Name] = true
+		if err := validateScope(field, d.Channels, d.Shifts, names); err != nil {
+			return err
+		}
+	}
+	if len(p.Deposits) > 0 && len(p.Currency) != 3 {
+		return invalid("currency", "must be a currency code such as eur when deposits are required")
+	}
+	return nil
+}
+
+// validateScope checks the channels and shifts the rule or the terms
+// field applies to, given the names of the shifts defined.
+func validateScope(field string, channels []Channel, shifts []string, names map[string]bool) error {
+	for _, c := range channels {
+		if c != Online && c != Staff {
+			return invalid(field+".channels", "must be online or staff")
+		}
+	}
+	for _, s := range shifts {
+		if !names[s] {
+			return invalid(field+".shifts", fmt.Sprintf("names unknown shift %q", s))
+		}
+	}
+	return nil
+}
+
+func invalid(field, msg string) error {
+	return errors.ValidationError.Newf("invalid policy: %s %s", field, msg).
+		AddContext("policy."+field, msg)
+}
+
+// duration formats d in days when whole, and without trailing zero units
+// otherwise.
+func duration(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		if d == 24*time.Hour {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", d/(24*time.Hour))
+	}
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
+
+func containsChannel(cc []Channel, c Channel) bool {
+	for _, e := range cc {
+		if e == c {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,279 @@
+package policy
+
+import (
+	errors "reservations/pkg/error"
+	"testing"
+	"time"
+)
+
+// 2026-12-31 is a Thursday, the first of the dates below.
+var testShifts = []Shift{
+	{Name: "lunch", Start: "12:00", End: "15:00"},
+	{Name: "late", Days: []string{"fri", "sat"}, Start: "22:00", End: "02:00"},
+	{Name: "gala", Dates: []string{"2026-12-31"}, Start: "18:00", End: "23:00"},
+	{Name: "thursday", Days: []string{"thu"}, Start: "18:00", End: "23:00"},
+}
+
+func at(value string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func shift(name string) Shift {
+	for _, s := range testShifts {
+		if s.Name == name {
+			return s
+		}
+	}
+	panic("unknown shift " + name)
+}
+
+func TestShiftContains(t *testing.T) {
+	for _, tc := range []struct {
+		shift string
+		start string
+		want  bool
+	}{
+		{"lunch", "2026-12-24 12:00", true},
+		{"lunch", "2026-12-24 14:59", true},
+		{"lunch", "2026-12-24 15:00", false},
+		{"lunch", "2026-12-24 11:59", false},
+		// The early hours belong to the shift of the day before.
+		{"late", "2026-12-25 23:00", true},
+		{"late", "2026-12-26 01:30", true},
+		{"late", "2026-12-26 02:00", false},
+		{"late", "2026-12-27 01:00", true},
+		{"late", "2026-12-25 01:30", false},
+		{"late", "2026-12-28 01:00", false},
+		{"late", "2026-12-24 23:00", false},
+		{"gala", "2026-12-31 19:00", true},
+		{"gala", "2026-12-24 19:00", false},
+		{"thursday", "2026-12-24 19:00", true},
+		{"thursday", "2026-12-31 19:00", true},
+		{"thursday", "2026-12-25 19:00", false},
+	} {
+		if got := shift(tc.shift).contains(at(tc.start)); got != tc.want {
+			t.Errorf("shift %s contains %s = %v, want %v", tc.shift, tc.start, got, tc.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	p := Policy{
+		Shifts: testShifts,
+		Rules: []Rule{
+			{Name: "window", Channels: []Channel{Online}, MinLeadTime: 2 * time.Hour, MaxAdvance: 30 * 24 * time.Hour},
+			{Name: "late", Shifts: []string{"late"}, MaxPartySize: 4, Message: "parties over 4 must call"},
+			{Name: "party", MinPartySize: 1, MaxPartySize: 10},
+		},
+	}
+	now := at("2026-12-24 10:00")
+
+	for _, tc := range []struct {
+		name    string
+		booking Booking
+		rule    string
+		message string
+	}{
+		{
+			name:    "online in time",
+			booking: Booking{Channel: Online, Start: at("2026-12-24 13:00"), SeatCount: 2, Now: now},
+		},
+		{
+			name:    "online too late",
+			booking: Booking{Channel: Online, Start: at("2026-12-24 11:00"), SeatCount: 2, Now: now},
+			rule:    "window",
+			message: "must be booked at least 2h ahead",
+		},
+		{
+			name:    "online too early",
+			booking: Booking{Channel: Online, Start: at("2027-01-24 13:00"), SeatCount: 2, Now: now},
+			rule:    "window",
+			message: "must be booked at most 30 days ahead",
+		},
+		{
+			name:    "staff without window",
+			booking: Booking{Channel: Staff, Start: at("2026-12-24 11:00"), SeatCount: 2, Now: now},
+		},
+		{
+			name:    "edit keeping the start",
+			booking: Booking{Channel: Online, Start: at("2026-12-24 11:00"), PreviousStart: at("2026-12-24 11:00"), SeatCount: 3, Now: now},
+		},
+		{
+			name:    "edit moving the start",
+			booking: Booking{Channel: Online, Start: at("2026-12-24 11:30"), PreviousStart: at("2026-12-24 11:00"), SeatCount: 2, Now: now},
+			rule:    "window",
+			message: "must be booked at least 2h ahead",
+		},
+		{
+			name:    "large party late",
+			booking: Booking{Channel: Staff, Start: at("2026-12-26 01:00"), SeatCount: 6, Now: now},
+			rule:    "late",
+			message: "parties over 4 must call",
+		},
+		{
+			name:    "large party at lunch",
+			booking: Booking{Channel: Staff, Start: at("2026-12-26 13:00"), SeatCount: 6, Now: now},
+		},
+		{
+			name:    "too large a party",
+			booking: Booking{Channel: Staff, Start: at("2026-12-26 13:00"), SeatCount: 12, Now: now},
+			rule:    "party",
+			message: "must be for at most 10 guests",
+		},
+	} {
+		err := p.Check(tc.booking)
+		if tc.rule == "" {
+			if err != nil {
+				t.Errorf("%s: Check = %v, want no violation", tc.name, err)
+			}
+			continue
+		}
+		ctx := errors.GetErrorContext(err)
+		if errors.GetType(err) != errors.ValidationError || ctx["field"] != tc.rule || ctx["message"] != tc.message {
+			t.Errorf("%s: Check = %v, %v, want a violation of %s: %s", tc.name, err, ctx, tc.rule, tc.message)
+		}
+	}
+}
+
+func TestFee(t *testing.T) {
+	p := Policy{
+		Shifts: testShifts,
+		Cancellations: []Cancellation{
+			{Name: "gala", Shifts: []string{"gala"}, NonRefundable: true, FeePerGuest: 5000},
+			{Name: "thursday", Shifts: []string{"thursday"}, FreeUntil: 24 * time.Hour, FeePerGuest: 1000},
+			{Name: "online", Channels: []Channel{Online}, FreeUntil: 48 * time.Hour, FeePerGuest: 500},
+		},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		booking Booking
+		terms   string
+		fee     int64
+	}{
+		{
+			name:    "gala, weeks ahead",
+			booking: Booking{Channel: Staff, Start: at("2026-12-31 19:00"), Now: at("2026-12-01 10:00")},
+			terms:   "gala",
+			fee:     15000,
+		},
+		{
+			name:    "thursday in time",
+			booking: Booking{Channel: Staff, Start: at("2026-12-24 19:00"), Now: at("2026-12-22 19:00")},
+			terms:   "thursday",
+		},
+		{
+			name:    "thursday late",
+			booking: Booking{Channel: Staff, Start: at("2026-12-24 19:00"), Now: at("2026-12-24 07:00")},
+			terms:   "thursday",
+			fee:     3000,
+		},
+		{
+			name:    "online lunch late",
+			booking: Booking{Channel: Online, Start: at("2026-12-24 13:00"), Now: at("2026-12-23 13:00")},
+			terms:   "online",
+			fee:     1500,
+		},
+		{
+			name:    "staff lunch",
+			booking: Booking{Channel: Staff, Start: at("2026-12-24 13:00"), Now: at("2026-12-24 12:00")},
+		},
+	} {
+		terms, fee := p.Fee(tc.booking, 3)
+		if terms != tc.terms || fee != tc.fee {
+			t.Errorf("%s: Fee = %s, %d, want %s, %d", tc.name, terms, fee, tc.terms, tc.fee)
+		}
+	}
+}
+
+func TestDeposit(t *testing.T) {
+	p := Policy{
+		Currency: "eur",
+		Shifts:   testShifts,
+		Deposits: []Deposit{
+			{Name: "online groups", Channels: []Channel{Online}, MinPartySize: 4, AmountPerGuest: 1000},
+			{Name: "groups", MinPartySize: 8, AmountPerGuest: 500},
+		},
+	}
+	start := at("2026-12-24 13:00")
+
+	for _, tc := range []struct {
+		channel Channel
+		seats   int
+		terms   string
+		amount  int64
+	}{
+		{Online, 2, "", 0},
+		{Online, 4, "online groups", 4000},
+		{Online, 8, "online groups", 8000},
+		{Staff, 4, "", 0},
+		{Staff, 8, "groups", 4000},
+	} {
+		terms, amount := p.Deposit(Booking{Channel: tc.channel, Start: start, SeatCount: tc.seats})
+		if terms != tc.terms || amount != tc.amount {
+			t.Errorf("Deposit for %d guests booked %s = %s, %d, want %s, %d", tc.seats, tc.channel, terms, amount, tc.terms, tc.amount)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() Policy {
+		return Policy{
+			Currency:      "eur",
+			Shifts:        []Shift{{Name: "late", Days: []string{"fri"}, Start: "22:00", End: "02:00"}},
+			Rules:         []Rule{{Name: "party", Channels: []Channel{Online}, Shifts: []string{"late"}, MaxPartySize: 8}},
+			Cancellations: []Cancellation{{Name: "late", FreeUntil: time.Hour, FeePerGuest: 500}},
+			Deposits:      []Deposit{{Name: "groups", MinPartySize: 6, AmountPerGuest: 1000}},
+		}
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("Validate = %v, want the policy valid", err)
+	}
+
+	for _, tc := range []struct {
+		field  string
+		change func(p *Policy)
+	}{
+		{"shifts[0].name", func(p *Policy) { p.Shifts[0].Name = "" }},
+		{"shifts[1].name", func(p *Policy) { p.Shifts = append(p.Shifts, p.Shifts[0]) }},
+		{"shifts[0].days", func(p *Policy) { p.Shifts[0].Days = []string{"friday"} }},
+		{"shifts[0].dates", func(p *Policy) { p.Shifts[0].Dates = []string{"2026-12-31"} }},
+		{"shifts[0].dates", func(p *Policy) { p.Shifts[0].Days, p.Shifts[0].Dates = nil, []string{"31.12.2026"} }},
+		{"shifts[0].start", func(p *Policy) { p.Shifts[0].Start = "10pm" }},
+		{"shifts[0].end", func(p *Policy) { p.Shifts[0].End = "24:00" }},
+		{"shifts[0].end", func(p *Policy) { p.Shifts[0].End = "22:00" }},
+		{"rules[0].name", func(p *Policy) { p.Rules[0].Name = "" }},
+		{"rules[1].name", func(p *Policy) { p.Rules = append(p.Rules, p.Rules[0]) }},
+		{"rules[0].minLeadTime", func(p *Policy) { p.Rules[0].MinLeadTime = -time.Hour }},
+		{"rules[0].maxAdvance", func(p *Policy) { p.Rules[0].MaxAdvance = -time.Hour }},
+		{"rules[0].maxAdvance", func(p *Policy) { p.Rules[0].MinLeadTime, p.Rules[0].MaxAdvance = 2*time.Hour, time.Hour }},
+		{"rules[0].minPartySize", func(p *Policy) { p.Rules[0].MinPartySize = -1 }},
+		{"rules[0].maxPartySize", func(p *Policy) { p.Rules[0].MaxPartySize = -1 }},
+		{"rules[0].maxPartySize", func(p *Policy) { p.Rules[0].MinPartySize = 10 }},
+		{"rules[0]", func(p *Policy) { p.Rules[0].MaxPartySize = 0 }},
+		{"rules[0].channels", func(p *Policy) { p.Rules[0].Channels = []Channel{"phone"} }},
+		{"rules[0].shifts", func(p *Policy) { p.Rules[0].Shifts = []string{"brunch"} }},
+		{"cancellations[0].name", func(p *Policy) { p.Cancellations[0].Name = "" }},
+		{"cancellations[1].name", func(p *Policy) { p.Cancellations = append(p.Cancellations, p.Cancellations[0]) }},
+		{"cancellations[0].freeUntil", func(p *Policy) { p.Cancellations[0].FreeUntil = -time.Hour }},
+		{"cancellations[0].feePerGuest", func(p *Policy) { p.Cancellations[0].FeePerGuest = -1 }},
+		{"cancellations[0].shifts", func(p *Policy) { p.Cancellations[0].Shifts = []string{"brunch"} }},
+		{"deposits[0].name", func(p *Policy) { p.Deposits[0].Name = "" }},
+		{"deposits[1].name", func(p *Policy) { p.Deposits = append(p.Deposits, p.Deposits[0]) }},
+		{"deposits[0].minPartySize", func(p *Policy) { p.Deposits[0].MinPartySize = -1 }},
+		{"deposits[0].amountPerGuest", func(p *Policy) { p.Deposits[0].AmountPerGuest = 0 }},
+		{"deposits[0].channels", func(p *Policy) { p.Deposits[0].Channels = []Channel{"phone"} }},
+		{"currency", func(p *Policy) { p.Currency = "" }},
+	} {
+		p := valid()
+		tc.change(&p)
+		err := p.Validate()
+		if errors.GetType(err) != errors.ValidationError || errors.GetErrorContext(err)["field"] != "policy."+tc.field {
+			t.Errorf("Validate of a policy with an invalid %s = %v", tc.field, err)
+		}
+	}
+}
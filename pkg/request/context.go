@@ -0,0 +1,50 @@
+package request
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+type contextKey int
+
+const (
+	requestIDContextKey contextKey = iota
+	actorContextKey
+)
+
+// AnonymousActor is reported for calls that carry no caller identity.
+const AnonymousActor = "anonymous"
+
+// WithID returns a copy of ctx carrying the given request ID.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, id)
+}
+
+// IDFromContext returns the request ID stored in ctx, if any.
+func IDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+// WithActor returns a copy of ctx carrying the identity of the caller.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey, actor)
+}
+
+// ActorFromContext returns the identity of the caller, or AnonymousActor.
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorContextKey).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+// NewID generates a random request ID.
+func NewID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,176 @@
+// Package client provides a reservation.Service implementation that calls a
+// remote reservation service over HTTP.
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-kit/kit/endpoint"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"net/http"
+	"net/url"
+	"reservations/pkg/reservation"
+	"reservations/pkg/storage"
+	"reservations/pkg/transport"
+	"strings"
+)
+
+type client struct {
+	bookReservation                  endpoint.Endpoint
+	discardReservation               endpoint.Endpoint
+	editReservation                  endpoint.Endpoint
+	getReservationByID               endpoint.Endpoint
+	getReservationHistoryPerCustomer endpoint.Endpoint
+}
+
+// New returns a reservation.Service backed by the HTTP API at instance, e.g.
+// "http://localhost:8080". Errors returned by the server are decoded into
+// errors.AppError values carrying the original error type.
+func New(instance string, options ...httptransport.ClientOption) (reservation.Service, error) {
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	u, err := url.Parse(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	options = append(httpjson.DefaultClientOptions(), options...)
+
+	return &client{
+		bookReservation: httptransport.NewClient(
+			"POST", u,
+			encodeBookReservationRequest,
+			decodeReservationPtrResponse,
+			options...,
+		).Endpoint(),
+		discardReservation: httptransport.NewClient(
+			"DELETE", u,
+			encodeReservationIDRequest,
+			decodeDiscardReservationResponse,
+			options...,
+		).Endpoint(),
+		editReservation: httptransport.NewClient(
+			"PUT", u,
+			encodeEditReservationRequest,
+			decodeReservationResponse,
+			options...,
+		).Endpoint(),
+		getReservationByID: httptransport.NewClient(
+			"GET", u,
+			encodeReservationIDRequest,
+			decodeReservationResponse,
+			options...,
+		).Endpoint(),
+		getReservationHistoryPerCustomer: httptransport.NewClient(
+			"GET", u,
+			encodeGetReservationHistoryPerCustomerRequest,
+			decodeGetReservationHistoryPerCustomerResponse,
+			options...,
+		).Endpoint(),
+	}, nil
+}
+
+type bookReservationRequest struct {
+	CustomerID  int
+	Reservation *reservation.Reservation
+}
+
+type editReservationRequest struct {
+	ReservationID int
+	Reservation   *reservation.Reservation
+}
+
+type getReservationHistoryPerCustomerRequest struct {
+	CustomerID int
+	Opts       *storage.QueryOptions
+}
+
+func (c *client) BookReservation(ctx context.Context, cID int, r *reservation.Reservation) (*reservation.Reservation, error) {
+	resp, err := c.bookReservation(ctx, bookReservationRequest{CustomerID: cID, Reservation: r})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*reservation.Reservation), nil
+}
+
+func (c *client) DiscardReservation(ctx context.Context, rID int) error {
+	_, err := c.discardReservation(ctx, rID)
+	return err
+}
+
+func (c *client) EditReservation(ctx context.Context, rID int, r *reservation.Reservation) (reservation.Reservation, error) {
+	resp, err := c.editReservation(ctx, editReservationRequest{ReservationID: rID, Reservation: r})
+	if err != nil {
+		return reservation.Reservation{}, err
+	}
+	return resp.(reservation.Reservation), nil
+}
+
+func (c *client) GetReservationByID(ctx context.Context, rID int) (reservation.Reservation, error) {
+	resp, err := c.getReservationByID(ctx, rID)
+	if err != nil {
+		return reservation.Reservation{}, err
+	}
+	return resp.(reservation.Reservation), nil
+}
+
+func (c *client) GetReservationHistoryPerCustomer(ctx context.Context, cID int, opts *storage.QueryOptions) ([]reservation.Reservation, error) {
+	resp, err := c.getReservationHistoryPerCustomer(ctx, getReservationHistoryPerCustomerRequest{CustomerID: cID, Opts: opts})
+	if err != nil {
+		return nil, err
+	}
+	return resp.([]reservation.Reservation), nil
+}
+
+func encodeBookReservationRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	req := request.(bookReservationRequest)
+	r.URL.Path = fmt.Sprintf("/customer/%d/reservation", req.CustomerID)
+	return httptransport.EncodeJSONRequest(ctx, r, req.Reservation)
+}
+
+func encodeEditReservationRequest(ctx context.Context, r *http.Request, request interface{}) error {
+	req := request.(editReservationRequest)
+	r.URL.Path = fmt.Sprintf("/reservation/%d", req.ReservationID)
+	return httptransport.EncodeJSONRequest(ctx, r, req.Reservation)
+}
+
+func encodeReservationIDRequest(_ context.Context, r *http.Request, request interface{}) error {
+	r.URL.Path = fmt.Sprintf("/reservation/%d", request.(int))
+	return nil
+}
+
+func encodeGetReservationHistoryPerCustomerRequest(_ context.Context, r *http.Request, request interface{}) error {
+	req := request.(getReservationHistoryPerCustomerRequest)
+	r.URL.Path = fmt.Sprintf("/customer/%d/reservations", req.CustomerID)
+	r.URL.RawQuery = httpjson.EncodeQueryOptions(req.Opts).Encode()
+	return nil
+}
+
+func decodeReservationPtrResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var resp struct {
+		Reservation *reservation.Reservation `json:"reservation"`
+	}
+	err := httpjson.DecodeResponse(r, &resp)
+	return resp.Reservation, err
+}
+
+func decodeReservationResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var resp struct {
+		Reservation reservation.Reservation `json:"reservation"`
+	}
+	err := httpjson.DecodeResponse(r, &resp)
+	return resp.Reservation, err
+}
+
+func decodeDiscardReservationResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	return nil, httpjson.DecodeResponse(r, nil)
+}
+
+func decodeGetReservationHistoryPerCustomerResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	var resp struct {
+		Reservations []reservation.Reservation `json:"reservations"`
+	}
+	err := httpjson.DecodeResponse(r, &resp)
+	return resp.Reservations, err
+}
@@ -3,6 +3,7 @@ package reservation
 import (
 	"context"
 	"github.com/go-kit/kit/endpoint"
+	"reservations/pkg/authz"
 	"reservations/pkg/storage"
 )
 
@@ -10,15 +11,54 @@ type Endpoints struct {
 	BookReservationEndpoint                 endpoint.Endpoint
 	DiscardReservationEndpoint              endpoint.Endpoint
 	EditReservationEndpoint                 endpoint.Endpoint
+	GetReservationByIDEndpoint              endpoint.Endpoint
 	GetReservationHistoryByCustomerEndpoint endpoint.Endpoint
 }
 
-func MakeServerEndpoints(s Service) Endpoints {
-	return Endpoints{
-		BookReservationEndpoint:                 MakeBookReservationEndpoint(s),
-		DiscardReservationEndpoint:              MakeDiscardReservationEndpoint(s),
-		EditReservationEndpoint:                 MakeEditReservationEndpoint(s),
-		GetReservationHistoryByCustomerEndpoint: MakeGetReservationHistoryPerCustomerEndpoint(s),
+// MakeServerEndpoints returns the service endpoints, each checked against
+// the policy by az and wrapped in the given middlewares, the first one
+// outermost.
+func MakeServerEndpoints(s Service, az authz.Authorizer, mw ...endpoint.Middleware) Endpoints {
+	owner := reservationOwner(s)
+	e := Endpoints{
+		BookReservationEndpoint:                 az.Require(authz.PermReservationWrite, owner)(MakeBookReservationEndpoint(s)),
+		DiscardReservationEndpoint:              az.Require(authz.PermReservationWrite, owner)(MakeDiscardReservationEndpoint(s)),
+		EditReservationEndpoint:                 az.Require(authz.PermReservationWrite, owner)(MakeEditReservationEndpoint(s)),
+		GetReservationByIDEndpoint:              az.Require(authz.PermReservationRead, owner)(MakeGetReservationByIDEndpoint(s)),
+		GetReservationHistoryByCustomerEndpoint: az.Require(authz.PermReservationRead, owner)(MakeGetReservationHistoryPerCustomerEndpoint(s)),
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		m := mw[i]
+		e.BookReservationEndpoint = m(e.BookReservationEndpoint)
+		e.DiscardReservationEndpoint = m(e.DiscardReservationEndpoint)
+		e.EditReservationEndpoint = m(e.EditReservationEndpoint)
+		e.GetReservationByIDEndpoint = m(e.GetReservationByIDEndpoint)
+		e.GetReservationHistoryByCustomerEndpoint = m(e.GetReservationHistoryByCustomerEndpoint)
+	}
+	return e
+}
+
+// reservationOwner returns the customer a request addresses, looking up the
+// reservation for requests naming one by ID.
+func reservationOwner(s Service) authz.OwnerFunc {
+	return func(ctx context.Context, request interface{}) (int, error) {
+		var rID int
+		switch req := request.(type) {
+		case bookReservationRequest:
+			return req.CustomerID, nil
+		case getReservationHistoryPerCustomerRequest:
+			return req.CustomerID, nil
+		case discardReservationRequest:
+			rID = req.ReservationID
+		case editReservationRequest:
+			rID = req.ReservationID
+		case getReservationByIDRequest:
+			rID = req.ReservationID
+		default:
+			return 0, nil
+		}
+		r, err := s.GetReservationByID(ctx, rID)
+		return r.CustomerID, err
 	}
 }
 
@@ -134,7 +174,7 @@ func (r editReservationResponse) HTTPError() error { return r.Err }
 
 // EditReservation godoc
 // @Summary Edit an existing reservation
-// @Description Edit an existing reservation
+// @Description Edit an existing reservation. An edit requiring a deposit, or a larger one, raises the deposit while unpaid, and is refused once a smaller one is paid.
 // @Tags reservation
 // @Param id path string true "Reservation ID"
 // @Accept  json
@@ -150,3 +190,34 @@ func MakeEditReservationEndpoint(s Service) endpoint.Endpoint {
 		}, nil
 	}
 }
+
+type getReservationByIDRequest struct {
+	ReservationID int
+}
+
+type getReservationByIDResponse struct {
+	Reservation Reservation `json:"reservation"`
+	Err         error       `json:"err,omitempty"`
+}
+
+func (r getReservationByIDResponse) HTTPError() error { return r.Err }
+
+// GetReservationByID godoc
+// @Summary Get an existing reservation
+// @Description Get an existing reservation
+// @Tags reservation
+// @Param id path string true "Reservation ID"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} reservation.Reservation
+// @Router /reservation/{id} [get]
+func MakeGetReservationByIDEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getReservationByIDRequest)
+		r, e := s.GetReservationByID(ctx, req.ReservationID)
+		return getReservationByIDResponse{
+			Reservation: r,
+			Err:         e,
+		}, nil
+	}
+}
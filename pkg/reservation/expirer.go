@@ -0,0 +1,67 @@
+package reservation
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"reservations/pkg/payment"
+	"reservations/pkg/tenant"
+	"time"
+)
+
+// DepositExpirer cancels the reservations whose deposit was not paid in
+// time, freeing their tables.
+type DepositExpirer struct {
+	deposits payment.Repository
+	repo     Repository
+	interval time.Duration
+	logger   log.Logger
+}
+
+func NewDepositExpirer(deposits payment.Repository, repo Repository, interval time.Duration, logger log.Logger) *DepositExpirer {
+	return &DepositExpirer{
+		deposits: deposits,
+		repo:     repo,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run cancels the reservations with overdue deposits until ctx is
+// cancelled.
+func (e *DepositExpirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.expire(ctx); err != nil {
+			e.logger.Log("component", "deposits", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *DepositExpirer) expire(ctx context.Context) error {
+	now := time.Now().Unix()
+	dd, err := e.deposits.FindOverdueDeposits(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range dd {
+		// A reservation failing does not hold the others back.
+		expired, err := e.repo.ExpireReservation(tenant.WithVenue(ctx, d.VenueID), d, now)
+		if err != nil {
+			e.logger.Log("component", "deposits", "reservation", d.ReservationID, "err", err)
+			continue
+		}
+		if expired {
+			e.logger.Log("component", "deposits", "reservation", d.ReservationID, "expired", d.PayBy)
+		}
+	}
+	return nil
+}
@@ -2,8 +2,13 @@ package reservation
 
 import (
 	"context"
+	"fmt"
 	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	errors "reservations/pkg/error"
+	"reservations/pkg/event"
 	"reservations/pkg/storage"
+	"reservations/pkg/tracing"
 	"time"
 )
 
@@ -45,9 +50,168 @@ func (mw loggingMiddleware) EditReservation(ctx context.Context, rID int, res *R
 	return mw.next.EditReservation(ctx, rID, res)
 }
 
+func (mw loggingMiddleware) GetReservationByID(ctx context.Context, rID int) (r Reservation, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "GetReservationByID", "id", rID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetReservationByID(ctx, rID)
+}
+
 func (mw loggingMiddleware) GetReservationHistoryPerCustomer(ctx context.Context, cID int, opts *storage.QueryOptions) (result []Reservation, err error) {
 	defer func(begin time.Time) {
 		mw.logger.Log("method", "GetReservationHistoryPerCustomer", "id", cID, "took", time.Since(begin), "err", err)
 	}(time.Now())
 	return mw.next.GetReservationHistoryPerCustomer(ctx, cID, opts)
 }
+
+// Notifier is told about every change made to a reservation through the
+// service once it is committed, e.g. to push it to connected clients.
+type Notifier interface {
+	ReservationChanged(ctx context.Context, t event.Type, res Reservation)
+}
+
+// NotifyingMiddleware tells n about every successful change to a reservation.
+func NotifyingMiddleware(n Notifier) Middleware {
+	return func(next Service) Service {
+		return &notifyingMiddleware{
+			next:     next,
+			notifier: n,
+		}
+	}
+}
+
+type notifyingMiddleware struct {
+	next     Service
+	notifier Notifier
+}
+
+func (mw notifyingMiddleware) BookReservation(ctx context.Context, cID int, r *Reservation) (*Reservation, error) {
+	result, err := mw.next.BookReservation(ctx, cID, r)
+	if err == nil {
+		mw.notifier.ReservationChanged(ctx, event.ReservationBooked, *result)
+	}
+	return result, err
+}
+
+func (mw notifyingMiddleware) DiscardReservation(ctx context.Context, rID int) error {
+	before, err := mw.next.GetReservationByID(ctx, rID)
+	if err != nil {
+		return err
+	}
+	err = mw.next.DiscardReservation(ctx, rID)
+	if err == nil {
+		mw.notifier.ReservationChanged(ctx, event.ReservationCancelled, before)
+	}
+	return err
+}
+
+func (mw notifyingMiddleware) EditReservation(ctx context.Context, rID int, res *Reservation) (Reservation, error) {
+	result, err := mw.next.EditReservation(ctx, rID, res)
+	if err == nil {
+		mw.notifier.ReservationChanged(ctx, event.ReservationEdited, result)
+	}
+	return result, err
+}
+
+func (mw notifyingMiddleware) GetReservationByID(ctx context.Context, rID int) (Reservation, error) {
+	return mw.next.GetReservationByID(ctx, rID)
+}
+
+func (mw notifyingMiddleware) GetReservationHistoryPerCustomer(ctx context.Context, cID int, opts *storage.QueryOptions) ([]Reservation, error) {
+	return mw.next.GetReservationHistoryPerCustomer(ctx, cID, opts)
+}
+
+// InstrumentingMiddleware records the count and latency of every call and
+// counts failed calls by error type.
+func InstrumentingMiddleware(requestCount metrics.Counter, requestLatency metrics.Histogram, errorCount metrics.Counter) Middleware {
+	return func(next Service) Service {
+		return &instrumentingMiddleware{
+			next:           next,
+			requestCount:   requestCount,
+			requestLatency: requestLatency,
+			errorCount:     errorCount,
+		}
+	}
+}
+
+type instrumentingMiddleware struct {
+	next           Service
+	requestCount   metrics.Counter
+	requestLatency metrics.Histogram
+	errorCount     metrics.Counter
+}
+
+func (mw instrumentingMiddleware) observe(method string, begin time.Time, err error) {
+	lvs := []string{"method", method, "error", fmt.Sprint(err != nil)}
+	mw.requestCount.With(lvs...).Add(1)
+	mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	if err != nil {
+		mw.errorCount.With("method", method, "type", errors.GetType(err).String()).Add(1)
+	}
+}
+
+func (mw instrumentingMiddleware) BookReservation(ctx context.Context, cID int, r *Reservation) (result *Reservation, err error) {
+	defer func(begin time.Time) { mw.observe("BookReservation", begin, err) }(time.Now())
+	return mw.next.BookReservation(ctx, cID, r)
+}
+
+func (mw instrumentingMiddleware) DiscardReservation(ctx context.Context, rID int) (err error) {
+	defer func(begin time.Time) { mw.observe("DiscardReservation", begin, err) }(time.Now())
+	return mw.next.DiscardReservation(ctx, rID)
+}
+
+func (mw instrumentingMiddleware) EditReservation(ctx context.Context, rID int, res *Reservation) (r Reservation, err error) {
+	defer func(begin time.Time) { mw.observe("EditReservation", begin, err) }(time.Now())
+	return mw.next.EditReservation(ctx, rID, res)
+}
+
+func (mw instrumentingMiddleware) GetReservationByID(ctx context.Context, rID int) (r Reservation, err error) {
+	defer func(begin time.Time) { mw.observe("GetReservationByID", begin, err) }(time.Now())
+	return mw.next.GetReservationByID(ctx, rID)
+}
+
+func (mw instrumentingMiddleware) GetReservationHistoryPerCustomer(ctx context.Context, cID int, opts *storage.QueryOptions) (result []Reservation, err error) {
+	defer func(begin time.Time) { mw.observe("GetReservationHistoryPerCustomer", begin, err) }(time.Now())
+	return mw.next.GetReservationHistoryPerCustomer(ctx, cID, opts)
+}
+
+// TracingMiddleware wraps every call in a span.
+func TracingMiddleware() Middleware {
+	return func(next Service) Service {
+		return &tracingMiddleware{next: next}
+	}
+}
+
+type tracingMiddleware struct {
+	next Service
+}
+
+func (mw tracingMiddleware) BookReservation(ctx context.Context, cID int, r *Reservation) (result *Reservation, err error) {
+	ctx, span := tracing.StartSpan(ctx, "reservation.BookReservation")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.BookReservation(ctx, cID, r)
+}
+
+func (mw tracingMiddleware) DiscardReservation(ctx context.Context, rID int) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "reservation.DiscardReservation")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.DiscardReservation(ctx, rID)
+}
+
+func (mw tracingMiddleware) EditReservation(ctx context.Context, rID int, res *Reservation) (r Reservation, err error) {
+	ctx, span := tracing.StartSpan(ctx, "reservation.EditReservation")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.EditReservation(ctx, rID, res)
+}
+
+func (mw tracingMiddleware) GetReservationByID(ctx context.Context, rID int) (r Reservation, err error) {
+	ctx, span := tracing.StartSpan(ctx, "reservation.GetReservationByID")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.GetReservationByID(ctx, rID)
+}
+
+func (mw tracingMiddleware) GetReservationHistoryPerCustomer(ctx context.Context, cID int, opts *storage.QueryOptions) (result []Reservation, err error) {
+	ctx, span := tracing.StartSpan(ctx, "reservation.GetReservationHistoryPerCustomer")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.GetReservationHistoryPerCustomer(ctx, cID, opts)
+}
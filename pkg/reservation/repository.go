@@ -1,18 +1,43 @@
 package reservation
 
 import (
+	"context"
 	"github.com/doug-martin/goqu/v7"
-	"github.com/doug-martin/goqu/v7/exec"
+	"reservations/pkg/audit"
 	errors "reservations/pkg/error"
+	"reservations/pkg/event"
+	"reservations/pkg/fee"
+	"reservations/pkg/outbox"
+	"reservations/pkg/payment"
+	"reservations/pkg/seating"
 	"reservations/pkg/storage"
+	"reservations/pkg/tenant"
+	"reservations/pkg/tracing"
 	"time"
 )
 
 type Repository interface {
-	AddReservation(cID int, r *Reservation) (*Reservation, error)
-	RemoveReservation(rID int) error
-	UpdateReservation(rID int, r *Reservation) (Reservation, error)
-	FindReservationsByCustomerID(cID int, opts *storage.QueryOptions) ([]Reservation, error)
+	// AddReservation books r for the customer cID, requiring the deposit d
+	// unless nil.
+	AddReservation(ctx context.Context, cID int, r *Reservation, d *payment.Deposit) (*Reservation, error)
+	// RemoveReservation deletes the reservation rID, charging f unless nil.
+	RemoveReservation(ctx context.Context, rID int, f *fee.Fee) error
+	// UpdateReservation stores the changes in r to the reservation rID,
+	// charging f unless nil and requiring the deposit d unless nil.
+	UpdateReservation(ctx context.Context, rID int, r *Reservation, f *fee.Fee, d *payment.Deposit) (Reservation, error)
+	FindReservationByID(ctx context.Context, rID int) (Reservation, error)
+	FindReservationsByCustomerID(ctx context.Context, cID int, opts *storage.QueryOptions) ([]Reservation, error)
+	CountBookingsSince(ctx context.Context, since int64) (BookingStats, error)
+	// ExpireReservation cancels the reservation held by the deposit d,
+	// reporting whether d was still unpaid past its due time at now.
+	// Parties already seated are kept, with their deposit no longer due.
+	ExpireReservation(ctx context.Context, d payment.Deposit, now int64) (bool, error)
+}
+
+// BookingStats summarizes the reservations booked in a period.
+type BookingStats struct {
+	Bookings int `db:"bookings"`
+	Seats    int `db:"seats"`
 }
 
 type reservationRepository struct {
@@ -23,29 +48,52 @@ func NewReservationRepository(db storage.Persistence) Repository {
 	return &reservationRepository{db: db}
 }
 
-func (r *reservationRepository) AddReservation(cID int, res *Reservation) (*Reservation, error) {
+func (r *reservationRepository) AddReservation(ctx context.Context, cID int, res *Reservation, d *payment.Deposit) (_ *Reservation, err error) {
+	ctx, span := tracing.StartSpan(ctx, "reservationRepository.AddReservation", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
 	created := time.Now().Unix()
+	vID := tenant.VenueFromContext(ctx)
 
-	result, err := r.db.Tx(func(tx *goqu.TxDatabase) exec.QueryExecutor {
-		res.Created = created
-		res.LastUpdated = created
-		res.CustomerID = cID
-		return tx.From("reservation").Insert(res)
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		n, err := tx.From("customer").Where(goqu.Ex{"cid": cID, "venue_id": vID}).Count()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return errors.NotFound.Newf("customer with ID %d not found", cID).
+				AddContext("CustomerID", "non existent ID")
+		}
+
+		if err := Insert(ctx, tx, vID, cID, res, created); err != nil {
+			return err
+		}
+		return InsertDeposit(tx, *res, d, created)
 	})
 
+	if t := errors.GetType(err); t == errors.NotFound || t == errors.Conflict || t == errors.ValidationError {
+		return nil, err
+	}
 	if err != nil {
 		return nil, errors.DBError.Wrap(err, "error adding new reservation")
 	}
 
-	rID, _ := result.LastInsertId()
-	res.ReservationID = int(rID)
-
 	return res, nil
 }
 
-func (r *reservationRepository) RemoveReservation(rID int) error {
-	_, err := r.db.Tx(func(tx *goqu.TxDatabase) exec.QueryExecutor {
-		return tx.From("reservation").Where(goqu.Ex{"rid": rID}).Delete()
+func (r *reservationRepository) RemoveReservation(ctx context.Context, rID int, f *fee.Fee) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "reservationRepository.RemoveReservation", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	where := goqu.Ex{"rid": rID, "venue_id": tenant.VenueFromContext(ctx)}
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		var res Reservation
+		found, err := tx.From("reservation").Where(where).ScanStruct(&res)
+		if err != nil || !found {
+			return err
+		}
+		return Cancel(ctx, tx, res, f, time.Now().Unix())
 	})
 
 	if err != nil {
@@ -54,26 +102,282 @@ func (r *reservationRepository) RemoveReservation(rID int) error {
 	return nil
 }
 
-func (r *reservationRepository) UpdateReservation(rID int, res *Reservation) (result Reservation, err error) {
+func (r *reservationRepository) UpdateReservation(ctx context.Context, rID int, res *Reservation, f *fee.Fee, d *payment.Deposit) (result Reservation, err error) {
+	ctx, span := tracing.StartSpan(ctx, "reservationRepository.UpdateReservation", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
 	lastUpdated := time.Now().Unix()
+	vID := tenant.VenueFromContext(ctx)
 
-	_, err = r.db.Tx(func(tx *goqu.TxDatabase) exec.QueryExecutor {
-		res.LastUpdated = lastUpdated
-		return tx.From("reservation").Update(res)
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		if f != nil {
+			var prev Reservation
+			found, err := tx.From("reservation").Where(goqu.Ex{"rid": rID, "venue_id": vID}).ScanStruct(&prev)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return errors.NotFound.Newf("reservation with ID %d not found", rID).
+					AddContext("ReservationID", "non existent ID")
+			}
+			if err := InsertFee(tx, prev, f); err != nil {
+				return err
+			}
+		}
+
+		res.ReservationID = rID
+		var err error
+		result, err = Update(ctx, tx, vID, res, d, lastUpdated)
+		return err
 	})
 
-	_, err = r.db.DB.From("reservation").Where(
+	if t := errors.GetType(err); t == errors.NotFound || t == errors.Conflict || t == errors.ValidationError {
+		return result, err
+	}
+	if err != nil {
+		return result, errors.DBError.Wrapf(err, "error updating reservation with ID %d", rID)
+	}
+
+	return r.FindReservationByID(ctx, rID)
+}
+
+func (r *reservationRepository) ExpireReservation(ctx context.Context, d payment.Deposit, now int64) (expired bool, err error) {
+	ctx, span := tracing.StartSpan(ctx, "reservationRepository.ExpireReservation", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		var res Reservation
+		found, err := tx.From("reservation").
+			Where(goqu.Ex{"rid": d.ReservationID, "venue_id": d.VenueID}).
+			ScanStruct(&res)
+		if err != nil {
+			return err
+		}
+
+		expired, err = payment.ExpireDeposit(tx, d.DepositID, now)
+		if err != nil || !expired || !found || res.Status != seating.StatusBooked {
+			return err
+		}
+		return Delete(ctx, tx, res)
+	})
+
+	if err != nil {
+		return false, errors.DBError.Wrapf(err, "error expiring reservation with ID %d", d.ReservationID)
+	}
+	return expired, nil
+}
+
+const auditEntity = "reservation"
+
+// Insert stores res, booked by the customer cID, within tx and records the
+// booking in the outbox and the audit log. The reservation is seated first, failing with a
+// Conflict if nothing is free.
+func Insert(ctx context.Context, tx *goqu.TxDatabase, vID int, cID int, res *Reservation, now int64) error {
+	if err := seat(tx, vID, res); err != nil {
+		return err
+	}
+	return insert(ctx, tx, vID, cID, res, now)
+}
+
+// InsertUnseated stores res like Insert but without a table, flagged for
+// reassignment, for bookings to be kept even though nothing is free.
+func InsertUnseated(ctx context.Context, tx *goqu.TxDatabase, vID int, cID int, res *Reservation, now int64) error {
+	res.TableID = 0
+	res.CombinationID = 0
+	res.NeedsReassignment = true
+	return insert(ctx, tx, vID, cID, res, now)
+}
+
+func insert(ctx context.Context, tx *goqu.TxDatabase, vID int, cID int, res *Reservation, now int64) error {
+	res.Status = seating.StatusBooked
+	res.Created = now
+	res.LastUpdated = now
+	res.CustomerID = cID
+	res.VenueID = vID
+	rID, err := storage.InsertReturningID(tx, "reservation", "rid", res)
+	if err != nil {
+		return err
+	}
+	res.ReservationID = rID
+
+	if err := audit.Append(ctx, tx, audit.ActionCreate, auditEntity, res.ReservationID, nil, res); err != nil {
+		return err
+	}
+	return outbox.Append(ctx, tx, event.ReservationBooked, event.AggregateReservation, res.ReservationID, res)
+}
+
+// Update reseats the reservation res.ReservationID for the changes in res
+// and stores them within tx, recording the edit in the outbox and the audit
+// log. The deposit d required for the changes, unless nil, is required in
+// addition to the deposit already held, see payment.RequireDeposit. It
+// returns the reservation as stored.
+func Update(ctx context.Context, tx *goqu.TxDatabase, vID int, res *Reservation, d *payment.Deposit, now int64) (result Reservation, err error) {
+	where := goqu.Ex{"rid": res.ReservationID, "venue_id": vID}
+
+	var before Reservation
+	found, err := tx.From("reservation").Where(where).ScanStruct(&before)
+	if err != nil {
+		return result, err
+	}
+	if !found {
+		return result, errors.NotFound.Newf("reservation with ID %d not found", res.ReservationID).
+			AddContext("ReservationID", "non existent ID")
+	}
+
+	if err := seat(tx, vID, res); err != nil {
+		return result, err
+	}
+
+	res.Deposit, res.PayBy = before.Deposit, before.PayBy
+	if d != nil {
+		d.ReservationID = before.ReservationID
+		d.CustomerID = before.CustomerID
+		d.VenueID = before.VenueID
+		held, err := payment.RequireDeposit(ctx, tx, d, now)
+		if err != nil {
+			return result, err
+		}
+		res.Deposit, res.PayBy = held.Amount, held.PayBy
+	}
+
+	res.LastUpdated = now
+	_, err = tx.From("reservation").Prepared(true).Where(where).Update(goqu.Record{
+		"seat_count":         res.SeatCount,
+		"start_time":         res.StartTime,
+		"reservation_name":   res.ReservationName,
+		"phone":              res.Phone,
+		"comments":           res.Comments,
+		"area_id":            res.AreaID,
+		"table_id":           res.TableID,
+		"combination_id":     res.CombinationID,
+		"needs_reassignment": false,
+		"deposit":            res.Deposit,
+		"pay_by":             res.PayBy,
+		"starts_at":          res.StartsAt,
+		"ends_at":            res.EndsAt,
+		"last_updated":       res.LastUpdated,
+	}).Exec()
+	if err != nil {
+		return result, err
+	}
+
+	if _, err := tx.From("reservation").Where(where).ScanStruct(&result); err != nil {
+		return result, err
+	}
+
+	if err := audit.Append(ctx, tx, audit.ActionUpdate, auditEntity, res.ReservationID, before, result); err != nil {
+		return result, err
+	}
+	return result, outbox.Append(ctx, tx, event.ReservationEdited, event.AggregateReservation, res.ReservationID, result)
+}
+
+// Delete removes res within tx and records the cancellation in the outbox
+// and the audit log.
+func Delete(ctx context.Context, tx *goqu.TxDatabase, res Reservation) error {
+	if _, err := tx.From("reservation").Where(goqu.Ex{"rid": res.ReservationID}).Delete().Exec(); err != nil {
+		return err
+	}
+	if err := audit.Append(ctx, tx, audit.ActionDelete, auditEntity, res.ReservationID, res, nil); err != nil {
+		return err
+	}
+	return outbox.Append(ctx, tx, event.ReservationCancelled, event.AggregateReservation, res.ReservationID, res)
+}
+
+// Cancel deletes res within tx, charging the fee f unless nil, and stores
+// the settlement of its deposit keeping what pays the fee. The gateway is
+// left to be called once tx commits, see Terms.Settle.
+func Cancel(ctx context.Context, tx *goqu.TxDatabase, res Reservation, f *fee.Fee, now int64) error {
+	if err := InsertFee(tx, res, f); err != nil {
+		return err
+	}
+	if err := Delete(ctx, tx, res); err != nil {
+		return err
+	}
+	if res.Deposit == 0 {
+		return nil
+	}
+
+	var keep int64
+	var fID int
+	if f != nil {
+		keep, fID = f.Amount, f.FeeID
+	}
+	// Deposits settled already, e.g. by hand, are left alone.
+	_, err := payment.Settle(ctx, tx, res.VenueID, res.ReservationID, keep, fID, now)
+	if t := errors.GetType(err); t == errors.NotFound || t == errors.Conflict {
+		return nil
+	}
+	return err
+}
+
+// InsertFee records the fee f for a change to res within tx, unless f is nil.
+func InsertFee(tx *goqu.TxDatabase, res Reservation, f *fee.Fee) error {
+	if f == nil {
+		return nil
+	}
+	f.ReservationID = res.ReservationID
+	f.CustomerID = res.CustomerID
+	f.VenueID = res.VenueID
+	return fee.Insert(tx, f, time.Now().Unix())
+}
+
+// InsertDeposit requires the deposit d for the booking res within tx, unless
+// d is nil.
+func InsertDeposit(tx *goqu.TxDatabase, res Reservation, d *payment.Deposit, now int64) error {
+	if d == nil {
+		return nil
+	}
+	d.ReservationID = res.ReservationID
+	d.CustomerID = res.CustomerID
+	d.VenueID = res.VenueID
+	return payment.InsertDeposit(tx, d, now)
+}
+
+// seat allocates a table or table combination to res within tx, unless the
+// venue has no tables.
+func seat(tx *goqu.TxDatabase, vID int, res *Reservation) error {
+	a, ok, err := seating.Allocate(tx, vID, res.slot())
+	if err != nil {
+		return err
+	}
+	res.HoldToken = ""
+	res.TableID = 0
+	res.CombinationID = 0
+	res.NeedsReassignment = false
+	if ok {
+		res.TableID = a.TableID
+		res.CombinationID = a.CombinationID
+		res.AreaID = a.AreaID
+	}
+	return nil
+}
+
+func (r *reservationRepository) FindReservationByID(ctx context.Context, rID int) (res Reservation, err error) {
+	ctx, span := tracing.StartSpan(ctx, "reservationRepository.FindReservationByID", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	found, err := r.db.DB.From("reservation").Where(
 		goqu.C("rid").Eq(rID),
-	).ScanStruct(&result)
+		goqu.C("venue_id").Eq(tenant.VenueFromContext(ctx)),
+	).ScanStruct(&res)
 
 	if err != nil {
-		return result, errors.DBError.Wrapf(err, "error updating reservation with ID %d", rID)
+		return res, errors.DBError.Wrapf(err, "error getting reservation with ID %d", rID)
+	}
+
+	if !found {
+		return res, errors.NotFound.Newf("reservation with ID %d not found", rID).
+			AddContext("ReservationID", "non existent ID")
 	}
 
-	return result, nil
+	return res, nil
 }
 
-func (r *reservationRepository) FindReservationsByCustomerID(cID int, opts *storage.QueryOptions) (rr []Reservation, err error) {
+func (r *reservationRepository) FindReservationsByCustomerID(ctx context.Context, cID int, opts *storage.QueryOptions) (rr []Reservation, err error) {
+	ctx, span := tracing.StartSpan(ctx, "reservationRepository.FindReservationsByCustomerID", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	opts.Limit = r.db.PageSize(opts.Limit)
 	err = r.db.DB.From("reservation").
 		Select("reservation.*").
 		Join(
@@ -81,6 +385,10 @@ func (r *reservationRepository) FindReservationsByCustomerID(cID int, opts *stor
 			goqu.On(goqu.Ex{
 				"reservation.customer_id": goqu.I("customer.cid"),
 			})).
+		Where(goqu.Ex{
+			"reservation.customer_id": cID,
+			"reservation.venue_id":    tenant.VenueFromContext(ctx),
+		}).
 		Order(goqu.C("last_updated").Desc()).
 		Limit(opts.Limit).
 		Offset(opts.Offset).
@@ -91,3 +399,24 @@ func (r *reservationRepository) FindReservationsByCustomerID(cID int, opts *stor
 	}
 	return rr, nil
 }
+
+func (r *reservationRepository) CountBookingsSince(ctx context.Context, since int64) (stats BookingStats, err error) {
+	ctx, span := tracing.StartSpan(ctx, "reservationRepository.CountBookingsSince", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	_, err = r.db.DB.From("reservation").
+		Select(
+			goqu.COUNT(goqu.Star()).As("bookings"),
+			goqu.COALESCE(goqu.SUM("seat_count"), 0).As("seats"),
+		).
+		Where(
+			goqu.C("created").Gte(since),
+			goqu.C("venue_id").Eq(tenant.VenueFromContext(ctx)),
+		).
+		ScanStruct(&stats)
+
+	if err != nil {
+		return stats, errors.DBError.Wrap(err, "error counting booked reservations")
+	}
+	return stats, nil
+}
@@ -0,0 +1,257 @@
+package reservation
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v7"
+	"reservations/pkg/audit"
+	"reservations/pkg/customer"
+	errors "reservations/pkg/error"
+	"reservations/pkg/fee"
+	"reservations/pkg/payment"
+	"reservations/pkg/seating"
+	"reservations/pkg/storage"
+	"reservations/pkg/storage/storagetest"
+	"testing"
+	"time"
+)
+
+const startsAt = 1767261600 // 2026-01-01T10:00:00Z
+
+func newReservation(seats int) *Reservation {
+	return &Reservation{
+		SeatCount:       seats,
+		StartTime:       "2026-01-01T10:00:00Z",
+		ReservationName: "Doe",
+		StartsAt:        startsAt,
+		EndsAt:          startsAt + 7200,
+	}
+}
+
+func addCustomer(t *testing.T, ctx context.Context, db *storage.Persistence) int {
+	c, err := customer.NewCustomerRepository(*db).AddCustomer(ctx, &customer.Customer{FirstName: "Ann", LastName: "Doe"})
+	if err != nil {
+		t.Fatalf("AddCustomer: %v", err)
+	}
+	return c.CustomerID
+}
+
+func TestAddReservation(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		r := NewReservationRepository(*db)
+		ctx := context.Background()
+
+		if _, err := r.AddReservation(ctx, 99, newReservation(2), nil); errors.GetType(err) != errors.NotFound {
+			t.Errorf("AddReservation for an unknown customer = %v, want a NotFound", err)
+		}
+
+		cID := addCustomer(t, ctx, db)
+		d := &payment.Deposit{Policy: "large", Amount: 5000, Currency: "eur", PayBy: startsAt - 86400}
+		res, err := r.AddReservation(ctx, cID, newReservation(8), d)
+		if err != nil {
+			t.Fatalf("AddReservation: %v", err)
+		}
+		if res.Status != seating.StatusBooked || res.TableID != 0 || res.NeedsReassignment {
+			t.Errorf("AddReservation without tables = %+v, want it booked unseated", res)
+		}
+
+		got, err := payment.NewPaymentRepository(*db).FindDepositByReservationID(ctx, res.ReservationID)
+		if err != nil {
+			t.Fatalf("FindDepositByReservationID: %v", err)
+		}
+		if got.DepositID != d.DepositID || got.CustomerID != cID || got.Status != payment.StatusPending || got.Amount != 5000 {
+			t.Errorf("deposit = %+v, want the pending deposit of the booking", got)
+		}
+
+		rr, err := r.FindReservationsByCustomerID(ctx, cID, &storage.QueryOptions{})
+		if err != nil || len(rr) != 1 || rr[0].ReservationID != res.ReservationID {
+			t.Errorf("FindReservationsByCustomerID = %v, %v, want the booking", rr, err)
+		}
+	})
+}
+
+func TestAddReservationSeats(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		r := NewReservationRepository(*db)
+		ctx := context.Background()
+		cID := addCustomer(t, ctx, db)
+
+		seats := seating.NewSeatingRepository(*db)
+		a, err := seats.AddArea(ctx, &seating.Area{Name: "Terrace"})
+		if err != nil {
+			t.Fatalf("AddArea: %v", err)
+		}
+		tbl, err := seats.AddTable(ctx, &seating.Table{AreaID: a.AreaID, Name: "T1", Capacity: 4, Width: 1, Height: 1, Shape: seating.ShapeSquare})
+		if err != nil {
+			t.Fatalf("AddTable: %v", err)
+		}
+
+		res, err := r.AddReservation(ctx, cID, newReservation(4), nil)
+		if err != nil {
+			t.Fatalf("AddReservation: %v", err)
+		}
+		if res.TableID != tbl.TableID || res.AreaID != a.AreaID {
+			t.Errorf("AddReservation = %+v, want it seated at table %d", res, tbl.TableID)
+		}
+
+		if _, err := r.AddReservation(ctx, cID, newReservation(2), nil); errors.GetType(err) != errors.Conflict {
+			t.Errorf("AddReservation with every table taken = %v, want a Conflict", err)
+		}
+		later := newReservation(2)
+		later.StartsAt, later.EndsAt = res.EndsAt, res.EndsAt+7200
+		if _, err := r.AddReservation(ctx, cID, later, nil); err != nil {
+			t.Errorf("AddReservation once the table is free again: %v", err)
+		}
+	})
+}
+
+func TestAddReservationConcurrently(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		r := NewReservationRepository(*db)
+		ctx := context.Background()
+		cID := addCustomer(t, ctx, db)
+
+		seats := seating.NewSeatingRepository(*db)
+		a, err := seats.AddArea(ctx, &seating.Area{Name: "Terrace"})
+		if err != nil {
+			t.Fatalf("AddArea: %v", err)
+		}
+		if _, err := seats.AddTable(ctx, &seating.Table{AreaID: a.AreaID, Name: "T1", Capacity: 4, Width: 1, Height: 1, Shape: seating.ShapeSquare}); err != nil {
+			t.Fatalf("AddTable: %v", err)
+		}
+
+		const bookings = 16
+		start := make(chan struct{})
+		errs := make(chan error, bookings)
+		for i := 0; i < bookings; i++ {
+			go func() {
+				<-start
+				_, err := r.AddReservation(ctx, cID, newReservation(2), nil)
+				errs <- err
+			}()
+		}
+		close(start)
+
+		// The table goes to one booking, the others being told it is taken
+		// rather than failing.
+		booked := 0
+		for i := 0; i < bookings; i++ {
+			err := <-errs
+			switch {
+			case err == nil:
+				booked++
+			case errors.GetType(err) != errors.Conflict:
+				t.Errorf("AddReservation = %v, want a Conflict", err)
+			}
+		}
+		if booked != 1 {
+			t.Errorf("%d concurrent bookings got the only table", booked)
+		}
+	})
+}
+
+func TestUpdateReservation(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		r := NewReservationRepository(*db)
+		ctx := context.Background()
+		cID := addCustomer(t, ctx, db)
+
+		if _, err := r.UpdateReservation(ctx, 99, newReservation(2), nil, nil); errors.GetType(err) != errors.NotFound {
+			t.Errorf("UpdateReservation of an unknown reservation = %v, want a NotFound", err)
+		}
+
+		res, err := r.AddReservation(ctx, cID, newReservation(2), nil)
+		if err != nil {
+			t.Fatalf("AddReservation: %v", err)
+		}
+		changed := *res
+		changed.SeatCount = 3
+		f := &fee.Fee{Kind: fee.Modification, Policy: "late", Amount: 500}
+		got, err := r.UpdateReservation(ctx, res.ReservationID, &changed, f, nil)
+		if err != nil {
+			t.Fatalf("UpdateReservation: %v", err)
+		}
+		if got.SeatCount != 3 || got.Fee != 500 {
+			t.Errorf("UpdateReservation = %+v, want 3 seats and the fee", got)
+		}
+
+		var entries []audit.Entry
+		if err := db.DB.From("audit_log").Where(goqu.Ex{"entity_id": res.ReservationID, "entity_type": auditEntity, "action": audit.ActionUpdate}).
+			ScanStructs(&entries); err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || len(entries[0].Before) == 0 || len(entries[0].After) == 0 {
+			t.Errorf("audit entries = %+v, want the update with its before and after", entries)
+		}
+	})
+}
+
+func TestRemoveReservation(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		r := NewReservationRepository(*db)
+		ctx := context.Background()
+		cID := addCustomer(t, ctx, db)
+
+		res, err := r.AddReservation(ctx, cID, newReservation(2), nil)
+		if err != nil {
+			t.Fatalf("AddReservation: %v", err)
+		}
+		f := &fee.Fee{Kind: fee.Cancellation, Policy: "late", Amount: 1500}
+		if err := r.RemoveReservation(ctx, res.ReservationID, f); err != nil {
+			t.Fatalf("RemoveReservation: %v", err)
+		}
+		if _, err := r.FindReservationByID(ctx, res.ReservationID); errors.GetType(err) != errors.NotFound {
+			t.Errorf("FindReservationByID after RemoveReservation = %v, want a NotFound", err)
+		}
+
+		var ff []fee.Fee
+		if err := db.DB.From("reservation_fee").ScanStructs(&ff); err != nil {
+			t.Fatal(err)
+		}
+		if len(ff) != 1 || ff[0].ReservationID != res.ReservationID || ff[0].CustomerID != cID || ff[0].Amount != 1500 {
+			t.Errorf("fees = %+v, want the cancellation fee", ff)
+		}
+	})
+}
+
+func TestRemoveReservationSettlesDeposit(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		r := NewReservationRepository(*db)
+		payments := payment.NewPaymentService(payment.NewPaymentRepository(*db), payment.NewFakeGateway())
+		fees := fee.NewFeeService(fee.NewFeeRepository(*db), payments)
+		ctx := context.Background()
+		cID := addCustomer(t, ctx, db)
+
+		res := newReservation(6)
+		res.Deposit, res.PayBy = 3000, time.Now().Add(time.Hour).Unix()
+		d := &payment.Deposit{Policy: "large", Amount: 3000, Currency: "eur", PayBy: res.PayBy}
+		if _, err := r.AddReservation(ctx, cID, res, d); err != nil {
+			t.Fatalf("AddReservation: %v", err)
+		}
+		if _, err := payments.PayDeposit(ctx, res.ReservationID, "pm_card_visa"); err != nil {
+			t.Fatalf("PayDeposit: %v", err)
+		}
+
+		// The settlement is stored with the cancellation, and only carried out
+		// with the gateway once committed.
+		f := &fee.Fee{Kind: fee.Cancellation, Policy: "late", Amount: 1500}
+		if err := r.RemoveReservation(ctx, res.ReservationID, f); err != nil {
+			t.Fatalf("RemoveReservation: %v", err)
+		}
+		settling, err := payments.GetDeposit(ctx, res.ReservationID)
+		if err != nil || settling.Status != payment.StatusSettling || settling.Keep != 1500 || settling.FeeID != f.FeeID {
+			t.Fatalf("deposit after RemoveReservation = %+v, %v, want it settling keeping the fee", settling, err)
+		}
+		if settled, err := payments.ProcessSettlement(ctx, res.ReservationID); err != nil || settled.Status != payment.StatusCaptured || settled.Captured != 1500 {
+			t.Fatalf("ProcessSettlement = %+v, %v, want the fee captured", settled, err)
+		}
+
+		// Waiving the fee refunds what the deposit kept of it.
+		if _, err := fees.WaiveFee(ctx, f.FeeID, "goodwill"); err != nil {
+			t.Fatalf("WaiveFee: %v", err)
+		}
+		refunded, err := payments.GetDeposit(ctx, res.ReservationID)
+		if err != nil || refunded.Status != payment.StatusRefunded || refunded.Refunded != 1500 || refunded.Keep != 0 {
+			t.Errorf("deposit after WaiveFee = %+v, %v, want the fee refunded", refunded, err)
+		}
+	})
+}
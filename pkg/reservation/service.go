@@ -2,50 +2,169 @@ package reservation
 
 import (
 	"context"
+	"reservations/pkg/fee"
+	"reservations/pkg/policy"
+	"reservations/pkg/seating"
 	"reservations/pkg/storage"
+	"time"
 )
 
 type Service interface {
 	BookReservation(ctx context.Context, cID int, r *Reservation) (*Reservation, error)
 	DiscardReservation(ctx context.Context, rID int) error
 	EditReservation(ctx context.Context, rID int, r *Reservation) (Reservation, error)
+	GetReservationByID(ctx context.Context, rID int) (Reservation, error)
 	GetReservationHistoryPerCustomer(ctx context.Context, cID int, opts *storage.QueryOptions) ([]Reservation, error)
 }
 
 type Reservation struct {
-	ReservationID   int    `json:"reservationId" db:"rid" goqu:"skipinsert"`
-	SeatCount       int    `json:"seatCount" db:"seat_count"`
+	ReservationID int `json:"reservationId" db:"rid" goqu:"skipinsert"`
+	SeatCount     int `json:"seatCount" db:"seat_count"`
+	// StartTime is when the party arrives, as an RFC 3339 timestamp or, as
+	// older clients send it, in Unix seconds.
 	StartTime       string `json:"startTime" db:"start_time"`
 	ReservationName string `json:"reservationName" db:"reservation_name"`
 	CustomerID      int    `json:"customerId" db:"customer_id"`
 	Phone           string `json:"phone"`
 	Comments        string `json:"comments"`
-	Created         int64  `json:"created"`
-	LastUpdated     int64  `json:"lastUpdated" db:"last_updated"`
+	// AreaID is the seating area asked for, or the one of the table
+	// allocated when any area would do.
+	AreaID  int `json:"areaId" db:"area_id"`
+	TableID int `json:"tableId" db:"table_id"`
+	// CombinationID is set instead of TableID for parties seated at tables
+	// pushed together.
+	CombinationID int `json:"combinationId" db:"combination_id"`
+	// NeedsReassignment is set when the table allocated is no longer
+	// available, e.g. because its area was closed.
+	NeedsReassignment bool `json:"needsReassignment" db:"needs_reassignment"`
+	// Status is booked until the host stand seats the party, and completed
+	// once it leaves.
+	Status string `json:"status" enums:"booked,seated,completed"`
+	// HoldToken redeems a hold taken during checkout: the booking gets the
+	// table kept by the hold, which is released.
+	HoldToken string `json:"holdToken,omitempty" db:"-"`
+	// SeriesID is the recurring series the reservation is an occurrence of,
+	// scheduled on the date Occurrence, or 0 for one-off bookings.
+	SeriesID   int    `json:"seriesId,omitempty" db:"series_id"`
+	Occurrence string `json:"occurrence,omitempty"`
+	// Fee totals the unwaived fees charged for modifying the reservation
+	// late, in the minor unit of the currency.
+	Fee int64 `json:"fee" goqu:"skipinsert"`
+	// Deposit is required to hold the reservation by PayBy, after which it
+	// is cancelled unless paid, in the minor unit of the currency.
+	Deposit     int64 `json:"deposit"`
+	PayBy       int64 `json:"payBy,omitempty" db:"pay_by"`
+	StartsAt    int64 `json:"startsAt" db:"starts_at"`
+	EndsAt      int64 `json:"endsAt" db:"ends_at"`
+	VenueID     int   `json:"venueId" db:"venue_id"`
+	Created     int64 `json:"created"`
+	LastUpdated int64 `json:"lastUpdated" db:"last_updated"`
 }
 
 type reservationService struct {
-	resRepo Repository
+	resRepo  Repository
+	duration time.Duration
+	terms    Terms
 }
 
-func NewReservationService(repo Repository) Service {
+// NewReservationService returns the reservation service. duration is how
+// long the table allocated to a booking is held, and terms what bookings and
+// edits are held to.
+func NewReservationService(repo Repository, duration time.Duration, terms Terms) Service {
 	return &reservationService{
-		resRepo: repo,
+		resRepo:  repo,
+		duration: duration,
+		terms:    terms,
 	}
 }
 
 func (s *reservationService) BookReservation(ctx context.Context, cID int, r *Reservation) (*Reservation, error) {
-	return s.resRepo.AddReservation(cID, r)
+	if err := s.schedule(r); err != nil {
+		return nil, err
+	}
+	ch := policy.ChannelFromContext(ctx)
+	if err := s.terms.Check(*r, nil, ch); err != nil {
+		return nil, err
+	}
+	r.SeriesID = 0
+	r.Occurrence = ""
+	r.Fee = 0
+	return s.resRepo.AddReservation(ctx, cID, r, s.terms.Deposit(r, ch))
 }
 
 func (s *reservationService) DiscardReservation(ctx context.Context, rID int) error {
-	return s.resRepo.RemoveReservation(rID)
+	if len(s.terms.Policy.Cancellations) == 0 && s.terms.Payments == nil {
+		return s.resRepo.RemoveReservation(ctx, rID, nil)
+	}
+
+	prev, err := s.resRepo.FindReservationByID(ctx, rID)
+	if err != nil {
+		return err
+	}
+	f := s.terms.Charge(prev, policy.ChannelFromContext(ctx), prev.SeatCount, fee.Cancellation)
+	if err := s.resRepo.RemoveReservation(ctx, rID, f); err != nil {
+		return err
+	}
+	s.terms.Settle(ctx, prev)
+	return nil
 }
 
 func (s *reservationService) EditReservation(ctx context.Context, rID int, res *Reservation) (r Reservation, err error) {
-	return s.resRepo.UpdateReservation(rID, res)
+	if err := s.schedule(res); err != nil {
+		return r, err
+	}
+	if len(s.terms.Policy.Rules) == 0 && len(s.terms.Policy.Cancellations) == 0 && s.terms.Payments == nil {
+		return s.resRepo.UpdateReservation(ctx, rID, res, nil, nil)
+	}
+
+	prev, err := s.resRepo.FindReservationByID(ctx, rID)
+	if err != nil {
+		return r, err
+	}
+	ch := policy.ChannelFromContext(ctx)
+	if err := s.terms.Check(*res, &prev, ch); err != nil {
+		return r, err
+	}
+	// The edited booking may require a deposit the original did not, or a
+	// larger one.
+	return s.resRepo.UpdateReservation(ctx, rID, res, s.terms.EditFee(prev, *res, ch), s.terms.Deposit(res, ch))
+}
+
+func (s *reservationService) GetReservationByID(ctx context.Context, rID int) (Reservation, error) {
+	return s.resRepo.FindReservationByID(ctx, rID)
 }
 
 func (s *reservationService) GetReservationHistoryPerCustomer(ctx context.Context, cID int, opts *storage.QueryOptions) ([]Reservation, error) {
-	return s.resRepo.FindReservationsByCustomerID(cID, opts)
+	return s.resRepo.FindReservationsByCustomerID(ctx, cID, opts)
+}
+
+// schedule sets the time range for which r holds its table.
+func (s *reservationService) schedule(r *Reservation) error {
+	startsAt, err := seating.ParseStartTime(r.StartTime)
+	if err != nil {
+		return err
+	}
+	r.StartsAt = startsAt
+	r.EndsAt = startsAt + int64(s.duration/time.Second)
+	return seating.ValidateSlot(r.slot())
+}
+
+// start returns the start of r in the UTC offset it was booked in.
+func (r *Reservation) start() time.Time {
+	t, err := time.Parse(time.RFC3339, r.StartTime)
+	if err != nil {
+		return time.Unix(r.StartsAt, 0)
+	}
+	return t
+}
+
+func (r *Reservation) slot() seating.Slot {
+	return seating.Slot{
+		StartsAt:      r.StartsAt,
+		EndsAt:        r.EndsAt,
+		Seats:         r.SeatCount,
+		AreaID:        r.AreaID,
+		ReservationID: r.ReservationID,
+		HoldToken:     r.HoldToken,
+	}
 }
@@ -0,0 +1,66 @@
+package reservation
+
+import (
+	"context"
+	errors "reservations/pkg/error"
+	"reservations/pkg/payment"
+	"reservations/pkg/policy"
+	"reservations/pkg/storage"
+	"reservations/pkg/storage/storagetest"
+	"testing"
+	"time"
+)
+
+func TestEditReservationRequiresDeposit(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		payments := payment.NewPaymentService(payment.NewPaymentRepository(*db), payment.NewFakeGateway())
+		terms := Terms{
+			Policy: policy.Policy{
+				Currency: "eur",
+				Deposits: []policy.Deposit{{Name: "large", MinPartySize: 6, AmountPerGuest: 500}},
+			},
+			Payments:   payments,
+			DepositTTL: 24 * time.Hour,
+		}
+		s := NewReservationService(NewReservationRepository(*db), 2*time.Hour, terms)
+		ctx := context.Background()
+		cID := addCustomer(t, ctx, db)
+		start := time.Now().UTC().Truncate(time.Hour).Add(72 * time.Hour).Format(time.RFC3339)
+
+		edit := func(rID int, seats int) (Reservation, error) {
+			return s.EditReservation(ctx, rID, &Reservation{SeatCount: seats, StartTime: start, ReservationName: "Doe"})
+		}
+
+		small, err := s.BookReservation(ctx, cID, &Reservation{SeatCount: 2, StartTime: start, ReservationName: "Doe"})
+		if err != nil || small.Deposit != 0 {
+			t.Fatalf("BookReservation = %+v, %v, want no deposit", small, err)
+		}
+		res, err := edit(small.ReservationID, 6)
+		if err != nil || res.Deposit != 3000 || res.PayBy == 0 {
+			t.Fatalf("EditReservation to 6 seats = %+v, %v, want a deposit of 3000", res, err)
+		}
+		d, err := payments.GetDeposit(ctx, res.ReservationID)
+		if err != nil || d.Status != payment.StatusPending || d.Amount != 3000 || d.PayBy != res.PayBy {
+			t.Fatalf("deposit = %+v, %v, want a pending one of 3000", d, err)
+		}
+
+		// Pending deposits are raised along with the party.
+		if res, err = edit(res.ReservationID, 8); err != nil || res.Deposit != 4000 {
+			t.Fatalf("EditReservation to 8 seats = %+v, %v, want a deposit of 4000", res, err)
+		}
+		if d, err = payments.GetDeposit(ctx, res.ReservationID); err != nil || d.Amount != 4000 {
+			t.Errorf("deposit = %+v, %v, want 4000", d, err)
+		}
+
+		// Paid deposits are kept as they are, and cannot be outgrown.
+		if _, err := payments.PayDeposit(ctx, res.ReservationID, "pm_card_visa"); err != nil {
+			t.Fatalf("PayDeposit: %v", err)
+		}
+		if res, err = edit(res.ReservationID, 6); err != nil || res.Deposit != 4000 {
+			t.Errorf("EditReservation to 6 seats = %+v, %v, want the deposit of 4000 kept", res, err)
+		}
+		if _, err := edit(res.ReservationID, 10); errors.GetType(err) != errors.Conflict {
+			t.Errorf("EditReservation beyond the deposit paid = %v, want a Conflict", err)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package reservation
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics"
+	"reservations/pkg/storage"
+	"reservations/pkg/tenant"
+	"time"
+)
+
+// ReportStats periodically sets bookings and seats to the number of
+// reservations booked since midnight UTC and the seats they hold, labelled
+// by venue, until ctx is cancelled.
+func ReportStats(ctx context.Context, repo Repository, venues tenant.Repository, interval time.Duration, logger log.Logger, bookings metrics.Gauge, seats metrics.Gauge) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := reportStats(ctx, repo, venues, bookings, seats); err != nil {
+			logger.Log("component", "stats", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func reportStats(ctx context.Context, repo Repository, venues tenant.Repository, bookings metrics.Gauge, seats metrics.Gauge) error {
+	midnight := time.Now().UTC().Truncate(24 * time.Hour)
+	opts := &storage.QueryOptions{}
+	for {
+		vv, err := venues.FindAllVenues(ctx, opts)
+		if err != nil || len(vv) == 0 {
+			return err
+		}
+
+		for _, v := range vv {
+			stats, err := repo.CountBookingsSince(tenant.WithVenue(ctx, v.VenueID), midnight.Unix())
+			if err != nil {
+				return err
+			}
+			bookings.With("venue", v.Slug).Set(float64(stats.Bookings))
+			seats.With("venue", v.Slug).Set(float64(stats.Seats))
+		}
+		opts.Offset += uint(len(vv))
+	}
+}
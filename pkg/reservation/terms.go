@@ -0,0 +1,111 @@
+package reservation
+
+import (
+	"context"
+	"reservations/pkg/fee"
+	"reservations/pkg/payment"
+	"reservations/pkg/policy"
+	"reservations/pkg/seating"
+	"time"
+)
+
+// Terms are what every booking is held to, whether booked on its own or as
+// the occurrence of a series: the booking policy, the fees charged for late
+// changes and the deposits required.
+type Terms struct {
+	Policy policy.Policy
+	// Payments settles deposits when reservations are cancelled. Deposits
+	// are only required unless it is nil.
+	Payments payment.Service
+	// DepositTTL is how long after booking deposits are due.
+	DepositTTL time.Duration
+}
+
+// Check applies the booking policy to r, booked through ch, which edits prev
+// unless nil.
+func (t Terms) Check(r Reservation, prev *Reservation, ch policy.Channel) error {
+	if len(t.Policy.Rules) == 0 {
+		return nil
+	}
+
+	b := r.booking(ch)
+	if prev != nil {
+		b.PreviousStart = prev.start()
+	}
+	return t.Policy.Check(b)
+}
+
+// Deposit sets the deposit required for r, booked through ch, returning it
+// or nil if none is. The deposit is due DepositTTL from now, or by the start
+// of r if sooner.
+func (t Terms) Deposit(r *Reservation, ch policy.Channel) *payment.Deposit {
+	r.Deposit = 0
+	r.PayBy = 0
+	if t.Payments == nil {
+		return nil
+	}
+
+	name, amount := t.Policy.Deposit(r.booking(ch))
+	if amount == 0 {
+		return nil
+	}
+	r.Deposit = amount
+	r.PayBy = time.Now().Add(t.DepositTTL).Unix()
+	if r.PayBy > r.StartsAt {
+		r.PayBy = r.StartsAt
+	}
+	return &payment.Deposit{
+		Policy:   name,
+		Amount:   amount,
+		Currency: t.Policy.Currency,
+		PayBy:    r.PayBy,
+	}
+}
+
+// Charge returns the fee for cancelling seats of the guests of prev, as
+// booked before the change, or nil if free. Parties already seated are not
+// charged.
+func (t Terms) Charge(prev Reservation, ch policy.Channel, seats int, kind fee.Kind) *fee.Fee {
+	if prev.Status != seating.StatusBooked {
+		return nil
+	}
+
+	name, amount := t.Policy.Fee(prev.booking(ch), seats)
+	if amount == 0 {
+		return nil
+	}
+	return &fee.Fee{Kind: kind, Policy: name, Amount: amount}
+}
+
+// EditFee returns the fee for changing prev into r through ch, or nil if
+// free: moving a reservation is charged for its whole party, and reducing it
+// for the seats cancelled.
+func (t Terms) EditFee(prev Reservation, r Reservation, ch policy.Channel) *fee.Fee {
+	switch {
+	case r.StartsAt != prev.StartsAt:
+		return t.Charge(prev, ch, prev.SeatCount, fee.Modification)
+	case r.SeatCount < prev.SeatCount:
+		return t.Charge(prev, ch, prev.SeatCount-r.SeatCount, fee.Modification)
+	}
+	return nil
+}
+
+// Settle carries out with the gateway the settlement of the deposit of prev
+// stored on its cancellation, see Cancel. The cancellation stands should the
+// gateway fail, the settlement being retried by the payment.Settler.
+func (t Terms) Settle(ctx context.Context, prev Reservation) {
+	if t.Payments == nil || prev.Deposit == 0 {
+		return
+	}
+	t.Payments.ProcessSettlement(ctx, prev.ReservationID)
+}
+
+// booking returns r as booked now through ch, to apply the policy to.
+func (r Reservation) booking(ch policy.Channel) policy.Booking {
+	return policy.Booking{
+		Channel:   ch,
+		Start:     r.start(),
+		SeatCount: r.SeatCount,
+		Now:       time.Now(),
+	}
+}
@@ -3,15 +3,17 @@ package reservation
 import (
 	"context"
 	"encoding/json"
+	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
 	httptransport "github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
 	"net/http"
+	"reservations/pkg/authz"
 	"reservations/pkg/transport"
 )
 
-func MakeHTTPHandler(r *mux.Router, s Service, logger log.Logger) *mux.Router {
-	e := MakeServerEndpoints(s)
+func MakeHTTPHandler(r *mux.Router, s Service, az authz.Authorizer, logger log.Logger, mw ...endpoint.Middleware) *mux.Router {
+	e := MakeServerEndpoints(s, az, mw...)
 
 	options := httpjson.DefaultServerOptions(logger)
 
@@ -39,6 +41,14 @@ func MakeHTTPHandler(r *mux.Router, s Service, logger log.Logger) *mux.Router {
 			options...,
 		))
 
+	r.Methods("GET").Path("/reservation/{id}").
+		Handler(httptransport.NewServer(
+			e.GetReservationByIDEndpoint,
+			decodeGetReservationByIDRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
 	r.Methods("GET").Path("/customer/{id}/reservations").
 		Handler(httptransport.NewServer(
 			e.GetReservationHistoryByCustomerEndpoint,
@@ -88,6 +98,14 @@ func decodeEditReservationRequest(_ context.Context, r *http.Request) (request i
 	return req, nil
 }
 
+func decodeGetReservationByIDRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "reservation ID")
+	if err != nil {
+		return nil, err
+	}
+	return getReservationByIDRequest{ReservationID: id}, nil
+}
+
 func decodeGetReservationHistoryPerCustomerRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
 	id, err := httpjson.ParseIntPathParam(r, "id", "customer ID")
 	if err != nil {
@@ -0,0 +1,237 @@
+package reservation
+
+import (
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	grpctransport "github.com/go-kit/kit/transport/grpc"
+	"reservations/pkg/authz"
+	"reservations/pkg/pb"
+	"reservations/pkg/transport/grpcutil"
+)
+
+type grpcServer struct {
+	bookReservation                  grpctransport.Handler
+	discardReservation               grpctransport.Handler
+	editReservation                  grpctransport.Handler
+	getReservationByID               grpctransport.Handler
+	getReservationHistoryPerCustomer grpctransport.Handler
+}
+
+// MakeGRPCServer exposes the reservation service endpoints as a pb.ReservationsServer.
+func MakeGRPCServer(s Service, az authz.Authorizer, logger log.Logger, mw ...endpoint.Middleware) pb.ReservationsServer {
+	e := MakeServerEndpoints(s, az, mw...)
+
+	options := grpcutil.DefaultServerOptions(logger)
+
+	return &grpcServer{
+		bookReservation: grpctransport.NewServer(
+			e.BookReservationEndpoint,
+			decodeGRPCBookReservationRequest,
+			encodeGRPCBookReservationResponse,
+			options...,
+		),
+		discardReservation: grpctransport.NewServer(
+			e.DiscardReservationEndpoint,
+			decodeGRPCDiscardReservationRequest,
+			encodeGRPCDiscardReservationResponse,
+			options...,
+		),
+		editReservation: grpctransport.NewServer(
+			e.EditReservationEndpoint,
+			decodeGRPCEditReservationRequest,
+			encodeGRPCEditReservationResponse,
+			options...,
+		),
+		getReservationByID: grpctransport.NewServer(
+			e.GetReservationByIDEndpoint,
+			decodeGRPCGetReservationByIDRequest,
+			encodeGRPCGetReservationByIDResponse,
+			options...,
+		),
+		getReservationHistoryPerCustomer: grpctransport.NewServer(
+			e.GetReservationHistoryByCustomerEndpoint,
+			decodeGRPCGetReservationHistoryPerCustomerRequest,
+			encodeGRPCGetReservationHistoryPerCustomerResponse,
+			options...,
+		),
+	}
+}
+
+func (s *grpcServer) BookReservation(ctx context.Context, req *pb.BookReservationRequest) (*pb.BookReservationReply, error) {
+	_, rep, err := s.bookReservation.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, grpcutil.EncodeError(err)
+	}
+	return rep.(*pb.BookReservationReply), nil
+}
+
+func (s *grpcServer) DiscardReservation(ctx context.Context, req *pb.DiscardReservationRequest) (*pb.DiscardReservationReply, error) {
+	_, rep, err := s.discardReservation.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, grpcutil.EncodeError(err)
+	}
+	return rep.(*pb.DiscardReservationReply), nil
+}
+
+func (s *grpcServer) EditReservation(ctx context.Context, req *pb.EditReservationRequest) (*pb.EditReservationReply, error) {
+	_, rep, err := s.editReservation.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, grpcutil.EncodeError(err)
+	}
+	return rep.(*pb.EditReservationReply), nil
+}
+
+func (s *grpcServer) GetReservationByID(ctx context.Context, req *pb.GetReservationByIDRequest) (*pb.GetReservationByIDReply, error) {
+	_, rep, err := s.getReservationByID.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, grpcutil.EncodeError(err)
+	}
+	return rep.(*pb.GetReservationByIDReply), nil
+}
+
+func (s *grpcServer) GetReservationHistoryPerCustomer(ctx context.Context, req *pb.GetReservationHistoryPerCustomerRequest) (*pb.GetReservationHistoryPerCustomerReply, error) {
+	_, rep, err := s.getReservationHistoryPerCustomer.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, grpcutil.EncodeError(err)
+	}
+	return rep.(*pb.GetReservationHistoryPerCustomerReply), nil
+}
+
+func decodeGRPCBookReservationRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.BookReservationRequest)
+	return bookReservationRequest{
+		CustomerID:  int(req.CustomerId),
+		Reservation: reservationFromPB(req.Reservation),
+	}, nil
+}
+
+func encodeGRPCBookReservationResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(bookReservationResponse)
+	if resp.Err != nil {
+		return nil, grpcutil.EncodeError(resp.Err)
+	}
+	return &pb.BookReservationReply{Reservation: reservationToPB(*resp.Reservation)}, nil
+}
+
+func decodeGRPCDiscardReservationRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.DiscardReservationRequest)
+	return discardReservationRequest{ReservationID: int(req.ReservationId)}, nil
+}
+
+func encodeGRPCDiscardReservationResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(discardReservationResponse)
+	if resp.Err != nil {
+		return nil, grpcutil.EncodeError(resp.Err)
+	}
+	return &pb.DiscardReservationReply{}, nil
+}
+
+func decodeGRPCEditReservationRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.EditReservationRequest)
+	return editReservationRequest{
+		ReservationID: int(req.ReservationId),
+		Reservation:   reservationFromPB(req.Reservation),
+	}, nil
+}
+
+func encodeGRPCEditReservationResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(editReservationResponse)
+	if resp.Err != nil {
+		return nil, grpcutil.EncodeError(resp.Err)
+	}
+	return &pb.EditReservationReply{Reservation: reservationToPB(resp.Reservation)}, nil
+}
+
+func decodeGRPCGetReservationByIDRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.GetReservationByIDRequest)
+	return getReservationByIDRequest{ReservationID: int(req.ReservationId)}, nil
+}
+
+func encodeGRPCGetReservationByIDResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(getReservationByIDResponse)
+	if resp.Err != nil {
+		return nil, grpcutil.EncodeError(resp.Err)
+	}
+	return &pb.GetReservationByIDReply{Reservation: reservationToPB(resp.Reservation)}, nil
+}
+
+func decodeGRPCGetReservationHistoryPerCustomerRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.GetReservationHistoryPerCustomerRequest)
+	return getReservationHistoryPerCustomerRequest{
+		CustomerID: int(req.CustomerId),
+		Limit:      uint(req.Limit),
+		Offset:     uint(req.Offset),
+	}, nil
+}
+
+func encodeGRPCGetReservationHistoryPerCustomerResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(getReservationHistoryPerCustomerResponse)
+	if resp.Err != nil {
+		return nil, grpcutil.EncodeError(resp.Err)
+	}
+	rep := &pb.GetReservationHistoryPerCustomerReply{}
+	for _, r := range resp.Reservations {
+		rep.Reservations = append(rep.Reservations, reservationToPB(r))
+	}
+	return rep, nil
+}
+
+func reservationFromPB(r *pb.Reservation) *Reservation {
+	if r == nil {
+		return &Reservation{}
+	}
+	return &Reservation{
+		ReservationID:     int(r.ReservationId),
+		SeatCount:         int(r.SeatCount),
+		StartTime:         r.StartTime,
+		ReservationName:   r.ReservationName,
+		CustomerID:        int(r.CustomerId),
+		Phone:             r.Phone,
+		Comments:          r.Comments,
+		AreaID:            int(r.AreaId),
+		TableID:           int(r.TableId),
+		CombinationID:     int(r.CombinationId),
+		NeedsReassignment: r.NeedsReassignment,
+		Status:            r.Status,
+		HoldToken:         r.HoldToken,
+		SeriesID:          int(r.SeriesId),
+		Occurrence:        r.Occurrence,
+		Fee:               r.Fee,
+		Deposit:           r.Deposit,
+		PayBy:             r.PayBy,
+		StartsAt:          r.StartsAt,
+		EndsAt:            r.EndsAt,
+		VenueID:           int(r.VenueId),
+		Created:           r.Created,
+		LastUpdated:       r.LastUpdated,
+	}
+}
+
+func reservationToPB(r Reservation) *pb.Reservation {
+	return &pb.Reservation{
+		ReservationId:     int64(r.ReservationID),
+		SeatCount:         int64(r.SeatCount),
+		StartTime:         r.StartTime,
+		ReservationName:   r.ReservationName,
+		CustomerId:        int64(r.CustomerID),
+		Phone:             r.Phone,
+		Comments:          r.Comments,
+		AreaId:            int64(r.AreaID),
+		TableId:           int64(r.TableID),
+		CombinationId:     int64(r.CombinationID),
+		NeedsReassignment: r.NeedsReassignment,
+		Status:            r.Status,
+		HoldToken:         r.HoldToken,
+		SeriesId:          int64(r.SeriesID),
+		Occurrence:        r.Occurrence,
+		Fee:               r.Fee,
+		Deposit:           r.Deposit,
+		PayBy:             r.PayBy,
+		StartsAt:          r.StartsAt,
+		EndsAt:            r.EndsAt,
+		VenueId:           int64(r.VenueID),
+		Created:           r.Created,
+		LastUpdated:       r.LastUpdated,
+	}
+}
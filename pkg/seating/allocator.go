@@ -0,0 +1,243 @@
+package seating
+
+import (
+	"github.com/doug-martin/goqu/v7"
+	"github.com/doug-martin/goqu/v7/exp"
+	errors "reservations/pkg/error"
+	"reservations/pkg/storage"
+	"time"
+)
+
+// Allocation is what a booking holds for its duration: a single table or a
+// combination of tables pushed together.
+type Allocation struct {
+	TableID       int
+	CombinationID int
+	// AreaID is the area the booking is seated in, the one of the first
+	// table of a combination spanning several.
+	AreaID int
+}
+
+type fromFunc func(...interface{}) *goqu.Dataset
+
+// held selects the reservations other than the one of s holding the table
+// tid during s, either alone or as part of a combination. Parties that have
+// already left no longer hold their table.
+func held(from fromFunc, vID int, s Slot, tid exp.IdentifierExpression) *goqu.Dataset {
+	inCombination := from(goqu.T("combination_table").As("held_member")).Select(goqu.L("1")).Where(
+		goqu.I("held_member.combination_id").Eq(goqu.I("reservation.combination_id")),
+		goqu.I("held_member.table_id").Eq(tid),
+	)
+
+	return from("reservation").Select(goqu.L("1")).Where(
+		goqu.I("reservation.venue_id").Eq(vID),
+		goqu.I("reservation.rid").Neq(s.ReservationID),
+		goqu.I("reservation.status").Neq(StatusCompleted),
+		goqu.I("reservation.starts_at").Lt(s.EndsAt),
+		goqu.I("reservation.ends_at").Gt(s.StartsAt),
+		goqu.Or(
+			goqu.I("reservation.table_id").Eq(tid),
+			goqu.L("EXISTS ?", inCombination),
+		),
+	)
+}
+
+// onHold selects the unexpired holds on the table tid during s, either alone
+// or as part of a combination.
+func onHold(from fromFunc, vID int, s Slot, tid exp.IdentifierExpression) *goqu.Dataset {
+	inCombination := from(goqu.T("combination_table").As("hold_member")).Select(goqu.L("1")).Where(
+		goqu.I("hold_member.combination_id").Eq(goqu.I("slot_hold.combination_id")),
+		goqu.I("hold_member.table_id").Eq(tid),
+	)
+
+	return from("slot_hold").Select(goqu.L("1")).Where(
+		goqu.I("slot_hold.venue_id").Eq(vID),
+		goqu.I("slot_hold.expires_at").Gt(time.Now().Unix()),
+		goqu.I("slot_hold.starts_at").Lt(s.EndsAt),
+		goqu.I("slot_hold.ends_at").Gt(s.StartsAt),
+		goqu.Or(
+			goqu.I("slot_hold.table_id").Eq(tid),
+			goqu.L("EXISTS ?", inCombination),
+		),
+	)
+}
+
+// freeTables selects the tables of open areas seating the party of s that
+// no other reservation nor hold takes during s, smallest first.
+func freeTables(from fromFunc, vID int, s Slot) *goqu.Dataset {
+	where := goqu.Ex{
+		"dining_table.venue_id": vID,
+		"dining_table.capacity": goqu.Op{"gte": s.Seats},
+		"area.closed":           false,
+	}
+	if s.AreaID != 0 {
+		where["dining_table.area_id"] = s.AreaID
+	}
+
+	return from("dining_table").
+		Select("dining_table.*").
+		Join(goqu.T("area"), goqu.On(goqu.Ex{"dining_table.area_id": goqu.I("area.aid")})).
+		Where(
+			where,
+			goqu.L("NOT EXISTS ?", held(from, vID, s, goqu.I("dining_table.tid"))),
+			goqu.L("NOT EXISTS ?", onHold(from, vID, s, goqu.I("dining_table.tid"))),
+		).
+		Order(goqu.I("dining_table.capacity").Asc(), goqu.I("dining_table.tid").Asc())
+}
+
+// freeCombinations selects the combinations seating the party of s whose
+// tables are all in open areas and taken by no other reservation nor hold
+// during s, smallest first.
+func freeCombinations(from fromFunc, vID int, s Slot) *goqu.Dataset {
+	unavailable := []exp.Expression{
+		goqu.I("area.closed").IsTrue(),
+		goqu.L("EXISTS ?", held(from, vID, s, goqu.I("dining_table.tid"))),
+		goqu.L("EXISTS ?", onHold(from, vID, s, goqu.I("dining_table.tid"))),
+	}
+	if s.AreaID != 0 {
+		unavailable = append(unavailable, goqu.I("dining_table.area_id").Neq(s.AreaID))
+	}
+
+	blocked := from(goqu.T("combination_table").As("member")).Select(goqu.L("1")).
+		Join(goqu.T("dining_table"), goqu.On(goqu.Ex{"dining_table.tid": goqu.I("member.table_id")})).
+		Join(goqu.T("area"), goqu.On(goqu.Ex{"area.aid": goqu.I("dining_table.area_id")})).
+		Where(
+			goqu.I("member.combination_id").Eq(goqu.I("table_combination.coid")),
+			goqu.Or(unavailable...),
+		)
+
+	return from("table_combination").
+		Select("table_combination.*").
+		Where(
+			goqu.I("table_combination.venue_id").Eq(vID),
+			goqu.I("table_combination.capacity").Gte(s.Seats),
+			goqu.L("NOT EXISTS ?", blocked),
+		).
+		Order(goqu.I("table_combination.capacity").Asc(), goqu.I("table_combination.coid").Asc())
+}
+
+// Allocate picks what the booking described by s holds within tx, in which
+// the booking is then stored. The tables of the venue are locked until tx
+// ends, see lock, so that no other booking can take the same tables
+// meanwhile. The smallest free table seating the party is preferred
+// over a combination, which is only used for parties no single table fits.
+// It returns false if the venue has no tables at all, in which case bookings
+// are taken without seating them, and a Conflict if nothing is free. A slot
+// with a HoldToken takes what the hold kept instead.
+func Allocate(tx *goqu.TxDatabase, vID int, s Slot) (a Allocation, ok bool, err error) {
+	if err := lock(tx, vID); err != nil {
+		return a, false, err
+	}
+	if s.HoldToken != "" {
+		return redeem(tx, vID, s)
+	}
+
+	if s.AreaID != 0 {
+		var area Area
+		found, err := tx.From("area").Where(goqu.Ex{"aid": s.AreaID, "venue_id": vID}).ScanStruct(&area)
+		if err != nil {
+			return a, false, err
+		}
+		if !found {
+			return a, false, errors.NotFound.Newf("area with ID %d not found", s.AreaID).
+				AddContext("AreaID", "non existent ID")
+		}
+		if area.Closed {
+			return a, false, errors.Conflict.Newf("area %s is closed", area.Name).
+				AddContext("AreaID", area.ClosedReason)
+		}
+	} else {
+		n, err := tx.From("dining_table").Where(goqu.Ex{"venue_id": vID}).Count()
+		if err != nil || n == 0 {
+			return a, false, err
+		}
+	}
+
+	var t Table
+	found, err := freeTables(tx.From, vID, s).Limit(1).ScanStruct(&t)
+	if err != nil {
+		return a, false, err
+	}
+	if found {
+		return Allocation{TableID: t.TableID, AreaID: t.AreaID}, true, nil
+	}
+
+	var c Combination
+	found, err = freeCombinations(tx.From, vID, s).Limit(1).ScanStruct(&c)
+	if err != nil {
+		return a, false, err
+	}
+	if !found {
+		return a, false, errors.Conflict.Newf("no table for %d free at that time", s.Seats).
+			AddContext("startTime", "fully booked")
+	}
+
+	a = Allocation{CombinationID: c.CombinationID, AreaID: s.AreaID}
+	if a.AreaID == 0 {
+		_, err = tx.From("dining_table").Select("dining_table.area_id").
+			Join(goqu.T("combination_table"), goqu.On(goqu.Ex{"combination_table.table_id": goqu.I("dining_table.tid")})).
+			Where(goqu.Ex{"combination_table.combination_id": c.CombinationID}).
+			Order(goqu.I("dining_table.tid").Asc()).
+			Limit(1).
+			ScanVal(&a.AreaID)
+	}
+	return a, true, err
+}
+
+// lock keeps the tables of the venue vID from being allocated by other
+// transactions until tx ends, waiting for those allocating them meanwhile.
+// PostgreSQL locks the rows of the tables, in order so that allocations do not
+// deadlock, after which the statements of tx see what the others stored.
+// SQLite transactions hold the write lock from their start instead, see
+// storage.NewDB.
+func lock(tx *goqu.TxDatabase, vID int) error {
+	if tx.Dialect() != storage.Postgres {
+		return nil
+	}
+	var tids []int
+	return tx.From("dining_table").Select("tid").
+		Where(goqu.Ex{"venue_id": vID}).
+		Order(goqu.C("tid").Asc()).
+		ForUpdate(exp.Wait).
+		ScanVals(&tids)
+}
+
+// redeem releases the hold of s and returns what it kept, once checked that
+// the hold covers s. A hold taken while the venue had no tables keeps
+// nothing, in which case the booking is allocated as usual.
+func redeem(tx *goqu.TxDatabase, vID int, s Slot) (a Allocation, ok bool, err error) {
+	var h Hold
+	found, err := tx.From("slot_hold").Where(goqu.Ex{"token": s.HoldToken, "venue_id": vID}).ScanStruct(&h)
+	if err != nil {
+		return a, false, err
+	}
+	if !found {
+		return a, false, errors.NotFound.New("hold not found").
+			AddContext("holdToken", "non existent hold")
+	}
+	if h.ExpiresAt <= time.Now().Unix() {
+		return a, false, errors.Conflict.New("hold expired").
+			AddContext("holdToken", "expired, take a new hold")
+	}
+	if s.StartsAt != h.StartsAt || s.EndsAt > h.EndsAt {
+		return a, false, errors.ValidationError.New("booking time does not match the hold").
+			AddContext("startTime", "must be the start time of the hold")
+	}
+	if s.Seats > h.Seats {
+		return a, false, errors.ValidationError.Newf("the hold is for %d guests", h.Seats).
+			AddContext("seatCount", "must not exceed the seats of the hold")
+	}
+	if s.AreaID != 0 && s.AreaID != h.AreaID {
+		return a, false, errors.ValidationError.New("booking area does not match the hold").
+			AddContext("areaId", "must be the area of the hold")
+	}
+
+	if _, err := tx.From("slot_hold").Where(goqu.Ex{"hid": h.HoldID}).Delete().Exec(); err != nil {
+		return a, false, err
+	}
+	if h.TableID == 0 && h.CombinationID == 0 {
+		s.HoldToken = ""
+		return Allocate(tx, vID, s)
+	}
+	return Allocation{TableID: h.TableID, CombinationID: h.CombinationID, AreaID: h.AreaID}, true, nil
+}
@@ -0,0 +1,595 @@
+package seating
+
+import (
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"reservations/pkg/authz"
+)
+
+type Endpoints struct {
+	CreateAreaEndpoint         endpoint.Endpoint
+	RemoveAreaEndpoint         endpoint.Endpoint
+	CloseAreaEndpoint          endpoint.Endpoint
+	OpenAreaEndpoint           endpoint.Endpoint
+	GetAllAreasEndpoint        endpoint.Endpoint
+	CreateTableEndpoint        endpoint.Endpoint
+	UpdateTableEndpoint        endpoint.Endpoint
+	RemoveTableEndpoint        endpoint.Endpoint
+	GetTablesEndpoint          endpoint.Endpoint
+	GetFloorEndpoint           endpoint.Endpoint
+	SetTableStateEndpoint      endpoint.Endpoint
+	CreateCombinationEndpoint  endpoint.Endpoint
+	RemoveCombinationEndpoint  endpoint.Endpoint
+	GetAllCombinationsEndpoint endpoint.Endpoint
+	SearchAvailabilityEndpoint endpoint.Endpoint
+	CreateHoldEndpoint         endpoint.Endpoint
+	ReleaseHoldEndpoint        endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns the service endpoints, each checked against
+// the policy by az and wrapped in the given middlewares, the first one
+// outermost.
+func MakeServerEndpoints(s Service, az authz.Authorizer, mw ...endpoint.Middleware) Endpoints {
+	e := Endpoints{
+		CreateAreaEndpoint:         az.Require(authz.PermFloorManage, nil)(MakeCreateAreaEndpoint(s)),
+		RemoveAreaEndpoint:         az.Require(authz.PermFloorManage, nil)(MakeRemoveAreaEndpoint(s)),
+		CloseAreaEndpoint:          az.Require(authz.PermAreaClose, nil)(MakeCloseAreaEndpoint(s)),
+		OpenAreaEndpoint:           az.Require(authz.PermAreaClose, nil)(MakeOpenAreaEndpoint(s)),
+		GetAllAreasEndpoint:        az.Require(authz.PermFloorRead, nil)(MakeGetAllAreasEndpoint(s)),
+		CreateTableEndpoint:        az.Require(authz.PermFloorManage, nil)(MakeCreateTableEndpoint(s)),
+		UpdateTableEndpoint:        az.Require(authz.PermFloorManage, nil)(MakeUpdateTableEndpoint(s)),
+		RemoveTableEndpoint:        az.Require(authz.PermFloorManage, nil)(MakeRemoveTableEndpoint(s)),
+		GetTablesEndpoint:          az.Require(authz.PermFloorRead, nil)(MakeGetTablesEndpoint(s)),
+		GetFloorEndpoint:           az.Require(authz.PermFloorHost, nil)(MakeGetFloorEndpoint(s)),
+		SetTableStateEndpoint:      az.Require(authz.PermFloorHost, nil)(MakeSetTableStateEndpoint(s)),
+		CreateCombinationEndpoint:  az.Require(authz.PermFloorManage, nil)(MakeCreateCombinationEndpoint(s)),
+		RemoveCombinationEndpoint:  az.Require(authz.PermFloorManage, nil)(MakeRemoveCombinationEndpoint(s)),
+		GetAllCombinationsEndpoint: az.Require(authz.PermFloorRead, nil)(MakeGetAllCombinationsEndpoint(s)),
+		SearchAvailabilityEndpoint: az.Require(authz.PermFloorRead, nil)(MakeSearchAvailabilityEndpoint(s)),
+		CreateHoldEndpoint:         az.Require(authz.PermHoldWrite, nil)(MakeCreateHoldEndpoint(s)),
+		ReleaseHoldEndpoint:        az.Require(authz.PermHoldWrite, nil)(MakeReleaseHoldEndpoint(s)),
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		m := mw[i]
+		e.CreateAreaEndpoint = m(e.CreateAreaEndpoint)
+		e.RemoveAreaEndpoint = m(e.RemoveAreaEndpoint)
+		e.CloseAreaEndpoint = m(e.CloseAreaEndpoint)
+		e.OpenAreaEndpoint = m(e.OpenAreaEndpoint)
+		e.GetAllAreasEndpoint = m(e.GetAllAreasEndpoint)
+		e.CreateTableEndpoint = m(e.CreateTableEndpoint)
+		e.UpdateTableEndpoint = m(e.UpdateTableEndpoint)
+		e.RemoveTableEndpoint = m(e.RemoveTableEndpoint)
+		e.GetTablesEndpoint = m(e.GetTablesEndpoint)
+		e.GetFloorEndpoint = m(e.GetFloorEndpoint)
+		e.SetTableStateEndpoint = m(e.SetTableStateEndpoint)
+		e.CreateCombinationEndpoint = m(e.CreateCombinationEndpoint)
+		e.RemoveCombinationEndpoint = m(e.RemoveCombinationEndpoint)
+		e.GetAllCombinationsEndpoint = m(e.GetAllCombinationsEndpoint)
+		e.SearchAvailabilityEndpoint = m(e.SearchAvailabilityEndpoint)
+		e.CreateHoldEndpoint = m(e.CreateHoldEndpoint)
+		e.ReleaseHoldEndpoint = m(e.ReleaseHoldEndpoint)
+	}
+	return e
+}
+
+type createAreaRequest struct {
+	Area *Area
+}
+
+type createAreaResponse struct {
+	Area *Area `json:"area,omitempty"`
+	Err  error `json:"err,omitempty"`
+}
+
+func (r createAreaResponse) HTTPError() error { return r.Err }
+
+// CreateArea godoc
+// @Summary Create a seating area
+// @Description Create a seating area such as a terrace, to which tables are then added
+// @Tags seating
+// @Param area body seating.Area true "New Area"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} seating.Area
+// @Router /area [post]
+func MakeCreateAreaEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(createAreaRequest)
+		a, e := s.CreateArea(ctx, req.Area)
+		return createAreaResponse{
+			Area: a,
+			Err:  e,
+		}, nil
+	}
+}
+
+type removeAreaRequest struct {
+	AreaID int
+}
+
+type removeAreaResponse struct {
+	Err error `json:"err,omitempty"`
+}
+
+func (r removeAreaResponse) HTTPError() error { return r.Err }
+
+// RemoveArea godoc
+// @Summary Remove a seating area
+// @Description Remove a seating area, which must have no tables left
+// @Tags seating
+// @Param id path string true "Area ID"
+// @Accept  json
+// @Produce  json
+// @Router /area/{id} [delete]
+func MakeRemoveAreaEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(removeAreaRequest)
+		e := s.RemoveArea(ctx, req.AreaID)
+		return removeAreaResponse{
+			Err: e,
+		}, nil
+	}
+}
+
+type closeAreaRequest struct {
+	AreaID int
+	Reason string
+}
+
+type closeAreaResponse struct {
+	Closure Closure `json:"closure"`
+	Err     error   `json:"err,omitempty"`
+}
+
+func (r closeAreaResponse) HTTPError() error { return r.Err }
+
+// CloseArea godoc
+// @Summary Close a seating area
+// @Description Close a seating area, e.g. the terrace in rain. No tables are allocated in it until it is opened again and upcoming reservations seated in it are flagged for reassignment.
+// @Tags seating
+// @Param id path string true "Area ID"
+// @Param reason query string false "Reason for closing"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} seating.Closure
+// @Router /area/{id}/close [post]
+func MakeCloseAreaEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(closeAreaRequest)
+		c, e := s.CloseArea(ctx, req.AreaID, req.Reason)
+		return closeAreaResponse{
+			Closure: c,
+			Err:     e,
+		}, nil
+	}
+}
+
+type openAreaRequest struct {
+	AreaID int
+}
+
+type openAreaResponse struct {
+	Area Area  `json:"area"`
+	Err  error `json:"err,omitempty"`
+}
+
+func (r openAreaResponse) HTTPError() error { return r.Err }
+
+// OpenArea godoc
+// @Summary Open a closed seating area
+// @Description Open a closed seating area again, clearing the reassignment flag of the reservations seated in it
+// @Tags seating
+// @Param id path string true "Area ID"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} seating.Area
+// @Router /area/{id}/open [post]
+func MakeOpenAreaEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(openAreaRequest)
+		a, e := s.OpenArea(ctx, req.AreaID)
+		return openAreaResponse{
+			Area: a,
+			Err:  e,
+		}, nil
+	}
+}
+
+type getAllAreasRequest struct{}
+
+type getAllAreasResponse struct {
+	Areas []Area `json:"areas,omitempty"`
+	Err   error  `json:"err,omitempty"`
+}
+
+func (r getAllAreasResponse) HTTPError() error { return r.Err }
+
+// GetAllAreas godoc
+// @Summary List seating areas
+// @Description List the seating areas of the venue
+// @Tags seating
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} seating.Area
+// @Router /areas [get]
+func MakeGetAllAreasEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		aa, e := s.GetAllAreas(ctx)
+		return getAllAreasResponse{
+			Areas: aa,
+			Err:   e,
+		}, nil
+	}
+}
+
+type createTableRequest struct {
+	Table *Table
+}
+
+type createTableResponse struct {
+	Table *Table `json:"table,omitempty"`
+	Err   error  `json:"err,omitempty"`
+}
+
+func (r createTableResponse) HTTPError() error { return r.Err }
+
+// CreateTable godoc
+// @Summary Add a table to a seating area
+// @Description Add a table to a seating area
+// @Tags seating
+// @Param table body seating.Table true "New Table"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} seating.Table
+// @Router /table [post]
+func MakeCreateTableEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(createTableRequest)
+		t, e := s.CreateTable(ctx, req.Table)
+		return createTableResponse{
+			Table: t,
+			Err:   e,
+		}, nil
+	}
+}
+
+type updateTableRequest struct {
+	TableID int
+	Table   *Table
+}
+
+type updateTableResponse struct {
+	Table Table `json:"table,omitempty"`
+	Err   error `json:"err,omitempty"`
+}
+
+func (r updateTableResponse) HTTPError() error { return r.Err }
+
+// UpdateTable godoc
+// @Summary Update a table
+// @Description Update a table, including its place on the floor plan
+// @Tags seating
+// @Param id path string true "Table ID"
+// @Param table body seating.Table true "Updated Table"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} seating.Table
+// @Router /table/{id} [put]
+func MakeUpdateTableEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(updateTableRequest)
+		t, e := s.UpdateTable(ctx, req.TableID, req.Table)
+		return updateTableResponse{
+			Table: t,
+			Err:   e,
+		}, nil
+	}
+}
+
+type removeTableRequest struct {
+	TableID int
+}
+
+type removeTableResponse struct {
+	Err error `json:"err,omitempty"`
+}
+
+func (r removeTableResponse) HTTPError() error { return r.Err }
+
+// RemoveTable godoc
+// @Summary Remove a table
+// @Description Remove a table, flagging the reservations seated at it for reassignment
+// @Tags seating
+// @Param id path string true "Table ID"
+// @Accept  json
+// @Produce  json
+// @Router /table/{id} [delete]
+func MakeRemoveTableEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(removeTableRequest)
+		e := s.RemoveTable(ctx, req.TableID)
+		return removeTableResponse{
+			Err: e,
+		}, nil
+	}
+}
+
+type getTablesRequest struct {
+	AreaID int
+}
+
+type getTablesResponse struct {
+	Tables []Table `json:"tables,omitempty"`
+	Err    error   `json:"err,omitempty"`
+}
+
+func (r getTablesResponse) HTTPError() error { return r.Err }
+
+// GetTables godoc
+// @Summary List tables
+// @Description List the tables of the venue or of one of its areas
+// @Tags seating
+// @Param area query int false "Area ID"
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} seating.Table
+// @Router /tables [get]
+func MakeGetTablesEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getTablesRequest)
+		tt, e := s.GetTables(ctx, req.AreaID)
+		return getTablesResponse{
+			Tables: tt,
+			Err:    e,
+		}, nil
+	}
+}
+
+type getFloorRequest struct {
+	AreaID int
+}
+
+type getFloorResponse struct {
+	Floor Floor `json:"floor"`
+	Err   error `json:"err,omitempty"`
+}
+
+func (r getFloorResponse) HTTPError() error { return r.Err }
+
+// GetFloor godoc
+// @Summary Show the floor plan
+// @Description Show the areas and tables of the venue, or of one of its areas, with the live state of each table: free, reserved for a booking starting soon, seated or needing cleaning
+// @Tags seating
+// @Param area query int false "Area ID"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} seating.Floor
+// @Router /floor [get]
+func MakeGetFloorEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getFloorRequest)
+		f, e := s.GetFloor(ctx, req.AreaID)
+		return getFloorResponse{
+			Floor: f,
+			Err:   e,
+		}, nil
+	}
+}
+
+type setTableStateRequest struct {
+	TableID       int        `json:"-"`
+	State         TableState `json:"state"`
+	ReservationID int        `json:"reservationId"`
+}
+
+type setTableStateResponse struct {
+	Table FloorTable `json:"table"`
+	Err   error      `json:"err,omitempty"`
+}
+
+func (r setTableStateResponse) HTTPError() error { return r.Err }
+
+// SetTableState godoc
+// @Summary Update the state of a table
+// @Description Seat a reservation at a table, moving it there if booked elsewhere, or clear a table once its party leaves, marking it as needing cleaning or free
+// @Tags seating
+// @Param id path string true "Table ID"
+// @Param state body seating.setTableStateRequest true "New state, and the reservation seated for state seated"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} seating.FloorTable
+// @Router /table/{id}/state [put]
+func MakeSetTableStateEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(setTableStateRequest)
+		t, e := s.SetTableState(ctx, req.TableID, req.State, req.ReservationID)
+		return setTableStateResponse{
+			Table: t,
+			Err:   e,
+		}, nil
+	}
+}
+
+type createCombinationRequest struct {
+	Combination *Combination
+}
+
+type createCombinationResponse struct {
+	Combination *Combination `json:"combination,omitempty"`
+	Err         error        `json:"err,omitempty"`
+}
+
+func (r createCombinationResponse) HTTPError() error { return r.Err }
+
+// CreateCombination godoc
+// @Summary Define a table combination
+// @Description Define tables which may be pushed together for large parties. The capacity defaults to the sum of the tables' capacities.
+// @Tags seating
+// @Param combination body seating.Combination true "New Combination"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} seating.Combination
+// @Router /combination [post]
+func MakeCreateCombinationEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(createCombinationRequest)
+		c, e := s.CreateCombination(ctx, req.Combination)
+		return createCombinationResponse{
+			Combination: c,
+			Err:         e,
+		}, nil
+	}
+}
+
+type removeCombinationRequest struct {
+	CombinationID int
+}
+
+type removeCombinationResponse struct {
+	Err error `json:"err,omitempty"`
+}
+
+func (r removeCombinationResponse) HTTPError() error { return r.Err }
+
+// RemoveCombination godoc
+// @Summary Remove a table combination
+// @Description Remove a table combination, flagging the reservations holding it for reassignment
+// @Tags seating
+// @Param id path string true "Combination ID"
+// @Accept  json
+// @Produce  json
+// @Router /combination/{id} [delete]
+func MakeRemoveCombinationEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(removeCombinationRequest)
+		e := s.RemoveCombination(ctx, req.CombinationID)
+		return removeCombinationResponse{
+			Err: e,
+		}, nil
+	}
+}
+
+type getAllCombinationsRequest struct{}
+
+type getAllCombinationsResponse struct {
+	Combinations []Combination `json:"combinations,omitempty"`
+	Err          error         `json:"err,omitempty"`
+}
+
+func (r getAllCombinationsResponse) HTTPError() error { return r.Err }
+
+// GetAllCombinations godoc
+// @Summary List table combinations
+// @Description List the table combinations of the venue
+// @Tags seating
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} seating.Combination
+// @Router /combinations [get]
+func MakeGetAllCombinationsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		cc, e := s.GetAllCombinations(ctx)
+		return getAllCombinationsResponse{
+			Combinations: cc,
+			Err:          e,
+		}, nil
+	}
+}
+
+type searchAvailabilityRequest struct {
+	Slot Slot
+}
+
+type searchAvailabilityResponse struct {
+	Availability Availability `json:"availability"`
+	Err          error        `json:"err,omitempty"`
+}
+
+func (r searchAvailabilityResponse) HTTPError() error { return r.Err }
+
+// SearchAvailability godoc
+// @Summary Search free tables
+// @Description List the tables free for a party at a given time, smallest first, optionally in one area, along with the free table combinations seating the party
+// @Tags seating
+// @Param start query string true "Start time (RFC 3339)"
+// @Param seats query int true "Party size"
+// @Param area query int false "Area ID"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} seating.Availability
+// @Router /availability [get]
+func MakeSearchAvailabilityEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(searchAvailabilityRequest)
+		a, e := s.SearchAvailability(ctx, req.Slot)
+		if a.Tables == nil {
+			a.Tables = []Table{}
+		}
+		if a.Combinations == nil {
+			a.Combinations = []Combination{}
+		}
+		return searchAvailabilityResponse{
+			Availability: a,
+			Err:          e,
+		}, nil
+	}
+}
+
+type createHoldRequest struct {
+	Hold *Hold
+}
+
+type createHoldResponse struct {
+	Hold *Hold `json:"hold,omitempty"`
+	Err  error `json:"err,omitempty"`
+}
+
+func (r createHoldResponse) HTTPError() error { return r.Err }
+
+// CreateHold godoc
+// @Summary Hold a table during checkout
+// @Description Keep a table or combination for a party at a given time while the guest completes the booking. The hold expires after a while unless redeemed by booking with its token. Guests may only keep a few holds at once.
+// @Tags seating
+// @Param hold body seating.Hold true "Party and start time to hold"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} seating.Hold
+// @Router /holds [post]
+func MakeCreateHoldEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(createHoldRequest)
+		h, e := s.CreateHold(ctx, req.Hold)
+		return createHoldResponse{
+			Hold: h,
+			Err:  e,
+		}, nil
+	}
+}
+
+type releaseHoldRequest struct {
+	Token string
+}
+
+type releaseHoldResponse struct {
+	Err error `json:"err,omitempty"`
+}
+
+func (r releaseHoldResponse) HTTPError() error { return r.Err }
+
+// ReleaseHold godoc
+// @Summary Release a hold
+// @Description Release a hold before it expires, for instance when the guest abandons the checkout
+// @Tags seating
+// @Param token path string true "Hold token"
+// @Accept  json
+// @Produce  json
+// @Router /holds/{token} [delete]
+func MakeReleaseHoldEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(releaseHoldRequest)
+		e := s.ReleaseHold(ctx, req.Token)
+		return releaseHoldResponse{
+			Err: e,
+		}, nil
+	}
+}
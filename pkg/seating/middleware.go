@@ -0,0 +1,257 @@
+package seating
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"reservations/pkg/tracing"
+	"time"
+)
+
+// Middleware describes a service (as opposed to endpoint) middleware.
+type Middleware func(Service) Service
+
+func LoggingMiddleware(logger log.Logger) Middleware {
+	return func(next Service) Service {
+		return &loggingMiddleware{
+			next:   next,
+			logger: logger,
+		}
+	}
+}
+
+type loggingMiddleware struct {
+	next   Service
+	logger log.Logger
+}
+
+func (mw loggingMiddleware) CreateArea(ctx context.Context, a *Area) (result *Area, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "CreateArea", "name", a.Name, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.CreateArea(ctx, a)
+}
+
+func (mw loggingMiddleware) RemoveArea(ctx context.Context, aID int) (err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "RemoveArea", "id", aID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.RemoveArea(ctx, aID)
+}
+
+func (mw loggingMiddleware) CloseArea(ctx context.Context, aID int, reason string) (result Closure, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "CloseArea", "id", aID, "reason", reason, "flagged", len(result.Flagged), "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.CloseArea(ctx, aID, reason)
+}
+
+func (mw loggingMiddleware) OpenArea(ctx context.Context, aID int) (result Area, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "OpenArea", "id", aID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.OpenArea(ctx, aID)
+}
+
+func (mw loggingMiddleware) GetAllAreas(ctx context.Context) (result []Area, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "GetAllAreas", "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetAllAreas(ctx)
+}
+
+func (mw loggingMiddleware) CreateTable(ctx context.Context, t *Table) (result *Table, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "CreateTable", "name", t.Name, "area", t.AreaID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.CreateTable(ctx, t)
+}
+
+func (mw loggingMiddleware) RemoveTable(ctx context.Context, tID int) (err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "RemoveTable", "id", tID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.RemoveTable(ctx, tID)
+}
+
+func (mw loggingMiddleware) UpdateTable(ctx context.Context, tID int, t *Table) (result Table, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "UpdateTable", "id", tID, "area", t.AreaID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.UpdateTable(ctx, tID, t)
+}
+
+func (mw loggingMiddleware) GetTables(ctx context.Context, aID int) (result []Table, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "GetTables", "area", aID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetTables(ctx, aID)
+}
+
+func (mw loggingMiddleware) GetFloor(ctx context.Context, aID int) (result Floor, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "GetFloor", "area", aID, "tables", len(result.Tables), "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetFloor(ctx, aID)
+}
+
+func (mw loggingMiddleware) SetTableState(ctx context.Context, tID int, state TableState, rID int) (result FloorTable, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "SetTableState", "id", tID, "state", state, "reservation", rID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.SetTableState(ctx, tID, state, rID)
+}
+
+func (mw loggingMiddleware) CreateCombination(ctx context.Context, c *Combination) (result *Combination, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "CreateCombination", "name", c.Name, "tables", len(c.TableIDs), "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.CreateCombination(ctx, c)
+}
+
+func (mw loggingMiddleware) RemoveCombination(ctx context.Context, coID int) (err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "RemoveCombination", "id", coID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.RemoveCombination(ctx, coID)
+}
+
+func (mw loggingMiddleware) GetAllCombinations(ctx context.Context) (result []Combination, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "GetAllCombinations", "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetAllCombinations(ctx)
+}
+
+func (mw loggingMiddleware) SearchAvailability(ctx context.Context, s Slot) (result Availability, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "SearchAvailability", "seats", s.Seats, "area", s.AreaID, "tables", len(result.Tables), "combinations", len(result.Combinations), "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.SearchAvailability(ctx, s)
+}
+
+func (mw loggingMiddleware) CreateHold(ctx context.Context, h *Hold) (result *Hold, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "CreateHold", "seats", h.Seats, "start", h.StartTime, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.CreateHold(ctx, h)
+}
+
+func (mw loggingMiddleware) ReleaseHold(ctx context.Context, token string) (err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "ReleaseHold", "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.ReleaseHold(ctx, token)
+}
+
+// TracingMiddleware wraps every call in a span.
+func TracingMiddleware() Middleware {
+	return func(next Service) Service {
+		return &tracingMiddleware{next: next}
+	}
+}
+
+type tracingMiddleware struct {
+	next Service
+}
+
+func (mw tracingMiddleware) CreateArea(ctx context.Context, a *Area) (result *Area, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.CreateArea")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.CreateArea(ctx, a)
+}
+
+func (mw tracingMiddleware) RemoveArea(ctx context.Context, aID int) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.RemoveArea")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.RemoveArea(ctx, aID)
+}
+
+func (mw tracingMiddleware) CloseArea(ctx context.Context, aID int, reason string) (result Closure, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.CloseArea")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.CloseArea(ctx, aID, reason)
+}
+
+func (mw tracingMiddleware) OpenArea(ctx context.Context, aID int) (result Area, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.OpenArea")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.OpenArea(ctx, aID)
+}
+
+func (mw tracingMiddleware) GetAllAreas(ctx context.Context) (result []Area, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.GetAllAreas")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.GetAllAreas(ctx)
+}
+
+func (mw tracingMiddleware) CreateTable(ctx context.Context, t *Table) (result *Table, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.CreateTable")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.CreateTable(ctx, t)
+}
+
+func (mw tracingMiddleware) RemoveTable(ctx context.Context, tID int) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.RemoveTable")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.RemoveTable(ctx, tID)
+}
+
+func (mw tracingMiddleware) UpdateTable(ctx context.Context, tID int, t *Table) (result Table, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.UpdateTable")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.UpdateTable(ctx, tID, t)
+}
+
+func (mw tracingMiddleware) GetTables(ctx context.Context, aID int) (result []Table, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.GetTables")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.GetTables(ctx, aID)
+}
+
+func (mw tracingMiddleware) GetFloor(ctx context.Context, aID int) (result Floor, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.GetFloor")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.GetFloor(ctx, aID)
+}
+
+func (mw tracingMiddleware) SetTableState(ctx context.Context, tID int, state TableState, rID int) (result FloorTable, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.SetTableState")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.SetTableState(ctx, tID, state, rID)
+}
+
+func (mw tracingMiddleware) CreateCombination(ctx context.Context, c *Combination) (result *Combination, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.CreateCombination")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.CreateCombination(ctx, c)
+}
+
+func (mw tracingMiddleware) RemoveCombination(ctx context.Context, coID int) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.RemoveCombination")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.RemoveCombination(ctx, coID)
+}
+
+func (mw tracingMiddleware) GetAllCombinations(ctx context.Context) (result []Combination, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.GetAllCombinations")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.GetAllCombinations(ctx)
+}
+
+func (mw tracingMiddleware) SearchAvailability(ctx context.Context, s Slot) (result Availability, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.SearchAvailability")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.SearchAvailability(ctx, s)
+}
+
+func (mw tracingMiddleware) CreateHold(ctx context.Context, h *Hold) (result *Hold, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.CreateHold")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.CreateHold(ctx, h)
+}
+
+func (mw tracingMiddleware) ReleaseHold(ctx context.Context, token string) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "seating.ReleaseHold")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.ReleaseHold(ctx, token)
+}
@@ -0,0 +1,779 @@
+package seating
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v7"
+	"github.com/doug-martin/goqu/v7/exec"
+	"github.com/doug-martin/goqu/v7/exp"
+	errors "reservations/pkg/error"
+	"reservations/pkg/event"
+	"reservations/pkg/outbox"
+	"reservations/pkg/storage"
+	"reservations/pkg/tenant"
+	"reservations/pkg/tracing"
+	"time"
+)
+
+type Repository interface {
+	AddArea(ctx context.Context, a *Area) (*Area, error)
+	RemoveArea(ctx context.Context, aID int) error
+	CloseArea(ctx context.Context, aID int, reason string, now int64) (Closure, error)
+	OpenArea(ctx context.Context, aID int, now int64) (Area, error)
+	FindAllAreas(ctx context.Context) ([]Area, error)
+	FindAreaByID(ctx context.Context, aID int) (Area, error)
+	AddTable(ctx context.Context, t *Table) (*Table, error)
+	UpdateTable(ctx context.Context, tID int, t *Table) (Table, error)
+	RemoveTable(ctx context.Context, tID int) error
+	FindTables(ctx context.Context, aID int) ([]Table, error)
+	FindOccupancies(ctx context.Context, from int64, until int64) ([]Occupancy, error)
+	SetTableState(ctx context.Context, tID int, state TableState, rID int, now int64) error
+	FindFreeTables(ctx context.Context, s Slot) ([]Table, error)
+	AddCombination(ctx context.Context, c *Combination) (*Combination, error)
+	RemoveCombination(ctx context.Context, coID int) error
+	FindAllCombinations(ctx context.Context) ([]Combination, error)
+	FindFreeCombinations(ctx context.Context, s Slot) ([]Combination, error)
+	// AddHold keeps a table for h, failing with a Conflict if its holder
+	// already has limit unexpired holds, unless limit is 0.
+	AddHold(ctx context.Context, h *Hold, limit int) (*Hold, error)
+	RemoveHold(ctx context.Context, token string) error
+	// RemoveExpiredHolds deletes the holds of all venues expired by now.
+	RemoveExpiredHolds(ctx context.Context, now int64) (int64, error)
+}
+
+type seatingRepository struct {
+	db storage.Persistence
+}
+
+func NewSeatingRepository(db storage.Persistence) Repository {
+	return &seatingRepository{db: db}
+}
+
+func (r *seatingRepository) AddArea(ctx context.Context, a *Area) (_ *Area, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.AddArea", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	created := time.Now().Unix()
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		a.Created = created
+		a.LastUpdated = created
+		a.VenueID = tenant.VenueFromContext(ctx)
+		aID, err := storage.InsertReturningID(tx, "area", "aid", a)
+		a.AreaID = aID
+		return err
+	})
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error adding new area")
+	}
+	return a, nil
+}
+
+func (r *seatingRepository) RemoveArea(ctx context.Context, aID int) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.RemoveArea", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	_, err = r.db.Tx(func(tx *goqu.TxDatabase) exec.QueryExecutor {
+		return tx.From("area").Where(goqu.Ex{"aid": aID, "venue_id": tenant.VenueFromContext(ctx)}).Delete()
+	})
+
+	if err != nil {
+		return errors.DBError.Wrapf(err, "error deleting area with ID %d", aID)
+	}
+	return nil
+}
+
+func (r *seatingRepository) CloseArea(ctx context.Context, aID int, reason string, now int64) (c Closure, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.CloseArea", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	vID := tenant.VenueFromContext(ctx)
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		if err := setClosed(tx, vID, aID, true, reason, now); err != nil {
+			return err
+		}
+
+		// Reservations still to come lose their table, which is in the
+		// closed area, and are flagged until they are seated elsewhere.
+		upcoming := goqu.And(
+			goqu.Ex{"venue_id": vID, "ends_at": goqu.Op{"gt": now}},
+			seatedIn(tx.From, aID),
+		)
+		c.Flagged = []int{}
+		if err := tx.From("reservation").Select("rid").Where(upcoming).
+			Order(goqu.C("rid").Asc()).ScanVals(&c.Flagged); err != nil {
+			return err
+		}
+		if _, err := tx.From("reservation").Where(upcoming).Update(goqu.Record{
+			"needs_reassignment": true,
+			"last_updated":       now,
+		}).Exec(); err != nil {
+			return err
+		}
+
+		_, err := tx.From("area").Where(goqu.Ex{"aid": aID, "venue_id": vID}).ScanStruct(&c.Area)
+		return err
+	})
+
+	if errors.GetType(err) == errors.NotFound {
+		return c, err
+	}
+	if err != nil {
+		return c, errors.DBError.Wrapf(err, "error closing area with ID %d", aID)
+	}
+	return c, nil
+}
+
+func (r *seatingRepository) OpenArea(ctx context.Context, aID int, now int64) (a Area, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.OpenArea", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	vID := tenant.VenueFromContext(ctx)
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		if err := setClosed(tx, vID, aID, false, "", now); err != nil {
+			return err
+		}
+
+		// Bookings flagged by the closure may keep their tables again.
+		if _, err := tx.From("reservation").Where(
+			goqu.Ex{"venue_id": vID, "needs_reassignment": true},
+			seatedIn(tx.From, aID),
+		).Update(goqu.Record{
+			"needs_reassignment": false,
+			"last_updated":       now,
+		}).Exec(); err != nil {
+			return err
+		}
+
+		_, err := tx.From("area").Where(goqu.Ex{"aid": aID, "venue_id": vID}).ScanStruct(&a)
+		return err
+	})
+
+	if errors.GetType(err) == errors.NotFound {
+		return a, err
+	}
+	if err != nil {
+		return a, errors.DBError.Wrapf(err, "error opening area with ID %d", aID)
+	}
+	return a, nil
+}
+
+// seatedIn matches the reservations seated in the area aID, including
+// those holding a combination with a table there.
+func seatedIn(from fromFunc, aID int) exp.Expression {
+	return goqu.Or(
+		goqu.I("reservation.area_id").Eq(aID),
+		goqu.L("EXISTS ?", from("combination_table").Select(goqu.L("1")).
+			Join(goqu.T("dining_table"), goqu.On(goqu.Ex{"dining_table.tid": goqu.I("combination_table.table_id")})).
+			Where(
+				goqu.I("combination_table.combination_id").Eq(goqu.I("reservation.combination_id")),
+				goqu.I("dining_table.area_id").Eq(aID),
+			)),
+	)
+}
+
+func setClosed(tx *goqu.TxDatabase, vID int, aID int, closed bool, reason string, now int64) error {
+	res, err := tx.From("area").Where(goqu.Ex{"aid": aID, "venue_id": vID}).Update(goqu.Record{
+		"closed":        closed,
+		"closed_reason": reason,
+		"last_updated":  now,
+	}).Exec()
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return errors.NotFound.Newf("area with ID %d not found", aID).
+			AddContext("AreaID", "non existent ID")
+	}
+	return nil
+}
+
+func (r *seatingRepository) FindAllAreas(ctx context.Context) (aa []Area, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.FindAllAreas", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	err = r.db.DB.From("area").
+		Where(goqu.Ex{"venue_id": tenant.VenueFromContext(ctx)}).
+		Order(goqu.C("aid").Asc()).
+		ScanStructs(&aa)
+
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error getting all areas")
+	}
+	return aa, nil
+}
+
+func (r *seatingRepository) FindAreaByID(ctx context.Context, aID int) (a Area, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.FindAreaByID", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	found, err := r.db.DB.From("area").
+		Where(goqu.Ex{"aid": aID, "venue_id": tenant.VenueFromContext(ctx)}).
+		ScanStruct(&a)
+
+	if err != nil {
+		return a, errors.DBError.Wrapf(err, "error getting area with ID %d", aID)
+	}
+
+	if !found {
+		return a, errors.NotFound.Newf("area with ID %d not found", aID).
+			AddContext("AreaID", "non existent ID")
+	}
+
+	return a, nil
+}
+
+func (r *seatingRepository) AddTable(ctx context.Context, t *Table) (_ *Table, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.AddTable", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	created := time.Now().Unix()
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		t.Created = created
+		t.LastUpdated = created
+		t.VenueID = tenant.VenueFromContext(ctx)
+		tID, err := storage.InsertReturningID(tx, "dining_table", "tid", t)
+		t.TableID = tID
+		return err
+	})
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error adding new table")
+	}
+	return t, nil
+}
+
+func (r *seatingRepository) UpdateTable(ctx context.Context, tID int, t *Table) (result Table, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.UpdateTable", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	vID := tenant.VenueFromContext(ctx)
+	now := time.Now().Unix()
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		res, err := tx.From("dining_table").Where(goqu.Ex{"tid": tID, "venue_id": vID}).Update(goqu.Record{
+			"area_id":      t.AreaID,
+			"name":         t.Name,
+			"capacity":     t.Capacity,
+			"pos_x":        t.X,
+			"pos_y":        t.Y,
+			"width":        t.Width,
+			"height":       t.Height,
+			"shape":        t.Shape,
+			"rotation":     t.Rotation,
+			"last_updated": now,
+		}).Exec()
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return errors.NotFound.Newf("table with ID %d not found", tID).
+				AddContext("TableID", "non existent ID")
+		}
+
+		// Bookings seated at a table moved to another area follow it.
+		if _, err := tx.From("reservation").Where(goqu.Ex{"venue_id": vID, "table_id": tID}).Update(goqu.Record{
+			"area_id": t.AreaID,
+		}).Exec(); err != nil {
+			return err
+		}
+
+		_, err = tx.From("dining_table").Where(goqu.Ex{"tid": tID, "venue_id": vID}).ScanStruct(&result)
+		return err
+	})
+
+	if errors.GetType(err) == errors.NotFound {
+		return result, err
+	}
+	if err != nil {
+		return result, errors.DBError.Wrapf(err, "error updating table with ID %d", tID)
+	}
+	return result, nil
+}
+
+func (r *seatingRepository) RemoveTable(ctx context.Context, tID int) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.RemoveTable", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	vID := tenant.VenueFromContext(ctx)
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		// Bookings seated at the table have to be seated again.
+		if _, err := tx.From("reservation").Where(goqu.Ex{"venue_id": vID, "table_id": tID}).Update(goqu.Record{
+			"table_id":           0,
+			"needs_reassignment": true,
+			"last_updated":       time.Now().Unix(),
+		}).Exec(); err != nil {
+			return err
+		}
+		if _, err := tx.From("slot_hold").Where(goqu.Ex{"venue_id": vID, "table_id": tID}).Delete().Exec(); err != nil {
+			return err
+		}
+
+		_, err := tx.From("dining_table").Where(goqu.Ex{"tid": tID, "venue_id": vID}).Delete().Exec()
+		return err
+	})
+
+	if err != nil {
+		return errors.DBError.Wrapf(err, "error deleting table with ID %d", tID)
+	}
+	return nil
+}
+
+func (r *seatingRepository) FindTables(ctx context.Context, aID int) (tt []Table, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.FindTables", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	where := goqu.Ex{"venue_id": tenant.VenueFromContext(ctx)}
+	if aID != 0 {
+		where["area_id"] = aID
+	}
+
+	err = r.db.DB.From("dining_table").
+		Where(where).
+		Order(goqu.C("area_id").Asc(), goqu.C("tid").Asc()).
+		ScanStructs(&tt)
+
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error getting tables")
+	}
+	return tt, nil
+}
+
+// occupying matches the reservations not completed yet that are seated or
+// take place between from and until.
+func occupying(from int64, until int64) exp.Expression {
+	return goqu.And(
+		goqu.I("reservation.status").Neq(StatusCompleted),
+		goqu.Or(
+			goqu.I("reservation.status").Eq(StatusSeated),
+			goqu.And(
+				goqu.I("reservation.starts_at").Lt(until),
+				goqu.I("reservation.ends_at").Gt(from),
+			),
+		),
+	)
+}
+
+func (r *seatingRepository) FindOccupancies(ctx context.Context, from int64, until int64) (oo []Occupancy, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.FindOccupancies", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	vID := tenant.VenueFromContext(ctx)
+
+	err = r.db.DB.From("reservation").
+		Select("reservation.rid", "reservation.table_id", "reservation.status", "reservation.starts_at").
+		Where(
+			goqu.I("reservation.venue_id").Eq(vID),
+			goqu.I("reservation.table_id").Neq(0),
+			occupying(from, until),
+		).
+		ScanStructs(&oo)
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error getting occupied tables")
+	}
+
+	var combined []Occupancy
+	err = r.db.DB.From("reservation").
+		Select("reservation.rid", "combination_table.table_id", "reservation.status", "reservation.starts_at").
+		Join(goqu.T("combination_table"), goqu.On(goqu.Ex{"combination_table.combination_id": goqu.I("reservation.combination_id")})).
+		Where(
+			goqu.I("reservation.venue_id").Eq(vID),
+			occupying(from, until),
+		).
+		ScanStructs(&combined)
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error getting occupied table combinations")
+	}
+
+	return append(oo, combined...), nil
+}
+
+func (r *seatingRepository) SetTableState(ctx context.Context, tID int, state TableState, rID int, now int64) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.SetTableState", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	vID := tenant.VenueFromContext(ctx)
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		var t Table
+		found, err := tx.From("dining_table").Where(goqu.Ex{"tid": tID, "venue_id": vID}).ScanStruct(&t)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return errors.NotFound.Newf("table with ID %d not found", tID).
+				AddContext("TableID", "non existent ID")
+		}
+
+		// The parties seated at the table, alone or at a combination with
+		// it, and the tables they occupy.
+		var seated []int
+		if err := tx.From("reservation").Select("rid").Where(
+			goqu.Ex{"venue_id": vID, "status": StatusSeated},
+			holding(tx.From, tID),
+		).ScanVals(&seated); err != nil {
+			return err
+		}
+
+		tables := []int{tID}
+		if state == StateSeated {
+			if err := seat(tx, vID, t, rID, seated, now); err != nil {
+				return err
+			}
+		} else if len(seated) > 0 {
+			var members []int
+			if err := tx.From("combination_table").Select("combination_table.table_id").
+				Join(goqu.T("reservation"), goqu.On(goqu.Ex{"reservation.combination_id": goqu.I("combination_table.combination_id")})).
+				Where(goqu.Ex{"reservation.rid": seated}).
+				ScanVals(&members); err != nil {
+				return err
+			}
+			for _, m := range members {
+				if m != tID {
+					tables = append(tables, m)
+				}
+			}
+
+			if _, err := tx.From("reservation").Where(goqu.Ex{"rid": seated}).Update(goqu.Record{
+				"status":       StatusCompleted,
+				"last_updated": now,
+			}).Exec(); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.From("dining_table").Where(goqu.Ex{"venue_id": vID, "tid": tables}).Update(goqu.Record{
+			"needs_cleaning": state == StateNeedsCleaning,
+			"last_updated":   now,
+		}).Exec(); err != nil {
+			return err
+		}
+
+		var changed []Table
+		if err := tx.From("dining_table").Where(goqu.Ex{"venue_id": vID, "tid": tables}).ScanStructs(&changed); err != nil {
+			return err
+		}
+		for _, c := range changed {
+			ft := FloorTable{Table: c, State: state}
+			if state == StateSeated {
+				ft.ReservationID = rID
+			}
+			if err := outbox.Append(ctx, tx, event.TableStateChanged, event.AggregateTable, c.TableID, ft); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if t := errors.GetType(err); t == errors.NotFound || t == errors.Conflict {
+		return err
+	}
+	if err != nil {
+		return errors.DBError.Wrapf(err, "error setting state of table with ID %d", tID)
+	}
+	return nil
+}
+
+// holding matches the reservations holding the table tID, alone or as
+// part of a combination.
+func holding(from fromFunc, tID int) exp.Expression {
+	return goqu.Or(
+		goqu.I("reservation.table_id").Eq(tID),
+		goqu.L("EXISTS ?", from("combination_table").Select(goqu.L("1")).Where(
+			goqu.I("combination_table.combination_id").Eq(goqu.I("reservation.combination_id")),
+			goqu.I("combination_table.table_id").Eq(tID),
+		)),
+	)
+}
+
+// seat marks the reservation rID seated at t, moving it there unless it
+// holds t already. seated are the parties at t, which must have left first.
+func seat(tx *goqu.TxDatabase, vID int, t Table, rID int, seated []int, now int64) error {
+	for _, other := range seated {
+		if other != rID {
+			return errors.Conflict.Newf("table %s is taken by reservation %d", t.Name, other).
+				AddContext("state", "the party seated there has to leave first")
+		}
+	}
+
+	var res struct {
+		Status string `db:"status"`
+	}
+	found, err := tx.From("reservation").Select("status").Where(goqu.Ex{"rid": rID, "venue_id": vID}).ScanStruct(&res)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.NotFound.Newf("reservation with ID %d not found", rID).
+			AddContext("reservationId", "non existent ID")
+	}
+	if res.Status == StatusCompleted {
+		return errors.Conflict.Newf("reservation %d has already left", rID).
+			AddContext("reservationId", "completed")
+	}
+
+	update := goqu.Record{
+		"status":             StatusSeated,
+		"needs_reassignment": false,
+		"last_updated":       now,
+	}
+	n, err := tx.From("reservation").Where(goqu.Ex{"rid": rID}, holding(tx.From, t.TableID)).Count()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		update["table_id"] = t.TableID
+		update["combination_id"] = 0
+		update["area_id"] = t.AreaID
+	}
+	_, err = tx.From("reservation").Where(goqu.Ex{"rid": rID}).Update(update).Exec()
+	return err
+}
+
+func (r *seatingRepository) FindFreeTables(ctx context.Context, s Slot) (tt []Table, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.FindFreeTables", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	err = freeTables(r.db.DB.From, tenant.VenueFromContext(ctx), s).ScanStructs(&tt)
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error searching free tables")
+	}
+	return tt, nil
+}
+
+func (r *seatingRepository) AddCombination(ctx context.Context, c *Combination) (_ *Combination, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.AddCombination", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	created := time.Now().Unix()
+	vID := tenant.VenueFromContext(ctx)
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		var tt []Table
+		if err := tx.From("dining_table").Where(goqu.Ex{"venue_id": vID, "tid": c.TableIDs}).ScanStructs(&tt); err != nil {
+			return err
+		}
+		if len(tt) != len(c.TableIDs) {
+			return errors.NotFound.Newf("%d of the tables %v not found", len(c.TableIDs)-len(tt), c.TableIDs).
+				AddContext("tableIds", "non existent ID")
+		}
+		if c.Capacity == 0 {
+			for _, t := range tt {
+				c.Capacity += t.Capacity
+			}
+		}
+
+		c.Created = created
+		c.LastUpdated = created
+		c.VenueID = vID
+		coID, err := storage.InsertReturningID(tx, "table_combination", "coid", c)
+		if err != nil {
+			return err
+		}
+		c.CombinationID = coID
+
+		rows := make([]interface{}, len(c.TableIDs))
+		for i, tID := range c.TableIDs {
+			rows[i] = goqu.Record{"combination_id": coID, "table_id": tID}
+		}
+		_, err = tx.From("combination_table").Insert(rows...).Exec()
+		return err
+	})
+
+	if errors.GetType(err) == errors.NotFound {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error adding new table combination")
+	}
+	return c, nil
+}
+
+func (r *seatingRepository) RemoveCombination(ctx context.Context, coID int) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.RemoveCombination", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	vID := tenant.VenueFromContext(ctx)
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		n, err := tx.From("table_combination").Where(goqu.Ex{"coid": coID, "venue_id": vID}).Count()
+		if err != nil || n == 0 {
+			return err
+		}
+
+		// Bookings holding the combination have to be seated again.
+		if _, err := tx.From("reservation").Where(goqu.Ex{"venue_id": vID, "combination_id": coID}).Update(goqu.Record{
+			"combination_id":     0,
+			"needs_reassignment": true,
+			"last_updated":       time.Now().Unix(),
+		}).Exec(); err != nil {
+			return err
+		}
+		if _, err := tx.From("slot_hold").Where(goqu.Ex{"venue_id": vID, "combination_id": coID}).Delete().Exec(); err != nil {
+			return err
+		}
+
+		if _, err := tx.From("combination_table").Where(goqu.Ex{"combination_id": coID}).Delete().Exec(); err != nil {
+			return err
+		}
+		_, err = tx.From("table_combination").Where(goqu.Ex{"coid": coID, "venue_id": vID}).Delete().Exec()
+		return err
+	})
+
+	if err != nil {
+		return errors.DBError.Wrapf(err, "error deleting table combination with ID %d", coID)
+	}
+	return nil
+}
+
+func (r *seatingRepository) FindAllCombinations(ctx context.Context) (cc []Combination, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.FindAllCombinations", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	err = r.db.DB.From("table_combination").
+		Where(goqu.Ex{"venue_id": tenant.VenueFromContext(ctx)}).
+		Order(goqu.C("coid").Asc()).
+		ScanStructs(&cc)
+	if err == nil {
+		err = r.scanMembers(cc)
+	}
+
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error getting table combinations")
+	}
+	return cc, nil
+}
+
+func (r *seatingRepository) FindFreeCombinations(ctx context.Context, s Slot) (cc []Combination, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.FindFreeCombinations", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	err = freeCombinations(r.db.DB.From, tenant.VenueFromContext(ctx), s).ScanStructs(&cc)
+	if err == nil {
+		err = r.scanMembers(cc)
+	}
+
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error searching free table combinations")
+	}
+	return cc, nil
+}
+
+// AddHold keeps what Allocate picks for the slot of h until h expires.
+func (r *seatingRepository) AddHold(ctx context.Context, h *Hold, limit int) (_ *Hold, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.AddHold", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	vID := tenant.VenueFromContext(ctx)
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		if limit > 0 {
+			n, err := tx.From("slot_hold").Where(
+				goqu.C("venue_id").Eq(vID),
+				goqu.C("holder").Eq(h.Holder),
+				goqu.C("expires_at").Gt(h.Created),
+			).Count()
+			if err != nil {
+				return err
+			}
+			if n >= int64(limit) {
+				return errors.Conflict.Newf("%s already holds %d tables", h.Holder, n).
+					AddContext("hold", "book or release a held table first")
+			}
+		}
+
+		a, ok, err := Allocate(tx, vID, h.slot())
+		if err != nil {
+			return err
+		}
+		if ok {
+			h.TableID = a.TableID
+			h.CombinationID = a.CombinationID
+			h.AreaID = a.AreaID
+		}
+
+		h.VenueID = vID
+		hID, err := storage.InsertReturningID(tx, "slot_hold", "hid", h)
+		h.HoldID = hID
+		return err
+	})
+
+	if t := errors.GetType(err); t == errors.NotFound || t == errors.Conflict {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error adding new hold")
+	}
+	return h, nil
+}
+
+func (r *seatingRepository) RemoveHold(ctx context.Context, token string) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "seatingRepository.RemoveHold", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	_, err = r.db.DB.From("slot_hold").
+		Where(goqu.Ex{"token": token, "venue_id": tenant.VenueFromContext(ctx)}).
+		Delete().
+		Exec()
+
+	if err != nil {
+		return errors.DBError.Wrap(err, "error releasing hold")
+	}
+	return nil
+}
+
+func (r *seatingRepository) RemoveExpiredHolds(ctx context.Context, now int64) (n int64, err error) {
+	_, span := tracing.StartSpan(ctx, "seatingRepository.RemoveExpiredHolds", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	res, err := r.db.DB.From("slot_hold").
+		Where(goqu.I("expires_at").Lte(now)).
+		Delete().
+		Exec()
+	if err == nil {
+		n, err = res.RowsAffected()
+	}
+
+	if err != nil {
+		return 0, errors.DBError.Wrap(err, "error removing expired holds")
+	}
+	return n, nil
+}
+
+// scanMembers sets the tables of the combinations in cc.
+func (r *seatingRepository) scanMembers(cc []Combination) error {
+	if len(cc) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(cc))
+	index := make(map[int]int, len(cc))
+	for i, c := range cc {
+		ids[i] = c.CombinationID
+		index[c.CombinationID] = i
+	}
+
+	var members []struct {
+		CombinationID int `db:"combination_id"`
+		TableID       int `db:"table_id"`
+	}
+	err := r.db.DB.From("combination_table").
+		Where(goqu.Ex{"combination_id": ids}).
+		Order(goqu.C("combination_id").Asc(), goqu.C("table_id").Asc()).
+		ScanStructs(&members)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range members {
+		c := &cc[index[m.CombinationID]]
+		c.TableIDs = append(c.TableIDs, m.TableID)
+	}
+	return nil
+}
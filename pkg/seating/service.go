@@ -0,0 +1,520 @@
+// Package seating models the tables of a venue, grouped into areas such as
+// a terrace or a private room, and finds the tables free for a booking.
+package seating
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"reservations/pkg/authz"
+	errors "reservations/pkg/error"
+	"strconv"
+	"time"
+)
+
+type Service interface {
+	CreateArea(ctx context.Context, a *Area) (*Area, error)
+	RemoveArea(ctx context.Context, aID int) error
+	CloseArea(ctx context.Context, aID int, reason string) (Closure, error)
+	OpenArea(ctx context.Context, aID int) (Area, error)
+	GetAllAreas(ctx context.Context) ([]Area, error)
+	CreateTable(ctx context.Context, t *Table) (*Table, error)
+	RemoveTable(ctx context.Context, tID int) error
+	UpdateTable(ctx context.Context, tID int, t *Table) (Table, error)
+	GetTables(ctx context.Context, aID int) ([]Table, error)
+	GetFloor(ctx context.Context, aID int) (Floor, error)
+	SetTableState(ctx context.Context, tID int, state TableState, rID int) (FloorTable, error)
+	CreateCombination(ctx context.Context, c *Combination) (*Combination, error)
+	RemoveCombination(ctx context.Context, coID int) error
+	GetAllCombinations(ctx context.Context) ([]Combination, error)
+	SearchAvailability(ctx context.Context, s Slot) (Availability, error)
+	CreateHold(ctx context.Context, h *Hold) (*Hold, error)
+	ReleaseHold(ctx context.Context, token string) error
+}
+
+// Area groups tables which are opened and closed together.
+type Area struct {
+	AreaID       int    `json:"areaId" db:"aid" goqu:"skipinsert"`
+	Name         string `json:"name"`
+	Closed       bool   `json:"closed"`
+	ClosedReason string `json:"closedReason,omitempty" db:"closed_reason"`
+	VenueID      int    `json:"venueId" db:"venue_id"`
+	Created      int64  `json:"created"`
+	LastUpdated  int64  `json:"lastUpdated" db:"last_updated"`
+}
+
+// Table is a dining table seating up to Capacity guests.
+type Table struct {
+	TableID  int    `json:"tableId" db:"tid" goqu:"skipinsert"`
+	AreaID   int    `json:"areaId" db:"area_id"`
+	Name     string `json:"name"`
+	Capacity int    `json:"capacity"`
+	// X and Y place the center of the table on the floor plan of its area,
+	// which it covers Width by Height before being rotated clockwise by
+	// Rotation degrees.
+	X        float64 `json:"x" db:"pos_x"`
+	Y        float64 `json:"y" db:"pos_y"`
+	Width    float64 `json:"width"`
+	Height   float64 `json:"height"`
+	Shape    Shape   `json:"shape" swaggertype:"string" enums:"square,round,rectangle"`
+	Rotation float64 `json:"rotation"`
+	// NeedsCleaning is set when a party leaves until the host stand marks
+	// the table free again.
+	NeedsCleaning bool  `json:"needsCleaning" db:"needs_cleaning"`
+	VenueID       int   `json:"venueId" db:"venue_id"`
+	Created       int64 `json:"created"`
+	LastUpdated   int64 `json:"lastUpdated" db:"last_updated"`
+}
+
+// Shape is the outline of a table on the floor plan.
+type Shape string
+
+const (
+	ShapeSquare    Shape = "square"
+	ShapeRound     Shape = "round"
+	ShapeRectangle Shape = "rectangle"
+)
+
+// Statuses of a reservation, which the host stand moves from booked to
+// seated when the party arrives and to completed when it leaves.
+const (
+	StatusBooked    = "booked"
+	StatusSeated    = "seated"
+	StatusCompleted = "completed"
+)
+
+// TableState is the live state of a table shown on the floor plan.
+type TableState string
+
+const (
+	StateFree TableState = "free"
+	// StateReserved marks a table held by a booking starting soon or
+	// already due but not seated yet.
+	StateReserved      TableState = "reserved"
+	StateSeated        TableState = "seated"
+	StateNeedsCleaning TableState = "needs_cleaning"
+)
+
+// FloorTable is a table with its live state.
+type FloorTable struct {
+	Table
+	State TableState `json:"state" swaggertype:"string" enums:"free,reserved,seated,needs_cleaning"`
+	// ReservationID is the reservation seated at the table or, for reserved
+	// tables, the one expected next.
+	ReservationID int `json:"reservationId,omitempty"`
+}
+
+// Floor is the floor plan of a venue.
+type Floor struct {
+	Areas  []Area       `json:"areas"`
+	Tables []FloorTable `json:"tables"`
+}
+
+// Occupancy is a reservation holding a table, alone or as part of a
+// combination, now or soon.
+type Occupancy struct {
+	ReservationID int    `db:"rid"`
+	TableID       int    `db:"table_id"`
+	Status        string `db:"status"`
+	StartsAt      int64  `db:"starts_at"`
+}
+
+// Combination is a set of tables which may be pushed together to seat a
+// party no single table fits.
+type Combination struct {
+	CombinationID int    `json:"combinationId" db:"coid" goqu:"skipinsert"`
+	Name          string `json:"name"`
+	// Capacity is the seat count of the combined tables, which defaults to
+	// the sum of their capacities.
+	Capacity    int   `json:"capacity"`
+	TableIDs    []int `json:"tableIds" db:"-"`
+	VenueID     int   `json:"venueId" db:"venue_id"`
+	Created     int64 `json:"created"`
+	LastUpdated int64 `json:"lastUpdated" db:"last_updated"`
+}
+
+// Availability lists what is free for a slot.
+type Availability struct {
+	Tables       []Table       `json:"tables"`
+	Combinations []Combination `json:"combinations"`
+}
+
+// Closure is the outcome of closing an area.
+type Closure struct {
+	Area Area `json:"area"`
+	// Flagged lists the upcoming reservations seated in the area, which
+	// are flagged for reassignment.
+	Flagged []int `json:"flagged"`
+}
+
+// Hold keeps a table or combination for a party while the guest completes
+// a booking, until ExpiresAt. A booking given its Token takes what it holds.
+type Hold struct {
+	HoldID        int    `json:"-" db:"hid" goqu:"skipinsert"`
+	Token         string `json:"token"`
+	Seats         int    `json:"seats"`
+	StartTime     string `json:"startTime" db:"start_time"`
+	AreaID        int    `json:"areaId" db:"area_id"`
+	TableID       int    `json:"tableId,omitempty" db:"table_id"`
+	CombinationID int    `json:"combinationId,omitempty" db:"combination_id"`
+	StartsAt      int64  `json:"startsAt" db:"starts_at"`
+	EndsAt        int64  `json:"endsAt" db:"ends_at"`
+	ExpiresAt     int64  `json:"expiresAt" db:"expires_at"`
+	// Holder is the subject of the caller taking the hold, empty when
+	// authorization is disabled.
+	Holder  string `json:"-"`
+	VenueID int    `json:"venueId" db:"venue_id"`
+	Created int64  `json:"created"`
+}
+
+// Slot describes a booking to be seated: a party of Seats from StartsAt
+// until EndsAt, as Unix times, optionally restricted to an area.
+type Slot struct {
+	StartsAt int64
+	EndsAt   int64
+	Seats    int
+	AreaID   int
+	// ReservationID is the reservation being reseated, whose own table
+	// counts as free.
+	ReservationID int
+	// HoldToken redeems a hold, whose table the booking takes in place of
+	// allocating one.
+	HoldToken string
+}
+
+type seatingService struct {
+	seatRepo   Repository
+	duration   time.Duration
+	soon       time.Duration
+	holdTTL    time.Duration
+	guestHolds int
+}
+
+// NewSeatingService returns the seating service. duration is how long a
+// table is held for a booking, used for slots given without an end, and
+// soon how long before a booking starts its table is shown as reserved on
+// the floor plan. Holds expire holdTTL after being taken, and a guest may
+// keep up to guestHolds of them at once, any number if 0.
+func NewSeatingService(repo Repository, duration time.Duration, soon time.Duration, holdTTL time.Duration, guestHolds int) Service {
+	return &seatingService{
+		seatRepo:   repo,
+		duration:   duration,
+		soon:       soon,
+		holdTTL:    holdTTL,
+		guestHolds: guestHolds,
+	}
+}
+
+func (s *seatingService) CreateArea(ctx context.Context, a *Area) (*Area, error) {
+	if a.Name == "" {
+		return nil, errors.ValidationError.New("no area name given").
+			AddContext("name", "an area needs a name")
+	}
+	a.Closed = false
+	a.ClosedReason = ""
+	return s.seatRepo.AddArea(ctx, a)
+}
+
+func (s *seatingService) RemoveArea(ctx context.Context, aID int) error {
+	tt, err := s.seatRepo.FindTables(ctx, aID)
+	if err != nil {
+		return err
+	}
+	if len(tt) > 0 {
+		return errors.Conflict.Newf("area %d still has %d tables", aID, len(tt)).
+			AddContext("areaId", "remove its tables first")
+	}
+	return s.seatRepo.RemoveArea(ctx, aID)
+}
+
+func (s *seatingService) CloseArea(ctx context.Context, aID int, reason string) (Closure, error) {
+	return s.seatRepo.CloseArea(ctx, aID, reason, time.Now().Unix())
+}
+
+func (s *seatingService) OpenArea(ctx context.Context, aID int) (Area, error) {
+	return s.seatRepo.OpenArea(ctx, aID, time.Now().Unix())
+}
+
+func (s *seatingService) GetAllAreas(ctx context.Context) ([]Area, error) {
+	return s.seatRepo.FindAllAreas(ctx)
+}
+
+func (s *seatingService) CreateTable(ctx context.Context, t *Table) (*Table, error) {
+	if err := s.validateTable(ctx, t); err != nil {
+		return nil, err
+	}
+	t.NeedsCleaning = false
+	return s.seatRepo.AddTable(ctx, t)
+}
+
+func (s *seatingService) UpdateTable(ctx context.Context, tID int, t *Table) (Table, error) {
+	if err := s.validateTable(ctx, t); err != nil {
+		return Table{}, err
+	}
+	return s.seatRepo.UpdateTable(ctx, tID, t)
+}
+
+func (s *seatingService) validateTable(ctx context.Context, t *Table) error {
+	if t.Name == "" {
+		return errors.ValidationError.New("no table name given").
+			AddContext("name", "a table needs a name")
+	}
+	if t.Capacity < 1 {
+		return errors.ValidationError.Newf("invalid capacity %d", t.Capacity).
+			AddContext("capacity", "must be at least 1")
+	}
+	switch t.Shape {
+	case "":
+		t.Shape = ShapeSquare
+	case ShapeSquare, ShapeRound, ShapeRectangle:
+	default:
+		return errors.ValidationError.Newf("unknown shape %q", t.Shape).
+			AddContext("shape", "must be square, round or rectangle")
+	}
+	if t.Width < 0 || t.Height < 0 {
+		return errors.ValidationError.New("negative table size").
+			AddContext("width", "width and height must not be negative")
+	}
+	_, err := s.seatRepo.FindAreaByID(ctx, t.AreaID)
+	return err
+}
+
+func (s *seatingService) RemoveTable(ctx context.Context, tID int) error {
+	cc, err := s.seatRepo.FindAllCombinations(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range cc {
+		for _, id := range c.TableIDs {
+			if id == tID {
+				return errors.Conflict.Newf("table %d is part of combination %s", tID, c.Name).
+					AddContext("tableId", "remove the combination first")
+			}
+		}
+	}
+	return s.seatRepo.RemoveTable(ctx, tID)
+}
+
+func (s *seatingService) GetTables(ctx context.Context, aID int) ([]Table, error) {
+	return s.seatRepo.FindTables(ctx, aID)
+}
+
+func (s *seatingService) GetFloor(ctx context.Context, aID int) (f Floor, err error) {
+	now := time.Now().Unix()
+
+	aa, err := s.seatRepo.FindAllAreas(ctx)
+	if err != nil {
+		return f, err
+	}
+	f.Areas = []Area{}
+	for _, a := range aa {
+		if aID == 0 || a.AreaID == aID {
+			f.Areas = append(f.Areas, a)
+		}
+	}
+
+	tt, err := s.seatRepo.FindTables(ctx, aID)
+	if err != nil {
+		return f, err
+	}
+	oo, err := s.seatRepo.FindOccupancies(ctx, now, now+int64(s.soon/time.Second))
+	if err != nil {
+		return f, err
+	}
+
+	f.Tables = make([]FloorTable, len(tt))
+	for i, t := range tt {
+		f.Tables[i] = floorTable(t, oo)
+	}
+	return f, nil
+}
+
+// floorTable derives the state of t from the reservations in oo.
+func floorTable(t Table, oo []Occupancy) FloorTable {
+	ft := FloorTable{Table: t, State: StateFree}
+	if t.NeedsCleaning {
+		ft.State = StateNeedsCleaning
+		return ft
+	}
+
+	var next *Occupancy
+	for i, o := range oo {
+		if o.TableID != t.TableID {
+			continue
+		}
+		if o.Status == StatusSeated {
+			ft.State = StateSeated
+			ft.ReservationID = o.ReservationID
+			return ft
+		}
+		if next == nil || o.StartsAt < next.StartsAt {
+			next = &oo[i]
+		}
+	}
+	if next != nil {
+		ft.State = StateReserved
+		ft.ReservationID = next.ReservationID
+	}
+	return ft
+}
+
+func (s *seatingService) SetTableState(ctx context.Context, tID int, state TableState, rID int) (FloorTable, error) {
+	switch state {
+	case StateSeated:
+		if rID == 0 {
+			return FloorTable{}, errors.ValidationError.New("no reservation given").
+				AddContext("reservationId", "the reservation seated at the table is required")
+		}
+	case StateFree, StateNeedsCleaning:
+	default:
+		return FloorTable{}, errors.ValidationError.Newf("invalid table state %q", state).
+			AddContext("state", "must be seated, needs_cleaning or free")
+	}
+
+	if err := s.seatRepo.SetTableState(ctx, tID, state, rID, time.Now().Unix()); err != nil {
+		return FloorTable{}, err
+	}
+
+	f, err := s.GetFloor(ctx, 0)
+	if err != nil {
+		return FloorTable{}, err
+	}
+	for _, ft := range f.Tables {
+		if ft.TableID == tID {
+			return ft, nil
+		}
+	}
+	return FloorTable{}, errors.NotFound.Newf("table with ID %d not found", tID).
+		AddContext("TableID", "non existent ID")
+}
+
+func (s *seatingService) CreateCombination(ctx context.Context, c *Combination) (*Combination, error) {
+	if c.Name == "" {
+		return nil, errors.ValidationError.New("no combination name given").
+			AddContext("name", "a combination needs a name")
+	}
+	seen := make(map[int]bool, len(c.TableIDs))
+	for _, id := range c.TableIDs {
+		if seen[id] {
+			return nil, errors.ValidationError.Newf("table %d given twice", id).
+				AddContext("tableIds", "tables must be distinct")
+		}
+		seen[id] = true
+	}
+	if len(c.TableIDs) < 2 {
+		return nil, errors.ValidationError.New("too few tables").
+			AddContext("tableIds", "a combination needs at least 2 tables")
+	}
+	if c.Capacity < 0 {
+		return nil, errors.ValidationError.Newf("invalid capacity %d", c.Capacity).
+			AddContext("capacity", "must not be negative")
+	}
+	return s.seatRepo.AddCombination(ctx, c)
+}
+
+func (s *seatingService) RemoveCombination(ctx context.Context, coID int) error {
+	return s.seatRepo.RemoveCombination(ctx, coID)
+}
+
+func (s *seatingService) GetAllCombinations(ctx context.Context) ([]Combination, error) {
+	return s.seatRepo.FindAllCombinations(ctx)
+}
+
+func (s *seatingService) SearchAvailability(ctx context.Context, slot Slot) (a Availability, err error) {
+	if slot.EndsAt == 0 {
+		slot.EndsAt = slot.StartsAt + int64(s.duration/time.Second)
+	}
+	if err := ValidateSlot(slot); err != nil {
+		return a, err
+	}
+	if a.Tables, err = s.seatRepo.FindFreeTables(ctx, slot); err != nil {
+		return a, err
+	}
+	a.Combinations, err = s.seatRepo.FindFreeCombinations(ctx, slot)
+	return a, err
+}
+
+func (s *seatingService) CreateHold(ctx context.Context, h *Hold) (*Hold, error) {
+	startsAt, err := ParseStartTime(h.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	h.StartsAt = startsAt
+	h.EndsAt = startsAt + int64(s.duration/time.Second)
+	if err := ValidateSlot(h.slot()); err != nil {
+		return nil, err
+	}
+
+	token, err := newHoldToken()
+	if err != nil {
+		return nil, err
+	}
+	h.Token = token
+	h.TableID = 0
+	h.CombinationID = 0
+	h.Created = time.Now().Unix()
+	h.ExpiresAt = h.Created + int64(s.holdTTL/time.Second)
+
+	// Guests may hold tables without booking, so each of them only gets a
+	// few, lest one hold the whole floor.
+	limit := 0
+	h.Holder = ""
+	if g, ok := authz.GrantFromContext(ctx); ok {
+		h.Holder = g.Subject
+		if g.Role == authz.RoleGuest {
+			limit = s.guestHolds
+		}
+	}
+	return s.seatRepo.AddHold(ctx, h, limit)
+}
+
+func (s *seatingService) ReleaseHold(ctx context.Context, token string) error {
+	return s.seatRepo.RemoveHold(ctx, token)
+}
+
+func (h *Hold) slot() Slot {
+	return Slot{
+		StartsAt: h.StartsAt,
+		EndsAt:   h.EndsAt,
+		Seats:    h.Seats,
+		AreaID:   h.AreaID,
+	}
+}
+
+// newHoldToken returns a random token, hard to guess as it is all it takes
+// to redeem a hold.
+func newHoldToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "error generating hold token")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// ParseStartTime returns the Unix time of a booking starting at startTime,
+// given in RFC 3339 format or, as clients did before start times were
+// interpreted, in Unix seconds.
+func ParseStartTime(startTime string) (int64, error) {
+	if sec, err := strconv.ParseInt(startTime, 10, 64); err == nil && sec > 0 {
+		return sec, nil
+	}
+	t, err := time.Parse(time.RFC3339, startTime)
+	if err != nil {
+		return 0, errors.ValidationError.Wrapf(err, "invalid start time %q", startTime).
+			AddContext("startTime", "must be an RFC 3339 timestamp such as 2006-01-02T19:30:00+01:00, or Unix seconds")
+	}
+	return t.Unix(), nil
+}
+
+// ValidateSlot checks that slot describes a party and a time range.
+func ValidateSlot(slot Slot) error {
+	if slot.Seats < 1 {
+		return errors.ValidationError.Newf("invalid seat count %d", slot.Seats).
+			AddContext("seats", "must be at least 1")
+	}
+	if slot.StartsAt == 0 || slot.EndsAt <= slot.StartsAt {
+		return errors.ValidationError.New("invalid time range").
+			AddContext("start", "a start time before the end time is required")
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package seating
+
+import (
+	"context"
+	"reservations/pkg/authz"
+	errors "reservations/pkg/error"
+	"reservations/pkg/storage"
+	"reservations/pkg/storage/storagetest"
+	"testing"
+	"time"
+)
+
+func TestCreateHoldLimitsGuests(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		repo := NewSeatingRepository(*db)
+		s := NewSeatingService(repo, 2*time.Hour, 30*time.Minute, 10*time.Minute, 2)
+		ctx := context.Background()
+
+		a, err := s.CreateArea(ctx, &Area{Name: "Terrace"})
+		if err != nil {
+			t.Fatalf("CreateArea: %v", err)
+		}
+		for _, name := range []string{"T1", "T2", "T3", "T4"} {
+			if _, err := s.CreateTable(ctx, &Table{AreaID: a.AreaID, Name: name, Capacity: 4, Width: 1, Height: 1}); err != nil {
+				t.Fatalf("CreateTable: %v", err)
+			}
+		}
+
+		hold := func(ctx context.Context) (*Hold, error) {
+			return s.CreateHold(ctx, &Hold{Seats: 2, StartTime: time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)})
+		}
+		alice := authz.WithGrant(ctx, authz.Grant{Subject: "alice", Role: authz.RoleGuest})
+		bob := authz.WithGrant(ctx, authz.Grant{Subject: "bob", Role: authz.RoleGuest})
+		staff := authz.WithGrant(ctx, authz.Grant{Subject: "carol", Role: authz.RoleStaff})
+
+		first, err := hold(alice)
+		if err != nil {
+			t.Fatalf("CreateHold: %v", err)
+		}
+		if first.Holder != "alice" || first.TableID == 0 {
+			t.Errorf("CreateHold = %+v, want a table held by alice", first)
+		}
+		if _, err := hold(alice); err != nil {
+			t.Fatalf("second CreateHold: %v", err)
+		}
+		if _, err := hold(alice); errors.GetType(err) != errors.Conflict {
+			t.Errorf("third CreateHold of a guest = %v, want a Conflict", err)
+		}
+		if _, err := hold(bob); err != nil {
+			t.Errorf("CreateHold of another guest: %v", err)
+		}
+
+		if err := s.ReleaseHold(alice, first.Token); err != nil {
+			t.Fatalf("ReleaseHold: %v", err)
+		}
+		if _, err := hold(alice); err != nil {
+			t.Errorf("CreateHold after releasing one: %v", err)
+		}
+
+		if _, err := repo.RemoveExpiredHolds(ctx, time.Now().Add(time.Hour).Unix()); err != nil {
+			t.Fatalf("RemoveExpiredHolds: %v", err)
+		}
+		for i := 0; i < 3; i++ {
+			if _, err := hold(staff); err != nil {
+				t.Errorf("CreateHold %d of the staff: %v", i+1, err)
+			}
+		}
+	})
+}
+
+func TestParseStartTime(t *testing.T) {
+	for startTime, want := range map[string]int64{
+		"2026-01-01T10:00:00Z":      1767261600,
+		"2026-01-01T11:00:00+01:00": 1767261600,
+		"1767261600":                1767261600,
+	} {
+		if got, err := ParseStartTime(startTime); err != nil || got != want {
+			t.Errorf("ParseStartTime(%q) = %d, %v, want %d", startTime, got, err, want)
+		}
+	}
+
+	for _, startTime := range []string{"", "0", "-5", "2026-01-01 10:00", "tomorrow"} {
+		if _, err := ParseStartTime(startTime); errors.GetType(err) != errors.ValidationError {
+			t.Errorf("ParseStartTime(%q) = %v, want a ValidationError", startTime, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package seating
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"time"
+)
+
+// HoldSweeper deletes expired holds. Expired holds no longer keep their
+// tables nor can be redeemed, so sweeping only keeps the table small.
+type HoldSweeper struct {
+	repo     Repository
+	interval time.Duration
+	logger   log.Logger
+}
+
+func NewHoldSweeper(repo Repository, interval time.Duration, logger log.Logger) *HoldSweeper {
+	return &HoldSweeper{
+		repo:     repo,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run sweeps the holds until ctx is cancelled.
+func (s *HoldSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := s.repo.RemoveExpiredHolds(ctx, time.Now().Unix())
+		if err != nil {
+			s.logger.Log("component", "holds", "err", err)
+		} else if n > 0 {
+			s.logger.Log("component", "holds", "released", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
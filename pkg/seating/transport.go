@@ -0,0 +1,286 @@
+package seating
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"net/http"
+	"reservations/pkg/authz"
+	"reservations/pkg/transport"
+)
+
+func MakeHTTPHandler(r *mux.Router, s Service, az authz.Authorizer, logger log.Logger, mw ...endpoint.Middleware) *mux.Router {
+	e := MakeServerEndpoints(s, az, mw...)
+
+	options := httpjson.DefaultServerOptions(logger)
+
+	r.Methods("POST").Path("/area").
+		Handler(httptransport.NewServer(
+			e.CreateAreaEndpoint,
+			decodeCreateAreaRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("DELETE").Path("/area/{id}").
+		Handler(httptransport.NewServer(
+			e.RemoveAreaEndpoint,
+			decodeRemoveAreaRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("POST").Path("/area/{id}/close").
+		Handler(httptransport.NewServer(
+			e.CloseAreaEndpoint,
+			decodeCloseAreaRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("POST").Path("/area/{id}/open").
+		Handler(httptransport.NewServer(
+			e.OpenAreaEndpoint,
+			decodeOpenAreaRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("GET").Path("/areas").
+		Handler(httptransport.NewServer(
+			e.GetAllAreasEndpoint,
+			decodeGetAllAreasRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("POST").Path("/table").
+		Handler(httptransport.NewServer(
+			e.CreateTableEndpoint,
+			decodeCreateTableRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("PUT").Path("/table/{id}").
+		Handler(httptransport.NewServer(
+			e.UpdateTableEndpoint,
+			decodeUpdateTableRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("PUT").Path("/table/{id}/state").
+		Handler(httptransport.NewServer(
+			e.SetTableStateEndpoint,
+			decodeSetTableStateRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("DELETE").Path("/table/{id}").
+		Handler(httptransport.NewServer(
+			e.RemoveTableEndpoint,
+			decodeRemoveTableRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("GET").Path("/tables").
+		Handler(httptransport.NewServer(
+			e.GetTablesEndpoint,
+			decodeGetTablesRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("GET").Path("/floor").
+		Handler(httptransport.NewServer(
+			e.GetFloorEndpoint,
+			decodeGetFloorRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("POST").Path("/combination").
+		Handler(httptransport.NewServer(
+			e.CreateCombinationEndpoint,
+			decodeCreateCombinationRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("DELETE").Path("/combination/{id}").
+		Handler(httptransport.NewServer(
+			e.RemoveCombinationEndpoint,
+			decodeRemoveCombinationRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("GET").Path("/combinations").
+		Handler(httptransport.NewServer(
+			e.GetAllCombinationsEndpoint,
+			decodeGetAllCombinationsRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("GET").Path("/availability").
+		Handler(httptransport.NewServer(
+			e.SearchAvailabilityEndpoint,
+			decodeSearchAvailabilityRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("POST").Path("/holds").
+		Handler(httptransport.NewServer(
+			e.CreateHoldEndpoint,
+			decodeCreateHoldRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("DELETE").Path("/holds/{token}").
+		Handler(httptransport.NewServer(
+			e.ReleaseHoldEndpoint,
+			decodeReleaseHoldRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	return r
+}
+
+func decodeCreateAreaRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req createAreaRequest
+	if e := json.NewDecoder(r.Body).Decode(&req.Area); e != nil {
+		return nil, e
+	}
+	return req, nil
+}
+
+func decodeRemoveAreaRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "area ID")
+	if err != nil {
+		return nil, err
+	}
+	return removeAreaRequest{AreaID: id}, nil
+}
+
+func decodeCloseAreaRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "area ID")
+	if err != nil {
+		return nil, err
+	}
+	return closeAreaRequest{AreaID: id, Reason: r.URL.Query().Get("reason")}, nil
+}
+
+func decodeOpenAreaRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "area ID")
+	if err != nil {
+		return nil, err
+	}
+	return openAreaRequest{AreaID: id}, nil
+}
+
+func decodeGetAllAreasRequest(_ context.Context, _ *http.Request) (request interface{}, err error) {
+	return getAllAreasRequest{}, nil
+}
+
+func decodeCreateTableRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req createTableRequest
+	if e := json.NewDecoder(r.Body).Decode(&req.Table); e != nil {
+		return nil, e
+	}
+	return req, nil
+}
+
+func decodeUpdateTableRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "table ID")
+	if err != nil {
+		return nil, err
+	}
+	req := updateTableRequest{TableID: id}
+	if e := json.NewDecoder(r.Body).Decode(&req.Table); e != nil {
+		return nil, e
+	}
+	return req, nil
+}
+
+func decodeSetTableStateRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "table ID")
+	if err != nil {
+		return nil, err
+	}
+	var req setTableStateRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil {
+		return nil, e
+	}
+	req.TableID = id
+	return req, nil
+}
+
+func decodeRemoveTableRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "table ID")
+	if err != nil {
+		return nil, err
+	}
+	return removeTableRequest{TableID: id}, nil
+}
+
+func decodeGetTablesRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	return getTablesRequest{AreaID: int(httpjson.ParseUintQueryParam(r, "area"))}, nil
+}
+
+func decodeGetFloorRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	return getFloorRequest{AreaID: int(httpjson.ParseUintQueryParam(r, "area"))}, nil
+}
+
+func decodeCreateCombinationRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req createCombinationRequest
+	if e := json.NewDecoder(r.Body).Decode(&req.Combination); e != nil {
+		return nil, e
+	}
+	return req, nil
+}
+
+func decodeRemoveCombinationRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "combination ID")
+	if err != nil {
+		return nil, err
+	}
+	return removeCombinationRequest{CombinationID: id}, nil
+}
+
+func decodeGetAllCombinationsRequest(_ context.Context, _ *http.Request) (request interface{}, err error) {
+	return getAllCombinationsRequest{}, nil
+}
+
+func decodeSearchAvailabilityRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	startsAt, err := ParseStartTime(r.URL.Query().Get("start"))
+	if err != nil {
+		return nil, err
+	}
+	return searchAvailabilityRequest{Slot: Slot{
+		StartsAt: startsAt,
+		Seats:    int(httpjson.ParseUintQueryParam(r, "seats")),
+		AreaID:   int(httpjson.ParseUintQueryParam(r, "area")),
+	}}, nil
+}
+
+func decodeCreateHoldRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req createHoldRequest
+	if e := json.NewDecoder(r.Body).Decode(&req.Hold); e != nil {
+		return nil, e
+	}
+	return req, nil
+}
+
+func decodeReleaseHoldRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	return releaseHoldRequest{Token: mux.Vars(r)["token"]}, nil
+}
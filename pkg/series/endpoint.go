@@ -0,0 +1,191 @@
+package series
+
+import (
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"reservations/pkg/authz"
+	"reservations/pkg/reservation"
+)
+
+type Endpoints struct {
+	CreateSeriesEndpoint      endpoint.Endpoint
+	GetSeriesByIDEndpoint     endpoint.Endpoint
+	EditOccurrencesEndpoint   endpoint.Endpoint
+	CancelOccurrencesEndpoint endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns the service endpoints, each checked against
+// the policy by az and wrapped in the given middlewares, the first one
+// outermost.
+func MakeServerEndpoints(s Service, az authz.Authorizer, mw ...endpoint.Middleware) Endpoints {
+	owner := seriesOwner(s)
+	e := Endpoints{
+		CreateSeriesEndpoint:      az.Require(authz.PermReservationWrite, owner)(MakeCreateSeriesEndpoint(s)),
+		GetSeriesByIDEndpoint:     az.Require(authz.PermReservationRead, owner)(MakeGetSeriesByIDEndpoint(s)),
+		EditOccurrencesEndpoint:   az.Require(authz.PermReservationWrite, owner)(MakeEditOccurrencesEndpoint(s)),
+		CancelOccurrencesEndpoint: az.Require(authz.PermReservationWrite, owner)(MakeCancelOccurrencesEndpoint(s)),
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		m := mw[i]
+		e.CreateSeriesEndpoint = m(e.CreateSeriesEndpoint)
+		e.GetSeriesByIDEndpoint = m(e.GetSeriesByIDEndpoint)
+		e.EditOccurrencesEndpoint = m(e.EditOccurrencesEndpoint)
+		e.CancelOccurrencesEndpoint = m(e.CancelOccurrencesEndpoint)
+	}
+	return e
+}
+
+// seriesOwner returns the customer a request addresses, looking up the
+// series for requests naming one by ID.
+func seriesOwner(s Service) authz.OwnerFunc {
+	return func(ctx context.Context, request interface{}) (int, error) {
+		var sID int
+		switch req := request.(type) {
+		case createSeriesRequest:
+			return req.CustomerID, nil
+		case getSeriesByIDRequest:
+			sID = req.SeriesID
+		case editOccurrencesRequest:
+			sID = req.SeriesID
+		case cancelOccurrencesRequest:
+			sID = req.SeriesID
+		default:
+			return 0, nil
+		}
+		sr, err := s.GetSeriesByID(ctx, sID)
+		return sr.CustomerID, err
+	}
+}
+
+type createSeriesRequest struct {
+	CustomerID int
+	Series     *Series
+}
+
+type createSeriesResponse struct {
+	Series *Series `json:"series,omitempty"`
+	Err    error   `json:"err,omitempty"`
+}
+
+func (r createSeriesResponse) HTTPError() error { return r.Err }
+
+// CreateSeries godoc
+// @Summary Book a recurring reservation
+// @Description Book a reservation recurring daily, weekly or monthly, until a date, for a number of occurrences or until cancelled. The occurrences are booked as individual reservations some time ahead; those no table is free for are flagged for reassignment. The series is checked against the booking policy like its first occurrence; later occurrences are not held to the lead time and advance window, and those breaking another rule are cancelled, listed among the exceptions. Every occurrence requires its own deposit where the deposit terms apply.
+// @Tags series
+// @Param id path string true "Customer ID"
+// @Param series body series.Series true "New Series"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} series.Series
+// @Router /customer/{id}/series [post]
+func MakeCreateSeriesEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(createSeriesRequest)
+		sr, e := s.CreateSeries(ctx, req.CustomerID, req.Series)
+		return createSeriesResponse{
+			Series: sr,
+			Err:    e,
+		}, nil
+	}
+}
+
+type getSeriesByIDRequest struct {
+	SeriesID int
+}
+
+type getSeriesByIDResponse struct {
+	Series Series `json:"series"`
+	Err    error  `json:"err,omitempty"`
+}
+
+func (r getSeriesByIDResponse) HTTPError() error { return r.Err }
+
+// GetSeriesByID godoc
+// @Summary Get a recurring reservation
+// @Description Get a recurring reservation with the occurrences booked so far
+// @Tags series
+// @Param id path string true "Series ID"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} series.Series
+// @Router /series/{id} [get]
+func MakeGetSeriesByIDEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getSeriesByIDRequest)
+		sr, e := s.GetSeriesByID(ctx, req.SeriesID)
+		return getSeriesByIDResponse{
+			Series: sr,
+			Err:    e,
+		}, nil
+	}
+}
+
+type editOccurrencesRequest struct {
+	SeriesID    int
+	Date        string
+	Scope       Scope
+	Reservation *reservation.Reservation
+}
+
+type editOccurrencesResponse struct {
+	Series Series `json:"series"`
+	Err    error  `json:"err,omitempty"`
+}
+
+func (r editOccurrencesResponse) HTTPError() error { return r.Err }
+
+// EditOccurrences godoc
+// @Summary Edit occurrences of a recurring reservation
+// @Description Edit the occurrence on a date only, that one and the following ones, which are split off into a new series, or the whole series. Moving the start time of the occurrence moves the others alike. Occurrences moved or reduced late are charged like reservations edited on their own. The series edited is returned.
+// @Tags series
+// @Param id path string true "Series ID"
+// @Param date path string true "Date of the occurrence, e.g. 2006-01-02"
+// @Param scope query string false "Occurrences to edit" Enums(this, following, all) default(this)
+// @Param reservation body reservation.Reservation true "Changes"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} series.Series
+// @Router /series/{id}/occurrence/{date} [put]
+func MakeEditOccurrencesEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(editOccurrencesRequest)
+		sr, e := s.EditOccurrences(ctx, req.SeriesID, req.Date, req.Scope, req.Reservation)
+		return editOccurrencesResponse{
+			Series: sr,
+			Err:    e,
+		}, nil
+	}
+}
+
+type cancelOccurrencesRequest struct {
+	SeriesID int
+	Date     string
+	Scope    Scope
+}
+
+type cancelOccurrencesResponse struct {
+	Err error `json:"err,omitempty"`
+}
+
+func (r cancelOccurrencesResponse) HTTPError() error { return r.Err }
+
+// CancelOccurrences godoc
+// @Summary Cancel occurrences of a recurring reservation
+// @Description Cancel the occurrence on a date only, that one and the following ones, or the whole series. Occurrences already started are kept. Each occurrence cancelled is charged the cancellation fee, its deposit being settled keeping the fee.
+// @Tags series
+// @Param id path string true "Series ID"
+// @Param date path string true "Date of the occurrence, e.g. 2006-01-02"
+// @Param scope query string false "Occurrences to cancel" Enums(this, following, all) default(this)
+// @Accept  json
+// @Produce  json
+// @Router /series/{id}/occurrence/{date} [delete]
+func MakeCancelOccurrencesEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(cancelOccurrencesRequest)
+		e := s.CancelOccurrences(ctx, req.SeriesID, req.Date, req.Scope)
+		return cancelOccurrencesResponse{
+			Err: e,
+		}, nil
+	}
+}
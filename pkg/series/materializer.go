@@ -0,0 +1,66 @@
+package series
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"reservations/pkg/tenant"
+	"time"
+)
+
+// Materializer keeps the occurrences of every series booked horizon ahead
+// as time goes by.
+type Materializer struct {
+	repo     Repository
+	horizon  time.Duration
+	interval time.Duration
+	logger   log.Logger
+}
+
+func NewMaterializer(repo Repository, horizon time.Duration, interval time.Duration, logger log.Logger) *Materializer {
+	return &Materializer{
+		repo:     repo,
+		horizon:  horizon,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run books the occurrences coming within the horizon until ctx is
+// cancelled.
+func (m *Materializer) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.materialize(ctx); err != nil {
+			m.logger.Log("component", "series", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Materializer) materialize(ctx context.Context) error {
+	until := time.Now().Add(m.horizon).Unix()
+	ss, err := m.repo.FindSeriesToMaterialize(ctx, until)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range ss {
+		// A series failing does not hold the others back.
+		n, err := m.repo.Materialize(tenant.WithVenue(ctx, s.VenueID), s.SeriesID, until)
+		if err != nil {
+			m.logger.Log("component", "series", "series", s.SeriesID, "err", err)
+			continue
+		}
+		if n > 0 {
+			m.logger.Log("component", "series", "series", s.SeriesID, "booked", n)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package series
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"reservations/pkg/reservation"
+	"reservations/pkg/tracing"
+	"time"
+)
+
+// Middleware describes a service (as opposed to endpoint) middleware.
+type Middleware func(Service) Service
+
+func LoggingMiddleware(logger log.Logger) Middleware {
+	return func(next Service) Service {
+		return &loggingMiddleware{
+			next:   next,
+			logger: logger,
+		}
+	}
+}
+
+type loggingMiddleware struct {
+	next   Service
+	logger log.Logger
+}
+
+func (mw loggingMiddleware) CreateSeries(ctx context.Context, cID int, s *Series) (result *Series, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "CreateSeries", "customer", cID, "frequency", s.Frequency, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.CreateSeries(ctx, cID, s)
+}
+
+func (mw loggingMiddleware) GetSeriesByID(ctx context.Context, sID int) (s Series, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "GetSeriesByID", "id", sID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetSeriesByID(ctx, sID)
+}
+
+func (mw loggingMiddleware) EditOccurrences(ctx context.Context, sID int, date string, scope Scope, res *reservation.Reservation) (s Series, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "EditOccurrences", "id", sID, "date", date, "scope", scope, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.EditOccurrences(ctx, sID, date, scope, res)
+}
+
+func (mw loggingMiddleware) CancelOccurrences(ctx context.Context, sID int, date string, scope Scope) (err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "CancelOccurrences", "id", sID, "date", date, "scope", scope, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.CancelOccurrences(ctx, sID, date, scope)
+}
+
+// TracingMiddleware wraps every call in a span.
+func TracingMiddleware() Middleware {
+	return func(next Service) Service {
+		return &tracingMiddleware{next: next}
+	}
+}
+
+type tracingMiddleware struct {
+	next Service
+}
+
+func (mw tracingMiddleware) CreateSeries(ctx context.Context, cID int, s *Series) (result *Series, err error) {
+	ctx, span := tracing.StartSpan(ctx, "series.CreateSeries")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.CreateSeries(ctx, cID, s)
+}
+
+func (mw tracingMiddleware) GetSeriesByID(ctx context.Context, sID int) (s Series, err error) {
+	ctx, span := tracing.StartSpan(ctx, "series.GetSeriesByID")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.GetSeriesByID(ctx, sID)
+}
+
+func (mw tracingMiddleware) EditOccurrences(ctx context.Context, sID int, date string, scope Scope, res *reservation.Reservation) (s Series, err error) {
+	ctx, span := tracing.StartSpan(ctx, "series.EditOccurrences")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.EditOccurrences(ctx, sID, date, scope, res)
+}
+
+func (mw tracingMiddleware) CancelOccurrences(ctx context.Context, sID int, date string, scope Scope) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "series.CancelOccurrences")
+	defer tracing.EndSpan(span, &err)
+	return mw.next.CancelOccurrences(ctx, sID, date, scope)
+}
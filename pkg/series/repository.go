@@ -0,0 +1,529 @@
+package series
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v7"
+	errors "reservations/pkg/error"
+	"reservations/pkg/fee"
+	"reservations/pkg/policy"
+	"reservations/pkg/reservation"
+	"reservations/pkg/seating"
+	"reservations/pkg/storage"
+	"reservations/pkg/tenant"
+	"reservations/pkg/tracing"
+	"time"
+)
+
+type Repository interface {
+	// AddSeries stores s for the customer cID and books its occurrences
+	// starting before until.
+	AddSeries(ctx context.Context, cID int, s *Series, until int64) (*Series, error)
+	FindSeriesByID(ctx context.Context, sID int) (Series, error)
+	// EditOccurrences returns the series the occurrence on date belongs to
+	// after the edit, and the occurrences cancelled to be booked anew.
+	EditOccurrences(ctx context.Context, sID int, date string, scope Scope, res *reservation.Reservation, until int64) (Series, []Cancellation, error)
+	CancelOccurrences(ctx context.Context, sID int, date string, scope Scope) ([]Cancellation, error)
+	// FindSeriesToMaterialize returns the series of all venues which may
+	// have occurrences to book before until.
+	FindSeriesToMaterialize(ctx context.Context, until int64) ([]Series, error)
+	// Materialize books the occurrences of the series sID starting before
+	// until which are not booked yet, returning how many were.
+	Materialize(ctx context.Context, sID int, until int64) (int, error)
+}
+
+type seriesRepository struct {
+	db    storage.Persistence
+	terms reservation.Terms
+}
+
+// NewSeriesRepository returns the series repository, which books and
+// cancels occurrences on the same terms as one-off reservations.
+func NewSeriesRepository(db storage.Persistence, terms reservation.Terms) Repository {
+	return &seriesRepository{db: db, terms: terms}
+}
+
+func (r *seriesRepository) AddSeries(ctx context.Context, cID int, s *Series, until int64) (_ *Series, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seriesRepository.AddSeries", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	now := time.Now().Unix()
+	vID := tenant.VenueFromContext(ctx)
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		n, err := tx.From("customer").Where(goqu.Ex{"cid": cID, "venue_id": vID}).Count()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return errors.NotFound.Newf("customer with ID %d not found", cID).
+				AddContext("CustomerID", "non existent ID")
+		}
+
+		// The series is booked like its first occurrence, later ones
+		// being booked ahead as a matter of course.
+		t, ok, err := s.next(now)
+		if err != nil {
+			return err
+		}
+		if ok {
+			if err := r.terms.Check(s.occurrence(t), nil, s.Channel); err != nil {
+				return err
+			}
+		}
+
+		s.CustomerID = cID
+		s.VenueID = vID
+		s.Created = now
+		s.LastUpdated = now
+		sID, err := storage.InsertReturningID(tx, "reservation_series", "sid", s)
+		if err != nil {
+			return err
+		}
+		s.SeriesID = sID
+
+		if _, err := r.materialize(ctx, tx, vID, s, until, now); err != nil {
+			return err
+		}
+		s.Occurrences, err = occurrences(tx, vID, sID)
+		return err
+	})
+
+	if t := errors.GetType(err); t == errors.NotFound || t == errors.ValidationError {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error adding new reservation series")
+	}
+	return s, nil
+}
+
+func (r *seriesRepository) FindSeriesByID(ctx context.Context, sID int) (s Series, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seriesRepository.FindSeriesByID", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	vID := tenant.VenueFromContext(ctx)
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		if s, err = find(tx, vID, sID); err != nil {
+			return err
+		}
+		s.Occurrences, err = occurrences(tx, vID, sID)
+		return err
+	})
+
+	if errors.GetType(err) == errors.NotFound {
+		return s, err
+	}
+	if err != nil {
+		return s, errors.DBError.Wrapf(err, "error getting reservation series with ID %d", sID)
+	}
+	return s, nil
+}
+
+func (r *seriesRepository) EditOccurrences(ctx context.Context, sID int, date string, scope Scope, res *reservation.Reservation, until int64) (result Series, cc []Cancellation, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seriesRepository.EditOccurrences", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	now := time.Now().Unix()
+	vID := tenant.VenueFromContext(ctx)
+	ch := policy.ChannelFromContext(ctx)
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		s, err := find(tx, vID, sID)
+		if err != nil {
+			return err
+		}
+		i, t, err := s.occurrenceOn(date)
+		if err != nil {
+			return err
+		}
+		if scope != ScopeThis {
+			// Edits of several occurrences are checked like the edit of
+			// the one on date, the others being booked anew ahead.
+			prev := s.occurrence(t)
+			if err := r.terms.Check(*res, &prev, ch); err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case scope == ScopeThis:
+			if err := r.editOccurrence(ctx, tx, vID, s, t, res, ch, now); err != nil {
+				return err
+			}
+			result = s
+
+		case scope == ScopeFollowing && i > 0:
+			// The series is split, the occurrences from date on making up
+			// a new one.
+			tail := s
+			tail.SeriesID = 0
+			tail.StartTime = t.Format(time.RFC3339)
+			if tail.Count != 0 {
+				tail.Count -= i
+			}
+			if err := tail.apply(res, t, date); err != nil {
+				return err
+			}
+			tail.MaterializedUntil = 0
+			tail.Created = now
+			tail.LastUpdated = now
+			tail.SeriesID, err = storage.InsertReturningID(tx, "reservation_series", "sid", tail)
+			if err != nil {
+				return err
+			}
+
+			if cc, err = cancel(ctx, tx, vID, sID, date, now, r.rebookFee(*res, t, ch)); err != nil {
+				return err
+			}
+			s.truncate(i, date)
+			if err := update(tx, &s, now); err != nil {
+				return err
+			}
+			if _, err := r.materialize(ctx, tx, vID, &tail, until, now); err != nil {
+				return err
+			}
+			result = tail
+
+		default:
+			if err := s.apply(res, t, ""); err != nil {
+				return err
+			}
+			if cc, err = cancel(ctx, tx, vID, sID, "", now, r.rebookFee(*res, t, ch)); err != nil {
+				return err
+			}
+			s.MaterializedUntil = 0
+			if err := update(tx, &s, now); err != nil {
+				return err
+			}
+			if _, err := r.materialize(ctx, tx, vID, &s, until, now); err != nil {
+				return err
+			}
+			result = s
+		}
+
+		result.Occurrences, err = occurrences(tx, vID, result.SeriesID)
+		return err
+	})
+
+	if t := errors.GetType(err); t == errors.NotFound || t == errors.Conflict || t == errors.ValidationError {
+		return result, nil, err
+	}
+	if err != nil {
+		return result, nil, errors.DBError.Wrapf(err, "error editing reservation series with ID %d", sID)
+	}
+	return result, cc, nil
+}
+
+// editOccurrence applies res to the occurrence of s starting at t, booking
+// it if it is not yet, through ch.
+func (r *seriesRepository) editOccurrence(ctx context.Context, tx *goqu.TxDatabase, vID int, s Series, t time.Time, res *reservation.Reservation, ch policy.Channel, now int64) error {
+	startsAt, err := seating.ParseStartTime(res.StartTime)
+	if err != nil {
+		return err
+	}
+	res.StartsAt = startsAt
+	res.EndsAt = startsAt + s.Duration
+	res.SeriesID = s.SeriesID
+	res.Occurrence = t.Format(DateLayout)
+
+	var cur reservation.Reservation
+	found, err := tx.From("reservation").
+		Where(goqu.Ex{"venue_id": vID, "series_id": s.SeriesID, "occurrence": res.Occurrence}).
+		ScanStruct(&cur)
+	if err != nil {
+		return err
+	}
+	if !found {
+		cur = s.occurrence(t)
+	}
+	if err := r.terms.Check(*res, &cur, ch); err != nil {
+		return err
+	}
+
+	if !found {
+		d := r.terms.Deposit(res, ch)
+		if err := reservation.Insert(ctx, tx, vID, s.CustomerID, res, now); err != nil {
+			return err
+		}
+		return reservation.InsertDeposit(tx, *res, d, now)
+	}
+	if err := reservation.InsertFee(tx, cur, r.terms.EditFee(cur, *res, ch)); err != nil {
+		return err
+	}
+	res.ReservationID = cur.ReservationID
+	_, err = reservation.Update(ctx, tx, vID, res, r.terms.Deposit(res, ch), now)
+	return err
+}
+
+// rebookFee returns the fee charged for each occurrence cancelled to be
+// booked anew by an edit applying res to the occurrence starting at t, as
+// for editing it on its own: all occurrences move along with it and get its
+// party size.
+func (r *seriesRepository) rebookFee(res reservation.Reservation, t time.Time, ch policy.Channel) func(reservation.Reservation) *fee.Fee {
+	moved, _ := time.Parse(time.RFC3339, res.StartTime)
+	shift := int64(moved.Sub(t) / time.Second)
+	return func(prev reservation.Reservation) *fee.Fee {
+		next := prev
+		next.StartsAt += shift
+		next.SeatCount = res.SeatCount
+		return r.terms.EditFee(prev, next, ch)
+	}
+}
+
+func (r *seriesRepository) CancelOccurrences(ctx context.Context, sID int, date string, scope Scope) (cc []Cancellation, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seriesRepository.CancelOccurrences", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	now := time.Now().Unix()
+	vID := tenant.VenueFromContext(ctx)
+	ch := policy.ChannelFromContext(ctx)
+	charge := func(prev reservation.Reservation) *fee.Fee {
+		return r.terms.Charge(prev, ch, prev.SeatCount, fee.Cancellation)
+	}
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		s, err := find(tx, vID, sID)
+		if err != nil {
+			return err
+		}
+		i, _, err := s.occurrenceOn(date)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case scope == ScopeThis:
+			if cc, err = remove(ctx, tx, booked(tx, vID, sID).Where(goqu.Ex{"occurrence": date}), now, charge); err != nil {
+				return err
+			}
+			s.Exceptions = normalizeDates(append(s.Exceptions, date))
+			return update(tx, &s, now)
+
+		case scope == ScopeFollowing && i > 0:
+			if cc, err = cancel(ctx, tx, vID, sID, date, now, charge); err != nil {
+				return err
+			}
+			s.truncate(i, date)
+			return update(tx, &s, now)
+
+		default:
+			// The occurrences already started are kept as one-off
+			// reservations.
+			if cc, err = cancel(ctx, tx, vID, sID, "", now, charge); err != nil {
+				return err
+			}
+			if _, err := tx.From("reservation").Where(goqu.Ex{"venue_id": vID, "series_id": sID}).Update(goqu.Record{
+				"series_id":    0,
+				"occurrence":   "",
+				"last_updated": now,
+			}).Exec(); err != nil {
+				return err
+			}
+			_, err = tx.From("reservation_series").Where(goqu.Ex{"sid": sID, "venue_id": vID}).Delete().Exec()
+			return err
+		}
+	})
+
+	if errors.GetType(err) == errors.NotFound {
+		return nil, err
+	}
+	if err != nil {
+		return nil, errors.DBError.Wrapf(err, "error cancelling occurrences of reservation series with ID %d", sID)
+	}
+	return cc, nil
+}
+
+func (r *seriesRepository) FindSeriesToMaterialize(ctx context.Context, until int64) (ss []Series, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seriesRepository.FindSeriesToMaterialize", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	err = r.db.DB.From("reservation_series").
+		Where(
+			goqu.C("materialized_until").Lt(until),
+			goqu.Or(
+				goqu.C("repeat_until").Eq(""),
+				goqu.C("repeat_until").Gte(time.Now().UTC().AddDate(0, 0, -1).Format(DateLayout)),
+			),
+		).
+		Order(goqu.C("sid").Asc()).
+		ScanStructs(&ss)
+
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error getting reservation series to materialize")
+	}
+	return ss, nil
+}
+
+func (r *seriesRepository) Materialize(ctx context.Context, sID int, until int64) (n int, err error) {
+	ctx, span := tracing.StartSpan(ctx, "seriesRepository.Materialize", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	vID := tenant.VenueFromContext(ctx)
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		s, err := find(tx, vID, sID)
+		if err != nil {
+			return err
+		}
+		n, err = r.materialize(ctx, tx, vID, &s, until, time.Now().Unix())
+		return err
+	})
+
+	if err != nil {
+		return n, errors.DBError.Wrapf(err, "error materializing reservation series with ID %d", sID)
+	}
+	return n, nil
+}
+
+// materialize books the occurrences of s from now on starting before until
+// which are neither booked nor cancelled. Occurrences no table is free for
+// are booked nonetheless, flagged for reassignment.
+//
+// Occurrences are held to the booking policy of the channel s was booked
+// through, save for the lead time and advance window: they are booked ahead
+// as a matter of course, the series having been checked when booked. Those
+// breaking a rule, e.g. a party too large for the shift of that weekday, are
+// cancelled rather than booked. Each occurrence requires its own deposit.
+func (r *seriesRepository) materialize(ctx context.Context, tx *goqu.TxDatabase, vID int, s *Series, until int64, now int64) (n int, err error) {
+	var dates []string
+	if err := tx.From("reservation").Select("occurrence").
+		Where(goqu.Ex{"venue_id": vID, "series_id": s.SeriesID}).
+		ScanVals(&dates); err != nil {
+		return 0, err
+	}
+	booked := make(map[string]bool, len(dates))
+	for _, d := range dates {
+		booked[d] = true
+	}
+
+	from := s.MaterializedUntil
+	if from < now {
+		from = now
+	}
+	var due []time.Time
+	err = s.each(func(_ int, t time.Time) bool {
+		if t.Unix() >= until {
+			return false
+		}
+		d := t.Format(DateLayout)
+		if t.Unix() >= from && !booked[d] && !s.Exceptions.contains(d) {
+			due = append(due, t)
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, t := range due {
+		res := s.occurrence(t)
+		if err := r.terms.Check(res, &res, s.Channel); errors.GetType(err) == errors.ValidationError {
+			s.Exceptions = append(s.Exceptions, res.Occurrence)
+			continue
+		} else if err != nil {
+			return n, err
+		}
+
+		d := r.terms.Deposit(&res, s.Channel)
+		err := reservation.Insert(ctx, tx, vID, s.CustomerID, &res, now)
+		if errors.GetType(err) == errors.Conflict {
+			err = reservation.InsertUnseated(ctx, tx, vID, s.CustomerID, &res, now)
+		}
+		if err != nil {
+			return n, err
+		}
+		if err := reservation.InsertDeposit(tx, res, d, now); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	if until > s.MaterializedUntil {
+		s.MaterializedUntil = until
+	}
+	s.Exceptions = normalizeDates(s.Exceptions)
+	_, err = tx.From("reservation_series").Where(goqu.Ex{"sid": s.SeriesID}).Update(goqu.Record{
+		"exceptions":         s.Exceptions,
+		"materialized_until": s.MaterializedUntil,
+	}).Exec()
+	return n, err
+}
+
+// cancel deletes the occurrences of the series sID from date on which are
+// booked and not started by now, charging each the fee charge returns.
+func cancel(ctx context.Context, tx *goqu.TxDatabase, vID int, sID int, date string, now int64, charge func(reservation.Reservation) *fee.Fee) ([]Cancellation, error) {
+	return remove(ctx, tx, booked(tx, vID, sID).Where(goqu.C("occurrence").Gte(date), goqu.C("starts_at").Gte(now)), now, charge)
+}
+
+// remove deletes the occurrences selected by q, charging each the fee charge
+// returns.
+func remove(ctx context.Context, tx *goqu.TxDatabase, q *goqu.Dataset, now int64, charge func(reservation.Reservation) *fee.Fee) ([]Cancellation, error) {
+	var rr []reservation.Reservation
+	if err := q.ScanStructs(&rr); err != nil {
+		return nil, err
+	}
+
+	cc := make([]Cancellation, 0, len(rr))
+	for _, res := range rr {
+		f := charge(res)
+		if err := reservation.Cancel(ctx, tx, res, f, now); err != nil {
+			return nil, err
+		}
+		cc = append(cc, Cancellation{Reservation: res, Fee: f})
+	}
+	return cc, nil
+}
+
+// booked selects the occurrences of the series sID whose party has not
+// arrived yet.
+func booked(tx *goqu.TxDatabase, vID int, sID int) *goqu.Dataset {
+	return tx.From("reservation").Where(goqu.Ex{
+		"venue_id":  vID,
+		"series_id": sID,
+		"status":    seating.StatusBooked,
+	})
+}
+
+func find(tx *goqu.TxDatabase, vID int, sID int) (s Series, err error) {
+	found, err := tx.From("reservation_series").Where(goqu.Ex{"sid": sID, "venue_id": vID}).ScanStruct(&s)
+	if err != nil {
+		return s, err
+	}
+	if !found {
+		return s, errors.NotFound.Newf("reservation series with ID %d not found", sID).
+			AddContext("SeriesID", "non existent ID")
+	}
+	return s, nil
+}
+
+func occurrences(tx *goqu.TxDatabase, vID int, sID int) (rr []reservation.Reservation, err error) {
+	err = tx.From("reservation").
+		Where(goqu.Ex{"venue_id": vID, "series_id": sID}).
+		Order(goqu.C("starts_at").Asc()).
+		ScanStructs(&rr)
+	if rr == nil {
+		rr = []reservation.Reservation{}
+	}
+	return rr, err
+}
+
+func update(tx *goqu.TxDatabase, s *Series, now int64) error {
+	s.LastUpdated = now
+	_, err := tx.From("reservation_series").Prepared(true).Where(goqu.Ex{"sid": s.SeriesID}).Update(goqu.Record{
+		"seat_count":         s.SeatCount,
+		"start_time":         s.StartTime,
+		"reservation_name":   s.ReservationName,
+		"phone":              s.Phone,
+		"comments":           s.Comments,
+		"area_id":            s.AreaID,
+		"frequency":          s.Frequency,
+		"repeat_interval":    s.Interval,
+		"repeat_until":       s.Until,
+		"repeat_count":       s.Count,
+		"exceptions":         s.Exceptions,
+		"materialized_until": s.MaterializedUntil,
+		"last_updated":       s.LastUpdated,
+	}).Exec()
+	return err
+}
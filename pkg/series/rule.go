@@ -0,0 +1,180 @@
+package series
+
+import (
+	errors "reservations/pkg/error"
+	"reservations/pkg/reservation"
+	"time"
+)
+
+// start returns the start of the first occurrence of s, in the UTC offset
+// it was given in.
+func (s *Series) start() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s.StartTime)
+	if err != nil {
+		return t, errors.ValidationError.Wrapf(err, "invalid start time %q", s.StartTime).
+			AddContext("startTime", "must be an RFC 3339 timestamp such as 2006-01-02T12:30:00+01:00")
+	}
+	return t, nil
+}
+
+// each calls fn with the index and start of the occurrences of s in order,
+// cancelled ones included, until fn returns false or the series ends. Dates
+// missing from a month, such as the 31st, are skipped as in iCalendar.
+func (s *Series) each(fn func(i int, t time.Time) bool) error {
+	start, err := s.start()
+	if err != nil {
+		return err
+	}
+
+	i := 0
+	for k := 0; ; k++ {
+		var t time.Time
+		switch s.Frequency {
+		case Daily:
+			t = start.AddDate(0, 0, k*s.Interval)
+		case Weekly:
+			t = start.AddDate(0, 0, 7*k*s.Interval)
+		case Monthly:
+			t = start.AddDate(0, k*s.Interval, 0)
+			if t.Day() != start.Day() {
+				continue
+			}
+		default:
+			return errors.ValidationError.Newf("unknown frequency %q", s.Frequency)
+		}
+
+		if s.Count != 0 && i >= s.Count {
+			return nil
+		}
+		if s.Until != "" && t.Format(DateLayout) > s.Until {
+			return nil
+		}
+		if !fn(i, t) {
+			return nil
+		}
+		i++
+	}
+}
+
+// occurrenceOn returns the index and start of the occurrence of s on date.
+// It fails with NotFound if s does not recur on date or the occurrence was
+// cancelled.
+func (s *Series) occurrenceOn(date string) (i int, t time.Time, err error) {
+	found := false
+	err = s.each(func(j int, u time.Time) bool {
+		d := u.Format(DateLayout)
+		if d == date {
+			i, t, found = j, u, true
+		}
+		return d < date
+	})
+	if err != nil {
+		return 0, t, err
+	}
+	if !found || s.Exceptions.contains(date) {
+		return 0, t, errors.NotFound.Newf("series %d has no occurrence on %s", s.SeriesID, date).
+			AddContext("date", "not an occurrence of the series")
+	}
+	return i, t, nil
+}
+
+// next returns the start of the first occurrence of s from now on which is
+// not cancelled, reporting whether the series has one.
+func (s *Series) next(now int64) (t time.Time, ok bool, err error) {
+	err = s.each(func(_ int, u time.Time) bool {
+		if u.Unix() >= now && !s.Exceptions.contains(u.Format(DateLayout)) {
+			t, ok = u, true
+		}
+		return !ok
+	})
+	return t, ok, err
+}
+
+// occurrence returns the reservation booked for the occurrence of s
+// starting at t.
+func (s *Series) occurrence(t time.Time) reservation.Reservation {
+	return reservation.Reservation{
+		SeatCount:       s.SeatCount,
+		StartTime:       t.Format(time.RFC3339),
+		ReservationName: s.ReservationName,
+		Phone:           s.Phone,
+		Comments:        s.Comments,
+		AreaID:          s.AreaID,
+		SeriesID:        s.SeriesID,
+		Occurrence:      t.Format(DateLayout),
+		StartsAt:        t.Unix(),
+		EndsAt:          t.Unix() + s.Duration,
+	}
+}
+
+// truncate ends s before its occurrence i on date, returning false if no
+// occurrence is left.
+func (s *Series) truncate(i int, date string) bool {
+	if s.Count != 0 {
+		s.Count = i
+	} else {
+		d, _ := time.Parse(DateLayout, date)
+		s.Until = d.AddDate(0, 0, -1).Format(DateLayout)
+	}
+	s.Exceptions = s.Exceptions.before(date)
+	return i > 0
+}
+
+// before returns the dates before date.
+func (d Dates) before(date string) Dates {
+	out := Dates{}
+	for _, e := range d {
+		if e < date {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// shift returns the dates from date on moved by days, and drops the others.
+func (d Dates) shift(date string, days int) Dates {
+	out := Dates{}
+	for _, e := range d {
+		if e < date {
+			continue
+		}
+		t, _ := time.Parse(DateLayout, e)
+		out = append(out, t.AddDate(0, 0, days).Format(DateLayout))
+	}
+	return out
+}
+
+// apply sets the details of s to the ones of res, which reschedules the
+// occurrence of s at t, moving the occurrences after it alike. Exceptions
+// before the date from are dropped.
+func (s *Series) apply(res *reservation.Reservation, t time.Time, from string) error {
+	start, err := s.start()
+	if err != nil {
+		return err
+	}
+	moved, err := time.Parse(time.RFC3339, res.StartTime)
+	if err != nil {
+		return err
+	}
+
+	n := days(t, moved)
+	s.StartTime = start.Add(moved.Sub(t)).In(moved.Location()).Format(time.RFC3339)
+	s.Exceptions = s.Exceptions.shift(from, n)
+	if s.Until != "" {
+		u, _ := time.Parse(DateLayout, s.Until)
+		s.Until = u.AddDate(0, 0, n).Format(DateLayout)
+	}
+	s.SeatCount = res.SeatCount
+	s.ReservationName = res.ReservationName
+	s.Phone = res.Phone
+	s.Comments = res.Comments
+	s.AreaID = res.AreaID
+	return nil
+}
+
+// days returns the number of calendar days from a to b.
+func days(a time.Time, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
@@ -0,0 +1,287 @@
+// Package series books recurring reservations, such as a table every
+// Tuesday lunch, by materializing the individual reservations of a series
+// some time ahead.
+package series
+
+import (
+	"context"
+	"database/sql/driver"
+	errors "reservations/pkg/error"
+	"reservations/pkg/fee"
+	"reservations/pkg/policy"
+	"reservations/pkg/reservation"
+	"reservations/pkg/seating"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DateLayout is the format of the dates naming occurrences.
+const DateLayout = "2006-01-02"
+
+type Service interface {
+	CreateSeries(ctx context.Context, cID int, s *Series) (*Series, error)
+	GetSeriesByID(ctx context.Context, sID int) (Series, error)
+	// EditOccurrences applies the changes in res to the occurrence of the
+	// series on date and, depending on scope, to the ones after it or to
+	// the whole series.
+	EditOccurrences(ctx context.Context, sID int, date string, scope Scope, res *reservation.Reservation) (Series, error)
+	CancelOccurrences(ctx context.Context, sID int, date string, scope Scope) error
+}
+
+// Frequency is how often a series recurs, every Interval days, weeks or
+// months.
+type Frequency string
+
+const (
+	Daily   Frequency = "daily"
+	Weekly  Frequency = "weekly"
+	Monthly Frequency = "monthly"
+)
+
+// Rule tells when a series recurs, after the manner of an iCalendar RRULE.
+// A series without Until nor Count recurs until cancelled.
+type Rule struct {
+	Frequency Frequency `json:"frequency" swaggertype:"string" enums:"daily,weekly,monthly"`
+	// Interval is the number of days, weeks or months between occurrences,
+	// 1 unless given.
+	Interval int `json:"interval" db:"repeat_interval"`
+	// Until is the date of the last possible occurrence.
+	Until string `json:"until,omitempty" db:"repeat_until"`
+	// Count is the number of occurrences, including the cancelled ones.
+	Count int `json:"count,omitempty" db:"repeat_count"`
+}
+
+// Scope selects the occurrences an edit or cancellation applies to.
+type Scope string
+
+const (
+	ScopeThis      Scope = "this"
+	ScopeFollowing Scope = "following"
+	// ScopeAll applies to the whole series, leaving alone the occurrences
+	// already started.
+	ScopeAll Scope = "all"
+)
+
+// Series is a reservation recurring by Rule. The first occurrence starts at
+// StartTime, and the others at the same time of day and UTC offset.
+type Series struct {
+	SeriesID        int    `json:"seriesId" db:"sid" goqu:"skipinsert"`
+	CustomerID      int    `json:"customerId" db:"customer_id"`
+	SeatCount       int    `json:"seatCount" db:"seat_count"`
+	StartTime       string `json:"startTime" db:"start_time"`
+	ReservationName string `json:"reservationName" db:"reservation_name"`
+	Phone           string `json:"phone"`
+	Comments        string `json:"comments"`
+	AreaID          int    `json:"areaId" db:"area_id"`
+	Rule
+	// Exceptions lists the dates of the cancelled occurrences.
+	Exceptions Dates `json:"exceptions" swaggertype:"array,string"`
+	// Duration is how long, in seconds, each occurrence holds its table.
+	Duration int64 `json:"-"`
+	// Channel is the one the series was booked through, whose booking
+	// policy, fees and deposits every occurrence is held to.
+	Channel policy.Channel `json:"-"`
+	// MaterializedUntil is the Unix time up to which the occurrences are
+	// booked.
+	MaterializedUntil int64 `json:"materializedUntil" db:"materialized_until"`
+	VenueID           int   `json:"venueId" db:"venue_id"`
+	Created           int64 `json:"created"`
+	LastUpdated       int64 `json:"lastUpdated" db:"last_updated"`
+	// Occurrences are the reservations booked for the series, oldest first.
+	Occurrences []reservation.Reservation `json:"occurrences" db:"-"`
+}
+
+// Cancellation is an occurrence cancelled, as booked before, and the fee
+// charged for it unless nil, which its deposit is settled keeping.
+type Cancellation struct {
+	Reservation reservation.Reservation
+	Fee         *fee.Fee
+}
+
+// Dates is stored as a comma separated list.
+type Dates []string
+
+func (d Dates) Value() (driver.Value, error) {
+	return strings.Join(d, ","), nil
+}
+
+func (d *Dates) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return errors.DBError.Newf("cannot scan %T into dates", src)
+	}
+
+	*d = Dates{}
+	for _, date := range strings.Split(s, ",") {
+		if date != "" {
+			*d = append(*d, date)
+		}
+	}
+	return nil
+}
+
+func (d Dates) contains(date string) bool {
+	for _, e := range d {
+		if e == date {
+			return true
+		}
+	}
+	return false
+}
+
+type seriesService struct {
+	repo     Repository
+	duration time.Duration
+	horizon  time.Duration
+	terms    reservation.Terms
+}
+
+// NewSeriesService returns the series service. duration is how long each
+// occurrence holds its table, and horizon how far ahead occurrences are
+// booked. The deposits of cancelled occurrences are settled on terms.
+func NewSeriesService(repo Repository, duration time.Duration, horizon time.Duration, terms reservation.Terms) Service {
+	return &seriesService{
+		repo:     repo,
+		duration: duration,
+		horizon:  horizon,
+		terms:    terms,
+	}
+}
+
+func (s *seriesService) CreateSeries(ctx context.Context, cID int, sr *Series) (*Series, error) {
+	sr.Exceptions = normalizeDates(sr.Exceptions)
+	if err := validateSeries(sr); err != nil {
+		return nil, err
+	}
+	sr.Duration = int64(s.duration / time.Second)
+	sr.Channel = policy.ChannelFromContext(ctx)
+	sr.MaterializedUntil = 0
+	return s.repo.AddSeries(ctx, cID, sr, time.Now().Add(s.horizon).Unix())
+}
+
+func (s *seriesService) GetSeriesByID(ctx context.Context, sID int) (Series, error) {
+	return s.repo.FindSeriesByID(ctx, sID)
+}
+
+func (s *seriesService) EditOccurrences(ctx context.Context, sID int, date string, scope Scope, res *reservation.Reservation) (Series, error) {
+	if err := validateScope(scope); err != nil {
+		return Series{}, err
+	}
+	if _, err := seating.ParseStartTime(res.StartTime); err != nil {
+		return Series{}, err
+	}
+	if res.SeatCount < 1 {
+		return Series{}, errors.ValidationError.Newf("invalid seat count %d", res.SeatCount).
+			AddContext("seatCount", "must be at least 1")
+	}
+	sr, cc, err := s.repo.EditOccurrences(ctx, sID, date, scope, res, time.Now().Add(s.horizon).Unix())
+	if err != nil {
+		return sr, err
+	}
+	s.settle(ctx, cc)
+	return sr, nil
+}
+
+func (s *seriesService) CancelOccurrences(ctx context.Context, sID int, date string, scope Scope) error {
+	if err := validateScope(scope); err != nil {
+		return err
+	}
+	cc, err := s.repo.CancelOccurrences(ctx, sID, date, scope)
+	if err != nil {
+		return err
+	}
+	s.settle(ctx, cc)
+	return nil
+}
+
+// settle carries out with the gateway the settlements of the deposits of the
+// cancelled occurrences cc, stored with their cancellation.
+func (s *seriesService) settle(ctx context.Context, cc []Cancellation) {
+	for _, c := range cc {
+		s.terms.Settle(ctx, c.Reservation)
+	}
+}
+
+func validateSeries(s *Series) error {
+	if s.SeatCount < 1 {
+		return errors.ValidationError.Newf("invalid seat count %d", s.SeatCount).
+			AddContext("seatCount", "must be at least 1")
+	}
+	start, err := s.start()
+	if err != nil {
+		return err
+	}
+
+	switch s.Frequency {
+	case Daily, Weekly, Monthly:
+	default:
+		return errors.ValidationError.Newf("unknown frequency %q", s.Frequency).
+			AddContext("frequency", "must be daily, weekly or monthly")
+	}
+	if s.Interval == 0 {
+		s.Interval = 1
+	}
+	if s.Interval < 0 {
+		return errors.ValidationError.Newf("invalid interval %d", s.Interval).
+			AddContext("interval", "must be at least 1")
+	}
+	if s.Count < 0 {
+		return errors.ValidationError.Newf("invalid count %d", s.Count).
+			AddContext("count", "must not be negative")
+	}
+	if s.Until != "" {
+		if s.Count != 0 {
+			return errors.ValidationError.New("both until and count given").
+				AddContext("until", "a series ends either at a date or after a count")
+		}
+		if err := validateDate("until", s.Until); err != nil {
+			return err
+		}
+		if s.Until < start.Format(DateLayout) {
+			return errors.ValidationError.Newf("series ends on %s before it starts", s.Until).
+				AddContext("until", "must not be before the start date")
+		}
+	}
+	for _, d := range s.Exceptions {
+		if err := validateDate("exceptions", d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateDate(field string, date string) error {
+	if _, err := time.Parse(DateLayout, date); err != nil {
+		return errors.ValidationError.Wrapf(err, "invalid date %q", date).
+			AddContext(field, "must be a date such as 2006-01-02")
+	}
+	return nil
+}
+
+func validateScope(scope Scope) error {
+	switch scope {
+	case ScopeThis, ScopeFollowing, ScopeAll:
+		return nil
+	}
+	return errors.ValidationError.Newf("unknown scope %q", scope).
+		AddContext("scope", "must be this, following or all")
+}
+
+// normalizeDates sorts dd, dropping duplicates.
+func normalizeDates(dd Dates) Dates {
+	sort.Strings(dd)
+	out := Dates{}
+	for i, d := range dd {
+		if i == 0 || d != dd[i-1] {
+			out = append(out, d)
+		}
+	}
+	return out
+}
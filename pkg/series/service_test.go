@@ -0,0 +1,127 @@
+package series
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v7"
+	"reservations/pkg/customer"
+	errors "reservations/pkg/error"
+	"reservations/pkg/fee"
+	"reservations/pkg/payment"
+	"reservations/pkg/policy"
+	"reservations/pkg/reservation"
+	"reservations/pkg/storage"
+	"reservations/pkg/storage/storagetest"
+	"testing"
+	"time"
+)
+
+var testPolicy = policy.Policy{
+	Currency: "eur",
+	Shifts:   []policy.Shift{{Name: "saturday", Days: []string{"sat"}, Start: "00:00", End: "23:59"}},
+	Rules: []policy.Rule{
+		{Name: "window", MinLeadTime: 48 * time.Hour, MaxAdvance: 5 * 24 * time.Hour},
+		{Name: "saturday", Shifts: []string{"saturday"}, MaxPartySize: 4},
+	},
+	Cancellations: []policy.Cancellation{{Name: "strict", NonRefundable: true, FeePerGuest: 1000}},
+	Deposits:      []policy.Deposit{{Name: "large", MinPartySize: 6, AmountPerGuest: 500}},
+}
+
+// firstDay returns noon of the first day at least 3 days ahead which is
+// neither a Friday nor a Saturday, so that a daily series starting then has
+// two occurrences before its Saturday.
+func firstDay() time.Time {
+	t := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 3).Add(12 * time.Hour)
+	for t.Weekday() == time.Friday || t.Weekday() == time.Saturday {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+func newTestService(db *storage.Persistence) (Service, payment.Service) {
+	payments := payment.NewPaymentService(payment.NewPaymentRepository(*db), payment.NewFakeGateway())
+	terms := reservation.Terms{Policy: testPolicy, Payments: payments, DepositTTL: 24 * time.Hour}
+	return NewSeriesService(NewSeriesRepository(*db, terms), 2*time.Hour, 30*24*time.Hour, terms), payments
+}
+
+func TestCreateSeriesAppliesTerms(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		svc, payments := newTestService(db)
+		ctx := context.Background()
+		c, err := customer.NewCustomerRepository(*db).AddCustomer(ctx, &customer.Customer{FirstName: "Ann", LastName: "Doe", Email: "ann@example.com"})
+		if err != nil {
+			t.Fatalf("AddCustomer: %v", err)
+		}
+
+		tomorrow := time.Now().UTC().Add(24 * time.Hour).Format(time.RFC3339)
+		_, err = svc.CreateSeries(ctx, c.CustomerID, &Series{SeatCount: 6, StartTime: tomorrow, Rule: Rule{Frequency: Daily, Count: 7}})
+		if errors.GetType(err) != errors.ValidationError {
+			t.Errorf("CreateSeries starting within the lead time = %v, want a ValidationError", err)
+		}
+
+		first := firstDay()
+		s, err := svc.CreateSeries(ctx, c.CustomerID, &Series{SeatCount: 6, StartTime: first.Format(time.RFC3339), Rule: Rule{Frequency: Daily, Count: 7}})
+		if err != nil {
+			t.Fatalf("CreateSeries: %v", err)
+		}
+
+		// Occurrences beyond the advance window are booked, but not the one
+		// on Saturday, which is too large a party.
+		if len(s.Occurrences) != 6 || len(s.Exceptions) != 1 {
+			t.Fatalf("CreateSeries booked %d occurrences, cancelling %v, want 6 and the Saturday", len(s.Occurrences), s.Exceptions)
+		}
+		if d, _ := time.Parse(DateLayout, s.Exceptions[0]); d.Weekday() != time.Saturday {
+			t.Errorf("cancelled occurrence on %s, want a Saturday", s.Exceptions[0])
+		}
+		for _, res := range s.Occurrences {
+			d, err := payments.GetDeposit(ctx, res.ReservationID)
+			if err != nil || res.Deposit != 3000 || d.Amount != 3000 || d.Status != payment.StatusPending {
+				t.Errorf("occurrence %s has deposit %d, %+v, %v, want a pending one of 3000", res.Occurrence, res.Deposit, d, err)
+			}
+		}
+	})
+}
+
+func TestSeriesChangesAreCharged(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		svc, payments := newTestService(db)
+		ctx := context.Background()
+		c, err := customer.NewCustomerRepository(*db).AddCustomer(ctx, &customer.Customer{FirstName: "Ann", LastName: "Doe", Email: "ann@example.com"})
+		if err != nil {
+			t.Fatalf("AddCustomer: %v", err)
+		}
+
+		first := firstDay()
+		s, err := svc.CreateSeries(ctx, c.CustomerID, &Series{SeatCount: 6, StartTime: first.Format(time.RFC3339), Rule: Rule{Frequency: Daily, Count: 7}})
+		if err != nil {
+			t.Fatalf("CreateSeries: %v", err)
+		}
+		d0, d1 := s.Occurrences[0], s.Occurrences[1]
+		if _, err := payments.PayDeposit(ctx, d0.ReservationID, "pm_card_visa"); err != nil {
+			t.Fatalf("PayDeposit: %v", err)
+		}
+
+		smaller := reservation.Reservation{SeatCount: 4, StartTime: d1.StartTime}
+		if _, err := svc.EditOccurrences(ctx, s.SeriesID, d1.Occurrence, ScopeThis, &smaller); err != nil {
+			t.Fatalf("EditOccurrences: %v", err)
+		}
+		if err := svc.CancelOccurrences(ctx, s.SeriesID, d0.Occurrence, ScopeThis); err != nil {
+			t.Fatalf("CancelOccurrences: %v", err)
+		}
+
+		var ff []fee.Fee
+		if err := db.DB.From("reservation_fee").Order(goqu.C("fid").Asc()).ScanStructs(&ff); err != nil {
+			t.Fatal(err)
+		}
+		if len(ff) != 2 ||
+			ff[0].ReservationID != d1.ReservationID || ff[0].Kind != fee.Modification || ff[0].Amount != 2000 ||
+			ff[1].ReservationID != d0.ReservationID || ff[1].Kind != fee.Cancellation || ff[1].Amount != 6000 {
+			t.Errorf("fees = %+v, want 2000 for the seats cancelled and 6000 for the occurrence", ff)
+		}
+
+		// The deposit paid keeps what it can of the cancellation fee.
+		d, err := payments.GetDeposit(ctx, d0.ReservationID)
+		if err != nil || d.Status != payment.StatusCaptured || d.Captured != 3000 {
+			t.Errorf("deposit of the cancelled occurrence = %+v, %v, want 3000 captured", d, err)
+		}
+	})
+}
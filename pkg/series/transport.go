@@ -0,0 +1,112 @@
+package series
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"net/http"
+	"reservations/pkg/authz"
+	"reservations/pkg/transport"
+)
+
+func MakeHTTPHandler(r *mux.Router, s Service, az authz.Authorizer, logger log.Logger, mw ...endpoint.Middleware) *mux.Router {
+	e := MakeServerEndpoints(s, az, mw...)
+
+	options := httpjson.DefaultServerOptions(logger)
+
+	r.Methods("POST").Path("/customer/{id}/series").
+		Handler(httptransport.NewServer(
+			e.CreateSeriesEndpoint,
+			decodeCreateSeriesRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("GET").Path("/series/{id}").
+		Handler(httptransport.NewServer(
+			e.GetSeriesByIDEndpoint,
+			decodeGetSeriesByIDRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("PUT").Path("/series/{id}/occurrence/{date}").
+		Handler(httptransport.NewServer(
+			e.EditOccurrencesEndpoint,
+			decodeEditOccurrencesRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("DELETE").Path("/series/{id}/occurrence/{date}").
+		Handler(httptransport.NewServer(
+			e.CancelOccurrencesEndpoint,
+			decodeCancelOccurrencesRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	return r
+}
+
+func decodeCreateSeriesRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req createSeriesRequest
+
+	id, err := httpjson.ParseIntPathParam(r, "id", "customer ID")
+	if err != nil {
+		return nil, err
+	}
+	req.CustomerID = id
+
+	if e := json.NewDecoder(r.Body).Decode(&req.Series); e != nil {
+		return nil, e
+	}
+	return req, nil
+}
+
+func decodeGetSeriesByIDRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "series ID")
+	if err != nil {
+		return nil, err
+	}
+	return getSeriesByIDRequest{SeriesID: id}, nil
+}
+
+func decodeEditOccurrencesRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req editOccurrencesRequest
+
+	id, err := httpjson.ParseIntPathParam(r, "id", "series ID")
+	if err != nil {
+		return nil, err
+	}
+	req.SeriesID = id
+	req.Date = mux.Vars(r)["date"]
+	req.Scope = scope(r)
+
+	if e := json.NewDecoder(r.Body).Decode(&req.Reservation); e != nil {
+		return nil, e
+	}
+	return req, nil
+}
+
+func decodeCancelOccurrencesRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "series ID")
+	if err != nil {
+		return nil, err
+	}
+	return cancelOccurrencesRequest{
+		SeriesID: id,
+		Date:     mux.Vars(r)["date"],
+		Scope:    scope(r),
+	}, nil
+}
+
+func scope(r *http.Request) Scope {
+	if s := r.URL.Query().Get("scope"); s != "" {
+		return Scope(s)
+	}
+	return ScopeThis
+}
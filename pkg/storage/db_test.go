@@ -0,0 +1,34 @@
+package storage
+
+import "testing"
+
+func TestPageSize(t *testing.T) {
+	p := &Persistence{DefaultLimit: 20, MaxLimit: 50}
+	for _, tc := range []struct {
+		limit, want uint
+	}{
+		{0, 20},
+		{1, 1},
+		{50, 50},
+		{51, 50},
+		{1 << 20, 50},
+	} {
+		if got := p.PageSize(tc.limit); got != tc.want {
+			t.Errorf("PageSize(%d) = %d, want %d", tc.limit, got, tc.want)
+		}
+	}
+}
+
+func TestWithSQLiteOptions(t *testing.T) {
+	for _, tc := range []struct {
+		dsn, want string
+	}{
+		{"reservations.db", "reservations.db?_foreign_keys=on&_txlock=immediate"},
+		{"file:reservations.db?cache=shared", "file:reservations.db?cache=shared&_foreign_keys=on&_txlock=immediate"},
+		{"reservations.db?_fk=0&_txlock=deferred", "reservations.db?_fk=0&_txlock=deferred"},
+	} {
+		if got := withSQLiteOptions(tc.dsn); got != tc.want {
+			t.Errorf("withSQLiteOptions(%q) = %q, want %q", tc.dsn, got, tc.want)
+		}
+	}
+}
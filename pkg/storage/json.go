@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"database/sql/driver"
+	errors "reservations/pkg/error"
+)
+
+// JSON is a raw JSON document stored in a text column. Unlike
+// json.RawMessage it is written as a single value by goqu.
+type JSON []byte
+
+func (j JSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+	return string(j), nil
+}
+
+func (j *JSON) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*j = nil
+	case []byte:
+		*j = append((*j)[0:0], v...)
+	case string:
+		*j = JSON(v)
+	default:
+		return errors.DBError.Newf("cannot scan %T into JSON", src)
+	}
+	return nil
+}
+
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
+func (j *JSON) UnmarshalJSON(data []byte) error {
+	*j = append((*j)[0:0], data...)
+	return nil
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"github.com/go-kit/kit/metrics"
+	"time"
+)
+
+// Instrument makes Tx and WithTx count transactions and record their
+// duration, both labelled with the outcome (commit or rollback). It must be
+// called before the Persistence is handed to repositories, which keep a copy.
+func (p *Persistence) Instrument(txCount metrics.Counter, txDuration metrics.Histogram) {
+	p.txCount = txCount
+	p.txDuration = txDuration
+}
+
+func (p *Persistence) observeTx(begin time.Time, err *error) {
+	if p.txCount == nil {
+		return
+	}
+
+	outcome := "commit"
+	if *err != nil {
+		outcome = "rollback"
+	}
+	p.txCount.With("outcome", outcome).Add(1)
+	p.txDuration.With("outcome", outcome).Observe(time.Since(begin).Seconds())
+}
+
+// ReportPoolStats periodically copies the connection pool statistics into
+// connections, labelled by state (open, in_use or idle), and waits, the total
+// number of times a connection had to be waited for, until ctx is cancelled.
+func (p *Persistence) ReportPoolStats(ctx context.Context, interval time.Duration, connections metrics.Gauge, waits metrics.Gauge) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		stats := p.sqlDB.Stats()
+		connections.With("state", "open").Set(float64(stats.OpenConnections))
+		connections.With("state", "in_use").Set(float64(stats.InUse))
+		connections.With("state", "idle").Set(float64(stats.Idle))
+		waits.Set(float64(stats.WaitCount))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
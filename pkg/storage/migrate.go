@@ -0,0 +1,310 @@
+package storage
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"github.com/doug-martin/goqu/v7"
+	"io/fs"
+	"path"
+	errors "reservations/pkg/error"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migrations live in migrations/<dialect>/ as NNNN_name.up.sql and
+// NNNN_name.down.sql. Each file is sent to the database as a whole, so it
+// may hold several statements, including ones with semicolons inside, such
+// as function bodies.
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+const migrationsTable = "schema_migrations"
+
+// migrationLock is the key of the PostgreSQL advisory lock held while
+// migrating, "rsvmigr" in ASCII.
+const migrationLock int64 = 0x7273766d696772
+
+// Migration is a numbered schema change together with the statements
+// needed to revert it.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied. AppliedAt
+// is zero for pending migrations.
+type MigrationStatus struct {
+	Version   int    `json:"version"`
+	Name      string `json:"name"`
+	AppliedAt int64  `json:"appliedAt"`
+}
+
+type appliedMigration struct {
+	Version   int    `db:"version"`
+	Name      string `db:"name"`
+	AppliedAt int64  `db:"applied_at"`
+}
+
+// Migrate applies all pending migrations in order and returns them. Each
+// migration runs in its own transaction. Processes migrating the same
+// database at once, such as several servers starting, apply every
+// migration once: PostgreSQL ones wait for each other, and with SQLite,
+// whose writes are serialized anyway, migrations applied meanwhile are
+// skipped. Bookings migrated without an end time are given one lasting
+// BookingDuration.
+func (p *Persistence) Migrate() ([]Migration, error) {
+	unlock, err := p.lockMigrations()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	mm, applied, err := p.loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range mm {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		var skipped bool
+		err := p.WithTx(func(tx *goqu.TxDatabase) error {
+			if err := lockMigrationsTx(tx); err != nil {
+				return err
+			}
+			n, err := tx.From(migrationsTable).Where(goqu.Ex{"version": m.Version}).Count()
+			if err != nil || n > 0 {
+				skipped = true
+				return err
+			}
+			if _, err := tx.Exec(m.Up); err != nil {
+				return err
+			}
+			if m.Version == endTimesVersion {
+				if err := p.backfillEndTimes(tx); err != nil {
+					return err
+				}
+			}
+			_, err = tx.From(migrationsTable).Prepared(true).Insert(appliedMigration{
+				Version:   m.Version,
+				Name:      m.Name,
+				AppliedAt: time.Now().Unix(),
+			}).Exec()
+			return err
+		})
+		if err != nil {
+			return done, errors.DBError.Wrapf(err, "error applying migration %04d_%s", m.Version, m.Name)
+		}
+		if !skipped {
+			done = append(done, m)
+		}
+	}
+
+	return done, nil
+}
+
+// endTimesVersion is the migration deriving the start times of bookings
+// made before they were stored, whose end times are backfilled in Go.
+const endTimesVersion = 14
+
+// backfillEndTimes sets within tx the end time of bookings with a start time
+// but none, from the configured BookingDuration.
+func (p *Persistence) backfillEndTimes(tx *goqu.TxDatabase) error {
+	_, err := tx.From("reservation").Prepared(true).
+		Where(goqu.Ex{"ends_at": 0}, goqu.C("starts_at").Neq(0)).
+		Update(goqu.Record{"ends_at": goqu.L("starts_at + ?", int64(p.BookingDuration/time.Second))}).
+		Exec()
+	return err
+}
+
+// Rollback reverts the latest steps applied migrations and returns them,
+// newest first.
+func (p *Persistence) Rollback(steps int) ([]Migration, error) {
+	unlock, err := p.lockMigrations()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	mm, applied, err := p.loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(mm) - 1; i >= 0 && len(done) < steps; i-- {
+		m := mm[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+
+		err := p.WithTx(func(tx *goqu.TxDatabase) error {
+			if err := lockMigrationsTx(tx); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(m.Down); err != nil {
+				return err
+			}
+			res, err := tx.From(migrationsTable).Where(goqu.Ex{"version": m.Version}).Delete().Exec()
+			if err != nil {
+				return err
+			}
+			if n, err := res.RowsAffected(); err != nil || n == 0 {
+				return errors.Newf("migration %04d was rolled back concurrently", m.Version)
+			}
+			return nil
+		})
+		if err != nil {
+			return done, errors.DBError.Wrapf(err, "error rolling back migration %04d_%s", m.Version, m.Name)
+		}
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+// MigrationStatus lists all known migrations in order together with the
+// time they were applied.
+func (p *Persistence) MigrationStatus() ([]MigrationStatus, error) {
+	mm, applied, err := p.loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	ss := make([]MigrationStatus, len(mm))
+	for i, m := range mm {
+		ss[i] = MigrationStatus{
+			Version:   m.Version,
+			Name:      m.Name,
+			AppliedAt: applied[m.Version],
+		}
+	}
+	return ss, nil
+}
+
+// loadMigrations returns the migrations for the database dialect sorted by
+// version together with the application times of those already applied.
+func (p *Persistence) loadMigrations() ([]Migration, map[int]int64, error) {
+	mm, err := readMigrations(p.DB.Dialect())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = p.DB.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s
+(
+  version    integer PRIMARY KEY,
+  name       text NOT NULL,
+  applied_at bigint
+)`, migrationsTable))
+	if err != nil {
+		return nil, nil, errors.DBError.Wrap(err, "error creating schema migrations table")
+	}
+
+	var aa []appliedMigration
+	if err := p.DB.From(migrationsTable).ScanStructs(&aa); err != nil {
+		return nil, nil, errors.DBError.Wrap(err, "error getting applied migrations")
+	}
+
+	applied := make(map[int]int64, len(aa))
+	for _, a := range aa {
+		applied[a.Version] = a.AppliedAt
+	}
+	return mm, applied, nil
+}
+
+func readMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	files, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, errors.DBError.Wrapf(err, "no migrations for %s", dialect)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, f := range files {
+		name := f.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		parts := strings.SplitN(base, "_", 2)
+		version, err := strconv.Atoi(parts[0])
+		if err != nil || len(parts) != 2 {
+			return nil, errors.DBError.Newf("invalid migration file name %s", name)
+		}
+
+		body, err := fs.ReadFile(migrationFiles, path.Join(dir, name))
+		if err != nil {
+			return nil, errors.DBError.Wrapf(err, "error reading migration %s", name)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: parts[1]}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	mm := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		mm = append(mm, *m)
+	}
+	sort.Slice(mm, func(i, j int) bool { return mm[i].Version < mm[j].Version })
+	return mm, nil
+}
+
+// lockMigrations keeps other processes from migrating the database until
+// the function returned is called. PostgreSQL holds an advisory lock on a
+// connection set aside for it. SQLite migrations lock the database within
+// their transaction instead, see lockMigrationsTx.
+func (p *Persistence) lockMigrations() (func(), error) {
+	if p.DB.Dialect() != Postgres {
+		return func() {}, nil
+	}
+
+	ctx := context.Background()
+	conn, err := p.sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error locking schema migrations")
+	}
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLock); err != nil {
+		conn.Close()
+		return nil, errors.DBError.Wrap(err, "error locking schema migrations")
+	}
+
+	return func() {
+		conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLock)
+		conn.Close()
+	}, nil
+}
+
+// lockMigrationsTx takes the SQLite write lock at the start of tx, waiting
+// for the busy timeout while another process migrates. A transaction
+// reading first could not wait for it, as it would then block its commit.
+func lockMigrationsTx(tx *goqu.TxDatabase) error {
+	if tx.Dialect() != SQLite {
+		return nil
+	}
+	_, err := tx.From(migrationsTable).Where(goqu.C("version").Lt(0)).Delete().Exec()
+	return err
+}
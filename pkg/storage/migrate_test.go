@@ -0,0 +1,131 @@
+package storage_test
+
+import (
+	"github.com/doug-martin/goqu/v7"
+	"reservations/pkg/storage"
+	"reservations/pkg/storage/storagetest"
+	"testing"
+	"time"
+)
+
+func TestMigrationsRoundTrip(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		ss, err := db.MigrationStatus()
+		if err != nil {
+			t.Fatalf("MigrationStatus: %v", err)
+		}
+		for _, s := range ss {
+			if s.AppliedAt == 0 {
+				t.Errorf("migration %04d_%s not applied", s.Version, s.Name)
+			}
+		}
+
+		if mm, err := db.Migrate(); err != nil || len(mm) != 0 {
+			t.Errorf("Migrate of a migrated database = %v, %v, want nothing applied", mm, err)
+		}
+
+		mm, err := db.Rollback(len(ss))
+		if err != nil {
+			t.Fatalf("Rollback: %v", err)
+		}
+		if len(mm) != len(ss) || mm[0].Version != ss[len(ss)-1].Version {
+			t.Errorf("Rollback reverted %d migrations, want all %d newest first", len(mm), len(ss))
+		}
+
+		if mm, err := db.Migrate(); err != nil || len(mm) != len(ss) {
+			t.Errorf("Migrate after the rollback = %d migrations, %v, want %d", len(mm), err, len(ss))
+		}
+	})
+}
+
+func TestConcurrentMigrations(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		ss, err := db.MigrationStatus()
+		if err != nil {
+			t.Fatalf("MigrationStatus: %v", err)
+		}
+		if _, err := db.Rollback(len(ss)); err != nil {
+			t.Fatalf("Rollback: %v", err)
+		}
+
+		// Each Migrate runs its transactions on connections of its own.
+		applied := make(chan int, 2)
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				mm, err := db.Migrate()
+				applied <- len(mm)
+				errs <- err
+			}()
+		}
+		total := <-applied + <-applied
+		for i := 0; i < 2; i++ {
+			if err := <-errs; err != nil {
+				t.Errorf("Migrate: %v", err)
+			}
+		}
+		if total != len(ss) {
+			t.Errorf("concurrent Migrate applied %d migrations, want each of the %d once", total, len(ss))
+		}
+	})
+}
+
+func TestMigrationBackfillsReservationTimes(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		// Back to the schema the backfill migration applies to.
+		for {
+			mm, err := db.Rollback(1)
+			if err != nil || len(mm) == 0 {
+				t.Fatalf("Rollback = %v, %v", mm, err)
+			}
+			if mm[0].Version == 14 {
+				break
+			}
+		}
+
+		_, err := db.DB.From("customer").Insert(goqu.Record{"cid": 1, "first_name": "Ann", "last_name": "Doe", "email": "ann@example.com"}).Exec()
+		if err != nil {
+			t.Fatal(err)
+		}
+		startTimes := map[int]string{
+			1: "1767261600",
+			2: "2026-01-01T11:00:00+01:00",
+			3: "next friday",
+		}
+		for rID, startTime := range startTimes {
+			_, err := db.DB.From("reservation").Insert(goqu.Record{"rid": rID, "customer_id": 1, "seat_count": 2, "start_time": startTime}).Exec()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		_, err = db.DB.From("reservation").Insert(goqu.Record{"rid": 4, "customer_id": 1, "seat_count": 2, "start_time": "1767261600", "starts_at": 1767265200, "ends_at": 1767268800}).Exec()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		db.BookingDuration = 90 * time.Minute
+		if _, err := db.Migrate(); err != nil {
+			t.Fatalf("Migrate: %v", err)
+		}
+
+		var times []struct {
+			ReservationID int   `db:"rid"`
+			StartsAt      int64 `db:"starts_at"`
+			EndsAt        int64 `db:"ends_at"`
+		}
+		if err := db.DB.From("reservation").Select("rid", "starts_at", "ends_at").Order(goqu.C("rid").Asc()).ScanStructs(&times); err != nil {
+			t.Fatal(err)
+		}
+		want := [][2]int64{
+			{1767261600, 1767267000},
+			{1767261600, 1767267000},
+			{0, 0},
+			{1767265200, 1767268800},
+		}
+		for i, got := range times {
+			if got.StartsAt != want[i][0] || got.EndsAt != want[i][1] {
+				t.Errorf("reservation %d runs from %d to %d, want %d to %d", got.ReservationID, got.StartsAt, got.EndsAt, want[i][0], want[i][1])
+			}
+		}
+	})
+}
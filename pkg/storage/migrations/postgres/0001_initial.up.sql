@@ -0,0 +1,97 @@
+CREATE TABLE IF NOT EXISTS customer
+(
+  cid          serial PRIMARY KEY,
+  first_name   text NOT NULL,
+  last_name    text NOT NULL,
+  email        text NOT NULL,
+  phone        text,
+  created      bigint,
+  last_updated bigint
+);
+
+CREATE TABLE IF NOT EXISTS reservation
+(
+  rid              serial PRIMARY KEY,
+  seat_count       integer DEFAULT 1,
+  start_time       text,
+  customer_id      integer REFERENCES customer (cid),
+  reservation_name text,
+  phone            text,
+  comments         text,
+  created          bigint,
+  last_updated     bigint
+);
+
+CREATE TABLE IF NOT EXISTS idempotency_key
+(
+  client_id       text    NOT NULL,
+  idempotency_key text    NOT NULL,
+  request_hash    text    NOT NULL,
+  status_code     integer NOT NULL,
+  content_type    text,
+  body            bytea,
+  created         bigint,
+  expires_at      bigint,
+  PRIMARY KEY (client_id, idempotency_key)
+);
+
+CREATE TABLE IF NOT EXISTS audit_log
+(
+  aid          serial PRIMARY KEY,
+  actor        text    NOT NULL,
+  action       text    NOT NULL,
+  entity_type  text    NOT NULL,
+  entity_id    integer NOT NULL,
+  before       text,
+  after        text,
+  diff         text,
+  request_id   text,
+  created      bigint
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_entity ON audit_log (entity_type, entity_id);
+
+CREATE TABLE IF NOT EXISTS outbox
+(
+  eid            serial PRIMARY KEY,
+  event_type     text    NOT NULL,
+  aggregate_type text    NOT NULL,
+  aggregate_id   integer NOT NULL,
+  payload        text,
+  created        bigint,
+  published_at   bigint,
+  attempts       integer DEFAULT 0,
+  last_error     text
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending ON outbox (published_at, eid);
+
+CREATE TABLE IF NOT EXISTS webhook_subscription
+(
+  sid          serial PRIMARY KEY,
+  url          text    NOT NULL,
+  event_types  text    NOT NULL,
+  secret       text    NOT NULL,
+  active       boolean DEFAULT true,
+  created      bigint,
+  last_updated bigint
+);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery
+(
+  did              serial PRIMARY KEY,
+  subscription_id  integer NOT NULL REFERENCES webhook_subscription (sid),
+  event_id         integer NOT NULL,
+  event_type       text    NOT NULL,
+  payload          text,
+  status           text    NOT NULL,
+  attempts         integer DEFAULT 0,
+  next_attempt_at  bigint,
+  last_status_code integer,
+  last_error       text,
+  created          bigint,
+  last_updated     bigint,
+  UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_delivery_due ON webhook_delivery (status, next_attempt_at);
\ No newline at end of file
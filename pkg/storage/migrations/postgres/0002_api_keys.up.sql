@@ -0,0 +1,9 @@
+CREATE TABLE api_key
+(
+  kid        serial PRIMARY KEY,
+  name       text,
+  subject    text   NOT NULL,
+  key_hash   text   NOT NULL UNIQUE,
+  created    bigint,
+  revoked_at bigint NOT NULL DEFAULT 0
+);
\ No newline at end of file
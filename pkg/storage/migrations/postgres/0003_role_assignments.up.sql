@@ -0,0 +1,8 @@
+CREATE TABLE role_assignment
+(
+  subject      text PRIMARY KEY,
+  role         text    NOT NULL,
+  customer_id  integer NOT NULL DEFAULT 0,
+  created      bigint,
+  last_updated bigint
+);
\ No newline at end of file
@@ -0,0 +1,21 @@
+DELETE FROM role_assignment WHERE venue_id <> 1;
+
+ALTER TABLE role_assignment DROP CONSTRAINT role_assignment_pkey;
+
+ALTER TABLE role_assignment ADD PRIMARY KEY (subject);
+
+ALTER TABLE role_assignment DROP COLUMN venue_id;
+
+ALTER TABLE webhook_delivery DROP COLUMN venue_id;
+
+ALTER TABLE webhook_subscription DROP COLUMN venue_id;
+
+ALTER TABLE outbox DROP COLUMN venue_id;
+
+ALTER TABLE audit_log DROP COLUMN venue_id;
+
+ALTER TABLE reservation DROP COLUMN venue_id;
+
+ALTER TABLE customer DROP COLUMN venue_id;
+
+DROP TABLE venue;
\ No newline at end of file
@@ -0,0 +1,37 @@
+CREATE TABLE venue
+(
+  vid          serial PRIMARY KEY,
+  slug         text NOT NULL UNIQUE,
+  name         text NOT NULL,
+  created      bigint,
+  last_updated bigint
+);
+
+INSERT INTO venue (vid, slug, name, created, last_updated)
+VALUES (1, 'default', 'Default', extract(epoch FROM now())::bigint, extract(epoch FROM now())::bigint);
+
+SELECT setval('venue_vid_seq', (SELECT max(vid) FROM venue));
+
+ALTER TABLE customer ADD COLUMN venue_id integer NOT NULL DEFAULT 1 REFERENCES venue (vid);
+
+ALTER TABLE reservation ADD COLUMN venue_id integer NOT NULL DEFAULT 1 REFERENCES venue (vid);
+
+ALTER TABLE audit_log ADD COLUMN venue_id integer NOT NULL DEFAULT 1 REFERENCES venue (vid);
+
+ALTER TABLE outbox ADD COLUMN venue_id integer NOT NULL DEFAULT 1 REFERENCES venue (vid);
+
+ALTER TABLE webhook_subscription ADD COLUMN venue_id integer NOT NULL DEFAULT 1 REFERENCES venue (vid);
+
+ALTER TABLE webhook_delivery ADD COLUMN venue_id integer NOT NULL DEFAULT 1 REFERENCES venue (vid);
+
+CREATE INDEX customer_venue ON customer (venue_id);
+
+CREATE INDEX reservation_venue ON reservation (venue_id, customer_id);
+
+CREATE INDEX audit_log_venue ON audit_log (venue_id, aid);
+
+ALTER TABLE role_assignment ADD COLUMN venue_id integer NOT NULL DEFAULT 1 REFERENCES venue (vid);
+
+ALTER TABLE role_assignment DROP CONSTRAINT role_assignment_pkey;
+
+ALTER TABLE role_assignment ADD PRIMARY KEY (venue_id, subject);
\ No newline at end of file
@@ -0,0 +1,15 @@
+DROP INDEX reservation_table;
+
+ALTER TABLE reservation DROP COLUMN ends_at;
+
+ALTER TABLE reservation DROP COLUMN starts_at;
+
+ALTER TABLE reservation DROP COLUMN needs_reassignment;
+
+ALTER TABLE reservation DROP COLUMN table_id;
+
+ALTER TABLE reservation DROP COLUMN area_id;
+
+DROP TABLE dining_table;
+
+DROP TABLE area
\ No newline at end of file
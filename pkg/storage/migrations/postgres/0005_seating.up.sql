@@ -0,0 +1,35 @@
+CREATE TABLE area
+(
+  aid           serial PRIMARY KEY,
+  venue_id      integer NOT NULL DEFAULT 1 REFERENCES venue (vid),
+  name          text    NOT NULL,
+  closed        boolean NOT NULL DEFAULT false,
+  closed_reason text    NOT NULL DEFAULT '',
+  created       bigint,
+  last_updated  bigint
+);
+
+CREATE TABLE dining_table
+(
+  tid          serial PRIMARY KEY,
+  venue_id     integer NOT NULL DEFAULT 1 REFERENCES venue (vid),
+  area_id      integer NOT NULL REFERENCES area (aid),
+  name         text    NOT NULL,
+  capacity     integer NOT NULL,
+  created      bigint,
+  last_updated bigint
+);
+
+CREATE INDEX dining_table_area ON dining_table (venue_id, area_id);
+
+ALTER TABLE reservation ADD COLUMN area_id integer NOT NULL DEFAULT 0;
+
+ALTER TABLE reservation ADD COLUMN table_id integer NOT NULL DEFAULT 0;
+
+ALTER TABLE reservation ADD COLUMN needs_reassignment boolean NOT NULL DEFAULT false;
+
+ALTER TABLE reservation ADD COLUMN starts_at bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE reservation ADD COLUMN ends_at bigint NOT NULL DEFAULT 0;
+
+CREATE INDEX reservation_table ON reservation (venue_id, table_id, starts_at)
\ No newline at end of file
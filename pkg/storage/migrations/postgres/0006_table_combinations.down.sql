@@ -0,0 +1,5 @@
+ALTER TABLE reservation DROP COLUMN combination_id;
+
+DROP TABLE combination_table;
+
+DROP TABLE table_combination
\ No newline at end of file
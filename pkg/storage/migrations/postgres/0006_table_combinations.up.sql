@@ -0,0 +1,20 @@
+CREATE TABLE table_combination
+(
+  coid         serial PRIMARY KEY,
+  venue_id     integer NOT NULL DEFAULT 1 REFERENCES venue (vid),
+  name         text    NOT NULL,
+  capacity     integer NOT NULL,
+  created      bigint,
+  last_updated bigint
+);
+
+CREATE TABLE combination_table
+(
+  combination_id integer NOT NULL REFERENCES table_combination (coid),
+  table_id       integer NOT NULL REFERENCES dining_table (tid),
+  PRIMARY KEY (combination_id, table_id)
+);
+
+CREATE INDEX combination_table_table ON combination_table (table_id);
+
+ALTER TABLE reservation ADD COLUMN combination_id integer NOT NULL DEFAULT 0
\ No newline at end of file
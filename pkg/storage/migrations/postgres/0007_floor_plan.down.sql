@@ -0,0 +1,15 @@
+ALTER TABLE reservation DROP COLUMN status;
+
+ALTER TABLE dining_table DROP COLUMN needs_cleaning;
+
+ALTER TABLE dining_table DROP COLUMN rotation;
+
+ALTER TABLE dining_table DROP COLUMN shape;
+
+ALTER TABLE dining_table DROP COLUMN height;
+
+ALTER TABLE dining_table DROP COLUMN width;
+
+ALTER TABLE dining_table DROP COLUMN pos_y;
+
+ALTER TABLE dining_table DROP COLUMN pos_x
\ No newline at end of file
@@ -0,0 +1,15 @@
+ALTER TABLE dining_table ADD COLUMN pos_x double precision NOT NULL DEFAULT 0;
+
+ALTER TABLE dining_table ADD COLUMN pos_y double precision NOT NULL DEFAULT 0;
+
+ALTER TABLE dining_table ADD COLUMN width double precision NOT NULL DEFAULT 0;
+
+ALTER TABLE dining_table ADD COLUMN height double precision NOT NULL DEFAULT 0;
+
+ALTER TABLE dining_table ADD COLUMN shape text NOT NULL DEFAULT 'square';
+
+ALTER TABLE dining_table ADD COLUMN rotation double precision NOT NULL DEFAULT 0;
+
+ALTER TABLE dining_table ADD COLUMN needs_cleaning boolean NOT NULL DEFAULT false;
+
+ALTER TABLE reservation ADD COLUMN status text NOT NULL DEFAULT 'booked'
\ No newline at end of file
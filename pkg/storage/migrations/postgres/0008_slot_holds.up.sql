@@ -0,0 +1,19 @@
+CREATE TABLE slot_hold
+(
+  hid            serial PRIMARY KEY,
+  venue_id       integer NOT NULL DEFAULT 1 REFERENCES venue (vid),
+  token          text    NOT NULL UNIQUE,
+  seats          integer NOT NULL,
+  start_time     text    NOT NULL,
+  area_id        integer NOT NULL DEFAULT 0,
+  table_id       integer NOT NULL DEFAULT 0,
+  combination_id integer NOT NULL DEFAULT 0,
+  starts_at      bigint  NOT NULL,
+  ends_at        bigint  NOT NULL,
+  expires_at     bigint  NOT NULL,
+  created        bigint
+);
+
+CREATE INDEX slot_hold_time ON slot_hold (venue_id, starts_at);
+
+CREATE INDEX slot_hold_expiry ON slot_hold (expires_at);
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP INDEX reservation_series_occurrence;
+
+ALTER TABLE reservation DROP COLUMN occurrence;
+
+ALTER TABLE reservation DROP COLUMN series_id;
+
+DROP TABLE reservation_series;
\ No newline at end of file
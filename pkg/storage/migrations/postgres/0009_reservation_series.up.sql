@@ -0,0 +1,29 @@
+CREATE TABLE reservation_series
+(
+  sid                serial PRIMARY KEY,
+  venue_id           integer NOT NULL DEFAULT 1 REFERENCES venue (vid),
+  customer_id        integer NOT NULL REFERENCES customer (cid),
+  seat_count         integer NOT NULL,
+  start_time         text    NOT NULL,
+  reservation_name   text    NOT NULL DEFAULT '',
+  phone              text    NOT NULL DEFAULT '',
+  comments           text    NOT NULL DEFAULT '',
+  area_id            integer NOT NULL DEFAULT 0,
+  frequency          text    NOT NULL,
+  repeat_interval    integer NOT NULL DEFAULT 1,
+  repeat_until       text    NOT NULL DEFAULT '',
+  repeat_count       integer NOT NULL DEFAULT 0,
+  exceptions         text    NOT NULL DEFAULT '',
+  duration           bigint  NOT NULL,
+  materialized_until bigint  NOT NULL DEFAULT 0,
+  created            bigint,
+  last_updated       bigint
+);
+
+CREATE INDEX reservation_series_customer ON reservation_series (venue_id, customer_id);
+
+ALTER TABLE reservation ADD COLUMN series_id integer NOT NULL DEFAULT 0;
+
+ALTER TABLE reservation ADD COLUMN occurrence text NOT NULL DEFAULT '';
+
+CREATE INDEX reservation_series_occurrence ON reservation (venue_id, series_id, occurrence);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE reservation DROP COLUMN fee;
+
+DROP TABLE reservation_fee;
\ No newline at end of file
@@ -0,0 +1,19 @@
+CREATE TABLE reservation_fee
+(
+  fid           serial PRIMARY KEY,
+  venue_id      integer NOT NULL DEFAULT 1 REFERENCES venue (vid),
+  rid           integer NOT NULL,
+  customer_id   integer NOT NULL REFERENCES customer (cid),
+  kind          text    NOT NULL,
+  policy        text    NOT NULL DEFAULT '',
+  amount        bigint  NOT NULL,
+  waived        boolean NOT NULL DEFAULT false,
+  waived_by     text    NOT NULL DEFAULT '',
+  waiver_reason text    NOT NULL DEFAULT '',
+  created       bigint,
+  last_updated  bigint
+);
+
+CREATE INDEX reservation_fee_customer ON reservation_fee (venue_id, customer_id);
+
+ALTER TABLE reservation ADD COLUMN fee bigint NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE reservation DROP COLUMN pay_by;
+
+ALTER TABLE reservation DROP COLUMN deposit;
+
+DROP TABLE deposit;
\ No newline at end of file
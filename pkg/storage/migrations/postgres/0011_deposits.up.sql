@@ -0,0 +1,25 @@
+CREATE TABLE deposit
+(
+  did          serial PRIMARY KEY,
+  venue_id     integer NOT NULL DEFAULT 1 REFERENCES venue (vid),
+  rid          integer NOT NULL,
+  customer_id  integer NOT NULL REFERENCES customer (cid),
+  policy       text    NOT NULL DEFAULT '',
+  amount       bigint  NOT NULL,
+  currency     text    NOT NULL,
+  status       text    NOT NULL,
+  payment_id   text    NOT NULL DEFAULT '',
+  captured     bigint  NOT NULL DEFAULT 0,
+  refunded     bigint  NOT NULL DEFAULT 0,
+  pay_by       bigint  NOT NULL,
+  created      bigint,
+  last_updated bigint
+);
+
+CREATE UNIQUE INDEX deposit_reservation ON deposit (venue_id, rid);
+
+CREATE INDEX deposit_unpaid ON deposit (status, pay_by);
+
+ALTER TABLE reservation ADD COLUMN deposit bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE reservation ADD COLUMN pay_by bigint NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1,7 @@
+UPDATE outbox SET published_at = NULL WHERE eid IN (SELECT eid FROM outbox_delivery);
+
+DROP TABLE outbox_delivery;
+
+DROP INDEX outbox_seq;
+
+ALTER TABLE outbox DROP COLUMN seq
\ No newline at end of file
@@ -0,0 +1,17 @@
+ALTER TABLE outbox ADD COLUMN seq bigint;
+
+UPDATE outbox SET seq = eid WHERE published_at IS NOT NULL;
+
+CREATE UNIQUE INDEX outbox_seq ON outbox (seq);
+
+CREATE TABLE outbox_delivery
+(
+  publisher  text    NOT NULL,
+  eid        integer NOT NULL REFERENCES outbox (eid),
+  seq        bigint  NOT NULL,
+  attempts   integer NOT NULL DEFAULT 0,
+  last_error text,
+  PRIMARY KEY (publisher, eid)
+);
+
+CREATE INDEX outbox_delivery_pending ON outbox_delivery (publisher, seq)
\ No newline at end of file
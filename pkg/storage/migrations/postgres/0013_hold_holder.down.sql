@@ -0,0 +1,3 @@
+DROP INDEX slot_hold_holder;
+
+ALTER TABLE slot_hold DROP COLUMN holder;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE slot_hold ADD COLUMN holder text NOT NULL DEFAULT '';
+
+CREATE INDEX slot_hold_holder ON slot_hold (venue_id, holder, expires_at);
\ No newline at end of file
@@ -0,0 +1 @@
+-- The start and end times derived by the up migration are kept.
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- Reservations booked before 0005_seating get the start time parsed from
+-- start_time, given either as Unix seconds or as an RFC 3339 timestamp.
+-- Unparseable ones keep 0. Their end time follows from the configured
+-- booking duration, which Persistence.Migrate sets after this migration.
+UPDATE reservation
+SET starts_at = start_time::bigint
+WHERE starts_at = 0
+  AND start_time ~ '^[0-9]+$';
+
+UPDATE reservation
+SET starts_at = EXTRACT(EPOCH FROM start_time::timestamptz)::bigint
+WHERE starts_at = 0
+  AND start_time ~ '^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})$';
\ No newline at end of file
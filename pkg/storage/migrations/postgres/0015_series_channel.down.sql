@@ -0,0 +1 @@
+ALTER TABLE reservation_series DROP COLUMN channel;
\ No newline at end of file
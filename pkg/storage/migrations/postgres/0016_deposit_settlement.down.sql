@@ -0,0 +1,5 @@
+ALTER TABLE deposit DROP COLUMN settlement;
+
+ALTER TABLE deposit DROP COLUMN fee_id;
+
+ALTER TABLE deposit DROP COLUMN keep;
\ No newline at end of file
@@ -0,0 +1,8 @@
+ALTER TABLE deposit ADD COLUMN keep bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE deposit ADD COLUMN fee_id integer NOT NULL DEFAULT 0;
+
+ALTER TABLE deposit ADD COLUMN settlement integer NOT NULL DEFAULT 0;
+
+-- Deposits settled so far keep what was captured and not refunded.
+UPDATE deposit SET keep = captured - refunded;
\ No newline at end of file
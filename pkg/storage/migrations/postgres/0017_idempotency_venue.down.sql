@@ -0,0 +1,8 @@
+-- Keys reused across venues would collide without the venue.
+DELETE FROM idempotency_key WHERE venue <> '';
+
+ALTER TABLE idempotency_key DROP CONSTRAINT idempotency_key_pkey;
+
+ALTER TABLE idempotency_key DROP COLUMN venue;
+
+ALTER TABLE idempotency_key ADD PRIMARY KEY (client_id, idempotency_key);
\ No newline at end of file
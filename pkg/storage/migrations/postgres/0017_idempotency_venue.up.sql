@@ -0,0 +1,5 @@
+ALTER TABLE idempotency_key ADD COLUMN venue text NOT NULL DEFAULT '';
+
+ALTER TABLE idempotency_key DROP CONSTRAINT idempotency_key_pkey;
+
+ALTER TABLE idempotency_key ADD PRIMARY KEY (client_id, venue, idempotency_key);
\ No newline at end of file
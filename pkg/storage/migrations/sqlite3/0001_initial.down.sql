@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS webhook_delivery;
+DROP TABLE IF EXISTS webhook_subscription;
+DROP TABLE IF EXISTS outbox;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS idempotency_key;
+DROP TABLE IF EXISTS reservation;
+DROP TABLE IF EXISTS customer;
\ No newline at end of file
@@ -0,0 +1,99 @@
+CREATE TABLE IF NOT EXISTS reservation
+(
+  rid              integer PRIMARY KEY AUTOINCREMENT,
+  seat_count       integer DEFAULT 1,
+  start_time       integer,
+  customer_id      integer,
+  reservation_name text,
+  phone            text,
+  comments         text,
+  created          integer,
+  last_updated     integer,
+  FOREIGN KEY (customer_id) REFERENCES customer (cid)
+);
+
+CREATE TABLE IF NOT EXISTS customer
+(
+  cid          integer PRIMARY KEY AUTOINCREMENT,
+  first_name   text NOT NULL,
+  last_name    text NOT NULL,
+  email        text NOT NULL,
+  phone        text,
+  created      integer,
+  last_updated integer
+);
+
+CREATE TABLE IF NOT EXISTS idempotency_key
+(
+  client_id       text    NOT NULL,
+  idempotency_key text    NOT NULL,
+  request_hash    text    NOT NULL,
+  status_code     integer NOT NULL,
+  content_type    text,
+  body            blob,
+  created         integer,
+  expires_at      integer,
+  PRIMARY KEY (client_id, idempotency_key)
+);
+
+CREATE TABLE IF NOT EXISTS audit_log
+(
+  aid          integer PRIMARY KEY AUTOINCREMENT,
+  actor        text NOT NULL,
+  action       text NOT NULL,
+  entity_type  text NOT NULL,
+  entity_id    integer NOT NULL,
+  before       text,
+  after        text,
+  diff         text,
+  request_id   text,
+  created      integer
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_entity ON audit_log (entity_type, entity_id);
+
+CREATE TABLE IF NOT EXISTS outbox
+(
+  eid            integer PRIMARY KEY AUTOINCREMENT,
+  event_type     text    NOT NULL,
+  aggregate_type text    NOT NULL,
+  aggregate_id   integer NOT NULL,
+  payload        text,
+  created        integer,
+  published_at   integer,
+  attempts       integer DEFAULT 0,
+  last_error     text
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending ON outbox (published_at, eid);
+
+CREATE TABLE IF NOT EXISTS webhook_subscription
+(
+  sid          integer PRIMARY KEY AUTOINCREMENT,
+  url          text    NOT NULL,
+  event_types  text    NOT NULL,
+  secret       text    NOT NULL,
+  active       integer DEFAULT 1,
+  created      integer,
+  last_updated integer
+);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery
+(
+  did              integer PRIMARY KEY AUTOINCREMENT,
+  subscription_id  integer NOT NULL,
+  event_id         integer NOT NULL,
+  event_type       text    NOT NULL,
+  payload          text,
+  status           text    NOT NULL,
+  attempts         integer DEFAULT 0,
+  next_attempt_at  integer,
+  last_status_code integer,
+  last_error       text,
+  created          integer,
+  last_updated     integer,
+  UNIQUE (subscription_id, event_id),
+  FOREIGN KEY (subscription_id) REFERENCES webhook_subscription (sid)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_delivery_due ON webhook_delivery (status, next_attempt_at);
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE api_key
+(
+  kid        integer PRIMARY KEY AUTOINCREMENT,
+  name       text,
+  subject    text    NOT NULL,
+  key_hash   text    NOT NULL UNIQUE,
+  created    integer,
+  revoked_at integer NOT NULL DEFAULT 0
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE role_assignment;
\ No newline at end of file
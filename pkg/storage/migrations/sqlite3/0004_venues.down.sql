@@ -0,0 +1,160 @@
+CREATE TABLE role_assignment_subject
+(
+  subject      text PRIMARY KEY,
+  role         text    NOT NULL,
+  customer_id  integer NOT NULL DEFAULT 0,
+  created      integer,
+  last_updated integer
+);
+
+INSERT INTO role_assignment_subject (subject, role, customer_id, created, last_updated)
+SELECT subject, role, customer_id, created, last_updated FROM role_assignment WHERE venue_id = 1;
+
+DROP TABLE role_assignment;
+
+ALTER TABLE role_assignment_subject RENAME TO role_assignment;
+
+ALTER TABLE customer RENAME TO customer_old;
+
+ALTER TABLE reservation RENAME TO reservation_old;
+
+ALTER TABLE audit_log RENAME TO audit_log_old;
+
+ALTER TABLE outbox RENAME TO outbox_old;
+
+ALTER TABLE webhook_subscription RENAME TO webhook_subscription_old;
+
+ALTER TABLE webhook_delivery RENAME TO webhook_delivery_old;
+
+DROP INDEX customer_venue;
+
+DROP INDEX reservation_venue;
+
+DROP INDEX audit_log_venue;
+
+DROP INDEX audit_log_entity;
+
+DROP INDEX outbox_pending;
+
+DROP INDEX webhook_delivery_due;
+
+CREATE TABLE customer
+(
+  cid          integer PRIMARY KEY AUTOINCREMENT,
+  first_name   text NOT NULL,
+  last_name    text NOT NULL,
+  email        text NOT NULL,
+  phone        text,
+  created      integer,
+  last_updated integer
+);
+
+INSERT INTO customer (cid, first_name, last_name, email, phone, created, last_updated)
+SELECT cid, first_name, last_name, email, phone, created, last_updated FROM customer_old;
+
+CREATE TABLE reservation
+(
+  rid              integer PRIMARY KEY AUTOINCREMENT,
+  seat_count       integer DEFAULT 1,
+  start_time       integer,
+  customer_id      integer,
+  reservation_name text,
+  phone            text,
+  comments         text,
+  created          integer,
+  last_updated     integer,
+  FOREIGN KEY (customer_id) REFERENCES customer (cid)
+);
+
+INSERT INTO reservation (rid, seat_count, start_time, customer_id, reservation_name, phone, comments, created, last_updated)
+SELECT rid, seat_count, start_time, customer_id, reservation_name, phone, comments, created, last_updated FROM reservation_old;
+
+CREATE TABLE audit_log
+(
+  aid          integer PRIMARY KEY AUTOINCREMENT,
+  actor        text NOT NULL,
+  action       text NOT NULL,
+  entity_type  text NOT NULL,
+  entity_id    integer NOT NULL,
+  before       text,
+  after        text,
+  diff         text,
+  request_id   text,
+  created      integer
+);
+
+INSERT INTO audit_log (aid, actor, action, entity_type, entity_id, before, after, diff, request_id, created)
+SELECT aid, actor, action, entity_type, entity_id, before, after, diff, request_id, created FROM audit_log_old;
+
+CREATE INDEX audit_log_entity ON audit_log (entity_type, entity_id);
+
+CREATE TABLE outbox
+(
+  eid            integer PRIMARY KEY AUTOINCREMENT,
+  event_type     text    NOT NULL,
+  aggregate_type text    NOT NULL,
+  aggregate_id   integer NOT NULL,
+  payload        text,
+  created        integer,
+  published_at   integer,
+  attempts       integer DEFAULT 0,
+  last_error     text
+);
+
+INSERT INTO outbox (eid, event_type, aggregate_type, aggregate_id, payload, created, published_at, attempts, last_error)
+SELECT eid, event_type, aggregate_type, aggregate_id, payload, created, published_at, attempts, last_error FROM outbox_old;
+
+CREATE INDEX outbox_pending ON outbox (published_at, eid);
+
+CREATE TABLE webhook_subscription
+(
+  sid          integer PRIMARY KEY AUTOINCREMENT,
+  url          text    NOT NULL,
+  event_types  text    NOT NULL,
+  secret       text    NOT NULL,
+  active       integer DEFAULT 1,
+  created      integer,
+  last_updated integer
+);
+
+INSERT INTO webhook_subscription (sid, url, event_types, secret, active, created, last_updated)
+SELECT sid, url, event_types, secret, active, created, last_updated FROM webhook_subscription_old;
+
+CREATE TABLE webhook_delivery
+(
+  did              integer PRIMARY KEY AUTOINCREMENT,
+  subscription_id  integer NOT NULL,
+  event_id         integer NOT NULL,
+  event_type       text    NOT NULL,
+  payload          text,
+  status           text    NOT NULL,
+  attempts         integer DEFAULT 0,
+  next_attempt_at  integer,
+  last_status_code integer,
+  last_error       text,
+  created          integer,
+  last_updated     integer,
+  UNIQUE (subscription_id, event_id),
+  FOREIGN KEY (subscription_id) REFERENCES webhook_subscription (sid)
+);
+
+INSERT INTO webhook_delivery (did, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+                              last_status_code, last_error, created, last_updated)
+SELECT did, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+       last_status_code, last_error, created, last_updated FROM webhook_delivery_old;
+
+CREATE INDEX webhook_delivery_due ON webhook_delivery (status, next_attempt_at);
+
+DROP TABLE webhook_delivery_old;
+
+DROP TABLE webhook_subscription_old;
+
+DROP TABLE outbox_old;
+
+DROP TABLE audit_log_old;
+
+DROP TABLE reservation_old;
+
+DROP TABLE customer_old;
+
+DROP TABLE venue
\ No newline at end of file
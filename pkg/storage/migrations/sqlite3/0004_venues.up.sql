@@ -0,0 +1,47 @@
+CREATE TABLE venue
+(
+  vid          integer PRIMARY KEY AUTOINCREMENT,
+  slug         text NOT NULL UNIQUE,
+  name         text NOT NULL,
+  created      integer,
+  last_updated integer
+);
+
+INSERT INTO venue (vid, slug, name, created, last_updated)
+VALUES (1, 'default', 'Default', strftime('%s', 'now'), strftime('%s', 'now'));
+
+ALTER TABLE customer ADD COLUMN venue_id integer NOT NULL DEFAULT 1;
+
+ALTER TABLE reservation ADD COLUMN venue_id integer NOT NULL DEFAULT 1;
+
+ALTER TABLE audit_log ADD COLUMN venue_id integer NOT NULL DEFAULT 1;
+
+ALTER TABLE outbox ADD COLUMN venue_id integer NOT NULL DEFAULT 1;
+
+ALTER TABLE webhook_subscription ADD COLUMN venue_id integer NOT NULL DEFAULT 1;
+
+ALTER TABLE webhook_delivery ADD COLUMN venue_id integer NOT NULL DEFAULT 1;
+
+CREATE INDEX customer_venue ON customer (venue_id);
+
+CREATE INDEX reservation_venue ON reservation (venue_id, customer_id);
+
+CREATE INDEX audit_log_venue ON audit_log (venue_id, aid);
+
+CREATE TABLE role_assignment_venue
+(
+  venue_id     integer NOT NULL DEFAULT 1,
+  subject      text    NOT NULL,
+  role         text    NOT NULL,
+  customer_id  integer NOT NULL DEFAULT 0,
+  created      integer,
+  last_updated integer,
+  PRIMARY KEY (venue_id, subject)
+);
+
+INSERT INTO role_assignment_venue (venue_id, subject, role, customer_id, created, last_updated)
+SELECT 1, subject, role, customer_id, created, last_updated FROM role_assignment;
+
+DROP TABLE role_assignment;
+
+ALTER TABLE role_assignment_venue RENAME TO role_assignment;
\ No newline at end of file
@@ -0,0 +1,33 @@
+ALTER TABLE reservation RENAME TO reservation_old;
+
+DROP INDEX reservation_venue;
+
+DROP INDEX reservation_table;
+
+CREATE TABLE reservation
+(
+  rid              integer PRIMARY KEY AUTOINCREMENT,
+  seat_count       integer DEFAULT 1,
+  start_time       integer,
+  customer_id      integer,
+  reservation_name text,
+  phone            text,
+  comments         text,
+  created          integer,
+  last_updated     integer,
+  venue_id         integer NOT NULL DEFAULT 1,
+  FOREIGN KEY (customer_id) REFERENCES customer (cid)
+);
+
+INSERT INTO reservation (rid, seat_count, start_time, customer_id, reservation_name, phone, comments, created, last_updated,
+                         venue_id)
+SELECT rid, seat_count, start_time, customer_id, reservation_name, phone, comments, created, last_updated,
+       venue_id FROM reservation_old;
+
+CREATE INDEX reservation_venue ON reservation (venue_id, customer_id);
+
+DROP TABLE reservation_old;
+
+DROP TABLE dining_table;
+
+DROP TABLE area
\ No newline at end of file
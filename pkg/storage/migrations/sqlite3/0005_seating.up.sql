@@ -0,0 +1,36 @@
+CREATE TABLE area
+(
+  aid           integer PRIMARY KEY AUTOINCREMENT,
+  venue_id      integer NOT NULL DEFAULT 1,
+  name          text    NOT NULL,
+  closed        integer NOT NULL DEFAULT 0,
+  closed_reason text    NOT NULL DEFAULT '',
+  created       integer,
+  last_updated  integer
+);
+
+CREATE TABLE dining_table
+(
+  tid          integer PRIMARY KEY AUTOINCREMENT,
+  venue_id     integer NOT NULL DEFAULT 1,
+  area_id      integer NOT NULL,
+  name         text    NOT NULL,
+  capacity     integer NOT NULL,
+  created      integer,
+  last_updated integer,
+  FOREIGN KEY (area_id) REFERENCES area (aid)
+);
+
+CREATE INDEX dining_table_area ON dining_table (venue_id, area_id);
+
+ALTER TABLE reservation ADD COLUMN area_id integer NOT NULL DEFAULT 0;
+
+ALTER TABLE reservation ADD COLUMN table_id integer NOT NULL DEFAULT 0;
+
+ALTER TABLE reservation ADD COLUMN needs_reassignment integer NOT NULL DEFAULT 0;
+
+ALTER TABLE reservation ADD COLUMN starts_at integer NOT NULL DEFAULT 0;
+
+ALTER TABLE reservation ADD COLUMN ends_at integer NOT NULL DEFAULT 0;
+
+CREATE INDEX reservation_table ON reservation (venue_id, table_id, starts_at)
\ No newline at end of file
@@ -0,0 +1,22 @@
+CREATE TABLE table_combination
+(
+  coid         integer PRIMARY KEY AUTOINCREMENT,
+  venue_id     integer NOT NULL DEFAULT 1,
+  name         text    NOT NULL,
+  capacity     integer NOT NULL,
+  created      integer,
+  last_updated integer
+);
+
+CREATE TABLE combination_table
+(
+  combination_id integer NOT NULL,
+  table_id       integer NOT NULL,
+  PRIMARY KEY (combination_id, table_id),
+  FOREIGN KEY (combination_id) REFERENCES table_combination (coid),
+  FOREIGN KEY (table_id) REFERENCES dining_table (tid)
+);
+
+CREATE INDEX combination_table_table ON combination_table (table_id);
+
+ALTER TABLE reservation ADD COLUMN combination_id integer NOT NULL DEFAULT 0
\ No newline at end of file
@@ -0,0 +1,80 @@
+ALTER TABLE reservation RENAME TO reservation_old;
+
+DROP INDEX reservation_venue;
+
+DROP INDEX reservation_table;
+
+CREATE TABLE reservation
+(
+  rid                integer PRIMARY KEY AUTOINCREMENT,
+  seat_count         integer DEFAULT 1,
+  start_time         integer,
+  customer_id        integer,
+  reservation_name   text,
+  phone              text,
+  comments           text,
+  created            integer,
+  last_updated       integer,
+  venue_id           integer NOT NULL DEFAULT 1,
+  area_id            integer NOT NULL DEFAULT 0,
+  table_id           integer NOT NULL DEFAULT 0,
+  needs_reassignment integer NOT NULL DEFAULT 0,
+  starts_at          integer NOT NULL DEFAULT 0,
+  ends_at            integer NOT NULL DEFAULT 0,
+  combination_id     integer NOT NULL DEFAULT 0,
+  FOREIGN KEY (customer_id) REFERENCES customer (cid)
+);
+
+INSERT INTO reservation (rid, seat_count, start_time, customer_id, reservation_name, phone, comments, created, last_updated,
+                         venue_id, area_id, table_id, needs_reassignment, starts_at, ends_at, combination_id)
+SELECT rid, seat_count, start_time, customer_id, reservation_name, phone, comments, created, last_updated,
+       venue_id, area_id, table_id, needs_reassignment, starts_at, ends_at, combination_id FROM reservation_old;
+
+CREATE INDEX reservation_venue ON reservation (venue_id, customer_id);
+
+CREATE INDEX reservation_table ON reservation (venue_id, table_id, starts_at);
+
+DROP TABLE reservation_old;
+
+ALTER TABLE combination_table RENAME TO combination_table_old;
+
+DROP INDEX combination_table_table;
+
+ALTER TABLE dining_table RENAME TO dining_table_old;
+
+DROP INDEX dining_table_area;
+
+CREATE TABLE dining_table
+(
+  tid          integer PRIMARY KEY AUTOINCREMENT,
+  venue_id     integer NOT NULL DEFAULT 1,
+  area_id      integer NOT NULL,
+  name         text    NOT NULL,
+  capacity     integer NOT NULL,
+  created      integer,
+  last_updated integer,
+  FOREIGN KEY (area_id) REFERENCES area (aid)
+);
+
+INSERT INTO dining_table (tid, venue_id, area_id, name, capacity, created, last_updated)
+SELECT tid, venue_id, area_id, name, capacity, created, last_updated FROM dining_table_old;
+
+CREATE INDEX dining_table_area ON dining_table (venue_id, area_id);
+
+CREATE TABLE combination_table
+(
+  combination_id integer NOT NULL,
+  table_id       integer NOT NULL,
+  PRIMARY KEY (combination_id, table_id),
+  FOREIGN KEY (combination_id) REFERENCES table_combination (coid),
+  FOREIGN KEY (table_id) REFERENCES dining_table (tid)
+);
+
+INSERT INTO combination_table (combination_id, table_id)
+SELECT combination_id, table_id FROM combination_table_old;
+
+CREATE INDEX combination_table_table ON combination_table (table_id);
+
+DROP TABLE combination_table_old;
+
+DROP TABLE dining_table_old
\ No newline at end of file
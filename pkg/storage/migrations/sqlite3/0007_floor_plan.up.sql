@@ -0,0 +1,15 @@
+ALTER TABLE dining_table ADD COLUMN pos_x real NOT NULL DEFAULT 0;
+
+ALTER TABLE dining_table ADD COLUMN pos_y real NOT NULL DEFAULT 0;
+
+ALTER TABLE dining_table ADD COLUMN width real NOT NULL DEFAULT 0;
+
+ALTER TABLE dining_table ADD COLUMN height real NOT NULL DEFAULT 0;
+
+ALTER TABLE dining_table ADD COLUMN shape text NOT NULL DEFAULT 'square';
+
+ALTER TABLE dining_table ADD COLUMN rotation real NOT NULL DEFAULT 0;
+
+ALTER TABLE dining_table ADD COLUMN needs_cleaning integer NOT NULL DEFAULT 0;
+
+ALTER TABLE reservation ADD COLUMN status text NOT NULL DEFAULT 'booked'
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE slot_hold;
\ No newline at end of file
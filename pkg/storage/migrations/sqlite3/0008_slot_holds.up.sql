@@ -0,0 +1,19 @@
+CREATE TABLE slot_hold
+(
+  hid            integer PRIMARY KEY AUTOINCREMENT,
+  venue_id       integer NOT NULL DEFAULT 1,
+  token          text    NOT NULL UNIQUE,
+  seats          integer NOT NULL,
+  start_time     text    NOT NULL,
+  area_id        integer NOT NULL DEFAULT 0,
+  table_id       integer NOT NULL DEFAULT 0,
+  combination_id integer NOT NULL DEFAULT 0,
+  starts_at      integer NOT NULL,
+  ends_at        integer NOT NULL,
+  expires_at     integer NOT NULL,
+  created        integer
+);
+
+CREATE INDEX slot_hold_time ON slot_hold (venue_id, starts_at);
+
+CREATE INDEX slot_hold_expiry ON slot_hold (expires_at);
\ No newline at end of file
@@ -0,0 +1,42 @@
+ALTER TABLE reservation RENAME TO reservation_old;
+
+DROP INDEX reservation_venue;
+
+DROP INDEX reservation_table;
+
+DROP INDEX reservation_series_occurrence;
+
+CREATE TABLE reservation
+(
+  rid                integer PRIMARY KEY AUTOINCREMENT,
+  seat_count         integer DEFAULT 1,
+  start_time         integer,
+  customer_id        integer,
+  reservation_name   text,
+  phone              text,
+  comments           text,
+  created            integer,
+  last_updated       integer,
+  venue_id           integer NOT NULL DEFAULT 1,
+  area_id            integer NOT NULL DEFAULT 0,
+  table_id           integer NOT NULL DEFAULT 0,
+  needs_reassignment integer NOT NULL DEFAULT 0,
+  starts_at          integer NOT NULL DEFAULT 0,
+  ends_at            integer NOT NULL DEFAULT 0,
+  combination_id     integer NOT NULL DEFAULT 0,
+  status             text    NOT NULL DEFAULT 'booked',
+  FOREIGN KEY (customer_id) REFERENCES customer (cid)
+);
+
+INSERT INTO reservation (rid, seat_count, start_time, customer_id, reservation_name, phone, comments, created, last_updated,
+                         venue_id, area_id, table_id, needs_reassignment, starts_at, ends_at, combination_id, status)
+SELECT rid, seat_count, start_time, customer_id, reservation_name, phone, comments, created, last_updated,
+       venue_id, area_id, table_id, needs_reassignment, starts_at, ends_at, combination_id, status FROM reservation_old;
+
+CREATE INDEX reservation_venue ON reservation (venue_id, customer_id);
+
+CREATE INDEX reservation_table ON reservation (venue_id, table_id, starts_at);
+
+DROP TABLE reservation_old;
+
+DROP TABLE reservation_series
\ No newline at end of file
@@ -0,0 +1,30 @@
+CREATE TABLE reservation_series
+(
+  sid                integer PRIMARY KEY AUTOINCREMENT,
+  venue_id           integer NOT NULL DEFAULT 1,
+  customer_id        integer NOT NULL,
+  seat_count         integer NOT NULL,
+  start_time         text    NOT NULL,
+  reservation_name   text    NOT NULL DEFAULT '',
+  phone              text    NOT NULL DEFAULT '',
+  comments           text    NOT NULL DEFAULT '',
+  area_id            integer NOT NULL DEFAULT 0,
+  frequency          text    NOT NULL,
+  repeat_interval    integer NOT NULL DEFAULT 1,
+  repeat_until       text    NOT NULL DEFAULT '',
+  repeat_count       integer NOT NULL DEFAULT 0,
+  exceptions         text    NOT NULL DEFAULT '',
+  duration           integer NOT NULL,
+  materialized_until integer NOT NULL DEFAULT 0,
+  created            integer,
+  last_updated       integer,
+  FOREIGN KEY (customer_id) REFERENCES customer (cid)
+);
+
+CREATE INDEX reservation_series_customer ON reservation_series (venue_id, customer_id);
+
+ALTER TABLE reservation ADD COLUMN series_id integer NOT NULL DEFAULT 0;
+
+ALTER TABLE reservation ADD COLUMN occurrence text NOT NULL DEFAULT '';
+
+CREATE INDEX reservation_series_occurrence ON reservation (venue_id, series_id, occurrence);
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE reservation_fee
+(
+  fid           integer PRIMARY KEY AUTOINCREMENT,
+  venue_id      integer NOT NULL DEFAULT 1,
+  rid           integer NOT NULL,
+  customer_id   integer NOT NULL,
+  kind          text    NOT NULL,
+  policy        text    NOT NULL DEFAULT '',
+  amount        integer NOT NULL,
+  waived        integer NOT NULL DEFAULT 0,
+  waived_by     text    NOT NULL DEFAULT '',
+  waiver_reason text    NOT NULL DEFAULT '',
+  created       integer,
+  last_updated  integer,
+  FOREIGN KEY (customer_id) REFERENCES customer (cid)
+);
+
+CREATE INDEX reservation_fee_customer ON reservation_fee (venue_id, customer_id);
+
+ALTER TABLE reservation ADD COLUMN fee integer NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1,26 @@
+CREATE TABLE deposit
+(
+  did          integer PRIMARY KEY AUTOINCREMENT,
+  venue_id     integer NOT NULL DEFAULT 1,
+  rid          integer NOT NULL,
+  customer_id  integer NOT NULL,
+  policy       text    NOT NULL DEFAULT '',
+  amount       integer NOT NULL,
+  currency     text    NOT NULL,
+  status       text    NOT NULL,
+  payment_id   text    NOT NULL DEFAULT '',
+  captured     integer NOT NULL DEFAULT 0,
+  refunded     integer NOT NULL DEFAULT 0,
+  pay_by       integer NOT NULL,
+  created      integer,
+  last_updated integer,
+  FOREIGN KEY (customer_id) REFERENCES customer (cid)
+);
+
+CREATE UNIQUE INDEX deposit_reservation ON deposit (venue_id, rid);
+
+CREATE INDEX deposit_unpaid ON deposit (status, pay_by);
+
+ALTER TABLE reservation ADD COLUMN deposit integer NOT NULL DEFAULT 0;
+
+ALTER TABLE reservation ADD COLUMN pay_by integer NOT NULL DEFAULT 0;
\ No newline at end of file
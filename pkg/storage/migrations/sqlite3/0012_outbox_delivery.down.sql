@@ -0,0 +1,32 @@
+UPDATE outbox SET published_at = NULL WHERE eid IN (SELECT eid FROM outbox_delivery);
+
+DROP TABLE outbox_delivery;
+
+DROP INDEX outbox_seq;
+
+ALTER TABLE outbox RENAME TO outbox_old;
+
+DROP INDEX outbox_pending;
+
+CREATE TABLE outbox
+(
+  eid            integer PRIMARY KEY AUTOINCREMENT,
+  event_type     text    NOT NULL,
+  aggregate_type text    NOT NULL,
+  aggregate_id   integer NOT NULL,
+  payload        text,
+  created        integer,
+  published_at   integer,
+  attempts       integer DEFAULT 0,
+  last_error     text,
+  venue_id       integer NOT NULL DEFAULT 1
+);
+
+INSERT INTO outbox (eid, event_type, aggregate_type, aggregate_id, payload, created, published_at, attempts, last_error,
+                    venue_id)
+SELECT eid, event_type, aggregate_type, aggregate_id, payload, created, published_at, attempts, last_error,
+       venue_id FROM outbox_old;
+
+CREATE INDEX outbox_pending ON outbox (published_at, eid);
+
+DROP TABLE outbox_old
\ No newline at end of file
@@ -0,0 +1,18 @@
+ALTER TABLE outbox ADD COLUMN seq integer;
+
+UPDATE outbox SET seq = eid WHERE published_at IS NOT NULL;
+
+CREATE UNIQUE INDEX outbox_seq ON outbox (seq);
+
+CREATE TABLE outbox_delivery
+(
+  publisher  text    NOT NULL,
+  eid        integer NOT NULL,
+  seq        integer NOT NULL,
+  attempts   integer NOT NULL DEFAULT 0,
+  last_error text,
+  PRIMARY KEY (publisher, eid),
+  FOREIGN KEY (eid) REFERENCES outbox (eid)
+);
+
+CREATE INDEX outbox_delivery_pending ON outbox_delivery (publisher, seq)
\ No newline at end of file
@@ -0,0 +1,34 @@
+ALTER TABLE slot_hold RENAME TO slot_hold_old;
+
+DROP INDEX slot_hold_time;
+
+DROP INDEX slot_hold_expiry;
+
+DROP INDEX slot_hold_holder;
+
+CREATE TABLE slot_hold
+(
+  hid            integer PRIMARY KEY AUTOINCREMENT,
+  venue_id       integer NOT NULL DEFAULT 1,
+  token          text    NOT NULL UNIQUE,
+  seats          integer NOT NULL,
+  start_time     text    NOT NULL,
+  area_id        integer NOT NULL DEFAULT 0,
+  table_id       integer NOT NULL DEFAULT 0,
+  combination_id integer NOT NULL DEFAULT 0,
+  starts_at      integer NOT NULL,
+  ends_at        integer NOT NULL,
+  expires_at     integer NOT NULL,
+  created        integer
+);
+
+INSERT INTO slot_hold (hid, venue_id, token, seats, start_time, area_id, table_id, combination_id, starts_at, ends_at,
+                       expires_at, created)
+SELECT hid, venue_id, token, seats, start_time, area_id, table_id, combination_id, starts_at, ends_at,
+       expires_at, created FROM slot_hold_old;
+
+CREATE INDEX slot_hold_time ON slot_hold (venue_id, starts_at);
+
+CREATE INDEX slot_hold_expiry ON slot_hold (expires_at);
+
+DROP TABLE slot_hold_old
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- Reservations booked before 0005_seating get the start time parsed from
+-- start_time, given either as Unix seconds or as an RFC 3339 timestamp.
+-- Unparseable ones keep 0. Their end time follows from the configured
+-- booking duration, which Persistence.Migrate sets after this migration.
+UPDATE reservation
+SET starts_at = CASE
+                  WHEN start_time NOT GLOB '*[^0-9]*' THEN CAST(start_time AS integer)
+                  ELSE COALESCE(CAST(strftime('%s', start_time) AS integer), 0)
+                END
+WHERE starts_at = 0
+  AND start_time IS NOT NULL
+  AND start_time <> '';
\ No newline at end of file
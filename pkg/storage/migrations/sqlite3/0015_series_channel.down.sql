@@ -0,0 +1,37 @@
+ALTER TABLE reservation_series RENAME TO reservation_series_old;
+
+DROP INDEX reservation_series_customer;
+
+CREATE TABLE reservation_series
+(
+  sid                integer PRIMARY KEY AUTOINCREMENT,
+  venue_id           integer NOT NULL DEFAULT 1,
+  customer_id        integer NOT NULL,
+  seat_count         integer NOT NULL,
+  start_time         text    NOT NULL,
+  reservation_name   text    NOT NULL DEFAULT '',
+  phone              text    NOT NULL DEFAULT '',
+  comments           text    NOT NULL DEFAULT '',
+  area_id            integer NOT NULL DEFAULT 0,
+  frequency          text    NOT NULL,
+  repeat_interval    integer NOT NULL DEFAULT 1,
+  repeat_until       text    NOT NULL DEFAULT '',
+  repeat_count       integer NOT NULL DEFAULT 0,
+  exceptions         text    NOT NULL DEFAULT '',
+  duration           integer NOT NULL,
+  materialized_until integer NOT NULL DEFAULT 0,
+  created            integer,
+  last_updated       integer,
+  FOREIGN KEY (customer_id) REFERENCES customer (cid)
+);
+
+INSERT INTO reservation_series (sid, venue_id, customer_id, seat_count, start_time, reservation_name, phone, comments,
+                                area_id, frequency, repeat_interval, repeat_until, repeat_count, exceptions, duration,
+                                materialized_until, created, last_updated)
+SELECT sid, venue_id, customer_id, seat_count, start_time, reservation_name, phone, comments,
+       area_id, frequency, repeat_interval, repeat_until, repeat_count, exceptions, duration,
+       materialized_until, created, last_updated FROM reservation_series_old;
+
+CREATE INDEX reservation_series_customer ON reservation_series (venue_id, customer_id);
+
+DROP TABLE reservation_series_old
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Series booked before channels were recorded are taken for staff bookings.
+ALTER TABLE reservation_series ADD COLUMN channel text NOT NULL DEFAULT 'staff';
\ No newline at end of file
@@ -0,0 +1,35 @@
+ALTER TABLE deposit RENAME TO deposit_old;
+
+DROP INDEX deposit_reservation;
+
+DROP INDEX deposit_unpaid;
+
+CREATE TABLE deposit
+(
+  did          integer PRIMARY KEY AUTOINCREMENT,
+  venue_id     integer NOT NULL DEFAULT 1,
+  rid          integer NOT NULL,
+  customer_id  integer NOT NULL,
+  policy       text    NOT NULL DEFAULT '',
+  amount       integer NOT NULL,
+  currency     text    NOT NULL,
+  status       text    NOT NULL,
+  payment_id   text    NOT NULL DEFAULT '',
+  captured     integer NOT NULL DEFAULT 0,
+  refunded     integer NOT NULL DEFAULT 0,
+  pay_by       integer NOT NULL,
+  created      integer,
+  last_updated integer,
+  FOREIGN KEY (customer_id) REFERENCES customer (cid)
+);
+
+INSERT INTO deposit (did, venue_id, rid, customer_id, policy, amount, currency, status, payment_id, captured, refunded,
+                     pay_by, created, last_updated)
+SELECT did, venue_id, rid, customer_id, policy, amount, currency, status, payment_id, captured, refunded,
+       pay_by, created, last_updated FROM deposit_old;
+
+CREATE UNIQUE INDEX deposit_reservation ON deposit (venue_id, rid);
+
+CREATE INDEX deposit_unpaid ON deposit (status, pay_by);
+
+DROP TABLE deposit_old
\ No newline at end of file
@@ -0,0 +1,21 @@
+ALTER TABLE idempotency_key RENAME TO idempotency_key_old;
+
+CREATE TABLE idempotency_key
+(
+  client_id       text    NOT NULL,
+  idempotency_key text    NOT NULL,
+  request_hash    text    NOT NULL,
+  status_code     integer NOT NULL,
+  content_type    text,
+  body            blob,
+  created         integer,
+  expires_at      integer,
+  PRIMARY KEY (client_id, idempotency_key)
+);
+
+-- Keys reused across venues would collide without the venue.
+INSERT INTO idempotency_key (client_id, idempotency_key, request_hash, status_code, content_type, body, created, expires_at)
+SELECT client_id, idempotency_key, request_hash, status_code, content_type, body, created, expires_at FROM idempotency_key_old
+WHERE venue = '';
+
+DROP TABLE idempotency_key_old
\ No newline at end of file
@@ -0,0 +1,18 @@
+package storage
+
+import (
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanOptions describes the database to spans of repository calls.
+func (p Persistence) SpanOptions() []trace.SpanStartOption {
+	system := semconv.DBSystemSqlite
+	if p.DB.Dialect() == Postgres {
+		system = semconv.DBSystemPostgreSQL
+	}
+	return []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(system),
+	}
+}
@@ -0,0 +1,78 @@
+package stream
+
+import (
+	"context"
+	"reservations/pkg/event"
+	"sync"
+)
+
+const defaultBuffer = 64
+
+// Broker fans the events published by the outbox relay out to the
+// subscribers of their venue. It is meant to be registered with the relay
+// as a publisher. Publishing never blocks: a subscriber that does not keep
+// up is dropped, and is expected to resume from the last event it received.
+type Broker struct {
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	buffer int
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subs:   make(map[*Subscription]struct{}),
+		buffer: defaultBuffer,
+	}
+}
+
+// Subscription receives the events of one venue on C, which is closed when
+// the subscription is closed or dropped.
+type Subscription struct {
+	C <-chan event.Event
+
+	c       chan event.Event
+	venueID int
+	broker  *Broker
+}
+
+// Subscribe returns a subscription to the events of the venue vID.
+func (b *Broker) Subscribe(vID int) *Subscription {
+	c := make(chan event.Event, b.buffer)
+	s := &Subscription{C: c, c: c, venueID: vID, broker: b}
+
+	b.mu.Lock()
+	b.subs[s] = struct{}{}
+	b.mu.Unlock()
+	return s
+}
+
+// Close ends the subscription. It may be called more than once.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.drop(s)
+}
+
+func (b *Broker) drop(s *Subscription) {
+	if _, ok := b.subs[s]; ok {
+		delete(b.subs, s)
+		close(s.c)
+	}
+}
+
+func (b *Broker) Publish(_ context.Context, e event.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subs {
+		if s.venueID != e.VenueID {
+			continue
+		}
+		select {
+		case s.c <- e:
+		default:
+			b.drop(s)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package stream
+
+import (
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"reservations/pkg/authz"
+)
+
+type Endpoints struct {
+	StreamEventsEndpoint endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns the service endpoints, each checked against
+// the policy by az and wrapped in the given middlewares, the first one
+// outermost.
+func MakeServerEndpoints(s Service, az authz.Authorizer, mw ...endpoint.Middleware) Endpoints {
+	e := Endpoints{
+		StreamEventsEndpoint: az.Require(authz.PermFloorHost, nil)(MakeStreamEventsEndpoint(s)),
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		m := mw[i]
+		e.StreamEventsEndpoint = m(e.StreamEventsEndpoint)
+	}
+	return e
+}
+
+type streamEventsRequest struct {
+	LastEventID int
+}
+
+type streamEventsResponse struct {
+	Stream *Stream
+	Err    error
+}
+
+func (r streamEventsResponse) HTTPError() error { return r.Err }
+
+// StreamEvents godoc
+// @Summary Stream reservation and table changes
+// @Description Push reservation and table state events as Server-Sent Events while they are committed. Each event carries its sequence number, in the order the events were committed, as its id, which a client reconnecting sends back in the Last-Event-ID header to receive the events it missed.
+// @Tags stream
+// @Param Last-Event-ID header int false "Sequence number of the last event received"
+// @Param lastEventId query int false "Sequence number of the last event received, for clients unable to set headers"
+// @Produce  text/event-stream
+// @Success 200 {object} event.Event
+// @Router /events/stream [get]
+func MakeStreamEventsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(streamEventsRequest)
+		st, e := s.Subscribe(ctx, req.LastEventID)
+		return streamEventsResponse{
+			Stream: st,
+			Err:    e,
+		}, nil
+	}
+}
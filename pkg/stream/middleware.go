@@ -0,0 +1,31 @@
+package stream
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"time"
+)
+
+// Middleware describes a service (as opposed to endpoint) middleware.
+type Middleware func(Service) Service
+
+func LoggingMiddleware(logger log.Logger) Middleware {
+	return func(next Service) Service {
+		return &loggingMiddleware{
+			next:   next,
+			logger: logger,
+		}
+	}
+}
+
+type loggingMiddleware struct {
+	next   Service
+	logger log.Logger
+}
+
+func (mw loggingMiddleware) Subscribe(ctx context.Context, lastEventID int) (result *Stream, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "Subscribe", "last_event", lastEventID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.Subscribe(ctx, lastEventID)
+}
@@ -0,0 +1,46 @@
+package stream
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v7"
+	errors "reservations/pkg/error"
+	"reservations/pkg/event"
+	"reservations/pkg/storage"
+	"reservations/pkg/tracing"
+)
+
+type Repository interface {
+	FindEventsAfter(ctx context.Context, vID int, seq int, limit uint) ([]event.Event, error)
+}
+
+type streamRepository struct {
+	db storage.Persistence
+}
+
+func NewStreamRepository(db storage.Persistence) Repository {
+	return &streamRepository{db: db}
+}
+
+// FindEventsAfter reads the streamed events of the venue vID sequenced by the
+// outbox relay after the sequence number seq, delivered to the broker or not, in sequence.
+// Events not yet sequenced are left to the broker, which sees them after.
+func (r *streamRepository) FindEventsAfter(ctx context.Context, vID int, seq int, limit uint) (ee []event.Event, err error) {
+	ctx, span := tracing.StartSpan(ctx, "streamRepository.FindEventsAfter", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	err = r.db.DB.From("outbox").
+		Select("eid", "seq", "event_type", "aggregate_type", "aggregate_id", "venue_id", "payload", "created").
+		Where(goqu.Ex{
+			"venue_id":       vID,
+			"seq":            goqu.Op{"gt": seq},
+			"aggregate_type": aggregates,
+		}).
+		Order(goqu.C("seq").Asc()).
+		Limit(limit).
+		ScanStructs(&ee)
+
+	if err != nil {
+		return nil, errors.DBError.Wrapf(err, "error getting events after %d", seq)
+	}
+	return ee, nil
+}
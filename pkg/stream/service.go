@@ -0,0 +1,117 @@
+package stream
+
+import (
+	"context"
+	errors "reservations/pkg/error"
+	"reservations/pkg/event"
+	"reservations/pkg/tenant"
+)
+
+const replayBatchSize uint = 100
+
+// aggregates are the kinds of aggregates whose events are streamed.
+var aggregates = []string{event.AggregateReservation, event.AggregateTable}
+
+// ErrDropped ends a stream whose client did not keep up with the events.
+var ErrDropped = errors.New("stream dropped as the client fell behind")
+
+type Service interface {
+	// Subscribe opens a stream of the reservation and table events of the
+	// venue. Events sequenced after lastEventID, the sequence number of the
+	// last event the client received, are replayed first, unless it is 0.
+	Subscribe(ctx context.Context, lastEventID int) (*Stream, error)
+}
+
+type streamService struct {
+	repo   Repository
+	broker *Broker
+}
+
+// NewStreamService returns the stream service, which takes the live events
+// from broker.
+func NewStreamService(repo Repository, broker *Broker) Service {
+	return &streamService{
+		repo:   repo,
+		broker: broker,
+	}
+}
+
+func (s *streamService) Subscribe(ctx context.Context, lastEventID int) (*Stream, error) {
+	if lastEventID < 0 {
+		return nil, errors.ValidationError.Newf("invalid event ID %d", lastEventID).
+			AddContext("Last-Event-ID", "must not be negative")
+	}
+
+	// The subscription is taken before replaying so that no event sequenced
+	// meanwhile is missed; the ones seen twice are skipped by sequence.
+	// The venue is taken now: the stream is read outside the endpoint, from
+	// contexts which do not carry it.
+	vID := tenant.VenueFromContext(ctx)
+	return &Stream{
+		sub:       s.broker.Subscribe(vID),
+		repo:      s.repo,
+		venueID:   vID,
+		last:      lastEventID,
+		replaying: lastEventID != 0,
+	}, nil
+}
+
+// Stream is the sequence of events seen by one client.
+type Stream struct {
+	sub       *Subscription
+	repo      Repository
+	venueID   int
+	last      int
+	replaying bool
+	pending   []event.Event
+}
+
+// Next blocks until the next event is available or ctx is done. It returns
+// ErrDropped once the stream has been dropped for falling behind.
+func (s *Stream) Next(ctx context.Context) (event.Event, error) {
+	for {
+		if len(s.pending) > 0 {
+			e := s.pending[0]
+			s.pending = s.pending[1:]
+			s.last = e.Sequence
+			return e, nil
+		}
+
+		if s.replaying {
+			ee, err := s.repo.FindEventsAfter(ctx, s.venueID, s.last, replayBatchSize)
+			if err != nil {
+				return event.Event{}, err
+			}
+			s.pending = ee
+			s.replaying = uint(len(ee)) == replayBatchSize
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return event.Event{}, ctx.Err()
+		case e, ok := <-s.sub.C:
+			if !ok {
+				return event.Event{}, ErrDropped
+			}
+			if e.Sequence > s.last && streamed(e) {
+				s.last = e.Sequence
+				return e, nil
+			}
+		}
+	}
+}
+
+// Close releases the subscription of the stream.
+func (s *Stream) Close() {
+	s.sub.Close()
+}
+
+func streamed(e event.Event) bool {
+	for _, a := range aggregates {
+		if e.AggregateType == a {
+			return true
+		}
+	}
+	return false
+}
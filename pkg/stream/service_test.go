@@ -0,0 +1,49 @@
+package stream
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v7"
+	"reservations/pkg/event"
+	"reservations/pkg/outbox"
+	"reservations/pkg/storage"
+	"reservations/pkg/storage/storagetest"
+	"reservations/pkg/tenant"
+	"testing"
+)
+
+func TestStreamReplaysEventsOfItsVenue(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T, db *storage.Persistence) {
+		// Events 1 and 3 are of the first venue, 2 and 4 of the second.
+		err := db.WithTx(func(tx *goqu.TxDatabase) error {
+			for id := 1; id <= 4; id++ {
+				ctx := tenant.WithVenue(context.Background(), 2-id%2)
+				if err := outbox.Append(ctx, tx, event.ReservationBooked, event.AggregateReservation, id, map[string]int{"reservationId": id}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+		if _, err := outbox.NewOutboxRepository(*db).DispatchPendingEvents(context.Background(), []string{"stream"}, 10); err != nil {
+			t.Fatalf("DispatchPendingEvents: %v", err)
+		}
+
+		svc := NewStreamService(NewStreamRepository(*db), NewBroker())
+		st, err := svc.Subscribe(tenant.WithVenue(context.Background(), 2), 1)
+		if err != nil {
+			t.Fatalf("Subscribe: %v", err)
+		}
+		defer st.Close()
+
+		// The stream is read from the context of the transport, which does
+		// not carry the venue.
+		for _, want := range []int{2, 4} {
+			e, err := st.Next(context.Background())
+			if err != nil || e.AggregateID != want || e.VenueID != 2 {
+				t.Fatalf("Next = %+v, %v, want the event of reservation %d of venue 2", e, err, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"net/http"
+	"reservations/pkg/authz"
+	errors "reservations/pkg/error"
+	"reservations/pkg/transport"
+	"strconv"
+	"time"
+)
+
+const (
+	// HeaderLastEventID is sent by reconnecting clients with the id of the
+	// last event they received, which is its sequence number.
+	HeaderLastEventID = "Last-Event-ID"
+
+	// keepAlive is how often a comment is sent on an idle stream so that
+	// proxies do not close it.
+	keepAlive = 15 * time.Second
+)
+
+func MakeHTTPHandler(r *mux.Router, s Service, az authz.Authorizer, logger log.Logger, mw ...endpoint.Middleware) *mux.Router {
+	e := MakeServerEndpoints(s, az, mw...)
+
+	options := httpjson.DefaultServerOptions(logger)
+
+	r.Methods("GET").Path("/events/stream").
+		Handler(httpjson.KeepConnection(httptransport.NewServer(
+			e.StreamEventsEndpoint,
+			decodeStreamEventsRequest,
+			encodeStreamEventsResponse(logger),
+			options...,
+		)))
+
+	return r
+}
+
+func decodeStreamEventsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id := r.Header.Get(HeaderLastEventID)
+	if id == "" {
+		id = r.URL.Query().Get("lastEventId")
+	}
+	if id == "" {
+		return streamEventsRequest{}, nil
+	}
+
+	eID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, errors.ValidationError.Newf("invalid event ID %q", id).
+			AddContext(HeaderLastEventID, "must be the id of an event")
+	}
+	return streamEventsRequest{LastEventID: eID}, nil
+}
+
+// encodeStreamEventsResponse writes the events of the stream until the
+// client goes away. Errors past the headers cannot be reported to the client
+// any more and are logged instead, ending the stream for the client to
+// reconnect.
+func encodeStreamEventsResponse(logger log.Logger) httptransport.EncodeResponseFunc {
+	return func(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+		resp := response.(streamEventsResponse)
+		if resp.Err != nil {
+			return httpjson.EncodeResponse(ctx, w, resp)
+		}
+		st := resp.Stream
+		defer st.Close()
+
+		conn, _, _ := httpjson.ConnectionFromContext(ctx)
+		flusher, ok := conn.(http.Flusher)
+		if !ok {
+			return errors.New("streaming is not supported by the connection")
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for {
+			next, cancel := context.WithTimeout(ctx, keepAlive)
+			e, err := st.Next(next)
+			cancel()
+
+			switch {
+			case err == nil:
+				data, err := json.Marshal(e)
+				if err != nil {
+					logger.Log("component", "stream", "event", e.EventID, "err", err)
+					return nil
+				}
+				if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Sequence, e.Type, data); err != nil {
+					return nil
+				}
+			case ctx.Err() != nil:
+				return nil
+			case next.Err() != nil:
+				// Nothing happened for a while.
+				if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+					return nil
+				}
+			default:
+				logger.Log("component", "stream", "err", err)
+				return nil
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Package tenant lets one deployment serve several venues. Every request is
+// resolved to a venue, and repositories confine their queries to the venue
+// found in the context, so venues never see each other's data.
+package tenant
+
+import (
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	grpctransport "github.com/go-kit/kit/transport/grpc"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"google.golang.org/grpc/metadata"
+	"net/http"
+	"reservations/pkg/auth"
+	errors "reservations/pkg/error"
+	"strings"
+)
+
+// DefaultVenueID is the venue of single venue deployments, which also owns
+// all data created before venues existed.
+const DefaultVenueID = 1
+
+const (
+	// HeaderVenue names the venue a request is made for by its slug.
+	HeaderVenue = "X-Venue"
+	// MetadataVenue is the gRPC counterpart of HeaderVenue.
+	MetadataVenue = "x-venue"
+	// ClaimVenue is the JWT claim binding a token to a venue.
+	ClaimVenue = "venue"
+	// PathPrefix selects the venue in the URL, as in /venue/{slug}/customers.
+	PathPrefix = "/venue/"
+)
+
+type contextKey int
+
+const (
+	venueIDKey contextKey = iota
+	venueSlugKey
+)
+
+// WithVenue returns a copy of ctx confined to the venue with the given ID.
+func WithVenue(ctx context.Context, vID int) context.Context {
+	return context.WithValue(ctx, venueIDKey, vID)
+}
+
+// VenueFromContext returns the venue ctx is confined to, DefaultVenueID if
+// none.
+func VenueFromContext(ctx context.Context) int {
+	if vID, ok := ctx.Value(venueIDKey).(int); ok {
+		return vID
+	}
+	return DefaultVenueID
+}
+
+// WithVenueSlug returns a copy of ctx carrying the slug of the venue a
+// caller asked for, or a client is to ask for.
+func WithVenueSlug(ctx context.Context, slug string) context.Context {
+	return context.WithValue(ctx, venueSlugKey, slug)
+}
+
+// VenueSlugFromContext returns the slug of the venue a caller asked for,
+// which Middleware replaces by the one of the venue the request is
+// confined to.
+func VenueSlugFromContext(ctx context.Context) string {
+	slug, _ := ctx.Value(venueSlugKey).(string)
+	return slug
+}
+
+// HTTPToContext moves the venue named by a request into the context, where
+// Middleware resolves it.
+func HTTPToContext() httptransport.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		if slug := r.Header.Get(HeaderVenue); slug != "" {
+			ctx = WithVenueSlug(ctx, slug)
+		}
+		return ctx
+	}
+}
+
+// GRPCToContext is the gRPC counterpart of HTTPToContext.
+func GRPCToContext() grpctransport.ServerRequestFunc {
+	return func(ctx context.Context, md metadata.MD) context.Context {
+		if vv := md.Get(MetadataVenue); len(vv) > 0 {
+			ctx = WithVenueSlug(ctx, vv[0])
+		}
+		return ctx
+	}
+}
+
+// ContextToHTTP names the venue stored in the context on an outgoing
+// request.
+func ContextToHTTP() httptransport.RequestFunc {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		if slug := VenueSlugFromContext(ctx); slug != "" {
+			r.Header.Set(HeaderVenue, slug)
+		}
+		return ctx
+	}
+}
+
+// StripPathPrefix serves requests for /venue/{slug}/... as requests for the
+// remaining path made with the venue header set, so every route is also
+// available below the prefix.
+func StripPathPrefix(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, PathPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rest := strings.TrimPrefix(r.URL.Path, PathPrefix)
+		i := strings.IndexByte(rest, '/')
+		if i <= 0 {
+			http.NotFound(w, r)
+			return
+		}
+
+		r2 := r.Clone(r.Context())
+		r2.Header.Set(HeaderVenue, rest[:i])
+		r2.URL.Path = rest[i:]
+		r2.URL.RawPath = ""
+		next.ServeHTTP(w, r2)
+	})
+}
+
+// Middleware confines requests to the venue they name. A token bound to a
+// venue by its venue claim may only be used for that venue, which it also
+// selects when the request names none. Requests naming no venue at all are
+// served by the default venue unless required is set.
+func Middleware(repo Repository, required bool) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			slug := VenueSlugFromContext(ctx)
+
+			if p, ok := auth.PrincipalFromContext(ctx); ok {
+				if claim, ok := p.Claims[ClaimVenue].(string); ok && claim != "" {
+					if slug != "" && slug != claim {
+						return nil, errors.Forbidden.Newf("token is not valid for venue %s", slug).
+							AddContext("venue", "the token is bound to another venue")
+					}
+					slug = claim
+				}
+			}
+
+			if slug == "" {
+				if required {
+					return nil, errors.ValidationError.New("no venue given").
+						AddContext("venue", "name the venue in the "+HeaderVenue+" header or the path")
+				}
+				return next(WithVenue(ctx, DefaultVenueID), request)
+			}
+
+			v, err := repo.FindVenueBySlug(ctx, slug)
+			if err != nil {
+				return nil, err
+			}
+			return next(WithVenue(WithVenueSlug(ctx, v.Slug), v.VenueID), request)
+		}
+	}
+}
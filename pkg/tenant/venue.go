@@ -0,0 +1,115 @@
+package tenant
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v7"
+	"regexp"
+	errors "reservations/pkg/error"
+	"reservations/pkg/storage"
+	"reservations/pkg/tracing"
+	"time"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Venue is a restaurant served by the deployment. Its slug names it in
+// requests.
+type Venue struct {
+	VenueID     int    `json:"venueId" db:"vid" goqu:"skipinsert"`
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Created     int64  `json:"created"`
+	LastUpdated int64  `json:"lastUpdated" db:"last_updated"`
+}
+
+type Repository interface {
+	AddVenue(ctx context.Context, v *Venue) (*Venue, error)
+	FindAllVenues(ctx context.Context, opts *storage.QueryOptions) ([]Venue, error)
+	FindVenueBySlug(ctx context.Context, slug string) (Venue, error)
+}
+
+type venueRepository struct {
+	db storage.Persistence
+}
+
+func NewVenueRepository(db storage.Persistence) Repository {
+	return &venueRepository{db: db}
+}
+
+// CreateVenue validates and adds a new venue.
+func CreateVenue(ctx context.Context, repo Repository, slug string, name string) (*Venue, error) {
+	if !slugPattern.MatchString(slug) {
+		return nil, errors.ValidationError.Newf("invalid venue slug %q", slug).
+			AddContext("slug", "must be lowercase letters and digits separated by single dashes")
+	}
+	if name == "" {
+		return nil, errors.ValidationError.New("no venue name given").
+			AddContext("name", "a venue needs a name")
+	}
+
+	if _, err := repo.FindVenueBySlug(ctx, slug); err == nil {
+		return nil, errors.Conflict.Newf("venue %s already exists", slug).
+			AddContext("slug", "already taken")
+	} else if errors.GetType(err) != errors.NotFound {
+		return nil, err
+	}
+
+	return repo.AddVenue(ctx, &Venue{Slug: slug, Name: name})
+}
+
+func (r *venueRepository) AddVenue(ctx context.Context, v *Venue) (_ *Venue, err error) {
+	_, span := tracing.StartSpan(ctx, "venueRepository.AddVenue", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	created := time.Now().Unix()
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		v.Created = created
+		v.LastUpdated = created
+		vID, err := storage.InsertReturningID(tx, "venue", "vid", v)
+		v.VenueID = vID
+		return err
+	})
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error adding new venue")
+	}
+	return v, nil
+}
+
+func (r *venueRepository) FindAllVenues(ctx context.Context, opts *storage.QueryOptions) (vv []Venue, err error) {
+	_, span := tracing.StartSpan(ctx, "venueRepository.FindAllVenues", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	opts.Limit = r.db.PageSize(opts.Limit)
+
+	err = r.db.DB.From("venue").
+		Order(goqu.C("vid").Asc()).
+		Limit(opts.Limit).
+		Offset(opts.Offset).
+		ScanStructs(&vv)
+
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error getting all venues")
+	}
+	return vv, nil
+}
+
+func (r *venueRepository) FindVenueBySlug(ctx context.Context, slug string) (v Venue, err error) {
+	_, span := tracing.StartSpan(ctx, "venueRepository.FindVenueBySlug", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	found, err := r.db.DB.From("venue").Prepared(true).
+		Where(goqu.Ex{"slug": slug}).
+		ScanStruct(&v)
+
+	if err != nil {
+		return v, errors.DBError.Wrapf(err, "error getting venue %s", slug)
+	}
+
+	if !found {
+		return v, errors.NotFound.Newf("venue %s not found", slug).
+			AddContext("venue", "non existent venue")
+	}
+
+	return v, nil
+}
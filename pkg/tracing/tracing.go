@@ -0,0 +1,87 @@
+// Package tracing sets up OpenTelemetry tracing and provides the helpers the
+// transport, service and storage layers use to create spans.
+package tracing
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	errors "reservations/pkg/error"
+)
+
+// Supported exporters.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+const instrumentationName = "reservations"
+
+// Init installs a global tracer provider sending spans to the given exporter
+// and the W3C trace context propagator. endpoint is the host:port of an OTLP
+// HTTP collector and only used by the otlp exporter. The returned function
+// flushes pending spans and must be called on shutdown.
+func Init(ctx context.Context, exporter string, endpoint string, serviceName string, sampleRatio float64) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var (
+		exp sdktrace.SpanExporter
+		err error
+	)
+	switch exporter {
+	case ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exp, err = stdouttrace.New()
+	case ExporterOTLP:
+		exp, err = otlptracehttp.New(ctx,
+			otlptracehttp.WithEndpoint(endpoint),
+			otlptracehttp.WithInsecure(),
+		)
+	default:
+		return nil, errors.ValidationError.Newf("unknown trace exporter %q", exporter)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating %s trace exporter", exporter)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(serviceName),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// StartSpan starts a span named name as a child of the span in ctx, if any.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// EndSpan records err, if any, on span and ends it. It is meant to be
+// deferred with a pointer to a named error result.
+func EndSpan(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+		span.SetAttributes(attribute.String("error.type", errors.GetType(*err).String()))
+	}
+	span.End()
+}
@@ -0,0 +1,72 @@
+package grpcutil
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/transport"
+	grpctransport "github.com/go-kit/kit/transport/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"reservations/pkg/auth"
+	errors "reservations/pkg/error"
+	"reservations/pkg/request"
+	"reservations/pkg/tenant"
+)
+
+const (
+	// MetadataRequestID carries the ID used to correlate a call across logs and audit entries.
+	MetadataRequestID = "x-request-id"
+)
+
+// EncodeError converts a business-logic error into a gRPC status error.
+// Status errors are returned as they are.
+func EncodeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codeFrom(err), err.Error())
+}
+
+// PopulateRequestContext is the gRPC counterpart of httpjson.PopulateRequestContext.
+func PopulateRequestContext(ctx context.Context, md metadata.MD) context.Context {
+	id := first(md, MetadataRequestID)
+	if id == "" {
+		id = request.NewID()
+	}
+	return request.WithID(ctx, id)
+}
+
+func DefaultServerOptions(logger log.Logger) []grpctransport.ServerOption {
+	return []grpctransport.ServerOption{
+		grpctransport.ServerBefore(PopulateRequestContext, auth.GRPCToContext(), tenant.GRPCToContext()),
+		grpctransport.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+	}
+}
+
+func first(md metadata.MD, key string) string {
+	if vv := md.Get(key); len(vv) > 0 {
+		return vv[0]
+	}
+	return ""
+}
+
+func codeFrom(err error) codes.Code {
+	switch errors.GetType(err) {
+	case errors.NotFound:
+		return codes.NotFound
+	case errors.ValidationError:
+		return codes.InvalidArgument
+	case errors.Conflict:
+		return codes.FailedPrecondition
+	case errors.Unauthorized:
+		return codes.Unauthenticated
+	case errors.Forbidden:
+		return codes.PermissionDenied
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,37 @@
+package grpcutil
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	errors "reservations/pkg/error"
+	"testing"
+)
+
+func TestEncodeError(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want codes.Code
+	}{
+		{errors.NotFound.New("customer not found"), codes.NotFound},
+		{errors.ValidationError.New("invalid seat count"), codes.InvalidArgument},
+		{errors.Conflict.New("table taken"), codes.FailedPrecondition},
+		{errors.Unauthorized.New("no credentials"), codes.Unauthenticated},
+		{errors.Forbidden.New("missing permission"), codes.PermissionDenied},
+		{errors.DBError.New("connection lost"), codes.Internal},
+		{errors.New("unknown"), codes.Internal},
+		{status.Error(codes.Unavailable, "shutting down"), codes.Unavailable},
+		{EncodeError(errors.NotFound.New("customer not found")), codes.NotFound},
+	} {
+		err := EncodeError(tc.err)
+		if got := status.Code(err); got != tc.want {
+			t.Errorf("EncodeError(%v) has code %v, want %v", tc.err, got, tc.want)
+		}
+		if s, _ := status.FromError(err); s.Message() != status.Convert(tc.err).Message() {
+			t.Errorf("EncodeError(%v) = %q, want the message of the error", tc.err, s.Message())
+		}
+	}
+
+	if err := EncodeError(nil); err != nil {
+		t.Errorf("EncodeError(nil) = %v, want nil", err)
+	}
+}
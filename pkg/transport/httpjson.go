@@ -7,11 +7,27 @@ import (
 	"github.com/go-kit/kit/transport"
 	httptransport "github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
 	"net/http"
+	"net/url"
+	"reservations/pkg/auth"
 	errors "reservations/pkg/error"
+	"reservations/pkg/request"
+	"reservations/pkg/storage"
+	"reservations/pkg/tenant"
+	"reservations/pkg/tracing"
 	"strconv"
 )
 
+const (
+	// HeaderRequestID carries the ID used to correlate a request across logs and audit entries.
+	HeaderRequestID = "X-Request-ID"
+)
+
 // HTTPErrorer is implemented by all concrete response types that may contain
 // errors. It allows us to change the HTTP response code without needing to
 // trigger an endpoint (transport-level) error.
@@ -23,13 +39,10 @@ type HTTPErrorer interface {
 // client. Since we're using JSON, there's no reason to provide anything more specific.
 // There is also the option to specialize on a per-response (per-method) basis.
 func EncodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
-	e, ok := response.(HTTPErrorer)
-	resErr := e.HTTPError()
-
-	if ok && resErr != nil {
+	if e, ok := response.(HTTPErrorer); ok && e.HTTPError() != nil {
 		// Not a Go kit transport error, but a business-logic error.
 		// Provide those as HTTP errors.
-		EncodeError(ctx, resErr, w)
+		EncodeError(ctx, e.HTTPError(), w)
 		return nil
 	}
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -56,7 +69,11 @@ func HTTPRequestFinalizer(logger log.Logger) httptransport.ServerFinalizerFunc {
 		query := r.URL.RawQuery
 
 		var keyvals []interface{}
-		keyvals = append(keyvals, "proto", r.Proto, "method", r.Method, "route", route, "status_code", code)
+		keyvals = append(keyvals, "proto", r.Proto, "method", r.Method, "route", route, "status_code", code,
+			"request_id", request.IDFromContext(ctx))
+		if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+			keyvals = append(keyvals, "trace_id", sc.TraceID().String())
+		}
 		if len(query) > 0 {
 			keyvals = append(keyvals, "query", query)
 		}
@@ -65,11 +82,149 @@ func HTTPRequestFinalizer(logger log.Logger) httptransport.ServerFinalizerFunc {
 	}
 }
 
+// PopulateRequestContext stores the request ID in the context. A request ID
+// is generated when the client did not send one. The calling actor is never
+// taken from the request but set by the authentication middleware from the
+// principal.
+func PopulateRequestContext(ctx context.Context, r *http.Request) context.Context {
+	id := r.Header.Get(HeaderRequestID)
+	if id == "" {
+		id = request.NewID()
+	}
+	return request.WithID(ctx, id)
+}
+
+// StartServerSpan starts a span covering the whole request, continuing the
+// trace propagated by the caller in W3C trace context headers.
+func StartServerSpan(ctx context.Context, r *http.Request) context.Context {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+
+	name := r.URL.Path
+	if route := mux.CurrentRoute(r); route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			name = tpl
+		}
+	}
+
+	ctx, _ = tracing.StartSpan(ctx, r.Method+" "+name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(r.Method),
+			semconv.HTTPRouteKey.String(name),
+			semconv.HTTPTargetKey.String(r.URL.RequestURI()),
+		),
+	)
+	return ctx
+}
+
+// EndServerSpan ends the span started by StartServerSpan.
+func EndServerSpan(ctx context.Context, code int, _ *http.Request) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(code))
+	if code >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(code))
+	}
+	span.End()
+}
+
+type connectionKey struct{}
+
+type connection struct {
+	w http.ResponseWriter
+	r *http.Request
+}
+
+// KeepConnection stores the connection in the request context for encoders
+// taking it over, such as event streams and WebSockets: the writer go-kit
+// hands to encoders hides http.Flusher and http.Hijacker.
+func KeepConnection(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := &connection{w: w}
+		r = r.WithContext(context.WithValue(r.Context(), connectionKey{}, c))
+		c.r = r
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ConnectionFromContext returns the connection stored by KeepConnection.
+func ConnectionFromContext(ctx context.Context) (http.ResponseWriter, *http.Request, bool) {
+	c, ok := ctx.Value(connectionKey{}).(*connection)
+	if !ok {
+		return nil, nil, false
+	}
+	return c.w, c.r, true
+}
+
+// SetRequestIDHeader echoes the request ID back to the client.
+func SetRequestIDHeader(ctx context.Context, w http.ResponseWriter) context.Context {
+	if id := request.IDFromContext(ctx); id != "" {
+		w.Header().Set(HeaderRequestID, id)
+	}
+	return ctx
+}
+
+// SetRequestHeaders forwards the request ID and trace context of an
+// incoming call to an outgoing HTTP request.
+func SetRequestHeaders(ctx context.Context, r *http.Request) context.Context {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(r.Header))
+	if id := request.IDFromContext(ctx); id != "" {
+		r.Header.Set(HeaderRequestID, id)
+	}
+	return ctx
+}
+
+// DecodeError turns an error response written by EncodeError back into an
+// errors.AppError, so that clients see the same error type as the server.
+func DecodeError(r *http.Response) error {
+	var body struct {
+		Error errors.AppError `json:"error"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Error.OriginalError == nil {
+		return errors.Newf("unexpected response status %s", r.Status)
+	}
+	return body.Error
+}
+
+// DecodeResponse decodes a successful JSON response into v, or the error
+// carried by a failed one.
+func DecodeResponse(r *http.Response, v interface{}) error {
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		return DecodeError(r)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// EncodeQueryOptions is the inverse of parsing the limit and offset query parameters.
+func EncodeQueryOptions(opts *storage.QueryOptions) url.Values {
+	q := url.Values{}
+	if opts == nil {
+		return q
+	}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.FormatUint(uint64(opts.Limit), 10))
+	}
+	if opts.Offset > 0 {
+		q.Set("offset", strconv.FormatUint(uint64(opts.Offset), 10))
+	}
+	return q
+}
+
+func DefaultClientOptions() []httptransport.ClientOption {
+	return []httptransport.ClientOption{
+		httptransport.ClientBefore(SetRequestHeaders, auth.ContextToHTTP(), tenant.ContextToHTTP()),
+	}
+}
+
 func DefaultServerOptions(logger log.Logger) []httptransport.ServerOption {
 	return []httptransport.ServerOption{
+		httptransport.ServerBefore(PopulateRequestContext, StartServerSpan, auth.HTTPToContext(), tenant.HTTPToContext()),
+		httptransport.ServerAfter(SetRequestIDHeader),
 		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
 		httptransport.ServerErrorEncoder(EncodeError),
-		httptransport.ServerFinalizer(HTTPRequestFinalizer(logger)),
+		httptransport.ServerFinalizer(HTTPRequestFinalizer(logger), EndServerSpan),
 	}
 }
 
@@ -91,11 +246,17 @@ func ParseUintQueryParam(req *http.Request, paramName string) uint {
 }
 
 func codeFrom(err error) int {
-	customErr := err.(errors.AppError)
-
-	switch customErr.ErrorType {
+	switch errors.GetType(err) {
 	case errors.NotFound:
 		return http.StatusNotFound
+	case errors.ValidationError:
+		return http.StatusBadRequest
+	case errors.Conflict:
+		return http.StatusConflict
+	case errors.Unauthorized:
+		return http.StatusUnauthorized
+	case errors.Forbidden:
+		return http.StatusForbidden
 	// case ErrAlreadyExists, ErrInconsistentIDs:
 	// 	return http.StatusBadRequest
 	default:
@@ -0,0 +1,175 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/go-kit/kit/log"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultBatchSize   uint = 50
+	defaultMaxAttempts      = 8
+	defaultBaseBackoff      = 30 * time.Second
+	defaultMaxBackoff       = time.Hour
+	defaultTimeout          = 10 * time.Second
+)
+
+// DispatcherOption sets an optional parameter of a Dispatcher.
+type DispatcherOption func(*Dispatcher)
+
+// MaxAttempts sets how many times a delivery is attempted before it is moved
+// to the dead state.
+func MaxAttempts(n int) DispatcherOption {
+	return func(d *Dispatcher) { d.maxAttempts = n }
+}
+
+// Backoff sets the delay before the first retry, which doubles with every
+// further attempt up to max.
+func Backoff(base, max time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.baseBackoff = base
+		d.maxBackoff = max
+	}
+}
+
+// HTTPClient sets the client used to call subscribers.
+func HTTPClient(c *http.Client) DispatcherOption {
+	return func(d *Dispatcher) { d.client = c }
+}
+
+// Dispatcher sends due webhook deliveries, retrying failures with exponential
+// backoff until they succeed or run out of attempts.
+type Dispatcher struct {
+	repo        Repository
+	client      *http.Client
+	interval    time.Duration
+	batchSize   uint
+	maxAttempts int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+	logger      log.Logger
+}
+
+func NewDispatcher(repo Repository, interval time.Duration, logger log.Logger, options ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		repo:        repo,
+		client:      &http.Client{Timeout: defaultTimeout},
+		interval:    interval,
+		batchSize:   defaultBatchSize,
+		maxAttempts: defaultMaxAttempts,
+		baseBackoff: defaultBaseBackoff,
+		maxBackoff:  defaultMaxBackoff,
+		logger:      logger,
+	}
+	for _, option := range options {
+		option(d)
+	}
+	return d
+}
+
+// Run polls for due deliveries until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Flush(ctx); err != nil {
+			d.logger.Log("component", "webhook", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush attempts every delivery that is currently due.
+func (d *Dispatcher) Flush(ctx context.Context) error {
+	dd, err := d.repo.FindDueDeliveries(ctx, time.Now().Unix(), d.batchSize)
+	if err != nil {
+		return err
+	}
+
+	subs := make(map[int]Subscription)
+	for i := range dd {
+		s, ok := subs[dd[i].SubscriptionID]
+		if !ok {
+			if s, err = d.repo.FindSubscriptionByID(ctx, dd[i].VenueID, dd[i].SubscriptionID); err != nil {
+				return err
+			}
+			subs[s.SubscriptionID] = s
+		}
+
+		d.attempt(ctx, s, &dd[i])
+		if err := d.repo.UpdateDelivery(ctx, &dd[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, s Subscription, dl *Delivery) {
+	dl.Attempts++
+
+	code, err := d.send(ctx, s, dl)
+	dl.LastStatusCode = code
+	if err == nil {
+		dl.Status = StatusDelivered
+		dl.LastError = ""
+		return
+	}
+
+	dl.LastError = err.Error()
+	if dl.Attempts >= d.maxAttempts {
+		dl.Status = StatusDead
+		d.logger.Log("component", "webhook", "delivery", dl.DeliveryID, "subscription", s.SubscriptionID,
+			"status", dl.Status, "attempts", dl.Attempts, "err", err)
+		return
+	}
+
+	dl.Status = StatusRetrying
+	dl.NextAttemptAt = time.Now().Add(d.backoff(dl.Attempts)).Unix()
+}
+
+func (d *Dispatcher) send(ctx context.Context, s Subscription, dl *Delivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(dl.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set(HeaderEvent, string(dl.EventType))
+	req.Header.Set(HeaderDelivery, strconv.Itoa(dl.DeliveryID))
+	req.Header.Set(HeaderSignature, Sign(s.Secret, time.Now(), dl.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("subscriber responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	b := d.baseBackoff
+	for i := 1; i < attempts && b < d.maxBackoff; i++ {
+		b *= 2
+	}
+	if b > d.maxBackoff {
+		b = d.maxBackoff
+	}
+	return b
+}
@@ -0,0 +1,237 @@
+package webhook
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reservations/pkg/event"
+	"reservations/pkg/storage"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryRepository keeps subscriptions and deliveries in memory, for the
+// dispatcher only.
+type memoryRepository struct {
+	Repository
+	subs       map[int]Subscription
+	deliveries []Delivery
+}
+
+func (r *memoryRepository) FindSubscriptionByID(_ context.Context, _ int, sID int) (Subscription, error) {
+	return r.subs[sID], nil
+}
+
+func (r *memoryRepository) FindDueDeliveries(_ context.Context, now int64, limit uint) ([]Delivery, error) {
+	var dd []Delivery
+	for _, d := range r.deliveries {
+		if (d.Status == StatusPending || d.Status == StatusRetrying) && d.NextAttemptAt <= now && uint(len(dd)) < limit {
+			dd = append(dd, d)
+		}
+	}
+	return dd, nil
+}
+
+func (r *memoryRepository) UpdateDelivery(_ context.Context, d *Delivery) error {
+	for i := range r.deliveries {
+		if r.deliveries[i].DeliveryID == d.DeliveryID {
+			r.deliveries[i] = *d
+		}
+	}
+	return nil
+}
+
+// receiver records the requests made to a test server answering with the
+// status codes in turn, the last one repeatedly.
+type receiver struct {
+	mu       sync.Mutex
+	codes    []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	body, _ := ioutil.ReadAll(r.Body)
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+
+	code := rc.codes[0]
+	if len(rc.codes) > 1 {
+		rc.codes = rc.codes[1:]
+	}
+	w.WriteHeader(code)
+}
+
+func newTestDispatcher(t *testing.T, codes []int, options ...DispatcherOption) (*Dispatcher, *memoryRepository, *receiver) {
+	rc := &receiver{codes: codes}
+	srv := httptest.NewServer(rc)
+	t.Cleanup(srv.Close)
+
+	repo := &memoryRepository{
+		subs: map[int]Subscription{
+			1: {SubscriptionID: 1, URL: srv.URL, Secret: "s3cr3t", Active: true, VenueID: 1},
+		},
+		deliveries: []Delivery{{
+			DeliveryID:     7,
+			SubscriptionID: 1,
+			EventID:        42,
+			EventType:      event.ReservationBooked,
+			Payload:        storage.JSON(`{"eventId":42}`),
+			Status:         StatusPending,
+			VenueID:        1,
+		}},
+	}
+	return NewDispatcher(repo, time.Second, log.NewNopLogger(), options...), repo, rc
+}
+
+func TestDispatcherSignsDeliveries(t *testing.T) {
+	d, repo, rc := newTestDispatcher(t, []int{http.StatusNoContent})
+
+	if err := d.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if len(rc.requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(rc.requests))
+	}
+	req, body := rc.requests[0], rc.bodies[0]
+	if string(body) != `{"eventId":42}` {
+		t.Errorf("body = %s, want the payload", body)
+	}
+	if got := req.Header.Get(HeaderEvent); got != string(event.ReservationBooked) {
+		t.Errorf("%s = %q, want %q", HeaderEvent, got, event.ReservationBooked)
+	}
+	if got := req.Header.Get(HeaderDelivery); got != "7" {
+		t.Errorf("%s = %q, want 7", HeaderDelivery, got)
+	}
+
+	sig := req.Header.Get(HeaderSignature)
+	if !Verify("s3cr3t", sig, body, time.Minute) {
+		t.Errorf("signature %q does not verify", sig)
+	}
+	if Verify("other", sig, body, time.Minute) {
+		t.Errorf("signature %q verifies with another secret", sig)
+	}
+	if Verify("s3cr3t", sig, []byte(`{"eventId":43}`), time.Minute) {
+		t.Errorf("signature %q verifies another payload", sig)
+	}
+
+	dl := repo.deliveries[0]
+	if dl.Status != StatusDelivered || dl.Attempts != 1 || dl.LastStatusCode != http.StatusNoContent || dl.LastError != "" {
+		t.Errorf("delivery = %+v, want delivered after 1 attempt", dl)
+	}
+}
+
+func TestVerifyRejectsStaleSignatures(t *testing.T) {
+	payload := []byte(`{}`)
+	sig := Sign("s3cr3t", time.Now().Add(-10*time.Minute), payload)
+
+	if Verify("s3cr3t", sig, payload, 5*time.Minute) {
+		t.Error("stale signature verifies")
+	}
+	if !Verify("s3cr3t", sig, payload, 0) {
+		t.Error("signature does not verify without tolerance")
+	}
+	for _, header := range []string{"", "t=abc,v1=00", "v1=00", "t=" + strconv.FormatInt(time.Now().Unix(), 10)} {
+		if Verify("s3cr3t", header, payload, 0) {
+			t.Errorf("malformed signature %q verifies", header)
+		}
+	}
+}
+
+func TestDispatcherRetriesWithBackoff(t *testing.T) {
+	d, repo, rc := newTestDispatcher(t, []int{http.StatusInternalServerError, http.StatusOK},
+		Backoff(time.Minute, time.Hour))
+
+	before := time.Now()
+	if err := d.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	dl := repo.deliveries[0]
+	if dl.Status != StatusRetrying || dl.Attempts != 1 || dl.LastStatusCode != http.StatusInternalServerError || dl.LastError == "" {
+		t.Fatalf("delivery = %+v, want retrying after 1 failed attempt", dl)
+	}
+	if min := before.Add(time.Minute).Unix(); dl.NextAttemptAt < min || dl.NextAttemptAt > min+1 {
+		t.Errorf("next attempt at %d, want a minute later at %d", dl.NextAttemptAt, min)
+	}
+
+	// Not due yet.
+	if err := d.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if len(rc.requests) != 1 {
+		t.Fatalf("got %d requests before the retry was due, want 1", len(rc.requests))
+	}
+
+	repo.deliveries[0].NextAttemptAt = time.Now().Unix()
+	if err := d.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	dl = repo.deliveries[0]
+	if dl.Status != StatusDelivered || dl.Attempts != 2 || dl.LastError != "" {
+		t.Errorf("delivery = %+v, want delivered after 2 attempts", dl)
+	}
+}
+
+func TestDispatcherBackoff(t *testing.T) {
+	d := NewDispatcher(nil, time.Second, log.NewNopLogger(), Backoff(30*time.Second, 5*time.Minute))
+
+	for _, tc := range []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{5, 5 * time.Minute},
+		{20, 5 * time.Minute},
+	} {
+		if got := d.backoff(tc.attempts); got != tc.want {
+			t.Errorf("backoff(%d) = %v, want %v", tc.attempts, got, tc.want)
+		}
+	}
+}
+
+func TestDispatcherDeadLettersAfterMaxAttempts(t *testing.T) {
+	d, repo, rc := newTestDispatcher(t, []int{http.StatusBadGateway}, MaxAttempts(3))
+
+	for i := 0; i < 5; i++ {
+		repo.deliveries[0].NextAttemptAt = 0
+		if err := d.Flush(context.Background()); err != nil {
+			t.Fatalf("Flush: %v", err)
+		}
+	}
+
+	if len(rc.requests) != 3 {
+		t.Errorf("got %d requests, want 3", len(rc.requests))
+	}
+	dl := repo.deliveries[0]
+	if dl.Status != StatusDead || dl.Attempts != 3 || dl.LastStatusCode != http.StatusBadGateway || dl.LastError == "" {
+		t.Errorf("delivery = %+v, want dead after 3 attempts", dl)
+	}
+}
+
+func TestDispatcherDeadLettersUnreachableSubscribers(t *testing.T) {
+	d, repo, _ := newTestDispatcher(t, []int{http.StatusOK}, MaxAttempts(1))
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	repo.subs[1] = Subscription{SubscriptionID: 1, URL: srv.URL, Secret: "s3cr3t", Active: true, VenueID: 1}
+
+	if err := d.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	dl := repo.deliveries[0]
+	if dl.Status != StatusDead || dl.LastStatusCode != 0 || dl.LastError == "" {
+		t.Errorf("delivery = %+v, want dead without a status code", dl)
+	}
+}
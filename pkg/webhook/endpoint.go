@@ -0,0 +1,238 @@
+package webhook
+
+import (
+	"context"
+	"github.com/go-kit/kit/endpoint"
+	"reservations/pkg/authz"
+	"reservations/pkg/storage"
+)
+
+type Endpoints struct {
+	CreateSubscriptionEndpoint  endpoint.Endpoint
+	RemoveSubscriptionEndpoint  endpoint.Endpoint
+	GetAllSubscriptionsEndpoint endpoint.Endpoint
+	GetSubscriptionByIDEndpoint endpoint.Endpoint
+	GetDeliveriesEndpoint       endpoint.Endpoint
+	RetryDeliveryEndpoint       endpoint.Endpoint
+}
+
+// MakeServerEndpoints returns the service endpoints, each checked against
+// the policy by az and wrapped in the given middlewares, the first one
+// outermost.
+func MakeServerEndpoints(s Service, az authz.Authorizer, mw ...endpoint.Middleware) Endpoints {
+	e := Endpoints{
+		CreateSubscriptionEndpoint:  az.Require(authz.PermWebhookManage, nil)(MakeCreateSubscriptionEndpoint(s)),
+		RemoveSubscriptionEndpoint:  az.Require(authz.PermWebhookManage, nil)(MakeRemoveSubscriptionEndpoint(s)),
+		GetAllSubscriptionsEndpoint: az.Require(authz.PermWebhookManage, nil)(MakeGetAllSubscriptionsEndpoint(s)),
+		GetSubscriptionByIDEndpoint: az.Require(authz.PermWebhookManage, nil)(MakeGetSubscriptionByIDEndpoint(s)),
+		GetDeliveriesEndpoint:       az.Require(authz.PermWebhookManage, nil)(MakeGetDeliveriesEndpoint(s)),
+		RetryDeliveryEndpoint:       az.Require(authz.PermWebhookManage, nil)(MakeRetryDeliveryEndpoint(s)),
+	}
+	for i := len(mw) - 1; i >= 0; i-- {
+		m := mw[i]
+		e.CreateSubscriptionEndpoint = m(e.CreateSubscriptionEndpoint)
+		e.RemoveSubscriptionEndpoint = m(e.RemoveSubscriptionEndpoint)
+		e.GetAllSubscriptionsEndpoint = m(e.GetAllSubscriptionsEndpoint)
+		e.GetSubscriptionByIDEndpoint = m(e.GetSubscriptionByIDEndpoint)
+		e.GetDeliveriesEndpoint = m(e.GetDeliveriesEndpoint)
+		e.RetryDeliveryEndpoint = m(e.RetryDeliveryEndpoint)
+	}
+	return e
+}
+
+type createSubscriptionRequest struct {
+	Subscription *Subscription
+}
+
+type createSubscriptionResponse struct {
+	Subscription *Subscription `json:"subscription,omitempty"`
+	Err          error         `json:"err,omitempty"`
+}
+
+func (r createSubscriptionResponse) HTTPError() error { return r.Err }
+
+// CreateSubscription godoc
+// @Summary Subscribe a URL to webhook events
+// @Description Subscribe a URL to webhook events. The returned secret is used to sign deliveries and is shown only once.
+// @Tags webhook
+// @Param subscription body webhook.Subscription true "New Subscription"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} webhook.Subscription
+// @Router /webhook [post]
+func MakeCreateSubscriptionEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(createSubscriptionRequest)
+		sub, e := s.CreateSubscription(ctx, req.Subscription)
+		return createSubscriptionResponse{
+			Subscription: sub,
+			Err:          e,
+		}, nil
+	}
+}
+
+type removeSubscriptionRequest struct {
+	SubscriptionID int
+}
+
+type removeSubscriptionResponse struct {
+	Err error `json:"err,omitempty"`
+}
+
+func (r removeSubscriptionResponse) HTTPError() error { return r.Err }
+
+// RemoveSubscription godoc
+// @Summary Remove a webhook subscription
+// @Description Remove a webhook subscription together with its delivery log
+// @Tags webhook
+// @Param id path string true "Subscription ID"
+// @Accept  json
+// @Produce  json
+// @Router /webhook/{id} [delete]
+func MakeRemoveSubscriptionEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(removeSubscriptionRequest)
+		e := s.RemoveSubscription(ctx, req.SubscriptionID)
+		return removeSubscriptionResponse{
+			Err: e,
+		}, nil
+	}
+}
+
+type getAllSubscriptionsRequest struct {
+	Limit  uint
+	Offset uint
+}
+
+type getAllSubscriptionsResponse struct {
+	Subscriptions []Subscription `json:"subscriptions,omitempty"`
+	Err           error          `json:"err,omitempty"`
+}
+
+func (r getAllSubscriptionsResponse) HTTPError() error { return r.Err }
+
+// GetAllSubscriptions godoc
+// @Summary List webhook subscriptions
+// @Description List webhook subscriptions
+// @Tags webhook
+// @Param limit query int false "Subscription count limit" default(100)
+// @Param offset query int false "Subscription count offset" default(0)
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} webhook.Subscription
+// @Router /webhooks [get]
+func MakeGetAllSubscriptionsEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getAllSubscriptionsRequest)
+		ss, e := s.GetAllSubscriptions(ctx, &storage.QueryOptions{
+			Limit:  req.Limit,
+			Offset: req.Offset,
+		})
+		return getAllSubscriptionsResponse{
+			Subscriptions: ss,
+			Err:           e,
+		}, nil
+	}
+}
+
+type getSubscriptionByIDRequest struct {
+	SubscriptionID int
+}
+
+type getSubscriptionByIDResponse struct {
+	Subscription Subscription `json:"subscription"`
+	Err          error        `json:"err,omitempty"`
+}
+
+func (r getSubscriptionByIDResponse) HTTPError() error { return r.Err }
+
+// GetSubscriptionByID godoc
+// @Summary Get a webhook subscription
+// @Description Get a webhook subscription
+// @Tags webhook
+// @Param id path string true "Subscription ID"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} webhook.Subscription
+// @Router /webhook/{id} [get]
+func MakeGetSubscriptionByIDEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getSubscriptionByIDRequest)
+		sub, e := s.GetSubscriptionByID(ctx, req.SubscriptionID)
+		return getSubscriptionByIDResponse{
+			Subscription: sub,
+			Err:          e,
+		}, nil
+	}
+}
+
+type getDeliveriesRequest struct {
+	SubscriptionID int
+	Status         string
+	Limit          uint
+	Offset         uint
+}
+
+type getDeliveriesResponse struct {
+	Deliveries []Delivery `json:"deliveries,omitempty"`
+	Err        error      `json:"err,omitempty"`
+}
+
+func (r getDeliveriesResponse) HTTPError() error { return r.Err }
+
+// GetDeliveries godoc
+// @Summary List deliveries of a webhook subscription ordered by newest
+// @Description List deliveries of a webhook subscription ordered by newest
+// @Tags webhook
+// @Param id path string true "Subscription ID"
+// @Param status query string false "Delivery status (pending, retrying, delivered, dead)"
+// @Param limit query int false "Delivery count limit" default(100)
+// @Param offset query int false "Delivery count offset" default(0)
+// @Accept  json
+// @Produce  json
+// @Success 200 {array} webhook.Delivery
+// @Router /webhook/{id}/deliveries [get]
+func MakeGetDeliveriesEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(getDeliveriesRequest)
+		dd, e := s.GetDeliveries(ctx, req.SubscriptionID, req.Status, &storage.QueryOptions{
+			Limit:  req.Limit,
+			Offset: req.Offset,
+		})
+		return getDeliveriesResponse{
+			Deliveries: dd,
+			Err:        e,
+		}, nil
+	}
+}
+
+type retryDeliveryRequest struct {
+	DeliveryID int
+}
+
+type retryDeliveryResponse struct {
+	Delivery Delivery `json:"delivery"`
+	Err      error    `json:"err,omitempty"`
+}
+
+func (r retryDeliveryResponse) HTTPError() error { return r.Err }
+
+// RetryDelivery godoc
+// @Summary Retry a dead webhook delivery
+// @Description Move a dead webhook delivery back to the pending state
+// @Tags webhook
+// @Param id path string true "Delivery ID"
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} webhook.Delivery
+// @Router /webhook/delivery/{id}/retry [post]
+func MakeRetryDeliveryEndpoint(s Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
+		req := request.(retryDeliveryRequest)
+		d, e := s.RetryDelivery(ctx, req.DeliveryID)
+		return retryDeliveryResponse{
+			Delivery: d,
+			Err:      e,
+		}, nil
+	}
+}
@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"context"
+	"github.com/go-kit/kit/log"
+	"reservations/pkg/storage"
+	"time"
+)
+
+// Middleware describes a service (as opposed to endpoint) middleware.
+type Middleware func(Service) Service
+
+func LoggingMiddleware(logger log.Logger) Middleware {
+	return func(next Service) Service {
+		return &loggingMiddleware{
+			next:   next,
+			logger: logger,
+		}
+	}
+}
+
+type loggingMiddleware struct {
+	next   Service
+	logger log.Logger
+}
+
+func (mw loggingMiddleware) CreateSubscription(ctx context.Context, s *Subscription) (result *Subscription, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "CreateSubscription", "url", s.URL, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.CreateSubscription(ctx, s)
+}
+
+func (mw loggingMiddleware) RemoveSubscription(ctx context.Context, sID int) (err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "RemoveSubscription", "id", sID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.RemoveSubscription(ctx, sID)
+}
+
+func (mw loggingMiddleware) GetAllSubscriptions(ctx context.Context, opts *storage.QueryOptions) (result []Subscription, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "GetAllSubscriptions", "limit", opts.Limit, "offset", opts.Offset, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetAllSubscriptions(ctx, opts)
+}
+
+func (mw loggingMiddleware) GetSubscriptionByID(ctx context.Context, sID int) (result Subscription, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "GetSubscriptionByID", "id", sID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetSubscriptionByID(ctx, sID)
+}
+
+func (mw loggingMiddleware) GetDeliveries(ctx context.Context, sID int, status string, opts *storage.QueryOptions) (result []Delivery, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "GetDeliveries", "id", sID, "status", status, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.GetDeliveries(ctx, sID, status, opts)
+}
+
+func (mw loggingMiddleware) RetryDelivery(ctx context.Context, dID int) (result Delivery, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Log("method", "RetryDelivery", "id", dID, "took", time.Since(begin), "err", err)
+	}(time.Now())
+	return mw.next.RetryDelivery(ctx, dID)
+}
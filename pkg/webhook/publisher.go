@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	errors "reservations/pkg/error"
+	"reservations/pkg/event"
+	"time"
+)
+
+// Publisher schedules a delivery of every published event to each matching
+// active subscription. It is meant to be registered with the outbox relay;
+// the actual HTTP calls are made by the Dispatcher.
+func Publisher(repo Repository) event.Publisher {
+	return event.PublisherFunc(func(ctx context.Context, e event.Event) error {
+		ss, err := repo.FindActiveSubscriptions(ctx, e.VenueID)
+		if err != nil {
+			return err
+		}
+
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return errors.Wrapf(err, "error encoding event %d", e.EventID)
+		}
+
+		now := time.Now().Unix()
+		var dd []Delivery
+		for _, s := range ss {
+			if !s.Matches(e.Type) {
+				continue
+			}
+			dd = append(dd, Delivery{
+				SubscriptionID: s.SubscriptionID,
+				EventID:        e.EventID,
+				EventType:      e.Type,
+				VenueID:        e.VenueID,
+				Payload:        payload,
+				Status:         StatusPending,
+				NextAttemptAt:  now,
+				Created:        now,
+				LastUpdated:    now,
+			})
+		}
+
+		return repo.AddDeliveries(ctx, dd)
+	})
+}
@@ -0,0 +1,269 @@
+package webhook
+
+import (
+	"context"
+	"github.com/doug-martin/goqu/v7"
+	"github.com/doug-martin/goqu/v7/exec"
+	errors "reservations/pkg/error"
+	"reservations/pkg/storage"
+	"reservations/pkg/tracing"
+	"time"
+)
+
+type Repository interface {
+	AddSubscription(ctx context.Context, s *Subscription) (*Subscription, error)
+	RemoveSubscription(ctx context.Context, vID int, sID int) error
+	FindAllSubscriptions(ctx context.Context, vID int, opts *storage.QueryOptions) ([]Subscription, error)
+	FindActiveSubscriptions(ctx context.Context, vID int) ([]Subscription, error)
+	FindSubscriptionByID(ctx context.Context, vID int, sID int) (Subscription, error)
+	AddDeliveries(ctx context.Context, dd []Delivery) error
+	UpdateDelivery(ctx context.Context, d *Delivery) error
+	ResetDelivery(ctx context.Context, vID int, dID int) (Delivery, error)
+	FindDueDeliveries(ctx context.Context, now int64, limit uint) ([]Delivery, error)
+	FindDeliveryByID(ctx context.Context, vID int, dID int) (Delivery, error)
+	FindDeliveriesBySubscriptionID(ctx context.Context, vID int, sID int, status string, opts *storage.QueryOptions) ([]Delivery, error)
+}
+
+type webhookRepository struct {
+	db storage.Persistence
+}
+
+func NewWebhookRepository(db storage.Persistence) Repository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) AddSubscription(ctx context.Context, s *Subscription) (_ *Subscription, err error) {
+	_, span := tracing.StartSpan(ctx, "webhookRepository.AddSubscription", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	created := time.Now().Unix()
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		s.Created = created
+		s.LastUpdated = created
+		sID, err := storage.InsertReturningID(tx, "webhook_subscription", "sid", s)
+		s.SubscriptionID = sID
+		return err
+	})
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error adding new webhook subscription")
+	}
+
+	return s, nil
+}
+
+func (r *webhookRepository) RemoveSubscription(ctx context.Context, vID int, sID int) (err error) {
+	_, span := tracing.StartSpan(ctx, "webhookRepository.RemoveSubscription", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		if _, err := tx.From("webhook_delivery").Where(goqu.Ex{"subscription_id": sID, "venue_id": vID}).Delete().Exec(); err != nil {
+			return err
+		}
+		_, err := tx.From("webhook_subscription").Where(goqu.Ex{"sid": sID, "venue_id": vID}).Delete().Exec()
+		return err
+	})
+
+	if err != nil {
+		return errors.DBError.Wrapf(err, "error deleting webhook subscription with ID %d", sID)
+	}
+	return nil
+}
+
+func (r *webhookRepository) FindAllSubscriptions(ctx context.Context, vID int, opts *storage.QueryOptions) (ss []Subscription, err error) {
+	_, span := tracing.StartSpan(ctx, "webhookRepository.FindAllSubscriptions", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	opts.Limit = r.db.PageSize(opts.Limit)
+
+	err = r.db.DB.From("webhook_subscription").
+		Where(goqu.Ex{"venue_id": vID}).
+		Order(goqu.C("sid").Asc()).
+		Limit(opts.Limit).
+		Offset(opts.Offset).
+		ScanStructs(&ss)
+
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error getting all webhook subscriptions")
+	}
+	return ss, nil
+}
+
+func (r *webhookRepository) FindActiveSubscriptions(ctx context.Context, vID int) (ss []Subscription, err error) {
+	_, span := tracing.StartSpan(ctx, "webhookRepository.FindActiveSubscriptions", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	err = r.db.DB.From("webhook_subscription").
+		Where(goqu.Ex{"active": true, "venue_id": vID}).
+		ScanStructs(&ss)
+
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error getting active webhook subscriptions")
+	}
+	return ss, nil
+}
+
+func (r *webhookRepository) FindSubscriptionByID(ctx context.Context, vID int, sID int) (s Subscription, err error) {
+	_, span := tracing.StartSpan(ctx, "webhookRepository.FindSubscriptionByID", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	found, err := r.db.DB.From("webhook_subscription").Where(
+		goqu.C("sid").Eq(sID),
+		goqu.C("venue_id").Eq(vID),
+	).ScanStruct(&s)
+
+	if err != nil {
+		return s, errors.DBError.Wrapf(err, "error getting webhook subscription with ID %d", sID)
+	}
+
+	if !found {
+		return s, errors.NotFound.Newf("webhook subscription with ID %d not found", sID).
+			AddContext("SubscriptionID", "non existent ID")
+	}
+
+	return s, nil
+}
+
+// AddDeliveries schedules deliveries, ignoring those already scheduled for
+// the same subscription and event.
+func (r *webhookRepository) AddDeliveries(ctx context.Context, dd []Delivery) (err error) {
+	_, span := tracing.StartSpan(ctx, "webhookRepository.AddDeliveries", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	if len(dd) == 0 {
+		return nil
+	}
+
+	err = r.db.WithTx(func(tx *goqu.TxDatabase) error {
+		for i := range dd {
+			n, err := tx.From("webhook_delivery").Where(goqu.Ex{
+				"subscription_id": dd[i].SubscriptionID,
+				"event_id":        dd[i].EventID,
+			}).Count()
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				continue
+			}
+
+			if _, err := tx.From("webhook_delivery").Prepared(true).Insert(dd[i]).Exec(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return errors.DBError.Wrapf(err, "error scheduling webhook deliveries for event %d", dd[0].EventID)
+	}
+	return nil
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, d *Delivery) (err error) {
+	_, span := tracing.StartSpan(ctx, "webhookRepository.UpdateDelivery", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	d.LastUpdated = time.Now().Unix()
+
+	_, err = r.db.Tx(func(tx *goqu.TxDatabase) exec.QueryExecutor {
+		return tx.From("webhook_delivery").Prepared(true).Where(goqu.Ex{"did": d.DeliveryID}).Update(goqu.Record{
+			"status":           d.Status,
+			"attempts":         d.Attempts,
+			"next_attempt_at":  d.NextAttemptAt,
+			"last_status_code": d.LastStatusCode,
+			"last_error":       d.LastError,
+			"last_updated":     d.LastUpdated,
+		})
+	})
+
+	if err != nil {
+		return errors.DBError.Wrapf(err, "error updating webhook delivery with ID %d", d.DeliveryID)
+	}
+	return nil
+}
+
+func (r *webhookRepository) ResetDelivery(ctx context.Context, vID int, dID int) (_ Delivery, err error) {
+	_, span := tracing.StartSpan(ctx, "webhookRepository.ResetDelivery", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	now := time.Now().Unix()
+
+	_, err = r.db.Tx(func(tx *goqu.TxDatabase) exec.QueryExecutor {
+		return tx.From("webhook_delivery").Where(goqu.Ex{"did": dID, "venue_id": vID}).Update(goqu.Record{
+			"status":          StatusPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+			"last_updated":    now,
+		})
+	})
+
+	if err != nil {
+		return Delivery{}, errors.DBError.Wrapf(err, "error resetting webhook delivery with ID %d", dID)
+	}
+	return r.FindDeliveryByID(ctx, vID, dID)
+}
+
+func (r *webhookRepository) FindDueDeliveries(ctx context.Context, now int64, limit uint) (dd []Delivery, err error) {
+	_, span := tracing.StartSpan(ctx, "webhookRepository.FindDueDeliveries", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	err = r.db.DB.From("webhook_delivery").
+		Where(
+			goqu.C("status").In(StatusPending, StatusRetrying),
+			goqu.C("next_attempt_at").Lte(now),
+		).
+		Order(goqu.C("did").Asc()).
+		Limit(limit).
+		ScanStructs(&dd)
+
+	if err != nil {
+		return nil, errors.DBError.Wrap(err, "error getting due webhook deliveries")
+	}
+	return dd, nil
+}
+
+func (r *webhookRepository) FindDeliveryByID(ctx context.Context, vID int, dID int) (d Delivery, err error) {
+	_, span := tracing.StartSpan(ctx, "webhookRepository.FindDeliveryByID", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	found, err := r.db.DB.From("webhook_delivery").Where(
+		goqu.C("did").Eq(dID),
+		goqu.C("venue_id").Eq(vID),
+	).ScanStruct(&d)
+
+	if err != nil {
+		return d, errors.DBError.Wrapf(err, "error getting webhook delivery with ID %d", dID)
+	}
+
+	if !found {
+		return d, errors.NotFound.Newf("webhook delivery with ID %d not found", dID).
+			AddContext("DeliveryID", "non existent ID")
+	}
+
+	return d, nil
+}
+
+func (r *webhookRepository) FindDeliveriesBySubscriptionID(ctx context.Context, vID int, sID int, status string, opts *storage.QueryOptions) (dd []Delivery, err error) {
+	_, span := tracing.StartSpan(ctx, "webhookRepository.FindDeliveriesBySubscriptionID", r.db.SpanOptions()...)
+	defer tracing.EndSpan(span, &err)
+
+	opts.Limit = r.db.PageSize(opts.Limit)
+
+	where := goqu.Ex{"subscription_id": sID, "venue_id": vID}
+	if status != "" {
+		where["status"] = status
+	}
+
+	err = r.db.DB.From("webhook_delivery").Prepared(true).
+		Where(where).
+		Order(goqu.C("did").Desc()).
+		Limit(opts.Limit).
+		Offset(opts.Offset).
+		ScanStructs(&dd)
+
+	if err != nil {
+		return nil, errors.DBError.Wrapf(err, "error getting webhook deliveries for subscription with ID %d", sID)
+	}
+	return dd, nil
+}
@@ -0,0 +1,206 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/hex"
+	"net/url"
+	errors "reservations/pkg/error"
+	"reservations/pkg/event"
+	"reservations/pkg/storage"
+	"reservations/pkg/tenant"
+	"strings"
+)
+
+const (
+	// AllEvents subscribes to every event type.
+	AllEvents event.Type = "*"
+
+	secretLength = 32
+)
+
+// Delivery states.
+const (
+	StatusPending   = "pending"
+	StatusRetrying  = "retrying"
+	StatusDelivered = "delivered"
+	StatusDead      = "dead"
+)
+
+type Service interface {
+	CreateSubscription(ctx context.Context, s *Subscription) (*Subscription, error)
+	RemoveSubscription(ctx context.Context, sID int) error
+	GetAllSubscriptions(ctx context.Context, opts *storage.QueryOptions) ([]Subscription, error)
+	GetSubscriptionByID(ctx context.Context, sID int) (Subscription, error)
+	GetDeliveries(ctx context.Context, sID int, status string, opts *storage.QueryOptions) ([]Delivery, error)
+	RetryDelivery(ctx context.Context, dID int) (Delivery, error)
+}
+
+// Subscription registers a URL to receive signed callbacks for a set of event types.
+type Subscription struct {
+	SubscriptionID int        `json:"subscriptionId" db:"sid" goqu:"skipinsert"`
+	URL            string     `json:"url"`
+	EventTypes     EventTypes `json:"eventTypes" db:"event_types" swaggertype:"array,string"`
+	Secret         string     `json:"secret,omitempty"`
+	Active         bool       `json:"active"`
+	VenueID        int        `json:"venueId" db:"venue_id"`
+	Created        int64      `json:"created"`
+	LastUpdated    int64      `json:"lastUpdated" db:"last_updated"`
+}
+
+// Matches reports whether the subscription wants events of type t.
+func (s Subscription) Matches(t event.Type) bool {
+	for _, et := range s.EventTypes {
+		if et == AllEvents || et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is a single event to be sent to a single subscription, together
+// with the outcome of the latest attempt.
+type Delivery struct {
+	DeliveryID     int          `json:"deliveryId" db:"did" goqu:"skipinsert"`
+	SubscriptionID int          `json:"subscriptionId" db:"subscription_id"`
+	EventID        int          `json:"eventId" db:"event_id"`
+	EventType      event.Type   `json:"eventType" db:"event_type" swaggertype:"string"`
+	Payload        storage.JSON `json:"payload"`
+	Status         string       `json:"status"`
+	Attempts       int          `json:"attempts"`
+	NextAttemptAt  int64        `json:"nextAttemptAt" db:"next_attempt_at"`
+	LastStatusCode int          `json:"lastStatusCode" db:"last_status_code"`
+	LastError      string       `json:"lastError" db:"last_error"`
+	VenueID        int          `json:"venueId" db:"venue_id"`
+	Created        int64        `json:"created"`
+	LastUpdated    int64        `json:"lastUpdated" db:"last_updated"`
+}
+
+// EventTypes is stored as a comma separated list.
+type EventTypes []event.Type
+
+func (et EventTypes) Value() (driver.Value, error) {
+	ss := make([]string, len(et))
+	for i, t := range et {
+		ss[i] = string(t)
+	}
+	return strings.Join(ss, ","), nil
+}
+
+func (et *EventTypes) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return errors.DBError.Newf("cannot scan %T into event types", src)
+	}
+
+	*et = nil
+	for _, t := range strings.Split(s, ",") {
+		if t != "" {
+			*et = append(*et, event.Type(t))
+		}
+	}
+	return nil
+}
+
+type webhookService struct {
+	hookRepo Repository
+}
+
+func NewWebhookService(repo Repository) Service {
+	return &webhookService{
+		hookRepo: repo,
+	}
+}
+
+func (s *webhookService) CreateSubscription(ctx context.Context, sub *Subscription) (*Subscription, error) {
+	if err := validateSubscription(sub); err != nil {
+		return nil, err
+	}
+
+	if sub.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return nil, err
+		}
+		sub.Secret = secret
+	}
+	sub.Active = true
+	sub.VenueID = tenant.VenueFromContext(ctx)
+
+	return s.hookRepo.AddSubscription(ctx, sub)
+}
+
+func (s *webhookService) RemoveSubscription(ctx context.Context, sID int) error {
+	return s.hookRepo.RemoveSubscription(ctx, tenant.VenueFromContext(ctx), sID)
+}
+
+func (s *webhookService) GetAllSubscriptions(ctx context.Context, opts *storage.QueryOptions) ([]Subscription, error) {
+	ss, err := s.hookRepo.FindAllSubscriptions(ctx, tenant.VenueFromContext(ctx), opts)
+	for i := range ss {
+		ss[i].Secret = ""
+	}
+	return ss, err
+}
+
+func (s *webhookService) GetSubscriptionByID(ctx context.Context, sID int) (Subscription, error) {
+	sub, err := s.hookRepo.FindSubscriptionByID(ctx, tenant.VenueFromContext(ctx), sID)
+	sub.Secret = ""
+	return sub, err
+}
+
+func (s *webhookService) GetDeliveries(ctx context.Context, sID int, status string, opts *storage.QueryOptions) ([]Delivery, error) {
+	vID := tenant.VenueFromContext(ctx)
+	if _, err := s.hookRepo.FindSubscriptionByID(ctx, vID, sID); err != nil {
+		return nil, err
+	}
+	return s.hookRepo.FindDeliveriesBySubscriptionID(ctx, vID, sID, status, opts)
+}
+
+func (s *webhookService) RetryDelivery(ctx context.Context, dID int) (Delivery, error) {
+	vID := tenant.VenueFromContext(ctx)
+	d, err := s.hookRepo.FindDeliveryByID(ctx, vID, dID)
+	if err != nil {
+		return d, err
+	}
+	if d.Status != StatusDead {
+		return d, errors.Conflict.Newf("delivery %d is %s, only dead deliveries can be retried", dID, d.Status).
+			AddContext("status", d.Status)
+	}
+	return s.hookRepo.ResetDelivery(ctx, vID, dID)
+}
+
+func validateSubscription(sub *Subscription) error {
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.ValidationError.Newf("invalid webhook URL %q", sub.URL).
+			AddContext("url", "must be an absolute http or https URL")
+	}
+
+	if len(sub.EventTypes) == 0 {
+		return errors.ValidationError.New("no event types given").
+			AddContext("eventTypes", "at least one event type or * is required")
+	}
+	for _, t := range sub.EventTypes {
+		if t != AllEvents && !t.Known() {
+			return errors.ValidationError.Newf("unknown event type %s", t).
+				AddContext("eventTypes", "unknown event type")
+		}
+	}
+
+	return nil
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, secretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "error generating webhook secret")
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+)
+
+// Sign returns the signature header value for a payload sent at the given
+// time: "t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<payload>">".
+// Including the timestamp lets receivers reject replayed deliveries.
+func Sign(secret string, ts time.Time, payload []byte) string {
+	t := strconv.FormatInt(ts.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", t, computeMAC(secret, t, payload))
+}
+
+// Verify checks a signature header produced by Sign and rejects signatures
+// older than tolerance.
+func Verify(secret string, header string, payload []byte, tolerance time.Duration) bool {
+	var t, v1 string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			t = kv[1]
+		case "v1":
+			v1 = kv[1]
+		}
+	}
+
+	ts, err := strconv.ParseInt(t, 10, 64)
+	if err != nil || v1 == "" {
+		return false
+	}
+	if tolerance > 0 && time.Since(time.Unix(ts, 0)) > tolerance {
+		return false
+	}
+
+	return hmac.Equal([]byte(v1), []byte(computeMAC(secret, t, payload)))
+}
+
+func computeMAC(secret string, t string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
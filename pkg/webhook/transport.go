@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	"net/http"
+	"reservations/pkg/authz"
+	"reservations/pkg/transport"
+)
+
+func MakeHTTPHandler(r *mux.Router, s Service, az authz.Authorizer, logger log.Logger, mw ...endpoint.Middleware) *mux.Router {
+	e := MakeServerEndpoints(s, az, mw...)
+
+	options := httpjson.DefaultServerOptions(logger)
+
+	r.Methods("POST").Path("/webhook").
+		Handler(httptransport.NewServer(
+			e.CreateSubscriptionEndpoint,
+			decodeCreateSubscriptionRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("DELETE").Path("/webhook/{id}").
+		Handler(httptransport.NewServer(
+			e.RemoveSubscriptionEndpoint,
+			decodeRemoveSubscriptionRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("GET").Path("/webhook/{id}").
+		Handler(httptransport.NewServer(
+			e.GetSubscriptionByIDEndpoint,
+			decodeGetSubscriptionByIDRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("GET").Path("/webhooks").
+		Handler(httptransport.NewServer(
+			e.GetAllSubscriptionsEndpoint,
+			decodeGetAllSubscriptionsRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("GET").Path("/webhook/{id}/deliveries").
+		Handler(httptransport.NewServer(
+			e.GetDeliveriesEndpoint,
+			decodeGetDeliveriesRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	r.Methods("POST").Path("/webhook/delivery/{id}/retry").
+		Handler(httptransport.NewServer(
+			e.RetryDeliveryEndpoint,
+			decodeRetryDeliveryRequest,
+			httpjson.EncodeResponse,
+			options...,
+		))
+
+	return r
+}
+
+func decodeCreateSubscriptionRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req createSubscriptionRequest
+	if e := json.NewDecoder(r.Body).Decode(&req.Subscription); e != nil {
+		return nil, e
+	}
+	return req, nil
+}
+
+func decodeRemoveSubscriptionRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "subscription ID")
+	if err != nil {
+		return nil, err
+	}
+	return removeSubscriptionRequest{SubscriptionID: id}, nil
+}
+
+func decodeGetSubscriptionByIDRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "subscription ID")
+	if err != nil {
+		return nil, err
+	}
+	return getSubscriptionByIDRequest{SubscriptionID: id}, nil
+}
+
+func decodeGetAllSubscriptionsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	return getAllSubscriptionsRequest{
+		Limit:  httpjson.ParseUintQueryParam(r, "limit"),
+		Offset: httpjson.ParseUintQueryParam(r, "offset"),
+	}, nil
+}
+
+func decodeGetDeliveriesRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "subscription ID")
+	if err != nil {
+		return nil, err
+	}
+
+	return getDeliveriesRequest{
+		SubscriptionID: id,
+		Status:         r.URL.Query().Get("status"),
+		Limit:          httpjson.ParseUintQueryParam(r, "limit"),
+		Offset:         httpjson.ParseUintQueryParam(r, "offset"),
+	}, nil
+}
+
+func decodeRetryDeliveryRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := httpjson.ParseIntPathParam(r, "id", "delivery ID")
+	if err != nil {
+		return nil, err
+	}
+	return retryDeliveryRequest{DeliveryID: id}, nil
+}